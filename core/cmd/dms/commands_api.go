@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/client"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Talk to the running dms server",
+	Long:  "Send requests to the dms server over its unix socket (found via DMS_SOCKET or the runtime directory)",
+}
+
+var apiCallCmd = &cobra.Command{
+	Use:               "call <method>",
+	Short:             "Call a server method",
+	Long:              "Call a server method and print its result as JSON. Parameters are given as --param key=value; values that parse as JSON are sent as such, anything else as a string.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAPIMethods,
+	Run:               runAPICall,
+}
+
+var apiSubscribeCmd = &cobra.Command{
+	Use:   "subscribe [services...]",
+	Short: "Stream service events",
+	Long:  "Subscribe to server events and print them as newline-delimited JSON. Subscribes to all services when none are given.",
+	Run:   runAPISubscribe,
+}
+
+var apiInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show server info",
+	Long:  "Show the API version and capabilities of the running server",
+	Args:  cobra.NoArgs,
+	Run:   runAPIInfo,
+}
+
+func init() {
+	apiCmd.PersistentFlags().String("socket", "", "Server socket path (default: $DMS_SOCKET or auto-detect)")
+	apiCallCmd.Flags().StringArrayP("param", "p", nil, "Request parameter as key=value (repeatable)")
+	apiCallCmd.Flags().String("params", "", "Request parameters as a JSON object")
+
+	apiCmd.AddCommand(apiCallCmd, apiSubscribeCmd, apiInfoCmd)
+}
+
+func dialAPI(cmd *cobra.Command) (*client.Client, error) {
+	socketPath, _ := cmd.Flags().GetString("socket")
+	if socketPath == "" {
+		var err error
+		socketPath, err = server.FindSocket()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return client.Dial(socketPath)
+}
+
+func parseAPIParams(raw string, pairs []string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &params); err != nil {
+			return nil, fmt.Errorf("invalid --params: %w", err)
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --param %q, expected key=value", pair)
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			decoded = value
+		}
+		params[key] = decoded
+	}
+
+	return params, nil
+}
+
+func printAPIResult(result json.RawMessage) {
+	if len(result) == 0 {
+		fmt.Fprintln(os.Stdout, "null")
+		return
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(result, &decoded); err != nil {
+		fmt.Fprintln(os.Stdout, string(result))
+		return
+	}
+
+	output, _ := json.MarshalIndent(decoded, "", "  ")
+	fmt.Fprintln(os.Stdout, string(output))
+}
+
+func runAPICall(cmd *cobra.Command, args []string) {
+	raw, _ := cmd.Flags().GetString("params")
+	pairs, _ := cmd.Flags().GetStringArray("param")
+	params, err := parseAPIParams(raw, pairs)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	c, err := dialAPI(cmd)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer c.Close()
+
+	if strings.HasSuffix(args[0], ".subscribe") || args[0] == "subscribe" {
+		err := c.Stream(args[0], params, func(result json.RawMessage) error {
+			_, err := fmt.Fprintln(os.Stdout, string(result))
+			return err
+		})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+
+	result, err := c.Call(args[0], params)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	printAPIResult(result)
+}
+
+func runAPISubscribe(cmd *cobra.Command, args []string) {
+	c, err := dialAPI(cmd)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer c.Close()
+
+	err = c.Subscribe(args, func(event json.RawMessage) error {
+		_, err := fmt.Fprintln(os.Stdout, string(event))
+		return err
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func runAPIInfo(cmd *cobra.Command, args []string) {
+	c, err := dialAPI(cmd)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer c.Close()
+
+	result, err := c.Call("getServerInfo", nil)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	printAPIResult(result)
+}
+
+// completeAPIMethods completes method names from the registry published by
+// the running server. When no server is reachable, or it predates
+// listMethods, the methods built into this binary are offered instead.
+func completeAPIMethods(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	methods := serverAPIMethods(cmd)
+	if methods == nil {
+		methods = server.ListMethods()
+	}
+
+	var completions []string
+	for _, method := range methods {
+		if !strings.HasPrefix(method.Name, toComplete) {
+			continue
+		}
+		if method.Description != "" {
+			completions = append(completions, method.Name+"\t"+method.Description)
+			continue
+		}
+		completions = append(completions, method.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func serverAPIMethods(cmd *cobra.Command) []server.MethodInfo {
+	c, err := dialAPI(cmd)
+	if err != nil {
+		return nil
+	}
+	defer c.Close()
+
+	result, err := c.Call("listMethods", nil)
+	if err != nil {
+		return nil
+	}
+
+	var methods []server.MethodInfo
+	if err := json.Unmarshal(result, &methods); err != nil {
+		return nil
+	}
+	return methods
+}
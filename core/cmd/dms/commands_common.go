@@ -369,6 +369,7 @@ func getCommonCommands() []*cobra.Command {
 		dank16Cmd,
 		brightnessCmd,
 		keybindsCmd,
+		apiCmd,
 		greeterCmd,
 		setupCmd,
 	}
@@ -33,11 +33,42 @@ var keybindsShowCmd = &cobra.Command{
 	Run:   runKeybindsShow,
 }
 
+var keybindsSetCmd = &cobra.Command{
+	Use:   "set <provider> <key> <action>",
+	Short: "Add or rebind a keybind",
+	Long:  "Bind a key combo (e.g. SUPER+SHIFT+Q) to an action in the provider's config, replacing any existing binding",
+	Args:  cobra.ExactArgs(3),
+	Run:   runKeybindsSet,
+}
+
+var keybindsRemoveCmd = &cobra.Command{
+	Use:   "remove <provider> <key>",
+	Short: "Remove a keybind",
+	Long:  "Remove all bindings of a key combo from the provider's config",
+	Args:  cobra.ExactArgs(2),
+	Run:   runKeybindsRemove,
+}
+
+var keybindsCheckCmd = &cobra.Command{
+	Use:   "check <provider>",
+	Short: "Check for conflicting keybinds",
+	Long:  "Report key combos bound more than once across the provider's config and included files",
+	Args:  cobra.ExactArgs(1),
+	Run:   runKeybindsCheck,
+}
+
 func init() {
 	keybindsShowCmd.Flags().String("path", "", "Override config path for the provider")
+	keybindsSetCmd.Flags().String("path", "", "Override config path for the provider")
+	keybindsSetCmd.Flags().String("desc", "", "Description stored as a comment next to the bind")
+	keybindsRemoveCmd.Flags().String("path", "", "Override config path for the provider")
+	keybindsCheckCmd.Flags().String("path", "", "Override config path for the provider")
 
 	keybindsCmd.AddCommand(keybindsListCmd)
 	keybindsCmd.AddCommand(keybindsShowCmd)
+	keybindsCmd.AddCommand(keybindsSetCmd)
+	keybindsCmd.AddCommand(keybindsRemoveCmd)
+	keybindsCmd.AddCommand(keybindsCheckCmd)
 
 	keybinds.SetJSONProviderFactory(func(filePath string) (keybinds.Provider, error) {
 		return providers.NewJSONFileProvider(filePath)
@@ -64,6 +95,11 @@ func initializeProviders() {
 		log.Warnf("Failed to register Sway provider: %v", err)
 	}
 
+	niriProvider := providers.NewNiriProvider("$HOME/.config/niri")
+	if err := registry.Register(niriProvider); err != nil {
+		log.Warnf("Failed to register Niri provider: %v", err)
+	}
+
 	config := keybinds.DefaultDiscoveryConfig()
 	if err := keybinds.AutoDiscoverProviders(registry, config); err != nil {
 		log.Warnf("Failed to auto-discover providers: %v", err)
@@ -99,6 +135,8 @@ func runKeybindsShow(cmd *cobra.Command, args []string) {
 			provider = providers.NewMangoWCProvider(customPath)
 		case "sway":
 			provider = providers.NewSwayProvider(customPath)
+		case "niri":
+			provider = providers.NewNiriProvider(customPath)
 		default:
 			log.Fatalf("Provider %s does not support custom path", providerName)
 		}
@@ -134,3 +172,71 @@ func runKeybindsShow(cmd *cobra.Command, args []string) {
 
 	fmt.Fprintln(os.Stdout, string(output))
 }
+
+func getWritableProvider(cmd *cobra.Command, providerName string) keybinds.WritableProvider {
+	var provider keybinds.Provider
+
+	customPath, _ := cmd.Flags().GetString("path")
+	if customPath != "" {
+		switch providerName {
+		case "hyprland":
+			provider = providers.NewHyprlandProvider(customPath)
+		case "mangowc":
+			provider = providers.NewMangoWCProvider(customPath)
+		case "sway":
+			provider = providers.NewSwayProvider(customPath)
+		default:
+			log.Fatalf("Provider %s does not support custom path", providerName)
+		}
+	} else {
+		var err error
+		provider, err = keybinds.GetDefaultRegistry().Get(providerName)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	writable, ok := provider.(keybinds.WritableProvider)
+	if !ok {
+		log.Fatalf("Provider %s does not support editing keybinds", providerName)
+	}
+	return writable
+}
+
+func runKeybindsSet(cmd *cobra.Command, args []string) {
+	provider := getWritableProvider(cmd, args[0])
+	description, _ := cmd.Flags().GetString("desc")
+
+	if err := provider.SetBind(args[1], args[2], description); err != nil {
+		log.Fatalf("Error setting keybind: %v", err)
+	}
+	fmt.Fprintf(os.Stdout, "Bound %s to %s\n", args[1], args[2])
+}
+
+func runKeybindsRemove(cmd *cobra.Command, args []string) {
+	provider := getWritableProvider(cmd, args[0])
+
+	if err := provider.RemoveBind(args[1]); err != nil {
+		log.Fatalf("Error removing keybind: %v", err)
+	}
+	fmt.Fprintf(os.Stdout, "Removed %s\n", args[1])
+}
+
+func runKeybindsCheck(cmd *cobra.Command, args []string) {
+	provider := getWritableProvider(cmd, args[0])
+
+	conflicts, err := provider.CheckConflicts()
+	if err != nil {
+		log.Fatalf("Error checking keybinds: %v", err)
+	}
+
+	output, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		log.Fatalf("Error generating JSON: %v", err)
+	}
+
+	fmt.Fprintln(os.Stdout, string(output))
+	if len(conflicts) > 0 {
+		os.Exit(1)
+	}
+}
@@ -7,6 +7,26 @@ import (
 	"strings"
 )
 
+// XDGDir returns the XDG base directory named by env, e.g. XDG_STATE_HOME,
+// or fallback under the home directory when it is unset. Without a home
+// directory the temp dir is used.
+func XDGDir(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(homeDir, fallback)
+}
+
+// DMSDir returns the DankMaterialShell directory inside an XDG base
+// directory, see XDGDir.
+func DMSDir(env, fallback string) string {
+	return filepath.Join(XDGDir(env, fallback), "DankMaterialShell")
+}
+
 // LocateDMSConfig searches for DMS installation following XDG Base Directory specification
 func LocateDMSConfig() (string, error) {
 	var primaryPaths []string
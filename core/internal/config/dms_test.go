@@ -0,0 +1,18 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXDGDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	assert.Equal(t, "/tmp/state", XDGDir("XDG_STATE_HOME", filepath.Join(".local", "state")))
+	assert.Equal(t, "/tmp/state/DankMaterialShell", DMSDir("XDG_STATE_HOME", filepath.Join(".local", "state")))
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	assert.Equal(t, "/home/user/.local/state/DankMaterialShell", DMSDir("XDG_STATE_HOME", filepath.Join(".local", "state")))
+}
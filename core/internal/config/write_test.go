@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state", "data.json")
+
+	require.NoError(t, WriteJSON(path, map[string]int{"a": 1}))
+	require.NoError(t, WriteJSON(path, map[string]int{"b": 2}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"b": 2}`, string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp files must not be left behind")
+
+	require.NoError(t, os.Chmod(path, 0600))
+	require.NoError(t, WriteJSON(path, map[string]int{"c": 3}))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
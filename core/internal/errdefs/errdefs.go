@@ -20,6 +20,7 @@ const (
 	ErrTypeSecretPromptTimeout
 	ErrTypeSecretAgentFailed
 	ErrTypeGeneric
+	ErrTypeInvalidKeyframes
 )
 
 type CustomError struct {
@@ -56,6 +57,7 @@ var (
 	ErrInvalidGamma          = NewCustomError(ErrTypeInvalidGamma, "gamma must be between 0 and 10")
 	ErrInvalidLocation       = NewCustomError(ErrTypeInvalidLocation, "invalid latitude/longitude")
 	ErrInvalidManualTimes    = NewCustomError(ErrTypeInvalidManualTimes, "both sunrise and sunset must be set or neither")
+	ErrInvalidKeyframes      = NewCustomError(ErrTypeInvalidKeyframes, "keyframes need distinct HH:MM times")
 	ErrNoWaylandDisplay      = NewCustomError(ErrTypeNoWaylandDisplay, "no wayland display available")
 	ErrNoGammaControl        = NewCustomError(ErrTypeNoGammaControl, "compositor does not support gamma control")
 	ErrNotInitialized        = NewCustomError(ErrTypeNotInitialized, "manager not initialized")
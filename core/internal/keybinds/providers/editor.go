@@ -0,0 +1,399 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+)
+
+type bindLine struct {
+	// head is the original text up to the action (or up to the description
+	// of a described bind), reused verbatim when an existing bind changes.
+	head   string
+	prefix string
+	mods   []string
+	key    string
+	// described marks syntaxes such as Hyprland's bindd that keep the
+	// description in its own field instead of a trailing comment.
+	described   bool
+	description string
+	action      string
+	comment     string
+}
+
+// bindSyntax describes the line-oriented config format of a compositor.
+type bindSyntax interface {
+	mainFile() string
+	defaultPrefix() string
+	parseInclude(line string) (string, bool)
+	parseVariable(line string) (string, string, bool)
+	parseScope(line, current string) (string, bool)
+	parseBind(line string) (*bindLine, bool)
+	formatBind(b *bindLine) string
+}
+
+type locatedBind struct {
+	file  string
+	index int
+	scope string
+	combo string
+	bind  *bindLine
+}
+
+type configEditor struct {
+	syntax  bindSyntax
+	main    string
+	files   []string
+	lines   map[string][]string
+	vars    map[string]string
+	binds   []locatedBind
+	visited map[string]bool
+}
+
+var canonicalMods = map[string]string{
+	"super":   "SUPER",
+	"mod4":    "SUPER",
+	"win":     "SUPER",
+	"logo":    "SUPER",
+	"mod":     "SUPER",
+	"alt":     "ALT",
+	"mod1":    "ALT",
+	"ctrl":    "CTRL",
+	"control": "CTRL",
+	"shift":   "SHIFT",
+}
+
+func loadConfigEditor(configPath string, syntax bindSyntax) (*configEditor, error) {
+	expanded, err := expandConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		return nil, err
+	}
+
+	main := expanded
+	if info.IsDir() {
+		main = filepath.Join(expanded, syntax.mainFile())
+	}
+
+	e := &configEditor{
+		syntax:  syntax,
+		main:    main,
+		lines:   make(map[string][]string),
+		vars:    make(map[string]string),
+		visited: make(map[string]bool),
+	}
+
+	if err := e.loadFile(main, ""); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *configEditor) loadFile(path, scope string) error {
+	if e.visited[path] {
+		return nil
+	}
+	e.visited[path] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	e.files = append(e.files, path)
+	e.lines[path] = lines
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if name, value, ok := e.syntax.parseVariable(line); ok {
+			e.vars[name] = value
+			continue
+		}
+
+		if next, ok := e.syntax.parseScope(line, scope); ok {
+			scope = next
+			continue
+		}
+
+		if target, ok := e.syntax.parseInclude(line); ok {
+			if err := e.loadInclude(target, filepath.Dir(path), scope); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if b, ok := e.syntax.parseBind(line); ok {
+			e.binds = append(e.binds, locatedBind{
+				file:  path,
+				index: i,
+				scope: scope,
+				combo: e.normalizeCombo(b.mods, b.key),
+				bind:  b,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (e *configEditor) loadInclude(target, baseDir, scope string) error {
+	expanded, err := expandConfigPath(e.expandVars(target))
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(baseDir, expanded)
+	}
+
+	matches, err := filepath.Glob(expanded)
+	if err != nil {
+		return err
+	}
+	slices.Sort(matches)
+
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if err := e.loadFile(match, scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *configEditor) expandVars(text string) string {
+	names := make([]string, 0, len(e.vars))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	// Longest first so $mod does not clobber $modShift.
+	slices.SortFunc(names, func(a, b string) int { return len(b) - len(a) })
+
+	for _, name := range names {
+		text = strings.ReplaceAll(text, "$"+name, e.vars[name])
+	}
+	return text
+}
+
+func (e *configEditor) normalizeCombo(mods []string, key string) string {
+	var normalized []string
+	for _, mod := range mods {
+		for _, part := range splitModifiers(e.expandVars(mod)) {
+			if canon, ok := canonicalMods[strings.ToLower(part)]; ok {
+				part = canon
+			} else {
+				part = strings.ToUpper(part)
+			}
+			if !slices.Contains(normalized, part) {
+				normalized = append(normalized, part)
+			}
+		}
+	}
+	slices.Sort(normalized)
+
+	return strings.Join(append(normalized, strings.ToLower(e.expandVars(key))), "+")
+}
+
+func splitModifiers(mods string) []string {
+	return strings.FieldsFunc(mods, func(r rune) bool {
+		return r == '+' || r == ' ' || r == '_' || r == '\t'
+	})
+}
+
+func splitKeyCombo(combo string) ([]string, string, error) {
+	parts := strings.Split(strings.TrimSpace(combo), "+")
+	key := strings.TrimSpace(parts[len(parts)-1])
+	if key == "" {
+		return nil, "", fmt.Errorf("invalid key combo %q", combo)
+	}
+
+	mods := make([]string, 0, len(parts)-1)
+	for _, mod := range parts[:len(parts)-1] {
+		if mod = strings.TrimSpace(mod); mod != "" {
+			mods = append(mods, mod)
+		}
+	}
+	return mods, key, nil
+}
+
+func (e *configEditor) findGlobal(combo string) []locatedBind {
+	var found []locatedBind
+	for _, b := range e.binds {
+		if b.scope == "" && b.combo == combo {
+			found = append(found, b)
+		}
+	}
+	return found
+}
+
+func (e *configEditor) SetBind(combo, action, description string) error {
+	action = strings.TrimSpace(action)
+	if action == "" {
+		return fmt.Errorf("action cannot be empty")
+	}
+
+	mods, key, err := splitKeyCombo(combo)
+	if err != nil {
+		return err
+	}
+
+	if existing := e.findGlobal(e.normalizeCombo(mods, key)); len(existing) > 0 {
+		target := existing[0]
+		updated := *target.bind
+		updated.action = action
+		if description != "" && updated.described {
+			updated.description = description
+		} else if description != "" {
+			updated.comment = description
+		}
+		e.lines[target.file][target.index] = e.syntax.formatBind(&updated)
+		return e.save(target.file)
+	}
+
+	file, index := e.insertionPoint()
+	indent := ""
+	if index > 0 {
+		prev := e.lines[file][index-1]
+		indent = prev[:len(prev)-len(strings.TrimLeft(prev, " \t"))]
+	}
+
+	line := e.syntax.formatBind(&bindLine{
+		prefix:  indent + e.syntax.defaultPrefix(),
+		mods:    mods,
+		key:     key,
+		action:  action,
+		comment: description,
+	})
+
+	e.lines[file] = slices.Insert(e.lines[file], index, line)
+	return e.save(file)
+}
+
+// insertionPoint returns where a new bind goes: after the last global bind
+// of the main file, then of any file, falling back to the end of the main
+// file.
+func (e *configEditor) insertionPoint() (string, int) {
+	var last *locatedBind
+	for i := range e.binds {
+		b := &e.binds[i]
+		if b.scope != "" {
+			continue
+		}
+		if last == nil || b.file == e.main || last.file != e.main {
+			last = b
+		}
+	}
+	if last != nil {
+		return last.file, last.index + 1
+	}
+
+	lines := e.lines[e.main]
+	index := len(lines)
+	if index > 0 && lines[index-1] == "" {
+		index--
+	}
+	return e.main, index
+}
+
+func (e *configEditor) RemoveBind(combo string) error {
+	mods, key, err := splitKeyCombo(combo)
+	if err != nil {
+		return err
+	}
+
+	existing := e.findGlobal(e.normalizeCombo(mods, key))
+	if len(existing) == 0 {
+		return fmt.Errorf("no binding found for %s", combo)
+	}
+
+	removals := make(map[string][]int)
+	for _, b := range existing {
+		removals[b.file] = append(removals[b.file], b.index)
+	}
+
+	for file, indices := range removals {
+		slices.Sort(indices)
+		for i := len(indices) - 1; i >= 0; i-- {
+			e.lines[file] = slices.Delete(e.lines[file], indices[i], indices[i]+1)
+		}
+		if err := e.save(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *configEditor) CheckConflicts() []keybinds.Conflict {
+	type group struct {
+		scope string
+		combo string
+		binds []locatedBind
+	}
+
+	var groups []*group
+	byKey := make(map[string]*group)
+	for _, b := range e.binds {
+		id := b.scope + "\x00" + b.combo
+		g, ok := byKey[id]
+		if !ok {
+			g = &group{scope: b.scope, combo: b.combo}
+			byKey[id] = g
+			groups = append(groups, g)
+		}
+		g.binds = append(g.binds, b)
+	}
+
+	conflicts := []keybinds.Conflict{}
+	for _, g := range groups {
+		if len(g.binds) < 2 {
+			continue
+		}
+
+		conflict := keybinds.Conflict{Key: g.combo, Scope: g.scope}
+		for _, b := range g.binds {
+			conflict.Bindings = append(conflict.Bindings, keybinds.BindLocation{
+				File:   b.file,
+				Line:   b.index + 1,
+				Key:    strings.Join(append(append([]string{}, b.bind.mods...), b.bind.key), "+"),
+				Action: b.bind.action,
+				Scope:  b.scope,
+			})
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts
+}
+
+func (e *configEditor) save(file string) error {
+	target, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(target, []byte(strings.Join(e.lines[file], "\n")))
+}
+
+func joinBindComment(text, comment string) string {
+	if comment == "" {
+		return text
+	}
+	return text + " # " + comment
+}
@@ -0,0 +1,289 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func readConfigFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestHyprlandSetBindReplacesExisting(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, `# main config
+$mainMod = SUPER
+
+bind = $mainMod, Q, exec, kitty # Terminal
+bind = $mainMod, C, killactive
+`)
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.SetBind("SUPER+q", "exec, foot", "Foot"); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	want := `# main config
+$mainMod = SUPER
+
+bind = $mainMod, Q, exec, foot # Foot
+bind = $mainMod, C, killactive
+`
+	if got := readConfigFile(t, configFile); got != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHyprlandSetBindKeepsComment(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, "bind = SUPER, Q, exec, kitty # Terminal\n")
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.SetBind("SUPER+Q", "exec, foot", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	if got := readConfigFile(t, configFile); got != "bind = SUPER, Q, exec, foot # Terminal\n" {
+		t.Errorf("config = %q", got)
+	}
+}
+
+func TestHyprlandSetBindKeepsBinddDescription(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, "bindd = SUPER, Q, Terminal, exec, kitty\n")
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.SetBind("SUPER+Q", "exec, foot", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	if got := readConfigFile(t, configFile); got != "bindd = SUPER, Q, Terminal, exec, foot\n" {
+		t.Errorf("config = %q", got)
+	}
+}
+
+func TestHyprlandSetBindUpdatesBinddDescription(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, "bindd = SUPER, Q, Terminal, exec, kitty # launcher\n")
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.SetBind("SUPER+Q", "exec, foot", "Foot"); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	if got := readConfigFile(t, configFile); got != "bindd = SUPER, Q, Foot, exec, foot # launcher\n" {
+		t.Errorf("config = %q", got)
+	}
+}
+
+func TestHyprlandSetBindInsertsAfterLastBind(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, `bind = SUPER, Q, exec, kitty
+
+submap = resize
+binde = , right, resizeactive, 10 0
+submap = reset
+
+# trailing comment
+`)
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.SetBind("SUPER+SHIFT+E", "exit", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	want := `bind = SUPER, Q, exec, kitty
+bind = SUPER SHIFT, E, exit
+
+submap = resize
+binde = , right, resizeactive, 10 0
+submap = reset
+
+# trailing comment
+`
+	if got := readConfigFile(t, configFile); got != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHyprlandRemoveBind(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "hyprland.conf")
+	writeConfigFile(t, configFile, `bind = SUPER, Q, exec, kitty
+bind = SUPER, C, killactive
+`)
+
+	provider := NewHyprlandProvider(tmpDir)
+	if err := provider.RemoveBind("super+c"); err != nil {
+		t.Fatalf("RemoveBind failed: %v", err)
+	}
+	if got := readConfigFile(t, configFile); got != "bind = SUPER, Q, exec, kitty\n" {
+		t.Errorf("config = %q", got)
+	}
+
+	if err := provider.RemoveBind("SUPER+C"); err == nil {
+		t.Error("Expected error removing missing bind")
+	}
+}
+
+func TestHyprlandCheckConflictsAcrossSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeConfigFile(t, filepath.Join(tmpDir, "hyprland.conf"), `$mod = SUPER
+source = ./binds.conf
+bind = $mod, Q, exec, kitty
+bind = SUPER, W, exec, firefox
+`)
+	writeConfigFile(t, filepath.Join(tmpDir, "binds.conf"), `bind = SUPER, q, killactive
+submap = resize
+bind = , W, resizeactive, 10 0
+submap = reset
+`)
+
+	provider := NewHyprlandProvider(tmpDir)
+	conflicts, err := provider.CheckConflicts()
+	if err != nil {
+		t.Fatalf("CheckConflicts failed: %v", err)
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Key != "SUPER+q" {
+		t.Errorf("Key = %q, want %q", conflicts[0].Key, "SUPER+q")
+	}
+	if len(conflicts[0].Bindings) != 2 {
+		t.Fatalf("Expected 2 bindings, got %d", len(conflicts[0].Bindings))
+	}
+	if filepath.Base(conflicts[0].Bindings[0].File) != "binds.conf" || conflicts[0].Bindings[0].Line != 1 {
+		t.Errorf("First binding = %+v", conflicts[0].Bindings[0])
+	}
+	if filepath.Base(conflicts[0].Bindings[1].File) != "hyprland.conf" || conflicts[0].Bindings[1].Line != 3 {
+		t.Errorf("Second binding = %+v", conflicts[0].Bindings[1])
+	}
+}
+
+func TestSwaySetAndRemoveBind(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config")
+	writeConfigFile(t, configFile, `set $mod Mod4
+
+    bindsym $mod+Return exec kitty
+    bindsym --release $mod+d exec wofi
+
+mode "resize" {
+    bindsym Left resize shrink width 10px
+}
+`)
+
+	provider := NewSwayProvider(tmpDir)
+	if err := provider.SetBind("Mod4+d", "exec fuzzel", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+	if err := provider.SetBind("Mod4+Shift+e", "exit", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+	if err := provider.RemoveBind("Super+Return"); err != nil {
+		t.Fatalf("RemoveBind failed: %v", err)
+	}
+
+	want := `set $mod Mod4
+
+    bindsym --release $mod+d exec fuzzel
+    bindsym Mod4+Shift+e exit
+
+mode "resize" {
+    bindsym Left resize shrink width 10px
+}
+`
+	if got := readConfigFile(t, configFile); got != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSwayCheckConflictsIgnoresModes(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeConfigFile(t, filepath.Join(tmpDir, "config"), `include conf.d/*
+bindsym Left focus left
+mode "resize" {
+    bindsym Left resize shrink width 10px
+}
+`)
+	if err := os.Mkdir(filepath.Join(tmpDir, "conf.d"), 0755); err != nil {
+		t.Fatalf("Failed to create conf.d: %v", err)
+	}
+	writeConfigFile(t, filepath.Join(tmpDir, "conf.d", "extra"), "bindsym Mod4+f fullscreen\nbindsym mod4+F floating toggle\n")
+
+	provider := NewSwayProvider(tmpDir)
+	conflicts, err := provider.CheckConflicts()
+	if err != nil {
+		t.Fatalf("CheckConflicts failed: %v", err)
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Key != "SUPER+f" {
+		t.Errorf("Key = %q, want %q", conflicts[0].Key, "SUPER+f")
+	}
+}
+
+func TestMangoWCSetBind(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.conf")
+	writeConfigFile(t, configFile, `# mango
+bind=SUPER,Return,spawn,kitty
+bind=NONE,Print,spawn,grim
+gappih=5
+`)
+
+	provider := NewMangoWCProvider(tmpDir)
+	if err := provider.SetBind("Print", "spawn,flameshot", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+	if err := provider.SetBind("SUPER+SHIFT+q", "killclient,", ""); err != nil {
+		t.Fatalf("SetBind failed: %v", err)
+	}
+
+	want := `# mango
+bind=SUPER,Return,spawn,kitty
+bind=NONE,Print,spawn,flameshot
+bind=SUPER+SHIFT,q,killclient,
+gappih=5
+`
+	if got := readConfigFile(t, configFile); got != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMangoWCCheckConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeConfigFile(t, filepath.Join(tmpDir, "config.conf"), "source=binds.conf\nbind=SUPER,Return,spawn,kitty\n")
+	writeConfigFile(t, filepath.Join(tmpDir, "binds.conf"), "bind=super,return,spawn,foot\nbind=ALT,Tab,focusstack,next\n")
+
+	provider := NewMangoWCProvider(tmpDir)
+	conflicts, err := provider.CheckConflicts()
+	if err != nil {
+		t.Fatalf("CheckConflicts failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Key != "SUPER+return" {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+}
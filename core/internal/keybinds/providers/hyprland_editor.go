@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+)
+
+var (
+	hyprlandSourceRegex     = regexp.MustCompile(`^\s*source\s*=\s*(.+?)\s*$`)
+	hyprlandVariableRegex   = regexp.MustCompile(`^\s*\$(\w+)\s*=\s*(.*?)\s*$`)
+	hyprlandSubmapLineRegex = regexp.MustCompile(`^\s*submap\s*=\s*(\S+)`)
+)
+
+type hyprlandSyntax struct{}
+
+func (hyprlandSyntax) mainFile() string {
+	return "hyprland.conf"
+}
+
+func (hyprlandSyntax) defaultPrefix() string {
+	return "bind = "
+}
+
+func (hyprlandSyntax) parseInclude(line string) (string, bool) {
+	m := hyprlandSourceRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func (hyprlandSyntax) parseVariable(line string) (string, string, bool) {
+	m := hyprlandVariableRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+func (hyprlandSyntax) parseScope(line, current string) (string, bool) {
+	m := hyprlandSubmapLineRegex.FindStringSubmatch(line)
+	if m == nil {
+		return current, false
+	}
+	if m[1] == "reset" {
+		return "", true
+	}
+	return m[1], true
+}
+
+func (hyprlandSyntax) parseBind(line string) (*bindLine, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "bind") {
+		return nil, false
+	}
+
+	f, ok := splitHyprlandBind(line)
+	if !ok {
+		return nil, false
+	}
+
+	return &bindLine{
+		head:        f.head,
+		mods:        splitModifiers(f.mods),
+		key:         f.key,
+		described:   f.described(),
+		description: f.description,
+		action:      f.action,
+		comment:     f.comment,
+	}, true
+}
+
+func (hyprlandSyntax) formatBind(b *bindLine) string {
+	switch {
+	case b.head != "" && b.described:
+		return joinBindComment(b.head+b.description+", "+b.action, b.comment)
+	case b.head != "":
+		return joinBindComment(b.head+b.action, b.comment)
+	}
+	return joinBindComment(b.prefix+strings.Join(b.mods, " ")+", "+b.key+", "+b.action, b.comment)
+}
+
+func (h *HyprlandProvider) SetBind(key, action, description string) error {
+	editor, err := loadConfigEditor(h.configPath, hyprlandSyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.SetBind(key, action, description)
+}
+
+func (h *HyprlandProvider) RemoveBind(key string) error {
+	editor, err := loadConfigEditor(h.configPath, hyprlandSyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.RemoveBind(key)
+}
+
+func (h *HyprlandProvider) CheckConflicts() ([]keybinds.Conflict, error) {
+	editor, err := loadConfigEditor(h.configPath, hyprlandSyntax{})
+	if err != nil {
+		return nil, err
+	}
+	return editor.CheckConflicts(), nil
+}
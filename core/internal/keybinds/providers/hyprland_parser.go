@@ -196,41 +196,86 @@ func hyprlandAutogenerateComment(dispatcher, params string) string {
 	}
 }
 
-func (p *HyprlandParser) getKeybindAtLine(lineNumber int) *HyprlandKeyBinding {
-	line := p.contentLines[lineNumber]
-	parts := strings.SplitN(line, "=", 2)
-	if len(parts) < 2 {
-		return nil
+// hyprlandBindFields is a bind line split into its raw fields. head is the
+// line up to the description of a bindd line or the dispatcher otherwise, so
+// an edit can keep the original spelling of the keyword, mods and key.
+type hyprlandBindFields struct {
+	head        string
+	flags       string
+	mods        string
+	key         string
+	description string
+	action      string
+	comment     string
+}
+
+// described reports whether the line is a bindd-style bind, which carries a
+// description field between the key and the dispatcher.
+func (f *hyprlandBindFields) described() bool {
+	return strings.Contains(f.flags, "d")
+}
+
+func splitHyprlandBind(line string) (*hyprlandBindFields, bool) {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return nil, false
 	}
 
-	keys := parts[1]
-	keyParts := strings.SplitN(keys, "#", 2)
-	keys = keyParts[0]
+	f := &hyprlandBindFields{}
+	name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[:eq]), CommentBindPattern))
+	if strings.HasPrefix(name, "bind") {
+		f.flags = strings.TrimPrefix(name, "bind")
+	}
 
-	var comment string
-	if len(keyParts) > 1 {
-		comment = strings.TrimSpace(keyParts[1])
+	body := line[eq+1:]
+	if i := strings.Index(body, "#"); i >= 0 {
+		f.comment = strings.TrimSpace(body[i+1:])
+		body = body[:i]
 	}
 
-	keyFields := strings.SplitN(keys, ",", 5)
-	if len(keyFields) < 3 {
-		return nil
+	actionField := 2
+	if f.described() {
+		actionField = 3
 	}
 
-	mods := strings.TrimSpace(keyFields[0])
-	key := strings.TrimSpace(keyFields[1])
-	dispatcher := strings.TrimSpace(keyFields[2])
+	fields := strings.SplitN(body, ",", actionField+2)
+	if len(fields) <= actionField {
+		return nil, false
+	}
 
-	var params string
-	if len(keyFields) > 3 {
-		paramParts := keyFields[3:]
-		params = strings.TrimSpace(strings.Join(paramParts, ","))
+	f.mods = strings.TrimSpace(fields[0])
+	f.key = strings.TrimSpace(fields[1])
+	if f.described() {
+		f.description = strings.TrimSpace(fields[2])
 	}
+	f.action = strings.TrimSpace(strings.Join(fields[actionField:], ","))
+
+	headLen := eq + 1 + len(fields[0]) + 1 + len(fields[1]) + 1
+	headLen += len(fields[2]) - len(strings.TrimLeft(fields[2], " \t"))
+	f.head = line[:headLen]
+
+	return f, true
+}
+
+func (p *HyprlandParser) getKeybindAtLine(lineNumber int) *HyprlandKeyBinding {
+	fields, ok := splitHyprlandBind(p.contentLines[lineNumber])
+	if !ok {
+		return nil
+	}
+
+	mods := fields.mods
+	key := fields.key
+	comment := fields.comment
+	dispatcher, params, _ := strings.Cut(fields.action, ",")
+	dispatcher = strings.TrimSpace(dispatcher)
+	params = strings.TrimSpace(params)
 
 	if comment != "" {
 		if strings.HasPrefix(comment, HideComment) {
 			return nil
 		}
+	} else if fields.description != "" {
+		comment = fields.description
 	} else {
 		comment = hyprlandAutogenerateComment(dispatcher, params)
 	}
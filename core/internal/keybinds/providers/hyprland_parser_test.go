@@ -115,6 +115,17 @@ func TestHyprlandGetKeybindAtLine(t *testing.T) {
 				Comment:    "Toggle fullscreen",
 			},
 		},
+		{
+			name: "keybind_with_description",
+			line: "bindd = SUPER, Return, Open terminal, exec, kitty",
+			expected: &HyprlandKeyBinding{
+				Mods:       []string{"SUPER"},
+				Key:        "Return",
+				Dispatcher: "exec",
+				Params:     "kitty",
+				Comment:    "Open terminal",
+			},
+		},
 		{
 			name: "keybind_no_mods",
 			line: "bind = , Print, exec, screenshot",
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+)
+
+var (
+	mangowcSourceRegex = regexp.MustCompile(`^\s*source\s*=\s*(.+?)\s*$`)
+)
+
+type mangowcSyntax struct{}
+
+func (mangowcSyntax) mainFile() string {
+	return "config.conf"
+}
+
+func (mangowcSyntax) defaultPrefix() string {
+	return "bind="
+}
+
+func (mangowcSyntax) parseInclude(line string) (string, bool) {
+	m := mangowcSourceRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func (mangowcSyntax) parseVariable(line string) (string, string, bool) {
+	return "", "", false
+}
+
+func (mangowcSyntax) parseScope(line, current string) (string, bool) {
+	return current, false
+}
+
+func (mangowcSyntax) parseBind(line string) (*bindLine, bool) {
+	f, ok := splitMangoWCBind(line)
+	if !ok {
+		return nil, false
+	}
+
+	var mods []string
+	if !strings.EqualFold(f.mods, "none") {
+		mods = splitModifiers(f.mods)
+	}
+
+	return &bindLine{
+		head:    f.head,
+		mods:    mods,
+		key:     f.key,
+		action:  f.action,
+		comment: f.comment,
+	}, true
+}
+
+func (mangowcSyntax) formatBind(b *bindLine) string {
+	if b.head != "" {
+		return joinBindComment(b.head+b.action, b.comment)
+	}
+	mods := "none"
+	if len(b.mods) > 0 {
+		mods = strings.Join(b.mods, "+")
+	}
+	return joinBindComment(b.prefix+mods+","+b.key+","+b.action, b.comment)
+}
+
+func (m *MangoWCProvider) SetBind(key, action, description string) error {
+	editor, err := loadConfigEditor(m.configPath, mangowcSyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.SetBind(key, action, description)
+}
+
+func (m *MangoWCProvider) RemoveBind(key string) error {
+	editor, err := loadConfigEditor(m.configPath, mangowcSyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.RemoveBind(key)
+}
+
+func (m *MangoWCProvider) CheckConflicts() ([]keybinds.Conflict, error) {
+	editor, err := loadConfigEditor(m.configPath, mangowcSyntax{})
+	if err != nil {
+		return nil, err
+	}
+	return editor.CheckConflicts(), nil
+}
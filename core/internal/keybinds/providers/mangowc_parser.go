@@ -13,6 +13,8 @@ const (
 
 var MangoWCModSeparators = []rune{'+', ' '}
 
+var mangowcBindRegex = regexp.MustCompile(`^\s*bind[lsr]*\s*=\s*(.+)$`)
+
 type MangoWCKeyBinding struct {
 	Mods    []string `json:"mods"`
 	Key     string   `json:"key"`
@@ -196,48 +198,66 @@ func mangowcAutogenerateComment(command, params string) string {
 	}
 }
 
-func (p *MangoWCParser) getKeybindAtLine(lineNumber int) *MangoWCKeyBinding {
-	if lineNumber >= len(p.contentLines) {
-		return nil
+// mangowcBindFields is a bind line split into its raw fields. head is the
+// line up to the command.
+type mangowcBindFields struct {
+	head    string
+	mods    string
+	key     string
+	action  string
+	comment string
+}
+
+func splitMangoWCBind(line string) (*mangowcBindFields, bool) {
+	m := mangowcBindRegex.FindStringSubmatchIndex(line)
+	if m == nil {
+		return nil, false
 	}
 
-	line := p.contentLines[lineNumber]
+	f := &mangowcBindFields{}
+	body := line[m[2]:]
+	if i := strings.Index(body, "#"); i >= 0 {
+		f.comment = strings.TrimSpace(body[i+1:])
+		body = body[:i]
+	}
 
-	bindMatch := regexp.MustCompile(`^(bind[lsr]*)\s*=\s*(.+)$`)
-	matches := bindMatch.FindStringSubmatch(line)
-	if len(matches) < 3 {
-		return nil
+	fields := strings.SplitN(body, ",", 3)
+	if len(fields) < 3 {
+		return nil, false
 	}
 
-	bindType := matches[1]
-	content := matches[2]
+	f.mods = strings.TrimSpace(fields[0])
+	f.key = strings.TrimSpace(fields[1])
+	f.action = strings.TrimSpace(fields[2])
 
-	parts := strings.SplitN(content, "#", 2)
-	keys := parts[0]
+	headLen := m[2] + len(fields[0]) + 1 + len(fields[1]) + 1
+	headLen += len(fields[2]) - len(strings.TrimLeft(fields[2], " \t"))
+	f.head = line[:headLen]
 
-	var comment string
-	if len(parts) > 1 {
-		comment = strings.TrimSpace(parts[1])
-	}
+	return f, true
+}
 
-	if strings.HasPrefix(comment, MangoWCHideComment) {
+func (p *MangoWCParser) getKeybindAtLine(lineNumber int) *MangoWCKeyBinding {
+	if lineNumber >= len(p.contentLines) {
 		return nil
 	}
 
-	keyFields := strings.SplitN(keys, ",", 4)
-	if len(keyFields) < 3 {
+	fields, ok := splitMangoWCBind(p.contentLines[lineNumber])
+	if !ok {
 		return nil
 	}
 
-	mods := strings.TrimSpace(keyFields[0])
-	key := strings.TrimSpace(keyFields[1])
-	command := strings.TrimSpace(keyFields[2])
-
-	var params string
-	if len(keyFields) > 3 {
-		params = strings.TrimSpace(keyFields[3])
+	comment := fields.comment
+	if strings.HasPrefix(comment, MangoWCHideComment) {
+		return nil
 	}
 
+	mods := fields.mods
+	key := fields.key
+	command, params, _ := strings.Cut(fields.action, ",")
+	command = strings.TrimSpace(command)
+	params = strings.TrimSpace(params)
+
 	if comment == "" {
 		comment = mangowcAutogenerateComment(command, params)
 	}
@@ -263,8 +283,6 @@ func (p *MangoWCParser) getKeybindAtLine(lineNumber int) *MangoWCKeyBinding {
 		}
 	}
 
-	_ = bindType
-
 	return &MangoWCKeyBinding{
 		Mods:    modList,
 		Key:     key,
@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+)
+
+type NiriProvider struct {
+	configPath string
+}
+
+func NewNiriProvider(configPath string) *NiriProvider {
+	if configPath == "" {
+		configPath = "$HOME/.config/niri"
+	}
+	return &NiriProvider{
+		configPath: configPath,
+	}
+}
+
+func (n *NiriProvider) Name() string {
+	return "niri"
+}
+
+func (n *NiriProvider) GetCheatSheet() (*keybinds.CheatSheet, error) {
+	section, err := ParseNiriKeys(n.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse niri config: %w", err)
+	}
+
+	categorizedBinds := make(map[string][]keybinds.Keybind)
+	n.convertSection(section, "", categorizedBinds)
+
+	return &keybinds.CheatSheet{
+		Title:    "Niri Keybinds",
+		Provider: n.Name(),
+		Binds:    categorizedBinds,
+	}, nil
+}
+
+func (n *NiriProvider) convertSection(section *NiriSection, subcategory string, categorizedBinds map[string][]keybinds.Keybind) {
+	currentSubcat := subcategory
+	if section.Name != "" {
+		currentSubcat = section.Name
+	}
+
+	for _, kb := range section.Keybinds {
+		category := n.categorizeByAction(kb.Action)
+		bind := n.convertKeybind(&kb, currentSubcat)
+		categorizedBinds[category] = append(categorizedBinds[category], bind)
+	}
+
+	for _, child := range section.Children {
+		n.convertSection(&child, currentSubcat, categorizedBinds)
+	}
+}
+
+func (n *NiriProvider) categorizeByAction(action string) string {
+	switch {
+	case action == "quit" ||
+		strings.HasPrefix(action, "power-") ||
+		strings.Contains(action, "inhibit") ||
+		action == "suspend" ||
+		action == "load-config-file":
+		return "System"
+	case strings.Contains(action, "monitor"):
+		return "Monitor"
+	case strings.Contains(action, "workspace"):
+		return "Workspace"
+	case strings.HasPrefix(action, "screenshot"):
+		return "Screenshot"
+	case strings.Contains(action, "overview") || action == "show-hotkey-overlay":
+		return "Overview"
+	case strings.Contains(action, "window") ||
+		strings.Contains(action, "column") ||
+		strings.Contains(action, "focus") ||
+		strings.Contains(action, "move") ||
+		strings.Contains(action, "swap") ||
+		strings.Contains(action, "width") ||
+		strings.Contains(action, "height") ||
+		strings.Contains(action, "consume") ||
+		strings.Contains(action, "expel") ||
+		strings.Contains(action, "floating") ||
+		action == "center-visible-columns":
+		return "Window"
+	case action == "spawn" || action == "spawn-sh":
+		return "Execute"
+	default:
+		return "Other"
+	}
+}
+
+func (n *NiriProvider) convertKeybind(kb *NiriKeyBinding, subcategory string) keybinds.Keybind {
+	return keybinds.Keybind{
+		Key:         n.formatKey(kb),
+		Description: kb.Comment,
+		Subcategory: subcategory,
+	}
+}
+
+func (n *NiriProvider) formatKey(kb *NiriKeyBinding) string {
+	parts := make([]string, 0, len(kb.Mods)+1)
+	parts = append(parts, kb.Mods...)
+	parts = append(parts, kb.Key)
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,663 @@
+package providers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type KDLNode struct {
+	Name     string         `json:"name"`
+	Args     []any          `json:"args"`
+	Props    map[string]any `json:"props"`
+	Children []*KDLNode     `json:"children"`
+	Comments []string       `json:"comments"`
+	Line     int            `json:"line"`
+}
+
+func (n *KDLNode) StringArgs() []string {
+	args := make([]string, 0, len(n.Args))
+	for _, arg := range n.Args {
+		args = append(args, kdlValueString(arg))
+	}
+	return args
+}
+
+func (n *KDLNode) Child(name string) *KDLNode {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func kdlValueString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+type kdlParser struct {
+	src      []rune
+	pos      int
+	line     int
+	comments []string
+}
+
+// ParseKDL parses a KDL document. Both KDL 1.0 and the 2.0 spellings of
+// keywords and raw strings (#true, #"..."#) are accepted, since niri
+// configs in the wild use either.
+func ParseKDL(content string) ([]*KDLNode, error) {
+	p := &kdlParser{src: []rune(content), line: 1}
+	if len(p.src) > 0 && p.src[0] == '\uFEFF' {
+		p.pos++
+	}
+
+	nodes, err := p.parseNodes(false)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return nodes, nil
+}
+
+func (p *kdlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *kdlParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *kdlParser) peekAt(offset int) rune {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func (p *kdlParser) next() rune {
+	r := p.src[p.pos]
+	p.pos++
+	if r == '\n' {
+		p.line++
+	}
+	return r
+}
+
+func (p *kdlParser) hasPrefix(s string) bool {
+	for i, r := range []rune(s) {
+		if p.peekAt(i) != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isKDLNewline(r rune) bool {
+	switch r {
+	case '\n', '\r', '\u0085', '\u000C', '\u2028', '\u2029':
+		return true
+	}
+	return false
+}
+
+func isKDLSpace(r rune) bool {
+	return !isKDLNewline(r) && unicode.IsSpace(r)
+}
+
+func isKDLIdentRune(r rune) bool {
+	if r == 0 || unicode.IsSpace(r) {
+		return false
+	}
+	return !strings.ContainsRune(`\/(){}<>;[]=,"#`, r)
+}
+
+// skipLinespace consumes whitespace, newlines, semicolons and comments
+// between nodes. Line comments directly above a node are kept so callers
+// can use them as section headers.
+func (p *kdlParser) skipLinespace() error {
+	blankLines := 0
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case isKDLNewline(r):
+			p.next()
+			blankLines++
+			if blankLines > 1 {
+				p.comments = nil
+			}
+		case isKDLSpace(r) || r == ';':
+			p.next()
+		case p.hasPrefix("//"):
+			p.pos += 2
+			start := p.pos
+			for !p.eof() && !isKDLNewline(p.peek()) {
+				p.pos++
+			}
+			p.comments = append(p.comments, strings.TrimSpace(string(p.src[start:p.pos])))
+			blankLines = 0
+		case p.hasPrefix("/*"):
+			if err := p.skipBlockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+		if !isKDLNewline(r) && !isKDLSpace(r) && r != '/' {
+			blankLines = 0
+		}
+	}
+	return nil
+}
+
+func (p *kdlParser) skipBlockComment() error {
+	p.pos += 2
+	depth := 1
+	for depth > 0 {
+		if p.eof() {
+			return fmt.Errorf("unterminated block comment")
+		}
+		switch {
+		case p.hasPrefix("/*"):
+			p.pos += 2
+			depth++
+		case p.hasPrefix("*/"):
+			p.pos += 2
+			depth--
+		default:
+			p.next()
+		}
+	}
+	return nil
+}
+
+// skipNodeSpace consumes whitespace within a node, including escaped
+// newlines and block comments. It stops at anything that may terminate
+// the node.
+func (p *kdlParser) skipNodeSpace() (bool, error) {
+	skipped := false
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case isKDLSpace(r):
+			p.next()
+		case r == '\\':
+			p.next()
+			for !p.eof() && isKDLSpace(p.peek()) {
+				p.next()
+			}
+			if p.hasPrefix("//") {
+				for !p.eof() && !isKDLNewline(p.peek()) {
+					p.next()
+				}
+			}
+			if p.eof() {
+				return skipped, nil
+			}
+			if !isKDLNewline(p.peek()) {
+				return skipped, fmt.Errorf("unexpected character after line continuation")
+			}
+			p.next()
+		case p.hasPrefix("/*"):
+			if err := p.skipBlockComment(); err != nil {
+				return skipped, err
+			}
+		default:
+			return skipped, nil
+		}
+		skipped = true
+	}
+	return skipped, nil
+}
+
+func (p *kdlParser) parseNodes(inBlock bool) ([]*KDLNode, error) {
+	var nodes []*KDLNode
+	for {
+		if err := p.skipLinespace(); err != nil {
+			return nil, err
+		}
+
+		if p.eof() {
+			if inBlock {
+				return nil, fmt.Errorf("unexpected end of input, expected '}'")
+			}
+			return nodes, nil
+		}
+
+		if p.peek() == '}' {
+			if !inBlock {
+				return nil, fmt.Errorf("unexpected '}'")
+			}
+			p.next()
+			p.comments = nil
+			return nodes, nil
+		}
+
+		discard := false
+		if p.hasPrefix("/-") {
+			p.pos += 2
+			discard = true
+			if _, err := p.skipNodeSpace(); err != nil {
+				return nil, err
+			}
+		}
+
+		node, err := p.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		if !discard {
+			nodes = append(nodes, node)
+		}
+	}
+}
+
+func (p *kdlParser) parseNode() (*KDLNode, error) {
+	node := &KDLNode{
+		Props:    make(map[string]any),
+		Comments: p.comments,
+		Line:     p.line,
+	}
+	p.comments = nil
+
+	if err := p.skipTypeAnnotation(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Name = name
+
+	for {
+		spaced, err := p.skipNodeSpace()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.eof() {
+			return node, nil
+		}
+
+		r := p.peek()
+		switch {
+		case isKDLNewline(r) || r == ';':
+			p.next()
+			return node, nil
+		case r == '}':
+			return node, nil
+		case p.hasPrefix("//"):
+			for !p.eof() && !isKDLNewline(p.peek()) {
+				p.pos++
+			}
+			return node, nil
+		}
+
+		discard := false
+		if p.hasPrefix("/-") {
+			p.pos += 2
+			discard = true
+			if _, err := p.skipNodeSpace(); err != nil {
+				return nil, err
+			}
+		} else if !spaced && r != '{' {
+			return nil, fmt.Errorf("expected whitespace before %q in node %q", r, node.Name)
+		}
+
+		if p.peek() == '{' {
+			p.next()
+			children, err := p.parseNodes(true)
+			if err != nil {
+				return nil, err
+			}
+			if !discard {
+				node.Children = append(node.Children, children...)
+			}
+			continue
+		}
+
+		key, value, isProp, err := p.parseEntry()
+		if err != nil {
+			return nil, err
+		}
+		if discard {
+			continue
+		}
+		if isProp {
+			node.Props[key] = value
+		} else {
+			node.Args = append(node.Args, value)
+		}
+	}
+}
+
+func (p *kdlParser) skipTypeAnnotation() error {
+	if p.peek() != '(' {
+		return nil
+	}
+	p.next()
+	if _, err := p.parseIdentifier(); err != nil {
+		return err
+	}
+	if p.peek() != ')' {
+		return fmt.Errorf("unterminated type annotation")
+	}
+	p.next()
+	return nil
+}
+
+func (p *kdlParser) parseIdentifier() (string, error) {
+	if s, ok, err := p.parseString(); ok || err != nil {
+		return s, err
+	}
+
+	start := p.pos
+	for !p.eof() && isKDLIdentRune(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("expected identifier, found %q", p.peek())
+	}
+	return string(p.src[start:p.pos]), nil
+}
+
+func (p *kdlParser) parseEntry() (string, any, bool, error) {
+	if err := p.skipTypeAnnotation(); err != nil {
+		return "", nil, false, err
+	}
+
+	start := p.pos
+	startLine := p.line
+
+	if s, ok, err := p.parseString(); err != nil {
+		return "", nil, false, err
+	} else if ok {
+		if p.peek() == '=' {
+			p.next()
+			value, err := p.parseValue()
+			return s, value, true, err
+		}
+		return "", s, false, nil
+	}
+
+	if !kdlStartsNumber(p.peek(), p.peekAt(1)) && p.peek() != '#' {
+		for !p.eof() && isKDLIdentRune(p.peek()) {
+			p.pos++
+		}
+		if p.peek() == '=' && p.pos > start {
+			key := string(p.src[start:p.pos])
+			p.next()
+			value, err := p.parseValue()
+			return key, value, true, err
+		}
+		p.pos = start
+		p.line = startLine
+	}
+
+	value, err := p.parseValue()
+	return "", value, false, err
+}
+
+func (p *kdlParser) parseValue() (any, error) {
+	if err := p.skipTypeAnnotation(); err != nil {
+		return nil, err
+	}
+
+	if s, ok, err := p.parseString(); ok || err != nil {
+		return s, err
+	}
+
+	if kdlStartsNumber(p.peek(), p.peekAt(1)) {
+		return p.parseNumber()
+	}
+
+	start := p.pos
+	if p.peek() == '#' {
+		p.pos++
+	}
+	for !p.eof() && isKDLIdentRune(p.peek()) {
+		p.pos++
+	}
+	word := string(p.src[start:p.pos])
+
+	switch word {
+	case "true", "#true":
+		return true, nil
+	case "false", "#false":
+		return false, nil
+	case "null", "#null":
+		return nil, nil
+	case "", "#":
+		return nil, fmt.Errorf("expected value, found %q", p.peek())
+	}
+	if strings.HasPrefix(word, "#") {
+		return nil, fmt.Errorf("unknown keyword %q", word)
+	}
+	// KDL 2.0 allows bare identifiers as string values.
+	return word, nil
+}
+
+func kdlStartsNumber(r, next rune) bool {
+	if r >= '0' && r <= '9' {
+		return true
+	}
+	return (r == '+' || r == '-') && next >= '0' && next <= '9'
+}
+
+func (p *kdlParser) parseNumber() (any, error) {
+	start := p.pos
+	for !p.eof() && isKDLIdentRune(p.peek()) {
+		p.pos++
+	}
+	raw := strings.ReplaceAll(string(p.src[start:p.pos]), "_", "")
+
+	sign := ""
+	body := raw
+	if strings.HasPrefix(body, "+") || strings.HasPrefix(body, "-") {
+		sign, body = body[:1], body[1:]
+	}
+
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(body, prefix) {
+			v, err := strconv.ParseInt(sign+body[2:], base, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", raw)
+			}
+			return v, nil
+		}
+	}
+
+	if !strings.ContainsAny(body, ".eE") {
+		if v, err := strconv.ParseInt(sign+body, 10, 64); err == nil {
+			return v, nil
+		}
+	}
+
+	v, err := strconv.ParseFloat(sign+body, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", raw)
+	}
+	return v, nil
+}
+
+// parseString reads a quoted, raw (r#"..."# or #"..."#) or multi-line
+// string. ok is false when the input does not start a string.
+func (p *kdlParser) parseString() (string, bool, error) {
+	switch {
+	case p.peek() == 'r' && (p.peekAt(1) == '"' || p.peekAt(1) == '#'):
+		if p.peekAt(1) == '#' && !p.rawStringAhead(1) {
+			return "", false, nil
+		}
+		p.next()
+		s, err := p.parseRawString()
+		return s, true, err
+	case p.peek() == '#' && p.rawStringAhead(0):
+		s, err := p.parseRawString()
+		return s, true, err
+	case p.hasPrefix(`"""`):
+		s, err := p.parseMultilineString()
+		return s, true, err
+	case p.peek() == '"':
+		s, err := p.parseQuotedString()
+		return s, true, err
+	}
+	return "", false, nil
+}
+
+func (p *kdlParser) rawStringAhead(offset int) bool {
+	i := offset
+	for p.peekAt(i) == '#' {
+		i++
+	}
+	return i > offset && p.peekAt(i) == '"'
+}
+
+func (p *kdlParser) parseRawString() (string, error) {
+	hashes := 0
+	for p.peek() == '#' {
+		p.next()
+		hashes++
+	}
+	if p.peek() != '"' {
+		return "", fmt.Errorf("invalid raw string")
+	}
+	p.next()
+
+	terminator := `"` + strings.Repeat("#", hashes)
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated raw string")
+		}
+		if p.hasPrefix(terminator) {
+			p.pos += len(terminator)
+			return sb.String(), nil
+		}
+		sb.WriteRune(p.next())
+	}
+}
+
+func (p *kdlParser) parseQuotedString() (string, error) {
+	p.next()
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated string")
+		}
+		r := p.next()
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (p *kdlParser) parseEscape(sb *strings.Builder) error {
+	if p.eof() {
+		return fmt.Errorf("unterminated escape sequence")
+	}
+	r := p.next()
+	switch r {
+	case 'n':
+		sb.WriteRune('\n')
+	case 'r':
+		sb.WriteRune('\r')
+	case 't':
+		sb.WriteRune('\t')
+	case 'b':
+		sb.WriteRune('\b')
+	case 'f':
+		sb.WriteRune('\f')
+	case 's':
+		sb.WriteRune(' ')
+	case '\\', '"', '/':
+		sb.WriteRune(r)
+	case 'u':
+		if p.peek() != '{' {
+			return fmt.Errorf("invalid unicode escape")
+		}
+		p.next()
+		start := p.pos
+		for !p.eof() && p.peek() != '}' {
+			p.next()
+		}
+		if p.eof() {
+			return fmt.Errorf("unterminated unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.src[start:p.pos]), 16, 32)
+		p.next()
+		if err != nil {
+			return fmt.Errorf("invalid unicode escape: %w", err)
+		}
+		sb.WriteRune(rune(code))
+	default:
+		if unicode.IsSpace(r) {
+			for !p.eof() && unicode.IsSpace(p.peek()) {
+				p.next()
+			}
+			return nil
+		}
+		return fmt.Errorf("invalid escape sequence \\%c", r)
+	}
+	return nil
+}
+
+// parseMultilineString handles KDL 2.0 """ strings, removing the
+// indentation of the closing line from every line.
+func (p *kdlParser) parseMultilineString() (string, error) {
+	p.pos += 3
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated multi-line string")
+		}
+		if p.hasPrefix(`"""`) {
+			p.pos += 3
+			break
+		}
+		r := p.next()
+		if r == '\\' {
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) < 2 {
+		return sb.String(), nil
+	}
+	indent := lines[len(lines)-1]
+	lines = lines[1 : len(lines)-1]
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKDLValues(t *testing.T) {
+	content := `node "string" 42 -1.5 0x1F true #false null raw=r#"a\"b"# key="value" bare=ident
+`
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		t.Fatalf("ParseKDL failed: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(nodes))
+	}
+
+	node := nodes[0]
+	if node.Name != "node" {
+		t.Errorf("Name = %q, want %q", node.Name, "node")
+	}
+
+	expectedArgs := []any{"string", int64(42), -1.5, int64(31), true, false, nil}
+	if !reflect.DeepEqual(node.Args, expectedArgs) {
+		t.Errorf("Args = %#v, want %#v", node.Args, expectedArgs)
+	}
+
+	expectedProps := map[string]any{"raw": `a\"b`, "key": "value", "bare": "ident"}
+	if !reflect.DeepEqual(node.Props, expectedProps) {
+		t.Errorf("Props = %#v, want %#v", node.Props, expectedProps)
+	}
+}
+
+func TestParseKDLChildrenAndComments(t *testing.T) {
+	content := `// leading comment
+parent {
+    child1 1; child2 2
+    /* block /* nested */ comment */
+    child3 \
+        3
+}
+/-disabled { child; }
+after node /-skipped "kept"
+`
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		t.Fatalf("ParseKDL failed: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(nodes))
+	}
+
+	parent := nodes[0]
+	if !reflect.DeepEqual(parent.Comments, []string{"leading comment"}) {
+		t.Errorf("Comments = %#v", parent.Comments)
+	}
+	if len(parent.Children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(parent.Children))
+	}
+	if parent.Children[2].Name != "child3" || !reflect.DeepEqual(parent.Children[2].Args, []any{int64(3)}) {
+		t.Errorf("line continuation not handled: %#v", parent.Children[2])
+	}
+
+	after := nodes[1]
+	if after.Name != "after" || !reflect.DeepEqual(after.StringArgs(), []string{"node", "kept"}) {
+		t.Errorf("slashdash argument not skipped: %#v", after.Args)
+	}
+}
+
+func TestParseKDLCommentsResetOnBlankLine(t *testing.T) {
+	content := `// detached
+
+node
+`
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		t.Fatalf("ParseKDL failed: %v", err)
+	}
+	if len(nodes[0].Comments) != 0 {
+		t.Errorf("Comments = %#v, want none", nodes[0].Comments)
+	}
+}
+
+func TestParseKDLStrings(t *testing.T) {
+	content := "node \"tab\\there\" \"\\u{1F600}\" #\"raw \"quoted\"\"#\n"
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		t.Fatalf("ParseKDL failed: %v", err)
+	}
+
+	expected := []string{"tab\there", "\U0001F600", `raw "quoted"`}
+	if !reflect.DeepEqual(nodes[0].StringArgs(), expected) {
+		t.Errorf("Args = %#v, want %#v", nodes[0].StringArgs(), expected)
+	}
+}
+
+func TestParseKDLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unterminated_block", "node {\n  child\n"},
+		{"unterminated_string", "node \"abc\n"},
+		{"stray_brace", "}\n"},
+		{"unterminated_comment", "/* never closed\n"},
+		{"bad_escape", "node \"\\q\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseKDL(tt.content); err == nil {
+				t.Errorf("expected error for %q", tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const NiriOverlayTitleProp = "hotkey-overlay-title"
+
+var niriSectionRegex = regexp.MustCompile(`^=+\s*(.*?)\s*=+$`)
+
+type NiriKeyBinding struct {
+	Mods    []string `json:"mods"`
+	Key     string   `json:"key"`
+	Action  string   `json:"action"`
+	Args    []string `json:"args"`
+	Comment string   `json:"comment"`
+	Source  string   `json:"source"`
+}
+
+type NiriSection struct {
+	Children []NiriSection    `json:"children"`
+	Keybinds []NiriKeyBinding `json:"keybinds"`
+	Name     string           `json:"name"`
+}
+
+type niriBindEntry struct {
+	section string
+	bind    NiriKeyBinding
+	removed bool
+}
+
+type NiriParser struct {
+	visited map[string]bool
+	binds   []niriBindEntry
+	index   map[string]int
+}
+
+func NewNiriParser() *NiriParser {
+	return &NiriParser{
+		visited: make(map[string]bool),
+		index:   make(map[string]int),
+	}
+}
+
+func expandConfigPath(path string) (string, error) {
+	expanded := os.ExpandEnv(path)
+	if strings.HasPrefix(expanded, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		expanded = filepath.Join(home, expanded[1:])
+	}
+	return filepath.Clean(expanded), nil
+}
+
+func resolveNiriConfig(path string) (string, error) {
+	expanded, err := expandConfigPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return expanded, nil
+	}
+
+	mainConfig := filepath.Join(expanded, "config.kdl")
+	if fileInfo, err := os.Stat(mainConfig); err != nil || !fileInfo.Mode().IsRegular() {
+		return "", os.ErrNotExist
+	}
+	return mainConfig, nil
+}
+
+func (p *NiriParser) ParseFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if p.visited[absPath] {
+		return nil
+	}
+	p.visited[absPath] = true
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := ParseKDL(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", absPath, err)
+	}
+
+	for _, node := range nodes {
+		switch node.Name {
+		case "include":
+			if err := p.parseInclude(node, filepath.Dir(absPath)); err != nil {
+				return err
+			}
+		case "binds":
+			p.collectBinds(node, absPath)
+		}
+	}
+
+	return nil
+}
+
+func (p *NiriParser) parseInclude(node *KDLNode, baseDir string) error {
+	if len(node.Args) == 0 {
+		return nil
+	}
+	target, ok := node.Args[0].(string)
+	if !ok {
+		return nil
+	}
+
+	includePath, err := expandConfigPath(target)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(baseDir, includePath)
+	}
+
+	err = p.ParseFile(includePath)
+	if err != nil && os.IsNotExist(err) {
+		if optional, _ := node.Props["optional"].(bool); optional {
+			return nil
+		}
+	}
+	return err
+}
+
+func (p *NiriParser) collectBinds(binds *KDLNode, source string) {
+	section := ""
+	for _, node := range binds.Children {
+		for _, comment := range node.Comments {
+			if m := niriSectionRegex.FindStringSubmatch(comment); m != nil {
+				section = m[1]
+			}
+		}
+
+		kb, hidden := p.parseBind(node, source)
+		if kb == nil {
+			continue
+		}
+		p.addBind(section, *kb, hidden)
+	}
+}
+
+func (p *NiriParser) parseBind(node *KDLNode, source string) (*NiriKeyBinding, bool) {
+	if len(node.Children) == 0 {
+		return nil, false
+	}
+
+	parts := strings.Split(node.Name, "+")
+	key := parts[len(parts)-1]
+	mods := parts[:len(parts)-1]
+	if key == "" {
+		return nil, false
+	}
+
+	action := node.Children[0]
+	kb := &NiriKeyBinding{
+		Mods:   append([]string{}, mods...),
+		Key:    key,
+		Action: action.Name,
+		Args:   action.StringArgs(),
+		Source: source,
+	}
+
+	hidden := false
+	if title, ok := node.Props[NiriOverlayTitleProp]; ok {
+		switch v := title.(type) {
+		case nil:
+			hidden = true
+		case string:
+			kb.Comment = v
+		}
+	}
+	if kb.Comment == "" {
+		kb.Comment = niriAutogenerateComment(kb.Action, kb.Args)
+	}
+
+	return kb, hidden
+}
+
+// addBind records a binding. Later definitions of the same key combo
+// replace earlier ones, matching how niri merges included configs.
+func (p *NiriParser) addBind(section string, kb NiriKeyBinding, hidden bool) {
+	combo := strings.ToLower(strings.Join(append(append([]string{}, kb.Mods...), kb.Key), "+"))
+	entry := niriBindEntry{section: section, bind: kb, removed: hidden}
+
+	if idx, ok := p.index[combo]; ok {
+		p.binds[idx] = entry
+		return
+	}
+
+	p.index[combo] = len(p.binds)
+	p.binds = append(p.binds, entry)
+}
+
+func (p *NiriParser) Sections() *NiriSection {
+	root := &NiriSection{
+		Children: []NiriSection{},
+		Keybinds: []NiriKeyBinding{},
+	}
+
+	sectionIndex := make(map[string]int)
+	for _, entry := range p.binds {
+		if entry.removed {
+			continue
+		}
+
+		if entry.section == "" {
+			root.Keybinds = append(root.Keybinds, entry.bind)
+			continue
+		}
+
+		idx, ok := sectionIndex[entry.section]
+		if !ok {
+			idx = len(root.Children)
+			sectionIndex[entry.section] = idx
+			root.Children = append(root.Children, NiriSection{
+				Children: []NiriSection{},
+				Keybinds: []NiriKeyBinding{},
+				Name:     entry.section,
+			})
+		}
+		root.Children[idx].Keybinds = append(root.Children[idx].Keybinds, entry.bind)
+	}
+
+	return root
+}
+
+func niriAutogenerateComment(action string, args []string) string {
+	switch action {
+	case "spawn":
+		return strings.Join(args, " ")
+	case "spawn-sh":
+		if len(args) > 0 {
+			return args[0]
+		}
+		return action
+	}
+
+	if len(args) > 0 {
+		return action + " " + strings.Join(args, " ")
+	}
+	return action
+}
+
+func ParseNiriKeys(path string) (*NiriSection, error) {
+	configFile, err := resolveNiriConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := NewNiriParser()
+	if err := parser.ParseFile(configFile); err != nil {
+		return nil, err
+	}
+	return parser.Sections(), nil
+}
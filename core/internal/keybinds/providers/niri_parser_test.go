@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNiriAutogenerateComment(t *testing.T) {
+	tests := []struct {
+		action   string
+		args     []string
+		expected string
+	}{
+		{"spawn", []string{"dms", "ipc", "call", "spotlight", "toggle"}, "dms ipc call spotlight toggle"},
+		{"spawn-sh", []string{"grim -g \"$(slurp)\""}, "grim -g \"$(slurp)\""},
+		{"focus-workspace", []string{"3"}, "focus-workspace 3"},
+		{"close-window", nil, "close-window"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			result := niriAutogenerateComment(tt.action, tt.args)
+			if result != tt.expected {
+				t.Errorf("niriAutogenerateComment(%q, %v) = %q, want %q", tt.action, tt.args, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNiriKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.kdl")
+
+	content := `input {
+    keyboard { xkb { layout "us"; } }
+}
+
+binds {
+    Mod+Return { spawn "kitty"; }
+
+    // === Window Management ===
+    Mod+Q repeat=false { close-window; }
+    Mod+T hotkey-overlay-title="Open Terminal" { spawn "foot"; }
+    Mod+Shift+Slash hotkey-overlay-title=null { show-hotkey-overlay; }
+
+    // === Workspaces ===
+    Mod+1 { focus-workspace 1; }
+}
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	section, err := ParseNiriKeys(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseNiriKeys failed: %v", err)
+	}
+
+	if len(section.Keybinds) != 1 {
+		t.Fatalf("expected 1 unsectioned bind, got %d", len(section.Keybinds))
+	}
+	if section.Keybinds[0].Comment != "kitty" {
+		t.Errorf("Comment = %q, want %q", section.Keybinds[0].Comment, "kitty")
+	}
+
+	if len(section.Children) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(section.Children))
+	}
+
+	window := section.Children[0]
+	if window.Name != "Window Management" {
+		t.Errorf("section name = %q, want %q", window.Name, "Window Management")
+	}
+	if len(window.Keybinds) != 2 {
+		t.Fatalf("expected 2 window binds (hidden bind skipped), got %d", len(window.Keybinds))
+	}
+
+	terminal := window.Keybinds[1]
+	if !reflect.DeepEqual(terminal.Mods, []string{"Mod"}) || terminal.Key != "T" {
+		t.Errorf("key = %v+%q", terminal.Mods, terminal.Key)
+	}
+	if terminal.Comment != "Open Terminal" {
+		t.Errorf("Comment = %q, want %q", terminal.Comment, "Open Terminal")
+	}
+	if terminal.Action != "spawn" || !reflect.DeepEqual(terminal.Args, []string{"foot"}) {
+		t.Errorf("action = %q %v", terminal.Action, terminal.Args)
+	}
+
+	if section.Children[1].Keybinds[0].Comment != "focus-workspace 1" {
+		t.Errorf("Comment = %q", section.Children[1].Keybinds[0].Comment)
+	}
+}
+
+func TestParseNiriKeysInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "dms"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mainConfig := `binds {
+    Mod+Q { close-window; }
+    Mod+D { spawn "fuzzel"; }
+}
+include "dms/binds.kdl"
+include optional=true "missing.kdl"
+include "config.kdl"
+`
+	included := `binds {
+    Mod+D hotkey-overlay-title="Launcher" { spawn "dms" "ipc" "call" "spotlight" "toggle"; }
+    Mod+V { spawn "dms" "ipc" "call" "clipboard" "toggle"; }
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.kdl"), []byte(mainConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "dms", "binds.kdl"), []byte(included), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	section, err := ParseNiriKeys(filepath.Join(tmpDir, "config.kdl"))
+	if err != nil {
+		t.Fatalf("ParseNiriKeys failed: %v", err)
+	}
+
+	if len(section.Keybinds) != 3 {
+		t.Fatalf("expected 3 binds, got %d", len(section.Keybinds))
+	}
+	if section.Keybinds[1].Comment != "Launcher" {
+		t.Errorf("included bind should override earlier one, got %q", section.Keybinds[1].Comment)
+	}
+	if section.Keybinds[2].Source != filepath.Join(tmpDir, "dms", "binds.kdl") {
+		t.Errorf("Source = %q", section.Keybinds[2].Source)
+	}
+}
+
+func TestParseNiriKeysMissingInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.kdl")
+	if err := os.WriteFile(configFile, []byte("include \"nope.kdl\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseNiriKeys(configFile); err == nil {
+		t.Error("expected error for missing include")
+	}
+}
+
+func TestParseNiriKeysEmbeddedConfig(t *testing.T) {
+	section, err := ParseNiriKeys("../../config/embedded/niri.kdl")
+	if err != nil {
+		t.Fatalf("ParseNiriKeys failed on embedded config: %v", err)
+	}
+
+	if len(section.Children) == 0 {
+		t.Fatal("expected sections from embedded config")
+	}
+	if section.Children[0].Name != "System & Overview" {
+		t.Errorf("first section = %q, want %q", section.Children[0].Name, "System & Overview")
+	}
+}
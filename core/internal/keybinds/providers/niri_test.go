@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNiriProviderName(t *testing.T) {
+	provider := NewNiriProvider("")
+	if provider.Name() != "niri" {
+		t.Errorf("Name() = %q, want %q", provider.Name(), "niri")
+	}
+}
+
+func TestNiriProviderDefaultPath(t *testing.T) {
+	provider := NewNiriProvider("")
+	if provider.configPath != "$HOME/.config/niri" {
+		t.Errorf("configPath = %q, want %q", provider.configPath, "$HOME/.config/niri")
+	}
+}
+
+func TestNiriCategorizeByAction(t *testing.T) {
+	tests := []struct {
+		action   string
+		expected string
+	}{
+		{"focus-workspace", "Workspace"},
+		{"move-column-to-workspace-down", "Workspace"},
+		{"focus-monitor-left", "Monitor"},
+		{"move-column-to-monitor-right", "Monitor"},
+		{"move-workspace-to-monitor-left", "Monitor"},
+		{"close-window", "Window"},
+		{"focus-column-left", "Window"},
+		{"maximize-column", "Window"},
+		{"toggle-window-floating", "Window"},
+		{"set-column-width", "Window"},
+		{"consume-or-expel-window-left", "Window"},
+		{"screenshot-window", "Screenshot"},
+		{"toggle-overview", "Overview"},
+		{"show-hotkey-overlay", "Overview"},
+		{"spawn", "Execute"},
+		{"spawn-sh", "Execute"},
+		{"quit", "System"},
+		{"power-off-monitors", "System"},
+		{"toggle-keyboard-shortcuts-inhibit", "System"},
+		{"something-else", "Other"},
+	}
+
+	provider := NewNiriProvider("")
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			result := provider.categorizeByAction(tt.action)
+			if result != tt.expected {
+				t.Errorf("categorizeByAction(%q) = %q, want %q", tt.action, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNiriGetCheatSheet(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.kdl")
+
+	content := `binds {
+    // === Apps ===
+    Mod+T hotkey-overlay-title="Open Terminal" { spawn "kitty"; }
+    Mod+Q { close-window; }
+    Mod+1 { focus-workspace 1; }
+}
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	provider := NewNiriProvider(tmpDir)
+	sheet, err := provider.GetCheatSheet()
+	if err != nil {
+		t.Fatalf("GetCheatSheet failed: %v", err)
+	}
+
+	if sheet.Title != "Niri Keybinds" {
+		t.Errorf("Title = %q, want %q", sheet.Title, "Niri Keybinds")
+	}
+	if sheet.Provider != "niri" {
+		t.Errorf("Provider = %q, want %q", sheet.Provider, "niri")
+	}
+
+	execBinds := sheet.Binds["Execute"]
+	if len(execBinds) != 1 {
+		t.Fatalf("expected 1 Execute bind, got %d", len(execBinds))
+	}
+	if execBinds[0].Key != "Mod+T" || execBinds[0].Description != "Open Terminal" || execBinds[0].Subcategory != "Apps" {
+		t.Errorf("unexpected bind: %+v", execBinds[0])
+	}
+
+	if len(sheet.Binds["Window"]) != 1 || len(sheet.Binds["Workspace"]) != 1 {
+		t.Errorf("unexpected categories: %+v", sheet.Binds)
+	}
+}
+
+func TestNiriGetCheatSheetMissingConfig(t *testing.T) {
+	provider := NewNiriProvider("/nonexistent/niri")
+	if _, err := provider.GetCheatSheet(); err == nil {
+		t.Error("expected error for missing config")
+	}
+}
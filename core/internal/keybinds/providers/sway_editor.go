@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+)
+
+var (
+	swayIncludeRegex = regexp.MustCompile(`^\s*include\s+(.+?)\s*$`)
+	swayModeRegex    = regexp.MustCompile(`^\s*mode\s+(?:--\S+\s+)*"?([^"{]+?)"?\s*\{\s*$`)
+)
+
+type swaySyntax struct{}
+
+func (swaySyntax) mainFile() string {
+	return "config"
+}
+
+func (swaySyntax) defaultPrefix() string {
+	return "bindsym "
+}
+
+func (swaySyntax) parseInclude(line string) (string, bool) {
+	m := swayIncludeRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func (swaySyntax) parseVariable(line string) (string, string, bool) {
+	m := swayVariableRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], strings.TrimSpace(m[2]), true
+}
+
+func (swaySyntax) parseScope(line, current string) (string, bool) {
+	if m := swayModeRegex.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	if current != "" && strings.TrimSpace(line) == "}" {
+		return "", true
+	}
+	return current, false
+}
+
+func (swaySyntax) parseBind(line string) (*bindLine, bool) {
+	f, ok := splitSwayBind(line)
+	if !ok {
+		return nil, false
+	}
+
+	mods, key, err := splitKeyCombo(f.combo)
+	if err != nil {
+		return nil, false
+	}
+
+	return &bindLine{
+		head:    f.head,
+		mods:    mods,
+		key:     key,
+		action:  f.command,
+		comment: f.comment,
+	}, true
+}
+
+func (swaySyntax) formatBind(b *bindLine) string {
+	if b.head != "" {
+		return joinBindComment(b.head+b.action, b.comment)
+	}
+	combo := strings.Join(append(append([]string{}, b.mods...), b.key), "+")
+	return joinBindComment(b.prefix+combo+" "+b.action, b.comment)
+}
+
+func (s *SwayProvider) SetBind(key, action, description string) error {
+	editor, err := loadConfigEditor(s.configPath, swaySyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.SetBind(key, action, description)
+}
+
+func (s *SwayProvider) RemoveBind(key string) error {
+	editor, err := loadConfigEditor(s.configPath, swaySyntax{})
+	if err != nil {
+		return err
+	}
+	return editor.RemoveBind(key)
+}
+
+func (s *SwayProvider) CheckConflicts() ([]keybinds.Conflict, error) {
+	editor, err := loadConfigEditor(s.configPath, swaySyntax{})
+	if err != nil {
+		return nil, err
+	}
+	return editor.CheckConflicts(), nil
+}
@@ -14,6 +14,11 @@ const (
 
 var SwayModSeparators = []rune{'+', ' '}
 
+var (
+	swayBindRegex     = regexp.MustCompile(`^\s*(bindsym|bindcode)\s+(.+)$`)
+	swayVariableRegex = regexp.MustCompile(`^\s*set\s+\$(\w+)\s+(.+)$`)
+)
+
 type SwayKeyBinding struct {
 	Mods    []string `json:"mods"`
 	Key     string   `json:"key"`
@@ -89,9 +94,8 @@ func (p *SwayParser) ReadContent(path string) error {
 }
 
 func (p *SwayParser) parseVariables() {
-	setRegex := regexp.MustCompile(`^\s*set\s+\$(\w+)\s+(.+)$`)
 	for _, line := range p.contentLines {
-		matches := setRegex.FindStringSubmatch(line)
+		matches := swayVariableRegex.FindStringSubmatch(line)
 		if len(matches) == 3 {
 			varName := matches[1]
 			varValue := strings.TrimSpace(matches[2])
@@ -200,51 +204,70 @@ func swayAutogenerateComment(command string) string {
 	}
 }
 
-func (p *SwayParser) getKeybindAtLine(lineNumber int) *SwayKeyBinding {
-	if lineNumber >= len(p.contentLines) {
-		return nil
-	}
+// swayBindFields is a bindsym/bindcode line split into its raw fields. head
+// is the line up to the command, including any --flags.
+type swayBindFields struct {
+	head    string
+	combo   string
+	command string
+	comment string
+}
 
-	line := p.contentLines[lineNumber]
+func splitSwayBind(line string) (*swayBindFields, bool) {
+	m := swayBindRegex.FindStringSubmatchIndex(line)
+	if m == nil {
+		return nil, false
+	}
 
-	bindMatch := regexp.MustCompile(`^\s*(bindsym|bindcode)\s+(.+)$`)
-	matches := bindMatch.FindStringSubmatch(line)
-	if len(matches) < 3 {
-		return nil
+	f := &swayBindFields{}
+	content := line[m[4]:]
+	if i := strings.Index(content, "#"); i >= 0 {
+		f.comment = strings.TrimSpace(content[i+1:])
+		content = content[:i]
 	}
 
-	content := matches[2]
+	rest := content
+	for strings.HasPrefix(rest, "--") {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			return nil, false
+		}
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
 
-	parts := strings.SplitN(content, "#", 2)
-	keys := strings.TrimSpace(parts[0])
+	end := strings.IndexAny(rest, " \t")
+	if end < 0 {
+		return nil, false
+	}
+	f.combo = rest[:end]
+	rest = strings.TrimLeft(rest[end:], " \t")
 
-	var comment string
-	if len(parts) > 1 {
-		comment = strings.TrimSpace(parts[1])
+	f.command = strings.TrimSpace(rest)
+	if f.command == "" {
+		return nil, false
 	}
+	f.head = line[:m[4]+len(content)-len(rest)]
 
-	if strings.HasPrefix(comment, SwayHideComment) {
+	return f, true
+}
+
+func (p *SwayParser) getKeybindAtLine(lineNumber int) *SwayKeyBinding {
+	if lineNumber >= len(p.contentLines) {
 		return nil
 	}
 
-	flags := ""
-	if strings.HasPrefix(keys, "--") {
-		spaceIdx := strings.Index(keys, " ")
-		if spaceIdx > 0 {
-			flags = keys[:spaceIdx]
-			keys = strings.TrimSpace(keys[spaceIdx+1:])
-		}
+	fields, ok := splitSwayBind(p.contentLines[lineNumber])
+	if !ok {
+		return nil
 	}
 
-	keyParts := strings.Fields(keys)
-	if len(keyParts) < 2 {
+	comment := fields.comment
+	if strings.HasPrefix(comment, SwayHideComment) {
 		return nil
 	}
 
-	keyCombo := keyParts[0]
-	keyCombo = p.expandVariables(keyCombo)
-	command := strings.Join(keyParts[1:], " ")
-	command = p.expandVariables(command)
+	keyCombo := p.expandVariables(fields.combo)
+	command := p.expandVariables(strings.Join(strings.Fields(fields.command), " "))
 
 	var modList []string
 	var key string
@@ -276,8 +299,6 @@ func (p *SwayParser) getKeybindAtLine(lineNumber int) *SwayKeyBinding {
 		comment = swayAutogenerateComment(command)
 	}
 
-	_ = flags
-
 	return &SwayKeyBinding{
 		Mods:    modList,
 		Key:     key,
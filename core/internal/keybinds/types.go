@@ -16,3 +16,27 @@ type Provider interface {
 	Name() string
 	GetCheatSheet() (*CheatSheet, error)
 }
+
+type BindLocation struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Key    string `json:"key"`
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+type Conflict struct {
+	Key      string         `json:"key"`
+	Scope    string         `json:"scope,omitempty"`
+	Bindings []BindLocation `json:"bindings"`
+}
+
+// WritableProvider is implemented by providers that can edit the
+// compositor config in place. Actions use the compositor's own syntax for
+// everything after the key combo.
+type WritableProvider interface {
+	Provider
+	SetBind(key, action, description string) error
+	RemoveBind(key string) error
+	CheckConflicts() ([]Conflict, error)
+}
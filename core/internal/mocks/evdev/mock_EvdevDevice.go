@@ -20,6 +20,54 @@ func (_m *MockEvdevDevice) EXPECT() *MockEvdevDevice_Expecter {
 	return &MockEvdevDevice_Expecter{mock: &_m.Mock}
 }
 
+// CapableEvents provides a mock function with given fields: t
+func (_m *MockEvdevDevice) CapableEvents(t go_evdev.EvType) []go_evdev.EvCode {
+	ret := _m.Called(t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CapableEvents")
+	}
+
+	var r0 []go_evdev.EvCode
+	if rf, ok := ret.Get(0).(func(go_evdev.EvType) []go_evdev.EvCode); ok {
+		r0 = rf(t)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]go_evdev.EvCode)
+		}
+	}
+
+	return r0
+}
+
+// MockEvdevDevice_CapableEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CapableEvents'
+type MockEvdevDevice_CapableEvents_Call struct {
+	*mock.Call
+}
+
+// CapableEvents is a helper method to define mock.On call
+//   - t go_evdev.EvType
+func (_e *MockEvdevDevice_Expecter) CapableEvents(t interface{}) *MockEvdevDevice_CapableEvents_Call {
+	return &MockEvdevDevice_CapableEvents_Call{Call: _e.mock.On("CapableEvents", t)}
+}
+
+func (_c *MockEvdevDevice_CapableEvents_Call) Run(run func(t go_evdev.EvType)) *MockEvdevDevice_CapableEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(go_evdev.EvType))
+	})
+	return _c
+}
+
+func (_c *MockEvdevDevice_CapableEvents_Call) Return(_a0 []go_evdev.EvCode) *MockEvdevDevice_CapableEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEvdevDevice_CapableEvents_Call) RunAndReturn(run func(go_evdev.EvType) []go_evdev.EvCode) *MockEvdevDevice_CapableEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function with no fields
 func (_m *MockEvdevDevice) Close() error {
 	ret := _m.Called()
@@ -112,6 +112,63 @@ func (_c *MockBackend_CancelCredentials_Call) RunAndReturn(run func(string) erro
 	return _c
 }
 
+// CheckConnectivity provides a mock function with no fields
+func (_m *MockBackend) CheckConnectivity() (network.Connectivity, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckConnectivity")
+	}
+
+	var r0 network.Connectivity
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (network.Connectivity, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() network.Connectivity); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(network.Connectivity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackend_CheckConnectivity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckConnectivity'
+type MockBackend_CheckConnectivity_Call struct {
+	*mock.Call
+}
+
+// CheckConnectivity is a helper method to define mock.On call
+func (_e *MockBackend_Expecter) CheckConnectivity() *MockBackend_CheckConnectivity_Call {
+	return &MockBackend_CheckConnectivity_Call{Call: _e.mock.On("CheckConnectivity")}
+}
+
+func (_c *MockBackend_CheckConnectivity_Call) Run(run func()) *MockBackend_CheckConnectivity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockBackend_CheckConnectivity_Call) Return(_a0 network.Connectivity, _a1 error) *MockBackend_CheckConnectivity_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackend_CheckConnectivity_Call) RunAndReturn(run func() (network.Connectivity, error)) *MockBackend_CheckConnectivity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ClearVPNCredentials provides a mock function with given fields: uuidOrName
 func (_m *MockBackend) ClearVPNCredentials(uuidOrName string) error {
 	ret := _m.Called(uuidOrName)
@@ -555,6 +612,64 @@ func (_c *MockBackend_ForgetWiFiNetwork_Call) RunAndReturn(run func(string) erro
 	return _c
 }
 
+// GetConnectionSettings provides a mock function with given fields: uuid
+func (_m *MockBackend) GetConnectionSettings(uuid string) (*network.ConnectionSettings, error) {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConnectionSettings")
+	}
+
+	var r0 *network.ConnectionSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*network.ConnectionSettings, error)); ok {
+		return rf(uuid)
+	}
+	if rf, ok := ret.Get(0).(func(string) *network.ConnectionSettings); ok {
+		r0 = rf(uuid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*network.ConnectionSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uuid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackend_GetConnectionSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetConnectionSettings'
+type MockBackend_GetConnectionSettings_Call struct {
+	*mock.Call
+}
+
+// GetConnectionSettings is a helper method to define mock.On call
+//   - uuid string
+func (_e *MockBackend_Expecter) GetConnectionSettings(uuid interface{}) *MockBackend_GetConnectionSettings_Call {
+	return &MockBackend_GetConnectionSettings_Call{Call: _e.mock.On("GetConnectionSettings", uuid)}
+}
+
+func (_c *MockBackend_GetConnectionSettings_Call) Run(run func(uuid string)) *MockBackend_GetConnectionSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockBackend_GetConnectionSettings_Call) Return(_a0 *network.ConnectionSettings, _a1 error) *MockBackend_GetConnectionSettings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackend_GetConnectionSettings_Call) RunAndReturn(run func(string) (*network.ConnectionSettings, error)) *MockBackend_GetConnectionSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCurrentState provides a mock function with no fields
 func (_m *MockBackend) GetCurrentState() (*network.BackendState, error) {
 	ret := _m.Called()
@@ -612,6 +727,63 @@ func (_c *MockBackend_GetCurrentState_Call) RunAndReturn(run func() (*network.Ba
 	return _c
 }
 
+// GetHotspotState provides a mock function with no fields
+func (_m *MockBackend) GetHotspotState() (*network.HotspotState, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHotspotState")
+	}
+
+	var r0 *network.HotspotState
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*network.HotspotState, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *network.HotspotState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*network.HotspotState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackend_GetHotspotState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHotspotState'
+type MockBackend_GetHotspotState_Call struct {
+	*mock.Call
+}
+
+// GetHotspotState is a helper method to define mock.On call
+func (_e *MockBackend_Expecter) GetHotspotState() *MockBackend_GetHotspotState_Call {
+	return &MockBackend_GetHotspotState_Call{Call: _e.mock.On("GetHotspotState")}
+}
+
+func (_c *MockBackend_GetHotspotState_Call) Run(run func()) *MockBackend_GetHotspotState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockBackend_GetHotspotState_Call) Return(_a0 *network.HotspotState, _a1 error) *MockBackend_GetHotspotState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackend_GetHotspotState_Call) RunAndReturn(run func() (*network.HotspotState, error)) *MockBackend_GetHotspotState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPromptBroker provides a mock function with no fields
 func (_m *MockBackend) GetPromptBroker() network.PromptBroker {
 	ret := _m.Called()
@@ -887,6 +1059,64 @@ func (_c *MockBackend_GetWiredNetworkDetails_Call) RunAndReturn(run func(string)
 	return _c
 }
 
+// ImportVPN provides a mock function with given fields: imp
+func (_m *MockBackend) ImportVPN(imp *network.VPNImport) (*network.VPNProfile, error) {
+	ret := _m.Called(imp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportVPN")
+	}
+
+	var r0 *network.VPNProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*network.VPNImport) (*network.VPNProfile, error)); ok {
+		return rf(imp)
+	}
+	if rf, ok := ret.Get(0).(func(*network.VPNImport) *network.VPNProfile); ok {
+		r0 = rf(imp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*network.VPNProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*network.VPNImport) error); ok {
+		r1 = rf(imp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBackend_ImportVPN_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportVPN'
+type MockBackend_ImportVPN_Call struct {
+	*mock.Call
+}
+
+// ImportVPN is a helper method to define mock.On call
+//   - imp *network.VPNImport
+func (_e *MockBackend_Expecter) ImportVPN(imp interface{}) *MockBackend_ImportVPN_Call {
+	return &MockBackend_ImportVPN_Call{Call: _e.mock.On("ImportVPN", imp)}
+}
+
+func (_c *MockBackend_ImportVPN_Call) Run(run func(imp *network.VPNImport)) *MockBackend_ImportVPN_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*network.VPNImport))
+	})
+	return _c
+}
+
+func (_c *MockBackend_ImportVPN_Call) Return(_a0 *network.VPNProfile, _a1 error) *MockBackend_ImportVPN_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBackend_ImportVPN_Call) RunAndReturn(run func(*network.VPNImport) (*network.VPNProfile, error)) *MockBackend_ImportVPN_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Initialize provides a mock function with no fields
 func (_m *MockBackend) Initialize() error {
 	ret := _m.Called()
@@ -1230,6 +1460,55 @@ func (_c *MockBackend_SetWiFiEnabled_Call) RunAndReturn(run func(bool) error) *M
 	return _c
 }
 
+// StartHotspot provides a mock function with given fields: ssid, password, band, channel
+func (_m *MockBackend) StartHotspot(ssid string, password string, band string, channel uint32) error {
+	ret := _m.Called(ssid, password, band, channel)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartHotspot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, uint32) error); ok {
+		r0 = rf(ssid, password, band, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackend_StartHotspot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartHotspot'
+type MockBackend_StartHotspot_Call struct {
+	*mock.Call
+}
+
+// StartHotspot is a helper method to define mock.On call
+//   - ssid string
+//   - password string
+//   - band string
+//   - channel uint32
+func (_e *MockBackend_Expecter) StartHotspot(ssid interface{}, password interface{}, band interface{}, channel interface{}) *MockBackend_StartHotspot_Call {
+	return &MockBackend_StartHotspot_Call{Call: _e.mock.On("StartHotspot", ssid, password, band, channel)}
+}
+
+func (_c *MockBackend_StartHotspot_Call) Run(run func(ssid string, password string, band string, channel uint32)) *MockBackend_StartHotspot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(uint32))
+	})
+	return _c
+}
+
+func (_c *MockBackend_StartHotspot_Call) Return(_a0 error) *MockBackend_StartHotspot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackend_StartHotspot_Call) RunAndReturn(run func(string, string, string, uint32) error) *MockBackend_StartHotspot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartMonitoring provides a mock function with given fields: onStateChange
 func (_m *MockBackend) StartMonitoring(onStateChange func()) error {
 	ret := _m.Called(onStateChange)
@@ -1276,6 +1555,51 @@ func (_c *MockBackend_StartMonitoring_Call) RunAndReturn(run func(func()) error)
 	return _c
 }
 
+// StopHotspot provides a mock function with no fields
+func (_m *MockBackend) StopHotspot() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopHotspot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackend_StopHotspot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StopHotspot'
+type MockBackend_StopHotspot_Call struct {
+	*mock.Call
+}
+
+// StopHotspot is a helper method to define mock.On call
+func (_e *MockBackend_Expecter) StopHotspot() *MockBackend_StopHotspot_Call {
+	return &MockBackend_StopHotspot_Call{Call: _e.mock.On("StopHotspot")}
+}
+
+func (_c *MockBackend_StopHotspot_Call) Run(run func()) *MockBackend_StopHotspot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockBackend_StopHotspot_Call) Return(_a0 error) *MockBackend_StopHotspot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackend_StopHotspot_Call) RunAndReturn(run func() error) *MockBackend_StopHotspot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StopMonitoring provides a mock function with no fields
 func (_m *MockBackend) StopMonitoring() {
 	_m.Called()
@@ -1356,6 +1680,53 @@ func (_c *MockBackend_SubmitCredentials_Call) RunAndReturn(run func(string, map[
 	return _c
 }
 
+// UpdateConnectionSettings provides a mock function with given fields: uuid, settings
+func (_m *MockBackend) UpdateConnectionSettings(uuid string, settings network.ConnectionSettings) error {
+	ret := _m.Called(uuid, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConnectionSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, network.ConnectionSettings) error); ok {
+		r0 = rf(uuid, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBackend_UpdateConnectionSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateConnectionSettings'
+type MockBackend_UpdateConnectionSettings_Call struct {
+	*mock.Call
+}
+
+// UpdateConnectionSettings is a helper method to define mock.On call
+//   - uuid string
+//   - settings network.ConnectionSettings
+func (_e *MockBackend_Expecter) UpdateConnectionSettings(uuid interface{}, settings interface{}) *MockBackend_UpdateConnectionSettings_Call {
+	return &MockBackend_UpdateConnectionSettings_Call{Call: _e.mock.On("UpdateConnectionSettings", uuid, settings)}
+}
+
+func (_c *MockBackend_UpdateConnectionSettings_Call) Run(run func(uuid string, settings network.ConnectionSettings)) *MockBackend_UpdateConnectionSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(network.ConnectionSettings))
+	})
+	return _c
+}
+
+func (_c *MockBackend_UpdateConnectionSettings_Call) Return(_a0 error) *MockBackend_UpdateConnectionSettings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackend_UpdateConnectionSettings_Call) RunAndReturn(run func(string, network.ConnectionSettings) error) *MockBackend_UpdateConnectionSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockBackend creates a new instance of MockBackend. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockBackend(t interface {
@@ -0,0 +1,422 @@
+package audio
+
+import (
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type fakePulseServer struct {
+	listener      net.Listener
+	path          string
+	mu            sync.Mutex
+	conns         map[net.Conn]bool
+	serverVersion uint32
+	defaultSink   string
+	defaultSource string
+	sinks         []deviceInfo
+	sources       []deviceInfo
+	sinkInputs    []streamInfo
+	sourceOutputs []streamInfo
+}
+
+func newFakePulseServer(t *testing.T) *fakePulseServer {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "native")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &fakePulseServer{
+		listener:      listener,
+		path:          path,
+		conns:         make(map[net.Conn]bool),
+		serverVersion: 35,
+		defaultSink:   "alsa_output.speakers",
+		defaultSource: "alsa_input.mic",
+		sinks: []deviceInfo{
+			{
+				index:       1,
+				name:        "alsa_output.speakers",
+				description: "Built-in Speakers",
+				volume:      []uint32{volumeNorm / 2, volumeNorm / 2},
+				monitorOf:   invalidIndex,
+				properties:  map[string]string{"device.form_factor": "internal", "device.bus": "pci"},
+				ports: []devicePort{
+					{name: "analog-output-speaker", description: "Speakers", priority: 100, available: 0},
+					{name: "analog-output-headphones", description: "Headphones", priority: 200, available: 1},
+				},
+				activePort: "analog-output-speaker",
+			},
+			{
+				index:       2,
+				name:        "bluez_output.headset",
+				description: "Headset",
+				volume:      []uint32{volumeNorm, volumeNorm},
+				state:       1,
+				monitorOf:   invalidIndex,
+				properties:  map[string]string{"device.bus": "bluetooth"},
+			},
+		},
+		sources: []deviceInfo{
+			{
+				index:       3,
+				name:        "alsa_output.speakers.monitor",
+				description: "Monitor of Built-in Speakers",
+				volume:      []uint32{volumeNorm, volumeNorm},
+				state:       2,
+				monitorOf:   1,
+				monitorName: "alsa_output.speakers",
+			},
+			{
+				index:       4,
+				name:        "alsa_input.mic",
+				description: "Built-in Microphone",
+				volume:      []uint32{volumeNorm * 3 / 4},
+				monitorOf:   invalidIndex,
+			},
+		},
+		sinkInputs: []streamInfo{
+			{
+				index:      10,
+				name:       "playback",
+				device:     1,
+				volume:     []uint32{volumeNorm, volumeNorm},
+				hasVolume:  true,
+				properties: map[string]string{"application.name": "Firefox", "media.name": "Video", "application.process.binary": "firefox"},
+			},
+		},
+		sourceOutputs: []streamInfo{
+			{
+				index:      20,
+				name:       "record",
+				device:     4,
+				volume:     []uint32{volumeNorm},
+				hasVolume:  true,
+				properties: map[string]string{"application.name": "OBS"},
+			},
+		},
+	}
+
+	go s.serve()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakePulseServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = false
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakePulseServer) Close() {
+	s.listener.Close()
+	s.dropClients()
+}
+
+func (s *fakePulseServer) dropClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+func (s *fakePulseServer) send(conn net.Conn, w *tagWriter) {
+	frame := make([]byte, frameHeaderSize)
+	binary.BigEndian.PutUint32(frame[0:], uint32(len(w.buf)))
+	binary.BigEndian.PutUint32(frame[4:], controlChannel)
+	conn.Write(append(frame, w.buf...))
+}
+
+func (s *fakePulseServer) reply(conn net.Conn, tag uint32, build func(w *tagWriter)) {
+	w := &tagWriter{}
+	w.putU32(cmdReply)
+	w.putU32(tag)
+	if build != nil {
+		build(w)
+	}
+	s.send(conn, w)
+}
+
+func (s *fakePulseServer) replyError(conn net.Conn, tag uint32, code uint32) {
+	w := &tagWriter{}
+	w.putU32(cmdError)
+	w.putU32(tag)
+	w.putU32(code)
+	s.send(conn, w)
+}
+
+func (s *fakePulseServer) broadcastEvent(facility uint32, index uint32) {
+	s.mu.Lock()
+	var targets []net.Conn
+	for conn, subscribed := range s.conns {
+		if subscribed {
+			targets = append(targets, conn)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, conn := range targets {
+		w := &tagWriter{}
+		w.putU32(cmdSubscribeEvent)
+		w.putU32(0xffffffff)
+		w.putU32(facility | 0x10)
+		w.putU32(index)
+		s.send(conn, w)
+	}
+}
+
+func (s *fakePulseServer) handle(conn net.Conn) {
+	for {
+		_, payload, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+
+		r := newTagReader(payload)
+		command := r.getU32()
+		tag := r.getU32()
+
+		if code := s.dispatch(conn, command, tag, r); code != 0 {
+			s.replyError(conn, tag, code)
+		}
+	}
+}
+
+func (s *fakePulseServer) dispatch(conn net.Conn, command, tag uint32, r *tagReader) uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch command {
+	case cmdAuth:
+		s.reply(conn, tag, func(w *tagWriter) { w.putU32(s.serverVersion) })
+	case cmdSetClientName:
+		r.getPropList()
+		s.reply(conn, tag, func(w *tagWriter) { w.putU32(7) })
+	case cmdSubscribe:
+		s.conns[conn] = true
+		s.reply(conn, tag, nil)
+	case cmdGetServerInfo:
+		s.reply(conn, tag, func(w *tagWriter) {
+			w.putString("user")
+			w.putString("host")
+			w.putString("15.0.0")
+			w.putString("PulseAudio (on PipeWire 1.2.0)")
+			w.putSampleSpec(sampleSpec{format: 3, channels: 2, rate: 48000})
+			w.putString(s.defaultSink)
+			w.putString(s.defaultSource)
+			w.putU32(42)
+			w.putChannelMap([]uint8{1, 2})
+		})
+	case cmdGetSinkInfoList:
+		s.reply(conn, tag, func(w *tagWriter) {
+			for _, d := range s.sinks {
+				writeDevice(w, d)
+			}
+		})
+	case cmdGetSourceInfoList:
+		s.reply(conn, tag, func(w *tagWriter) {
+			for _, d := range s.sources {
+				writeDevice(w, d)
+			}
+		})
+	case cmdGetSinkInputInfoList:
+		s.reply(conn, tag, func(w *tagWriter) {
+			for _, st := range s.sinkInputs {
+				writeSinkInput(w, st)
+			}
+		})
+	case cmdGetSourceOutputInfoList:
+		s.reply(conn, tag, func(w *tagWriter) {
+			for _, st := range s.sourceOutputs {
+				writeSourceOutput(w, st)
+			}
+		})
+	case cmdSetSinkVolume, cmdSetSourceVolume, cmdSetSinkMute, cmdSetSourceMute:
+		r.getU32()
+		name := r.getString()
+		devices := s.sinks
+		facility := uint32(0)
+		if command == cmdSetSourceVolume || command == cmdSetSourceMute {
+			devices = s.sources
+			facility = 1
+		}
+		d := findFakeDevice(devices, name)
+		if d == nil {
+			return 5
+		}
+		if command == cmdSetSinkVolume || command == cmdSetSourceVolume {
+			d.volume = r.getCVolume()
+		} else {
+			d.mute = r.getBool()
+		}
+		s.reply(conn, tag, nil)
+		go s.broadcastEvent(facility, d.index)
+	case cmdSetSinkInputVolume, cmdSetSourceOutputVolume, cmdSetSinkInputMute, cmdSetSourceOutputMute:
+		index := r.getU32()
+		streams := s.sinkInputs
+		if command == cmdSetSourceOutputVolume || command == cmdSetSourceOutputMute {
+			streams = s.sourceOutputs
+		}
+		st := findFakeStream(streams, index)
+		if st == nil {
+			return 5
+		}
+		if command == cmdSetSinkInputVolume || command == cmdSetSourceOutputVolume {
+			st.volume = r.getCVolume()
+		} else {
+			st.mute = r.getBool()
+		}
+		s.reply(conn, tag, nil)
+		go s.broadcastEvent(2, index)
+	case cmdSetDefaultSink, cmdSetDefaultSource:
+		name := r.getString()
+		if command == cmdSetDefaultSink {
+			if findFakeDevice(s.sinks, name) == nil {
+				return 5
+			}
+			s.defaultSink = name
+		} else {
+			if findFakeDevice(s.sources, name) == nil {
+				return 5
+			}
+			s.defaultSource = name
+		}
+		s.reply(conn, tag, nil)
+		go s.broadcastEvent(7, 0)
+	case cmdMoveSinkInput, cmdMoveSourceOutput:
+		index := r.getU32()
+		r.getU32()
+		name := r.getString()
+		streams, devices := s.sinkInputs, s.sinks
+		if command == cmdMoveSourceOutput {
+			streams, devices = s.sourceOutputs, s.sources
+		}
+		st := findFakeStream(streams, index)
+		d := findFakeDevice(devices, name)
+		if st == nil || d == nil {
+			return 5
+		}
+		st.device = d.index
+		s.reply(conn, tag, nil)
+		go s.broadcastEvent(2, index)
+	default:
+		return 2
+	}
+
+	return 0
+}
+
+func findFakeDevice(devices []deviceInfo, name string) *deviceInfo {
+	for i := range devices {
+		if devices[i].name == name {
+			return &devices[i]
+		}
+	}
+	return nil
+}
+
+func findFakeStream(streams []streamInfo, index uint32) *streamInfo {
+	for i := range streams {
+		if streams[i].index == index {
+			return &streams[i]
+		}
+	}
+	return nil
+}
+
+func writeDevice(w *tagWriter, d deviceInfo) {
+	w.putU32(d.index)
+	w.putString(d.name)
+	w.putString(d.description)
+	w.putSampleSpec(sampleSpec{format: 3, channels: uint8(len(d.volume)), rate: 48000})
+	w.putChannelMap(make([]uint8, len(d.volume)))
+	w.putU32(invalidIndex)
+	w.putCVolume(d.volume)
+	w.putBool(d.mute)
+	w.putU32(d.monitorOf)
+	if d.monitorName == "" {
+		w.putNullString()
+	} else {
+		w.putString(d.monitorName)
+	}
+	w.putUsec(0)
+	w.putString("PipeWire")
+	w.putU32(0)
+	w.putPropList(d.properties)
+	w.putUsec(0)
+	w.putVolume(volumeNorm)
+	w.putU32(d.state)
+	w.putU32(65537)
+	w.putU32(invalidIndex)
+	w.putU32(uint32(len(d.ports)))
+	for _, p := range d.ports {
+		w.putString(p.name)
+		w.putString(p.description)
+		w.putU32(p.priority)
+		w.putU32(p.available)
+	}
+	if d.activePort == "" {
+		w.putNullString()
+	} else {
+		w.putString(d.activePort)
+	}
+	w.putU8(1)
+	w.putFormatInfo(1, nil)
+}
+
+func writeSinkInput(w *tagWriter, s streamInfo) {
+	w.putU32(s.index)
+	w.putString(s.name)
+	w.putU32(invalidIndex)
+	w.putU32(5)
+	w.putU32(s.device)
+	w.putSampleSpec(sampleSpec{format: 3, channels: uint8(len(s.volume)), rate: 48000})
+	w.putChannelMap(make([]uint8, len(s.volume)))
+	w.putCVolume(s.volume)
+	w.putUsec(0)
+	w.putUsec(0)
+	w.putString("speex-float-1")
+	w.putString("PipeWire")
+	w.putBool(s.mute)
+	w.putPropList(s.properties)
+	w.putBool(s.corked)
+	w.putBool(s.hasVolume)
+	w.putBool(true)
+	w.putFormatInfo(1, map[string]string{"format.rate": "48000"})
+}
+
+func writeSourceOutput(w *tagWriter, s streamInfo) {
+	w.putU32(s.index)
+	w.putString(s.name)
+	w.putU32(invalidIndex)
+	w.putU32(6)
+	w.putU32(s.device)
+	w.putSampleSpec(sampleSpec{format: 3, channels: uint8(len(s.volume)), rate: 48000})
+	w.putChannelMap(make([]uint8, len(s.volume)))
+	w.putUsec(0)
+	w.putUsec(0)
+	w.putString("speex-float-1")
+	w.putString("PipeWire")
+	w.putPropList(s.properties)
+	w.putBool(s.corked)
+	w.putCVolume(s.volume)
+	w.putBool(s.mute)
+	w.putBool(s.hasVolume)
+	w.putBool(true)
+	w.putFormatInfo(1, nil)
+}
@@ -0,0 +1,198 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type Request struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req Request, manager *Manager) {
+	switch req.Method {
+	case "audio.getState":
+		handleGetState(conn, req, manager)
+	case "audio.setSinkVolume":
+		handleSetDeviceVolume(conn, req, "sink", manager.SetSinkVolume)
+	case "audio.setSourceVolume":
+		handleSetDeviceVolume(conn, req, "source", manager.SetSourceVolume)
+	case "audio.setSinkMute":
+		handleSetDeviceMute(conn, req, "sink", manager.SetSinkMute)
+	case "audio.setSourceMute":
+		handleSetDeviceMute(conn, req, "source", manager.SetSourceMute)
+	case "audio.setSinkInputVolume":
+		handleSetStreamVolume(conn, req, manager.SetSinkInputVolume)
+	case "audio.setSourceOutputVolume":
+		handleSetStreamVolume(conn, req, manager.SetSourceOutputVolume)
+	case "audio.setSinkInputMute":
+		handleSetStreamMute(conn, req, manager.SetSinkInputMute)
+	case "audio.setSourceOutputMute":
+		handleSetStreamMute(conn, req, manager.SetSourceOutputMute)
+	case "audio.setDefaultSink":
+		handleSetDefault(conn, req, "sink", manager.SetDefaultSink)
+	case "audio.setDefaultSource":
+		handleSetDefault(conn, req, "source", manager.SetDefaultSource)
+	case "audio.moveSinkInput":
+		handleMoveStream(conn, req, "sink", manager.MoveSinkInput)
+	case "audio.moveSourceOutput":
+		handleMoveStream(conn, req, "source", manager.MoveSourceOutput)
+	case "audio.subscribe":
+		handleSubscribe(conn, req, manager)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func handleGetState(conn net.Conn, req Request, manager *Manager) {
+	models.Respond(conn, req.ID, manager.GetState())
+}
+
+func parseVolume(req Request) (int, bool) {
+	volume, ok := req.Params["volume"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(volume), true
+}
+
+func parseIndex(req Request) (uint32, bool) {
+	index, ok := req.Params["index"].(float64)
+	if !ok || index < 0 {
+		return 0, false
+	}
+	return uint32(index), true
+}
+
+func parseMute(req Request) *bool {
+	mute, ok := req.Params["mute"].(bool)
+	if !ok {
+		return nil
+	}
+	return &mute
+}
+
+func handleSetDeviceVolume(conn net.Conn, req Request, param string, set func(string, int) error) {
+	volume, ok := parseVolume(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'volume' parameter"))
+		return
+	}
+
+	name, _ := req.Params[param].(string)
+	if err := set(name, volume); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "volume set"})
+}
+
+func handleSetDeviceMute(conn net.Conn, req Request, param string, set func(string, *bool) error) {
+	name, _ := req.Params[param].(string)
+	if err := set(name, parseMute(req)); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "mute set"})
+}
+
+func handleSetStreamVolume(conn net.Conn, req Request, set func(uint32, int) error) {
+	index, ok := parseIndex(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'index' parameter"))
+		return
+	}
+
+	volume, ok := parseVolume(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'volume' parameter"))
+		return
+	}
+
+	if err := set(index, volume); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "volume set"})
+}
+
+func handleSetStreamMute(conn net.Conn, req Request, set func(uint32, *bool) error) {
+	index, ok := parseIndex(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'index' parameter"))
+		return
+	}
+
+	if err := set(index, parseMute(req)); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "mute set"})
+}
+
+func handleSetDefault(conn net.Conn, req Request, param string, set func(string) error) {
+	name, ok := req.Params[param].(string)
+	if !ok || name == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid '%s' parameter", param))
+		return
+	}
+
+	if err := set(name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "default " + param + " set"})
+}
+
+func handleMoveStream(conn net.Conn, req Request, param string, move func(uint32, string) error) {
+	index, ok := parseIndex(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'index' parameter"))
+		return
+	}
+
+	name, ok := req.Params[param].(string)
+	if !ok || name == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid '%s' parameter", param))
+		return
+	}
+
+	if err := move(index, name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "stream moved"})
+}
+
+func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
+	clientID := fmt.Sprintf("client-%p", conn)
+	stateChan := manager.Subscribe(clientID)
+	defer manager.Unsubscribe(clientID)
+
+	initialState := manager.GetState()
+	if err := json.NewEncoder(conn).Encode(models.Response[State]{
+		ID:     req.ID,
+		Result: &initialState,
+	}); err != nil {
+		return
+	}
+
+	for state := range stateChan {
+		if err := json.NewEncoder(conn).Encode(models.Response[State]{
+			ID:     req.ID,
+			Result: &state,
+		}); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNetConn struct {
+	net.Conn
+	writeBuf *bytes.Buffer
+}
+
+func newMockNetConn() *mockNetConn {
+	return &mockNetConn{writeBuf: &bytes.Buffer{}}
+}
+
+func (m *mockNetConn) Write(b []byte) (n int, err error) {
+	return m.writeBuf.Write(b)
+}
+
+func (m *mockNetConn) Close() error {
+	return nil
+}
+
+func TestHandleGetState(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	conn := newMockNetConn()
+	HandleRequest(conn, Request{ID: 1, Method: "audio.getState"}, m)
+
+	var resp models.Response[State]
+	require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+	assert.EqualValues(t, 1, resp.ID)
+	require.NotNil(t, resp.Result)
+	assert.True(t, resp.Result.Available)
+	assert.Len(t, resp.Result.Sinks, 2)
+}
+
+func TestHandleSetSinkVolume(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	t.Run("missing volume", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 2, Method: "audio.setSinkVolume", Params: map[string]interface{}{}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Contains(t, resp.Error, "volume")
+	})
+
+	t.Run("default sink", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 3, Method: "audio.setSinkVolume", Params: map[string]interface{}{"volume": float64(90)}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Empty(t, resp.Error)
+		require.NotNil(t, resp.Result)
+		assert.True(t, resp.Result.Success)
+
+		waitForState(t, m, func(s State) bool { return s.Sinks[0].Volume == 90 })
+	})
+}
+
+func TestHandleMoveSinkInput(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	t.Run("missing sink", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 4, Method: "audio.moveSinkInput", Params: map[string]interface{}{"index": float64(10)}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Equal(t, "missing or invalid 'sink' parameter", resp.Error)
+	})
+
+	t.Run("moves stream", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 5, Method: "audio.moveSinkInput", Params: map[string]interface{}{
+			"index": float64(10),
+			"sink":  "bluez_output.headset",
+		}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Empty(t, resp.Error)
+
+		waitForState(t, m, func(s State) bool { return s.SinkInputs[0].Device == "bluez_output.headset" })
+	})
+}
+
+func TestHandleUnknownMethod(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	conn := newMockNetConn()
+	HandleRequest(conn, Request{ID: 6, Method: "audio.nope"}, m)
+
+	var resp models.Response[any]
+	require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+	assert.Equal(t, "unknown method: audio.nope", resp.Error)
+}
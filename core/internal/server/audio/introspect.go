@@ -0,0 +1,256 @@
+package audio
+
+import "fmt"
+
+type serverInfo struct {
+	serverVersion string
+	serverName    string
+	defaultSink   string
+	defaultSource string
+}
+
+type devicePort struct {
+	name        string
+	description string
+	priority    uint32
+	available   uint32
+}
+
+type deviceInfo struct {
+	index       uint32
+	name        string
+	description string
+	volume      []uint32
+	mute        bool
+	monitorOf   uint32
+	monitorName string
+	properties  map[string]string
+	state       uint32
+	ports       []devicePort
+	activePort  string
+}
+
+type streamInfo struct {
+	index      uint32
+	name       string
+	device     uint32
+	volume     []uint32
+	mute       bool
+	corked     bool
+	hasVolume  bool
+	properties map[string]string
+}
+
+func (c *pulseClient) getServerInfo() (*serverInfo, error) {
+	r, err := c.request(cmdGetServerInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.getString()
+	r.getString()
+	info := &serverInfo{
+		serverVersion: r.getString(),
+		serverName:    r.getString(),
+	}
+	r.getSampleSpec()
+	info.defaultSink = r.getString()
+	info.defaultSource = r.getString()
+	r.getU32()
+	r.getChannelMap()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to parse server info: %w", r.err)
+	}
+	return info, nil
+}
+
+func readDevice(r *tagReader) deviceInfo {
+	d := deviceInfo{
+		index:       r.getU32(),
+		name:        r.getString(),
+		description: r.getString(),
+	}
+	r.getSampleSpec()
+	r.getChannelMap()
+	r.getU32()
+	d.volume = r.getCVolume()
+	d.mute = r.getBool()
+	d.monitorOf = r.getU32()
+	d.monitorName = r.getString()
+	r.getUsec()
+	r.getString()
+	r.getU32()
+	d.properties = r.getPropList()
+	r.getUsec()
+	r.getVolume()
+	d.state = r.getU32()
+	r.getU32()
+	r.getU32()
+
+	portCount := r.getU32()
+	for i := uint32(0); i < portCount && r.err == nil; i++ {
+		d.ports = append(d.ports, devicePort{
+			name:        r.getString(),
+			description: r.getString(),
+			priority:    r.getU32(),
+			available:   r.getU32(),
+		})
+	}
+	d.activePort = r.getString()
+
+	formatCount := r.getU8()
+	for i := uint8(0); i < formatCount && r.err == nil; i++ {
+		r.getFormatInfo()
+	}
+
+	return d
+}
+
+func (c *pulseClient) listDevices(command uint32) ([]deviceInfo, error) {
+	r, err := c.request(command, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []deviceInfo
+	for !r.eof() {
+		d := readDevice(r)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to parse device list: %w", r.err)
+		}
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+func (c *pulseClient) getSinks() ([]deviceInfo, error) {
+	return c.listDevices(cmdGetSinkInfoList)
+}
+
+func (c *pulseClient) getSources() ([]deviceInfo, error) {
+	return c.listDevices(cmdGetSourceInfoList)
+}
+
+func readSinkInput(r *tagReader) streamInfo {
+	s := streamInfo{
+		index: r.getU32(),
+		name:  r.getString(),
+	}
+	r.getU32()
+	r.getU32()
+	s.device = r.getU32()
+	r.getSampleSpec()
+	r.getChannelMap()
+	s.volume = r.getCVolume()
+	r.getUsec()
+	r.getUsec()
+	r.getString()
+	r.getString()
+	s.mute = r.getBool()
+	s.properties = r.getPropList()
+	s.corked = r.getBool()
+	s.hasVolume = r.getBool()
+	r.getBool()
+	r.getFormatInfo()
+	return s
+}
+
+func readSourceOutput(r *tagReader) streamInfo {
+	s := streamInfo{
+		index: r.getU32(),
+		name:  r.getString(),
+	}
+	r.getU32()
+	r.getU32()
+	s.device = r.getU32()
+	r.getSampleSpec()
+	r.getChannelMap()
+	r.getUsec()
+	r.getUsec()
+	r.getString()
+	r.getString()
+	s.properties = r.getPropList()
+	s.corked = r.getBool()
+	s.volume = r.getCVolume()
+	s.mute = r.getBool()
+	s.hasVolume = r.getBool()
+	r.getBool()
+	r.getFormatInfo()
+	return s
+}
+
+func (c *pulseClient) listStreams(command uint32, read func(*tagReader) streamInfo) ([]streamInfo, error) {
+	r, err := c.request(command, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []streamInfo
+	for !r.eof() {
+		s := read(r)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to parse stream list: %w", r.err)
+		}
+		streams = append(streams, s)
+	}
+	return streams, nil
+}
+
+func (c *pulseClient) getSinkInputs() ([]streamInfo, error) {
+	return c.listStreams(cmdGetSinkInputInfoList, readSinkInput)
+}
+
+func (c *pulseClient) getSourceOutputs() ([]streamInfo, error) {
+	return c.listStreams(cmdGetSourceOutputInfoList, readSourceOutput)
+}
+
+func (c *pulseClient) setDeviceVolume(command uint32, name string, volume []uint32) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putU32(invalidIndex)
+		w.putString(name)
+		w.putCVolume(volume)
+	})
+	return err
+}
+
+func (c *pulseClient) setDeviceMute(command uint32, name string, mute bool) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putU32(invalidIndex)
+		w.putString(name)
+		w.putBool(mute)
+	})
+	return err
+}
+
+func (c *pulseClient) setStreamVolume(command uint32, index uint32, volume []uint32) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putU32(index)
+		w.putCVolume(volume)
+	})
+	return err
+}
+
+func (c *pulseClient) setStreamMute(command uint32, index uint32, mute bool) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putU32(index)
+		w.putBool(mute)
+	})
+	return err
+}
+
+func (c *pulseClient) setDefault(command uint32, name string) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putString(name)
+	})
+	return err
+}
+
+func (c *pulseClient) moveStream(command uint32, index uint32, deviceName string) error {
+	_, err := c.request(command, func(w *tagWriter) {
+		w.putU32(index)
+		w.putU32(invalidIndex)
+		w.putString(deviceName)
+	})
+	return err
+}
@@ -0,0 +1,662 @@
+package audio
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const (
+	refreshDelay     = 30 * time.Millisecond
+	reconnectDelay   = 2 * time.Second
+	MaxVolumePercent = 150
+)
+
+func NewManager() (*Manager, error) {
+	return newManager(defaultSocketPath())
+}
+
+func newManager(socketPath string) (*Manager, error) {
+	client, err := dialPulse(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		socketPath:  socketPath,
+		subscribers: make(map[string]chan State),
+		stopChan:    make(chan struct{}),
+		dirty:       make(chan struct{}, 1),
+	}
+
+	if err := m.attach(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	m.notifierWg.Add(1)
+	go m.notifier()
+
+	m.eventWg.Add(1)
+	go m.eventLoop()
+
+	return m, nil
+}
+
+func (m *Manager) attach(client *pulseClient) error {
+	mask := subscriptionMaskSink | subscriptionMaskSource | subscriptionMaskSinkInput |
+		subscriptionMaskSourceOutput | subscriptionMaskServer | subscriptionMaskCard
+	if err := client.subscribe(mask); err != nil {
+		return fmt.Errorf("failed to subscribe to server events: %w", err)
+	}
+
+	m.clientMutex.Lock()
+	m.client = client
+	m.clientMutex.Unlock()
+
+	return m.updateState()
+}
+
+func (m *Manager) getClient() (*pulseClient, error) {
+	m.clientMutex.RLock()
+	defer m.clientMutex.RUnlock()
+	if m.client == nil {
+		return nil, fmt.Errorf("audio server not connected")
+	}
+	return m.client, nil
+}
+
+func (m *Manager) eventLoop() {
+	defer m.eventWg.Done()
+
+	timer := time.NewTimer(refreshDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		client, err := m.getClient()
+		if err != nil {
+			if !m.reconnect() {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-m.stopChan:
+			return
+		case <-client.events:
+			timer.Reset(refreshDelay)
+		case <-timer.C:
+			if err := m.updateState(); err != nil {
+				log.Debugf("Audio: failed to refresh state: %v", err)
+			}
+		case <-client.Done():
+			m.handleDisconnect(client)
+		}
+	}
+}
+
+func (m *Manager) handleDisconnect(client *pulseClient) {
+	select {
+	case <-m.stopChan:
+		return
+	default:
+	}
+
+	log.Warn("Audio: lost connection to sound server")
+
+	m.clientMutex.Lock()
+	if m.client == client {
+		m.client = nil
+	}
+	m.clientMutex.Unlock()
+
+	m.stateMutex.Lock()
+	m.state = &State{
+		Sinks:         []Device{},
+		Sources:       []Device{},
+		SinkInputs:    []Stream{},
+		SourceOutputs: []Stream{},
+	}
+	m.stateMutex.Unlock()
+	m.notifySubscribers()
+}
+
+func (m *Manager) reconnect() bool {
+	ticker := time.NewTicker(reconnectDelay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return false
+		case <-ticker.C:
+		}
+
+		client, err := dialPulse(m.socketPath)
+		if err != nil {
+			continue
+		}
+		if err := m.attach(client); err != nil {
+			client.Close()
+			continue
+		}
+
+		log.Info("Audio: reconnected to sound server")
+		return true
+	}
+}
+
+func (m *Manager) updateState() error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	server, err := client.getServerInfo()
+	if err != nil {
+		return err
+	}
+	sinks, err := client.getSinks()
+	if err != nil {
+		return err
+	}
+	sources, err := client.getSources()
+	if err != nil {
+		return err
+	}
+	sinkInputs, err := client.getSinkInputs()
+	if err != nil {
+		return err
+	}
+	sourceOutputs, err := client.getSourceOutputs()
+	if err != nil {
+		return err
+	}
+
+	state := buildState(server, sinks, sources, sinkInputs, sourceOutputs)
+
+	m.stateMutex.Lock()
+	m.state = state
+	m.stateMutex.Unlock()
+
+	m.notifySubscribers()
+	return nil
+}
+
+func buildState(server *serverInfo, sinks, sources []deviceInfo, sinkInputs, sourceOutputs []streamInfo) *State {
+	state := &State{
+		Available:     true,
+		ServerName:    server.serverName,
+		ServerVersion: server.serverVersion,
+		DefaultSink:   server.defaultSink,
+		DefaultSource: server.defaultSource,
+		Sinks:         make([]Device, 0, len(sinks)),
+		Sources:       make([]Device, 0, len(sources)),
+		SinkInputs:    make([]Stream, 0, len(sinkInputs)),
+		SourceOutputs: make([]Stream, 0, len(sourceOutputs)),
+	}
+
+	sinkNames := make(map[uint32]string, len(sinks))
+	for _, d := range sinks {
+		sinkNames[d.index] = d.name
+		state.Sinks = append(state.Sinks, convertDevice(d, server.defaultSink))
+	}
+
+	sourceNames := make(map[uint32]string, len(sources))
+	for _, d := range sources {
+		sourceNames[d.index] = d.name
+		state.Sources = append(state.Sources, convertDevice(d, server.defaultSource))
+	}
+
+	for _, s := range sinkInputs {
+		state.SinkInputs = append(state.SinkInputs, convertStream(s, sinkNames[s.device]))
+	}
+	for _, s := range sourceOutputs {
+		state.SourceOutputs = append(state.SourceOutputs, convertStream(s, sourceNames[s.device]))
+	}
+
+	sort.Slice(state.Sinks, func(i, j int) bool { return state.Sinks[i].Index < state.Sinks[j].Index })
+	sort.Slice(state.Sources, func(i, j int) bool { return state.Sources[i].Index < state.Sources[j].Index })
+	sort.Slice(state.SinkInputs, func(i, j int) bool { return state.SinkInputs[i].Index < state.SinkInputs[j].Index })
+	sort.Slice(state.SourceOutputs, func(i, j int) bool { return state.SourceOutputs[i].Index < state.SourceOutputs[j].Index })
+
+	return state
+}
+
+func convertDevice(d deviceInfo, defaultName string) Device {
+	dev := Device{
+		Index:          d.index,
+		Name:           d.name,
+		Description:    d.description,
+		Volume:         volumeToPercent(maxVolume(d.volume)),
+		ChannelVolumes: volumesToPercent(d.volume),
+		Muted:          d.mute,
+		State:          deviceStateName(d.state),
+		IsDefault:      d.name == defaultName,
+		FormFactor:     d.properties["device.form_factor"],
+		Bus:            d.properties["device.bus"],
+		IconName:       d.properties["device.icon_name"],
+		Ports:          make([]Port, 0, len(d.ports)),
+		ActivePort:     d.activePort,
+	}
+
+	if d.monitorOf != invalidIndex {
+		dev.MonitorOf = d.monitorName
+	}
+
+	for _, p := range d.ports {
+		dev.Ports = append(dev.Ports, Port{
+			Name:        p.name,
+			Description: p.description,
+			Priority:    p.priority,
+			Available:   portAvailableName(p.available),
+		})
+	}
+
+	return dev
+}
+
+func convertStream(s streamInfo, deviceName string) Stream {
+	name := s.properties["media.name"]
+	if name == "" {
+		name = s.name
+	}
+
+	appName := s.properties["application.name"]
+	if appName == "" {
+		appName = s.properties["application.process.binary"]
+	}
+
+	return Stream{
+		Index:          s.index,
+		Name:           name,
+		AppName:        appName,
+		AppBinary:      s.properties["application.process.binary"],
+		IconName:       s.properties["application.icon_name"],
+		MediaRole:      s.properties["media.role"],
+		Device:         deviceName,
+		Volume:         volumeToPercent(maxVolume(s.volume)),
+		ChannelVolumes: volumesToPercent(s.volume),
+		Muted:          s.mute,
+		Corked:         s.corked,
+		HasVolume:      s.hasVolume,
+	}
+}
+
+func deviceStateName(state uint32) DeviceState {
+	switch state {
+	case 0:
+		return DeviceStateRunning
+	case 1:
+		return DeviceStateIdle
+	case 2:
+		return DeviceStateSuspended
+	default:
+		return DeviceStateUnknown
+	}
+}
+
+func portAvailableName(available uint32) string {
+	switch available {
+	case 1:
+		return "no"
+	case 2:
+		return "yes"
+	default:
+		return "unknown"
+	}
+}
+
+func maxVolume(volumes []uint32) uint32 {
+	var max uint32
+	for _, v := range volumes {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func volumeToPercent(v uint32) int {
+	return int((uint64(v)*100 + uint64(volumeNorm)/2) / uint64(volumeNorm))
+}
+
+func percentToVolume(percent int) uint32 {
+	return uint32((uint64(percent)*uint64(volumeNorm) + 50) / 100)
+}
+
+func volumesToPercent(volumes []uint32) []int {
+	percents := make([]int, len(volumes))
+	for i, v := range volumes {
+		percents[i] = volumeToPercent(v)
+	}
+	return percents
+}
+
+func scaleVolume(current []uint32, percent int) []uint32 {
+	target := percentToVolume(percent)
+	if len(current) == 0 {
+		return []uint32{target}
+	}
+
+	peak := maxVolume(current)
+	scaled := make([]uint32, len(current))
+	for i, v := range current {
+		if peak == 0 {
+			scaled[i] = target
+			continue
+		}
+		scaled[i] = uint32(uint64(v) * uint64(target) / uint64(peak))
+	}
+	return scaled
+}
+
+func validatePercent(percent int) error {
+	if percent < 0 || percent > MaxVolumePercent {
+		return fmt.Errorf("volume out of range: %d (0-%d)", percent, MaxVolumePercent)
+	}
+	return nil
+}
+
+func findDevice(devices []Device, name, defaultName string) (*Device, error) {
+	if name == "" {
+		name = defaultName
+	}
+	for i := range devices {
+		if devices[i].Name == name {
+			return &devices[i], nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no default device")
+	}
+	return nil, fmt.Errorf("device not found: %s", name)
+}
+
+func findStream(streams []Stream, index uint32) (*Stream, error) {
+	for i := range streams {
+		if streams[i].Index == index {
+			return &streams[i], nil
+		}
+	}
+	return nil, fmt.Errorf("stream not found: %d", index)
+}
+
+func channelVolumes(percents []int) []uint32 {
+	volumes := make([]uint32, len(percents))
+	for i, p := range percents {
+		volumes[i] = percentToVolume(p)
+	}
+	return volumes
+}
+
+func (m *Manager) SetSinkVolume(name string, percent int) error {
+	return m.setDeviceVolume(cmdSetSinkVolume, name, percent, true)
+}
+
+func (m *Manager) SetSourceVolume(name string, percent int) error {
+	return m.setDeviceVolume(cmdSetSourceVolume, name, percent, false)
+}
+
+func (m *Manager) setDeviceVolume(command uint32, name string, percent int, sink bool) error {
+	if err := validatePercent(percent); err != nil {
+		return err
+	}
+
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	devices, defaultName := state.Sources, state.DefaultSource
+	if sink {
+		devices, defaultName = state.Sinks, state.DefaultSink
+	}
+
+	dev, err := findDevice(devices, name, defaultName)
+	if err != nil {
+		return err
+	}
+
+	return client.setDeviceVolume(command, dev.Name, scaleVolume(channelVolumes(dev.ChannelVolumes), percent))
+}
+
+func (m *Manager) SetSinkMute(name string, mute *bool) error {
+	return m.setDeviceMute(cmdSetSinkMute, name, mute, true)
+}
+
+func (m *Manager) SetSourceMute(name string, mute *bool) error {
+	return m.setDeviceMute(cmdSetSourceMute, name, mute, false)
+}
+
+func (m *Manager) setDeviceMute(command uint32, name string, mute *bool, sink bool) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	devices, defaultName := state.Sources, state.DefaultSource
+	if sink {
+		devices, defaultName = state.Sinks, state.DefaultSink
+	}
+
+	dev, err := findDevice(devices, name, defaultName)
+	if err != nil {
+		return err
+	}
+
+	target := !dev.Muted
+	if mute != nil {
+		target = *mute
+	}
+
+	return client.setDeviceMute(command, dev.Name, target)
+}
+
+func (m *Manager) SetSinkInputVolume(index uint32, percent int) error {
+	return m.setStreamVolume(cmdSetSinkInputVolume, index, percent, true)
+}
+
+func (m *Manager) SetSourceOutputVolume(index uint32, percent int) error {
+	return m.setStreamVolume(cmdSetSourceOutputVolume, index, percent, false)
+}
+
+func (m *Manager) setStreamVolume(command uint32, index uint32, percent int, playback bool) error {
+	if err := validatePercent(percent); err != nil {
+		return err
+	}
+
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	streams := state.SourceOutputs
+	if playback {
+		streams = state.SinkInputs
+	}
+
+	stream, err := findStream(streams, index)
+	if err != nil {
+		return err
+	}
+	if !stream.HasVolume {
+		return fmt.Errorf("stream %d has no volume control", index)
+	}
+
+	return client.setStreamVolume(command, index, scaleVolume(channelVolumes(stream.ChannelVolumes), percent))
+}
+
+func (m *Manager) SetSinkInputMute(index uint32, mute *bool) error {
+	return m.setStreamMute(cmdSetSinkInputMute, index, mute, true)
+}
+
+func (m *Manager) SetSourceOutputMute(index uint32, mute *bool) error {
+	return m.setStreamMute(cmdSetSourceOutputMute, index, mute, false)
+}
+
+func (m *Manager) setStreamMute(command uint32, index uint32, mute *bool, playback bool) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	streams := state.SourceOutputs
+	if playback {
+		streams = state.SinkInputs
+	}
+
+	stream, err := findStream(streams, index)
+	if err != nil {
+		return err
+	}
+
+	target := !stream.Muted
+	if mute != nil {
+		target = *mute
+	}
+
+	return client.setStreamMute(command, index, target)
+}
+
+func (m *Manager) SetDefaultSink(name string) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+	if _, err := findDevice(m.GetState().Sinks, name, ""); err != nil {
+		return err
+	}
+	return client.setDefault(cmdSetDefaultSink, name)
+}
+
+func (m *Manager) SetDefaultSource(name string) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+	if _, err := findDevice(m.GetState().Sources, name, ""); err != nil {
+		return err
+	}
+	return client.setDefault(cmdSetDefaultSource, name)
+}
+
+func (m *Manager) MoveSinkInput(index uint32, sink string) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	if _, err := findStream(state.SinkInputs, index); err != nil {
+		return err
+	}
+	dev, err := findDevice(state.Sinks, sink, state.DefaultSink)
+	if err != nil {
+		return err
+	}
+
+	return client.moveStream(cmdMoveSinkInput, index, dev.Name)
+}
+
+func (m *Manager) MoveSourceOutput(index uint32, source string) error {
+	client, err := m.getClient()
+	if err != nil {
+		return err
+	}
+
+	state := m.GetState()
+	if _, err := findStream(state.SourceOutputs, index); err != nil {
+		return err
+	}
+	dev, err := findDevice(state.Sources, source, state.DefaultSource)
+	if err != nil {
+		return err
+	}
+
+	return client.moveStream(cmdMoveSourceOutput, index, dev.Name)
+}
+
+func (m *Manager) notifier() {
+	defer m.notifierWg.Done()
+	const minGap = 50 * time.Millisecond
+	timer := time.NewTimer(minGap)
+	timer.Stop()
+	var pending bool
+
+	for {
+		select {
+		case <-m.stopChan:
+			timer.Stop()
+			return
+		case <-m.dirty:
+			if pending {
+				continue
+			}
+			pending = true
+			timer.Reset(minGap)
+		case <-timer.C:
+			if !pending {
+				continue
+			}
+			pending = false
+
+			m.subMutex.RLock()
+			subCount := len(m.subscribers)
+			m.subMutex.RUnlock()
+
+			if subCount == 0 {
+				continue
+			}
+
+			currentState := m.GetState()
+			if m.lastNotified != nil && !stateChanged(m.lastNotified, &currentState) {
+				continue
+			}
+
+			m.subMutex.RLock()
+			for _, ch := range m.subscribers {
+				select {
+				case ch <- currentState:
+				default:
+					log.Warn("Audio: subscriber channel full, dropping update")
+				}
+			}
+			m.subMutex.RUnlock()
+
+			stateCopy := currentState
+			m.lastNotified = &stateCopy
+		}
+	}
+}
+
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stopChan)
+
+		m.clientMutex.Lock()
+		if m.client != nil {
+			m.client.Close()
+			m.client = nil
+		}
+		m.clientMutex.Unlock()
+
+		m.eventWg.Wait()
+		m.notifierWg.Wait()
+
+		m.subMutex.Lock()
+		for _, ch := range m.subscribers {
+			close(ch)
+		}
+		m.subscribers = make(map[string]chan State)
+		m.subMutex.Unlock()
+	})
+}
@@ -0,0 +1,218 @@
+package audio
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) (*Manager, *fakePulseServer) {
+	t.Helper()
+
+	server := newFakePulseServer(t)
+	m, err := newManager(server.path)
+	require.NoError(t, err)
+	t.Cleanup(m.Close)
+
+	return m, server
+}
+
+func waitForState(t *testing.T, m *Manager, cond func(State) bool) State {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		state := m.GetState()
+		if cond(state) {
+			return state
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for state, last: %+v", m.GetState())
+	return State{}
+}
+
+func TestNewManager_InitialState(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	state := m.GetState()
+	assert.True(t, state.Available)
+	assert.Equal(t, "PulseAudio (on PipeWire 1.2.0)", state.ServerName)
+	assert.Equal(t, "alsa_output.speakers", state.DefaultSink)
+	assert.Equal(t, "alsa_input.mic", state.DefaultSource)
+
+	require.Len(t, state.Sinks, 2)
+	speakers := state.Sinks[0]
+	assert.Equal(t, uint32(1), speakers.Index)
+	assert.Equal(t, "Built-in Speakers", speakers.Description)
+	assert.Equal(t, 50, speakers.Volume)
+	assert.Equal(t, []int{50, 50}, speakers.ChannelVolumes)
+	assert.True(t, speakers.IsDefault)
+	assert.Equal(t, DeviceStateRunning, speakers.State)
+	assert.Equal(t, "internal", speakers.FormFactor)
+	assert.Equal(t, "analog-output-speaker", speakers.ActivePort)
+	require.Len(t, speakers.Ports, 2)
+	assert.Equal(t, "unknown", speakers.Ports[0].Available)
+	assert.Equal(t, "no", speakers.Ports[1].Available)
+
+	assert.False(t, state.Sinks[1].IsDefault)
+	assert.Equal(t, DeviceStateIdle, state.Sinks[1].State)
+
+	require.Len(t, state.Sources, 2)
+	assert.Equal(t, "alsa_output.speakers", state.Sources[0].MonitorOf)
+	assert.Equal(t, DeviceStateSuspended, state.Sources[0].State)
+	assert.Empty(t, state.Sources[1].MonitorOf)
+	assert.Equal(t, 75, state.Sources[1].Volume)
+
+	require.Len(t, state.SinkInputs, 1)
+	assert.Equal(t, "Video", state.SinkInputs[0].Name)
+	assert.Equal(t, "Firefox", state.SinkInputs[0].AppName)
+	assert.Equal(t, "alsa_output.speakers", state.SinkInputs[0].Device)
+
+	require.Len(t, state.SourceOutputs, 1)
+	assert.Equal(t, "OBS", state.SourceOutputs[0].AppName)
+	assert.Equal(t, "record", state.SourceOutputs[0].Name)
+	assert.Equal(t, "alsa_input.mic", state.SourceOutputs[0].Device)
+}
+
+func TestNewManager_NoServer(t *testing.T) {
+	_, err := newManager(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestNewManager_OldProtocol(t *testing.T) {
+	server := newFakePulseServer(t)
+	server.serverVersion = 20
+
+	_, err := newManager(server.path)
+	assert.ErrorContains(t, err, "too old")
+}
+
+func TestManager_SetSinkVolume(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	require.NoError(t, m.SetSinkVolume("", 80))
+	state := waitForState(t, m, func(s State) bool { return s.Sinks[0].Volume == 80 })
+	assert.Equal(t, []int{80, 80}, state.Sinks[0].ChannelVolumes)
+
+	require.NoError(t, m.SetSinkVolume("bluez_output.headset", 30))
+	waitForState(t, m, func(s State) bool { return s.Sinks[1].Volume == 30 })
+
+	assert.Error(t, m.SetSinkVolume("missing", 30))
+	assert.Error(t, m.SetSinkVolume("", MaxVolumePercent+1))
+	assert.Error(t, m.SetSinkVolume("", -1))
+}
+
+func TestManager_SetSourceMute(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	muted := true
+	require.NoError(t, m.SetSourceMute("", &muted))
+	waitForState(t, m, func(s State) bool { return s.Sources[1].Muted })
+
+	require.NoError(t, m.SetSourceMute("alsa_input.mic", nil))
+	waitForState(t, m, func(s State) bool { return !s.Sources[1].Muted })
+}
+
+func TestManager_StreamControls(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	require.NoError(t, m.SetSinkInputVolume(10, 40))
+	waitForState(t, m, func(s State) bool { return s.SinkInputs[0].Volume == 40 })
+
+	require.NoError(t, m.SetSinkInputMute(10, nil))
+	waitForState(t, m, func(s State) bool { return s.SinkInputs[0].Muted })
+
+	require.NoError(t, m.SetSourceOutputVolume(20, 10))
+	waitForState(t, m, func(s State) bool { return s.SourceOutputs[0].Volume == 10 })
+
+	assert.Error(t, m.SetSinkInputVolume(99, 40))
+	assert.Error(t, m.SetSourceOutputMute(99, nil))
+}
+
+func TestManager_DefaultsAndMoves(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	require.NoError(t, m.SetDefaultSink("bluez_output.headset"))
+	state := waitForState(t, m, func(s State) bool { return s.DefaultSink == "bluez_output.headset" })
+	assert.False(t, state.Sinks[0].IsDefault)
+	assert.True(t, state.Sinks[1].IsDefault)
+
+	require.NoError(t, m.MoveSinkInput(10, "bluez_output.headset"))
+	waitForState(t, m, func(s State) bool { return s.SinkInputs[0].Device == "bluez_output.headset" })
+
+	require.NoError(t, m.MoveSourceOutput(20, "alsa_output.speakers.monitor"))
+	waitForState(t, m, func(s State) bool { return s.SourceOutputs[0].Device == "alsa_output.speakers.monitor" })
+
+	assert.Error(t, m.SetDefaultSource("missing"))
+	assert.Error(t, m.MoveSinkInput(10, "missing"))
+}
+
+func TestManager_SubscribeReceivesUpdates(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	ch := m.Subscribe("test")
+	defer m.Unsubscribe("test")
+
+	require.NoError(t, m.SetSinkVolume("", 65))
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case state := <-ch:
+			if state.Sinks[0].Volume == 65 {
+				return
+			}
+		case <-timeout:
+			t.Fatal("did not receive state update")
+		}
+	}
+}
+
+func TestManager_Disconnect(t *testing.T) {
+	m, server := newTestManager(t)
+
+	server.dropClients()
+
+	state := waitForState(t, m, func(s State) bool { return !s.Available })
+	assert.Empty(t, state.Sinks)
+
+	assert.Error(t, m.SetSinkVolume("", 50))
+}
+
+func TestScaleVolume(t *testing.T) {
+	assert.Equal(t, []uint32{volumeNorm, volumeNorm / 2}, scaleVolume([]uint32{volumeNorm / 2, volumeNorm / 4}, 100))
+	assert.Equal(t, []uint32{volumeNorm / 2, volumeNorm / 2}, scaleVolume([]uint32{0, 0}, 50))
+	assert.Equal(t, []uint32{volumeNorm}, scaleVolume(nil, 100))
+}
+
+func TestVolumePercentConversion(t *testing.T) {
+	assert.Equal(t, 100, volumeToPercent(volumeNorm))
+	assert.Equal(t, 0, volumeToPercent(0))
+	assert.Equal(t, 150, volumeToPercent(percentToVolume(150)))
+	assert.Equal(t, volumeNorm/2, percentToVolume(50))
+}
+
+func TestStateChanged(t *testing.T) {
+	base := &State{
+		Available: true,
+		Sinks:     []Device{{Index: 1, Name: "a", Volume: 50, ChannelVolumes: []int{50, 50}}},
+	}
+
+	same := &State{
+		Available: true,
+		Sinks:     []Device{{Index: 1, Name: "a", Volume: 50, ChannelVolumes: []int{50, 50}}},
+	}
+	assert.False(t, stateChanged(base, same))
+
+	balance := &State{
+		Available: true,
+		Sinks:     []Device{{Index: 1, Name: "a", Volume: 50, ChannelVolumes: []int{50, 40}}},
+	}
+	assert.True(t, stateChanged(base, balance))
+
+	assert.True(t, stateChanged(nil, base))
+}
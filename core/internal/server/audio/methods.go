@@ -0,0 +1,109 @@
+package audio
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "audio.getState",
+		Description: "Get sinks, sources, streams and defaults",
+	},
+	{
+		Name:        "audio.setSinkVolume",
+		Description: "Set sink volume",
+		Params: []models.ParamSpec{
+			{Name: "volume", Type: models.ParamNumber, Required: true},
+			{Name: "sink", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "audio.setSinkMute",
+		Description: "Set or toggle sink mute",
+		Params: []models.ParamSpec{
+			{Name: "sink", Type: models.ParamString},
+			{Name: "mute", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "audio.setSourceVolume",
+		Description: "Set source volume",
+		Params: []models.ParamSpec{
+			{Name: "volume", Type: models.ParamNumber, Required: true},
+			{Name: "source", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "audio.setSourceMute",
+		Description: "Set or toggle source mute",
+		Params: []models.ParamSpec{
+			{Name: "source", Type: models.ParamString},
+			{Name: "mute", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "audio.setSinkInputVolume",
+		Description: "Set playback stream volume",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "volume", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "audio.setSinkInputMute",
+		Description: "Set or toggle playback stream mute",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "mute", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "audio.setSourceOutputVolume",
+		Description: "Set recording stream volume",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "volume", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "audio.setSourceOutputMute",
+		Description: "Set or toggle recording stream mute",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "mute", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "audio.setDefaultSink",
+		Description: "Set default sink",
+		Params: []models.ParamSpec{
+			{Name: "sink", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "audio.setDefaultSource",
+		Description: "Set default source",
+		Params: []models.ParamSpec{
+			{Name: "source", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "audio.moveSinkInput",
+		Description: "Move playback stream to sink",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "sink", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "audio.moveSourceOutput",
+		Description: "Move recording stream to source",
+		Params: []models.ParamSpec{
+			{Name: "index", Type: models.ParamNumber, Required: true},
+			{Name: "source", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "audio.subscribe",
+		Description: "Subscribe to audio state changes",
+		Streaming:   true,
+	},
+}
@@ -0,0 +1,349 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+)
+
+const (
+	protocolVersion     uint32 = 32
+	protocolVersionMask uint32 = 0x0000ffff
+	controlChannel      uint32 = 0xffffffff
+	invalidIndex        uint32 = 0xffffffff
+	frameHeaderSize            = 20
+	maxFrameSize               = 16 * 1024 * 1024
+	cookieLength               = 256
+	requestTimeout             = 5 * time.Second
+	volumeNorm          uint32 = 0x10000
+)
+
+const (
+	cmdError                   uint32 = 0
+	cmdReply                   uint32 = 2
+	cmdAuth                    uint32 = 8
+	cmdSetClientName           uint32 = 9
+	cmdGetServerInfo           uint32 = 20
+	cmdGetSinkInfoList         uint32 = 22
+	cmdGetSourceInfoList       uint32 = 24
+	cmdGetSinkInputInfoList    uint32 = 30
+	cmdGetSourceOutputInfoList uint32 = 32
+	cmdSubscribe               uint32 = 35
+	cmdSetSinkVolume           uint32 = 36
+	cmdSetSinkInputVolume      uint32 = 37
+	cmdSetSourceVolume         uint32 = 38
+	cmdSetSinkMute             uint32 = 39
+	cmdSetSourceMute           uint32 = 40
+	cmdSetDefaultSink          uint32 = 44
+	cmdSetDefaultSource        uint32 = 45
+	cmdSubscribeEvent          uint32 = 66
+	cmdMoveSinkInput           uint32 = 67
+	cmdMoveSourceOutput        uint32 = 68
+	cmdSetSinkInputMute        uint32 = 69
+	cmdSetSourceOutputVolume   uint32 = 98
+	cmdSetSourceOutputMute     uint32 = 99
+)
+
+const (
+	subscriptionMaskSink         uint32 = 0x0001
+	subscriptionMaskSource       uint32 = 0x0002
+	subscriptionMaskSinkInput    uint32 = 0x0004
+	subscriptionMaskSourceOutput uint32 = 0x0008
+	subscriptionMaskServer       uint32 = 0x0080
+	subscriptionMaskCard         uint32 = 0x0200
+)
+
+var pulseErrors = map[uint32]string{
+	1:  "access denied",
+	2:  "unknown command",
+	3:  "invalid argument",
+	4:  "entity exists",
+	5:  "no such entity",
+	6:  "connection refused",
+	7:  "protocol error",
+	8:  "timeout",
+	9:  "no authentication key",
+	10: "internal error",
+	11: "connection terminated",
+	12: "entity killed",
+	13: "invalid server",
+	14: "module initialization failed",
+	15: "bad state",
+	16: "no data",
+	17: "incompatible protocol version",
+	18: "too large",
+	19: "not supported",
+	20: "unknown error code",
+	21: "no such extension",
+	22: "obsolete functionality",
+	23: "missing implementation",
+	24: "client forked",
+	25: "input/output error",
+	26: "device or resource busy",
+}
+
+type pulseError struct {
+	Code uint32
+}
+
+func (e *pulseError) Error() string {
+	if msg, ok := pulseErrors[e.Code]; ok {
+		return "pulse: " + msg
+	}
+	return fmt.Sprintf("pulse: error %d", e.Code)
+}
+
+var errClientClosed = errors.New("pulse: connection closed")
+
+type pulseReply struct {
+	data *tagReader
+	err  error
+}
+
+type pulseClient struct {
+	conn      net.Conn
+	writeMu   sync.Mutex
+	pendingMu sync.Mutex
+	pending   map[uint32]chan pulseReply
+	nextTag   uint32
+	events    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func defaultSocketPath() string {
+	if server := os.Getenv("PULSE_SERVER"); server != "" {
+		for _, entry := range strings.Fields(server) {
+			if path, ok := strings.CutPrefix(entry, "unix:"); ok {
+				return path
+			}
+			if strings.HasPrefix(entry, "/") {
+				return entry
+			}
+		}
+	}
+
+	if runtime := os.Getenv("PULSE_RUNTIME_PATH"); runtime != "" {
+		return filepath.Join(runtime, "native")
+	}
+
+	if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+		return filepath.Join(runtime, "pulse", "native")
+	}
+
+	return fmt.Sprintf("/run/user/%d/pulse/native", os.Getuid())
+}
+
+func loadCookie() []byte {
+	var candidates []string
+	if path := os.Getenv("PULSE_COOKIE"); path != "" {
+		candidates = append(candidates, path)
+	}
+	candidates = append(candidates, filepath.Join(config.XDGDir("XDG_CONFIG_HOME", ".config"), "pulse", "cookie"))
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".pulse-cookie"))
+	}
+
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if err == nil && len(data) >= cookieLength {
+			return data[:cookieLength]
+		}
+	}
+
+	return make([]byte, cookieLength)
+}
+
+func dialPulse(socketPath string) (*pulseClient, error) {
+	conn, err := net.DialTimeout("unix", socketPath, requestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", socketPath, err)
+	}
+
+	c := &pulseClient{
+		conn:    conn,
+		pending: make(map[uint32]chan pulseReply),
+		events:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+
+	if err := c.handshake(); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *pulseClient) handshake() error {
+	reply, err := c.request(cmdAuth, func(w *tagWriter) {
+		w.putU32(protocolVersion)
+		w.putArbitrary(loadCookie())
+	})
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	serverVersion := reply.getU32() & protocolVersionMask
+	if reply.err != nil {
+		return reply.err
+	}
+	if serverVersion < protocolVersion {
+		return fmt.Errorf("server protocol version %d is too old (need %d)", serverVersion, protocolVersion)
+	}
+
+	_, err = c.request(cmdSetClientName, func(w *tagWriter) {
+		w.putPropList(map[string]string{
+			"application.name":           "DankMaterialShell",
+			"application.id":             "com.danklinux.dms",
+			"application.process.id":     fmt.Sprintf("%d", os.Getpid()),
+			"application.process.binary": "dms",
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set client name: %w", err)
+	}
+
+	return nil
+}
+
+func (c *pulseClient) subscribe(mask uint32) error {
+	_, err := c.request(cmdSubscribe, func(w *tagWriter) {
+		w.putU32(mask)
+	})
+	return err
+}
+
+func (c *pulseClient) request(command uint32, build func(w *tagWriter)) (*tagReader, error) {
+	ch := make(chan pulseReply, 1)
+
+	c.pendingMu.Lock()
+	tag := c.nextTag
+	c.nextTag++
+	c.pending[tag] = ch
+	c.pendingMu.Unlock()
+
+	w := &tagWriter{}
+	w.putU32(command)
+	w.putU32(tag)
+	if build != nil {
+		build(w)
+	}
+
+	if err := c.writeFrame(w.buf); err != nil {
+		c.pendingMu.Lock()
+		delete(c.pending, tag)
+		c.pendingMu.Unlock()
+		return nil, err
+	}
+
+	timer := time.NewTimer(requestTimeout)
+	defer timer.Stop()
+
+	select {
+	case reply := <-ch:
+		return reply.data, reply.err
+	case <-c.done:
+		return nil, errClientClosed
+	case <-timer.C:
+		c.pendingMu.Lock()
+		delete(c.pending, tag)
+		c.pendingMu.Unlock()
+		return nil, fmt.Errorf("pulse: request %d timed out", command)
+	}
+}
+
+func (c *pulseClient) writeFrame(payload []byte) error {
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], controlChannel)
+	frame = append(frame, payload...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) (uint32, []byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:])
+	channel := binary.BigEndian.Uint32(header[4:])
+	if length > maxFrameSize {
+		return 0, nil, fmt.Errorf("pulse: frame too large (%d bytes)", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return channel, payload, nil
+}
+
+func (c *pulseClient) readLoop() {
+	defer c.Close()
+
+	for {
+		channel, payload, err := readFrame(c.conn)
+		if err != nil {
+			return
+		}
+		if channel != controlChannel {
+			continue
+		}
+
+		r := newTagReader(payload)
+		command := r.getU32()
+		tag := r.getU32()
+		if r.err != nil {
+			continue
+		}
+
+		switch command {
+		case cmdReply, cmdError:
+			c.pendingMu.Lock()
+			ch, ok := c.pending[tag]
+			delete(c.pending, tag)
+			c.pendingMu.Unlock()
+			if !ok {
+				continue
+			}
+
+			if command == cmdError {
+				ch <- pulseReply{err: &pulseError{Code: r.getU32()}}
+				continue
+			}
+			ch <- pulseReply{data: r}
+		case cmdSubscribeEvent:
+			select {
+			case c.events <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (c *pulseClient) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *pulseClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
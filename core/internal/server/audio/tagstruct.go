@@ -0,0 +1,343 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+const (
+	tagString     byte = 't'
+	tagStringNull byte = 'N'
+	tagU32        byte = 'L'
+	tagU8         byte = 'B'
+	tagU64        byte = 'R'
+	tagS64        byte = 'r'
+	tagSampleSpec byte = 'a'
+	tagArbitrary  byte = 'x'
+	tagBoolTrue   byte = '1'
+	tagBoolFalse  byte = '0'
+	tagTimeval    byte = 'T'
+	tagUsec       byte = 'U'
+	tagChannelMap byte = 'm'
+	tagCVolume    byte = 'v'
+	tagPropList   byte = 'P'
+	tagVolume     byte = 'V'
+	tagFormatInfo byte = 'f'
+)
+
+type sampleSpec struct {
+	format   uint8
+	channels uint8
+	rate     uint32
+}
+
+type tagWriter struct {
+	buf []byte
+}
+
+func (w *tagWriter) putU32(v uint32) {
+	w.buf = append(w.buf, tagU32)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, v)
+}
+
+func (w *tagWriter) putU8(v uint8) {
+	w.buf = append(w.buf, tagU8, v)
+}
+
+func (w *tagWriter) putUsec(v uint64) {
+	w.buf = append(w.buf, tagUsec)
+	w.buf = binary.BigEndian.AppendUint64(w.buf, v)
+}
+
+func (w *tagWriter) putString(s string) {
+	w.buf = append(w.buf, tagString)
+	w.buf = append(w.buf, s...)
+	w.buf = append(w.buf, 0)
+}
+
+func (w *tagWriter) putNullString() {
+	w.buf = append(w.buf, tagStringNull)
+}
+
+func (w *tagWriter) putBool(v bool) {
+	if v {
+		w.buf = append(w.buf, tagBoolTrue)
+		return
+	}
+	w.buf = append(w.buf, tagBoolFalse)
+}
+
+func (w *tagWriter) putArbitrary(data []byte) {
+	w.buf = append(w.buf, tagArbitrary)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(len(data)))
+	w.buf = append(w.buf, data...)
+}
+
+func (w *tagWriter) putSampleSpec(spec sampleSpec) {
+	w.buf = append(w.buf, tagSampleSpec, spec.format, spec.channels)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, spec.rate)
+}
+
+func (w *tagWriter) putChannelMap(channels []uint8) {
+	w.buf = append(w.buf, tagChannelMap, uint8(len(channels)))
+	w.buf = append(w.buf, channels...)
+}
+
+func (w *tagWriter) putCVolume(volumes []uint32) {
+	w.buf = append(w.buf, tagCVolume, uint8(len(volumes)))
+	for _, v := range volumes {
+		w.buf = binary.BigEndian.AppendUint32(w.buf, v)
+	}
+}
+
+func (w *tagWriter) putVolume(v uint32) {
+	w.buf = append(w.buf, tagVolume)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, v)
+}
+
+func (w *tagWriter) putPropList(props map[string]string) {
+	w.buf = append(w.buf, tagPropList)
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := append([]byte(props[k]), 0)
+		w.putString(k)
+		w.putU32(uint32(len(value)))
+		w.putArbitrary(value)
+	}
+	w.putNullString()
+}
+
+func (w *tagWriter) putFormatInfo(encoding uint8, props map[string]string) {
+	w.buf = append(w.buf, tagFormatInfo)
+	w.putU8(encoding)
+	w.putPropList(props)
+}
+
+type tagReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func newTagReader(buf []byte) *tagReader {
+	return &tagReader{buf: buf}
+}
+
+func (r *tagReader) eof() bool {
+	return r.err != nil || r.pos >= len(r.buf)
+}
+
+func (r *tagReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf("tagstruct: "+format, args...)
+	}
+}
+
+func (r *tagReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.buf) {
+		r.fail("short read at offset %d", r.pos)
+		return nil
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *tagReader) expect(tag byte) bool {
+	b := r.take(1)
+	if b == nil {
+		return false
+	}
+	if b[0] != tag {
+		r.fail("expected tag %q at offset %d, got %q", tag, r.pos-1, b[0])
+		return false
+	}
+	return true
+}
+
+func (r *tagReader) getU32() uint32 {
+	if !r.expect(tagU32) {
+		return 0
+	}
+	b := r.take(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *tagReader) getU8() uint8 {
+	if !r.expect(tagU8) {
+		return 0
+	}
+	b := r.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *tagReader) getUsec() uint64 {
+	if !r.expect(tagUsec) {
+		return 0
+	}
+	b := r.take(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *tagReader) getString() string {
+	b := r.take(1)
+	if b == nil {
+		return ""
+	}
+	switch b[0] {
+	case tagStringNull:
+		return ""
+	case tagString:
+	default:
+		r.fail("expected string tag at offset %d, got %q", r.pos-1, b[0])
+		return ""
+	}
+
+	for i := r.pos; i < len(r.buf); i++ {
+		if r.buf[i] == 0 {
+			s := string(r.buf[r.pos:i])
+			r.pos = i + 1
+			return s
+		}
+	}
+	r.fail("unterminated string at offset %d", r.pos)
+	return ""
+}
+
+func (r *tagReader) getBool() bool {
+	b := r.take(1)
+	if b == nil {
+		return false
+	}
+	switch b[0] {
+	case tagBoolTrue:
+		return true
+	case tagBoolFalse:
+		return false
+	default:
+		r.fail("expected boolean tag at offset %d, got %q", r.pos-1, b[0])
+		return false
+	}
+}
+
+func (r *tagReader) getArbitrary() []byte {
+	if !r.expect(tagArbitrary) {
+		return nil
+	}
+	lb := r.take(4)
+	if lb == nil {
+		return nil
+	}
+	return r.take(int(binary.BigEndian.Uint32(lb)))
+}
+
+func (r *tagReader) getSampleSpec() sampleSpec {
+	if !r.expect(tagSampleSpec) {
+		return sampleSpec{}
+	}
+	b := r.take(6)
+	if b == nil {
+		return sampleSpec{}
+	}
+	return sampleSpec{
+		format:   b[0],
+		channels: b[1],
+		rate:     binary.BigEndian.Uint32(b[2:]),
+	}
+}
+
+func (r *tagReader) getChannelMap() []uint8 {
+	if !r.expect(tagChannelMap) {
+		return nil
+	}
+	n := r.take(1)
+	if n == nil {
+		return nil
+	}
+	return r.take(int(n[0]))
+}
+
+func (r *tagReader) getCVolume() []uint32 {
+	if !r.expect(tagCVolume) {
+		return nil
+	}
+	n := r.take(1)
+	if n == nil {
+		return nil
+	}
+	volumes := make([]uint32, 0, n[0])
+	for i := 0; i < int(n[0]); i++ {
+		b := r.take(4)
+		if b == nil {
+			return nil
+		}
+		volumes = append(volumes, binary.BigEndian.Uint32(b))
+	}
+	return volumes
+}
+
+func (r *tagReader) getVolume() uint32 {
+	if !r.expect(tagVolume) {
+		return 0
+	}
+	b := r.take(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *tagReader) getPropList() map[string]string {
+	if !r.expect(tagPropList) {
+		return nil
+	}
+	props := make(map[string]string)
+	for r.err == nil {
+		if r.pos < len(r.buf) && r.buf[r.pos] == tagStringNull {
+			r.pos++
+			return props
+		}
+		key := r.getString()
+		length := r.getU32()
+		value := r.getArbitrary()
+		if r.err != nil {
+			return nil
+		}
+		if uint32(len(value)) != length {
+			r.fail("proplist length mismatch for %q", key)
+			return nil
+		}
+		if len(value) > 0 && value[len(value)-1] == 0 {
+			value = value[:len(value)-1]
+		}
+		props[key] = string(value)
+	}
+	return nil
+}
+
+func (r *tagReader) getFormatInfo() {
+	if !r.expect(tagFormatInfo) {
+		return
+	}
+	r.getU8()
+	r.getPropList()
+}
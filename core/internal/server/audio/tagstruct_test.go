@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagStruct_RoundTrip(t *testing.T) {
+	w := &tagWriter{}
+	w.putU32(42)
+	w.putU8(7)
+	w.putString("hello")
+	w.putNullString()
+	w.putBool(true)
+	w.putBool(false)
+	w.putArbitrary([]byte{1, 2, 3})
+	w.putSampleSpec(sampleSpec{format: 3, channels: 2, rate: 44100})
+	w.putChannelMap([]uint8{1, 2})
+	w.putCVolume([]uint32{volumeNorm, volumeNorm / 2})
+	w.putUsec(123456)
+	w.putVolume(volumeNorm)
+	w.putPropList(map[string]string{"application.name": "Test", "media.role": "music"})
+
+	r := newTagReader(w.buf)
+	assert.Equal(t, uint32(42), r.getU32())
+	assert.Equal(t, uint8(7), r.getU8())
+	assert.Equal(t, "hello", r.getString())
+	assert.Equal(t, "", r.getString())
+	assert.True(t, r.getBool())
+	assert.False(t, r.getBool())
+	assert.Equal(t, []byte{1, 2, 3}, r.getArbitrary())
+	assert.Equal(t, sampleSpec{format: 3, channels: 2, rate: 44100}, r.getSampleSpec())
+	assert.Equal(t, []uint8{1, 2}, r.getChannelMap())
+	assert.Equal(t, []uint32{volumeNorm, volumeNorm / 2}, r.getCVolume())
+	assert.Equal(t, uint64(123456), r.getUsec())
+	assert.Equal(t, volumeNorm, r.getVolume())
+	assert.Equal(t, map[string]string{"application.name": "Test", "media.role": "music"}, r.getPropList())
+
+	require.NoError(t, r.err)
+	assert.True(t, r.eof())
+}
+
+func TestTagStruct_WrongTag(t *testing.T) {
+	w := &tagWriter{}
+	w.putString("not a number")
+
+	r := newTagReader(w.buf)
+	assert.Equal(t, uint32(0), r.getU32())
+	assert.Error(t, r.err)
+	assert.True(t, r.eof())
+}
+
+func TestTagStruct_ShortRead(t *testing.T) {
+	r := newTagReader([]byte{tagU32, 0x00, 0x01})
+	r.getU32()
+	assert.Error(t, r.err)
+}
+
+func TestTagStruct_UnterminatedString(t *testing.T) {
+	r := newTagReader([]byte{tagString, 'a', 'b'})
+	r.getString()
+	assert.Error(t, r.err)
+}
+
+func TestTagStruct_ErrorIsSticky(t *testing.T) {
+	w := &tagWriter{}
+	w.putBool(true)
+	w.putU32(5)
+
+	r := newTagReader(w.buf)
+	r.getU32()
+	require.Error(t, r.err)
+	assert.Equal(t, uint32(0), r.getU32())
+}
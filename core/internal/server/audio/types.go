@@ -0,0 +1,194 @@
+package audio
+
+import "sync"
+
+type DeviceState string
+
+const (
+	DeviceStateRunning   DeviceState = "running"
+	DeviceStateIdle      DeviceState = "idle"
+	DeviceStateSuspended DeviceState = "suspended"
+	DeviceStateUnknown   DeviceState = "unknown"
+)
+
+type Port struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Priority    uint32 `json:"priority"`
+	Available   string `json:"available"`
+}
+
+type Device struct {
+	Index          uint32      `json:"index"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	Volume         int         `json:"volume"`
+	ChannelVolumes []int       `json:"channelVolumes"`
+	Muted          bool        `json:"muted"`
+	State          DeviceState `json:"state"`
+	IsDefault      bool        `json:"isDefault"`
+	MonitorOf      string      `json:"monitorOf,omitempty"`
+	FormFactor     string      `json:"formFactor,omitempty"`
+	Bus            string      `json:"bus,omitempty"`
+	IconName       string      `json:"iconName,omitempty"`
+	Ports          []Port      `json:"ports"`
+	ActivePort     string      `json:"activePort,omitempty"`
+}
+
+type Stream struct {
+	Index          uint32 `json:"index"`
+	Name           string `json:"name"`
+	AppName        string `json:"appName"`
+	AppBinary      string `json:"appBinary,omitempty"`
+	IconName       string `json:"iconName,omitempty"`
+	MediaRole      string `json:"mediaRole,omitempty"`
+	Device         string `json:"device"`
+	Volume         int    `json:"volume"`
+	ChannelVolumes []int  `json:"channelVolumes"`
+	Muted          bool   `json:"muted"`
+	Corked         bool   `json:"corked"`
+	HasVolume      bool   `json:"hasVolume"`
+}
+
+type State struct {
+	Available     bool     `json:"available"`
+	ServerName    string   `json:"serverName"`
+	ServerVersion string   `json:"serverVersion"`
+	DefaultSink   string   `json:"defaultSink"`
+	DefaultSource string   `json:"defaultSource"`
+	Sinks         []Device `json:"sinks"`
+	Sources       []Device `json:"sources"`
+	SinkInputs    []Stream `json:"sinkInputs"`
+	SourceOutputs []Stream `json:"sourceOutputs"`
+}
+
+type Manager struct {
+	socketPath   string
+	clientMutex  sync.RWMutex
+	client       *pulseClient
+	state        *State
+	stateMutex   sync.RWMutex
+	subscribers  map[string]chan State
+	subMutex     sync.RWMutex
+	stopChan     chan struct{}
+	dirty        chan struct{}
+	notifierWg   sync.WaitGroup
+	eventWg      sync.WaitGroup
+	lastNotified *State
+	closeOnce    sync.Once
+}
+
+func (m *Manager) GetState() State {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+	if m.state == nil {
+		return State{
+			Sinks:         []Device{},
+			Sources:       []Device{},
+			SinkInputs:    []Stream{},
+			SourceOutputs: []Stream{},
+		}
+	}
+	return *m.state
+}
+
+func (m *Manager) Subscribe(id string) chan State {
+	ch := make(chan State, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
+
+func (m *Manager) notifySubscribers() {
+	select {
+	case m.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func devicesChanged(old, new []Device) bool {
+	if len(old) != len(new) {
+		return true
+	}
+	for i := range new {
+		o, n := old[i], new[i]
+		if o.Index != n.Index || o.Name != n.Name || o.Description != n.Description {
+			return true
+		}
+		if o.Volume != n.Volume || o.Muted != n.Muted || o.State != n.State || o.IsDefault != n.IsDefault {
+			return true
+		}
+		if o.ActivePort != n.ActivePort || len(o.Ports) != len(n.Ports) {
+			return true
+		}
+		for j := range n.Ports {
+			if o.Ports[j] != n.Ports[j] {
+				return true
+			}
+		}
+		if !intsEqual(o.ChannelVolumes, n.ChannelVolumes) {
+			return true
+		}
+	}
+	return false
+}
+
+func streamsChanged(old, new []Stream) bool {
+	if len(old) != len(new) {
+		return true
+	}
+	for i := range new {
+		o, n := old[i], new[i]
+		if o.Index != n.Index || o.Name != n.Name || o.AppName != n.AppName || o.Device != n.Device {
+			return true
+		}
+		if o.Volume != n.Volume || o.Muted != n.Muted || o.Corked != n.Corked {
+			return true
+		}
+		if !intsEqual(o.ChannelVolumes, n.ChannelVolumes) {
+			return true
+		}
+	}
+	return false
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func stateChanged(old, new *State) bool {
+	if old == nil || new == nil {
+		return true
+	}
+	if old.Available != new.Available || old.ServerName != new.ServerName {
+		return true
+	}
+	if old.DefaultSink != new.DefaultSink || old.DefaultSource != new.DefaultSource {
+		return true
+	}
+	if devicesChanged(old.Sinks, new.Sinks) || devicesChanged(old.Sources, new.Sources) {
+		return true
+	}
+	if streamsChanged(old.SinkInputs, new.SinkInputs) || streamsChanged(old.SourceOutputs, new.SourceOutputs) {
+		return true
+	}
+	return false
+}
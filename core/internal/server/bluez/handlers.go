@@ -9,7 +9,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -81,7 +81,7 @@ func handleStopDiscovery(conn net.Conn, req Request, manager *Manager) {
 func handleSetPowered(conn net.Conn, req Request, manager *Manager) {
 	powered, ok := req.Params["powered"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'powered' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'powered' parameter"))
 		return
 	}
 
@@ -96,7 +96,7 @@ func handleSetPowered(conn net.Conn, req Request, manager *Manager) {
 func handlePairDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -111,7 +111,7 @@ func handlePairDevice(conn net.Conn, req Request, manager *Manager) {
 func handleConnectDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -126,7 +126,7 @@ func handleConnectDevice(conn net.Conn, req Request, manager *Manager) {
 func handleDisconnectDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -141,7 +141,7 @@ func handleDisconnectDevice(conn net.Conn, req Request, manager *Manager) {
 func handleRemoveDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -156,7 +156,7 @@ func handleRemoveDevice(conn net.Conn, req Request, manager *Manager) {
 func handleTrustDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -171,7 +171,7 @@ func handleTrustDevice(conn net.Conn, req Request, manager *Manager) {
 func handleUntrustDevice(conn net.Conn, req Request, manager *Manager) {
 	devicePath, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'device' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'device' parameter"))
 		return
 	}
 
@@ -186,7 +186,7 @@ func handleUntrustDevice(conn net.Conn, req Request, manager *Manager) {
 func handlePairingSubmit(conn net.Conn, req Request, manager *Manager) {
 	token, ok := req.Params["token"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'token' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'token' parameter"))
 		return
 	}
 
@@ -216,7 +216,7 @@ func handlePairingSubmit(conn net.Conn, req Request, manager *Manager) {
 func handlePairingCancel(conn net.Conn, req Request, manager *Manager) {
 	token, ok := req.Params["token"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'token' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'token' parameter"))
 		return
 	}
 
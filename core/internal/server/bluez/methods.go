@@ -0,0 +1,88 @@
+package bluez
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "bluetooth.getState",
+		Description: "Get current bluetooth state",
+	},
+	{
+		Name:        "bluetooth.startDiscovery",
+		Description: "Start device discovery",
+	},
+	{
+		Name:        "bluetooth.stopDiscovery",
+		Description: "Stop device discovery",
+	},
+	{
+		Name:        "bluetooth.setPowered",
+		Description: "Set adapter power state",
+		Params: []models.ParamSpec{
+			{Name: "powered", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.pair",
+		Description: "Pair with device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.connect",
+		Description: "Connect to device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.disconnect",
+		Description: "Disconnect from device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.remove",
+		Description: "Remove/unpair device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.trust",
+		Description: "Trust device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.untrust",
+		Description: "Untrust device",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.pairing.submit",
+		Description: "Submit pairing response",
+		Params: []models.ParamSpec{
+			{Name: "token", Type: models.ParamString, Required: true},
+			{Name: "secrets", Type: models.ParamObject},
+			{Name: "accept", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "bluetooth.pairing.cancel",
+		Description: "Cancel pairing prompt",
+		Params: []models.ParamSpec{
+			{Name: "token", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "bluetooth.subscribe",
+		Description: "Subscribe to bluetooth state changes",
+		Streaming:   true,
+	},
+}
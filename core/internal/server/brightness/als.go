@@ -0,0 +1,462 @@
+package brightness
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const autoBrightnessSaveDelay = 2 * time.Second
+
+type CurvePoint struct {
+	Lux     float64 `json:"lux"`
+	Percent int     `json:"percent"`
+}
+
+type AutoBrightnessConfig struct {
+	Enabled    bool          `json:"enabled"`
+	Devices    []string      `json:"devices,omitempty"`
+	Curve      []CurvePoint  `json:"curve"`
+	Hysteresis int           `json:"hysteresis"`
+	Smoothing  float64       `json:"smoothing"`
+	Interval   time.Duration `json:"-"`
+}
+
+type AutoBrightnessState struct {
+	Available bool                 `json:"available"`
+	Sensor    string               `json:"sensor,omitempty"`
+	Lux       float64              `json:"lux"`
+	Target    int                  `json:"target"`
+	Config    AutoBrightnessConfig `json:"config"`
+}
+
+func DefaultAutoBrightnessConfig() AutoBrightnessConfig {
+	return AutoBrightnessConfig{
+		Curve: []CurvePoint{
+			{Lux: 0, Percent: 5},
+			{Lux: 10, Percent: 20},
+			{Lux: 100, Percent: 40},
+			{Lux: 500, Percent: 65},
+			{Lux: 2000, Percent: 90},
+			{Lux: 10000, Percent: 100},
+		},
+		Hysteresis: 5,
+		Smoothing:  0.3,
+		Interval:   time.Second,
+	}
+}
+
+func DefaultAutoBrightnessPath() string {
+	return filepath.Join(config.DMSDir("XDG_CONFIG_HOME", ".config"), "auto-brightness.json")
+}
+
+// LoadAutoBrightnessConfig reads a saved config, including the learned
+// curve. A missing file yields the defaults, and fields absent from the file
+// keep their default values.
+func LoadAutoBrightnessConfig(path string) (AutoBrightnessConfig, error) {
+	cfg := DefaultAutoBrightnessConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DefaultAutoBrightnessConfig(), fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return DefaultAutoBrightnessConfig(), fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func SaveAutoBrightnessConfig(path string, cfg AutoBrightnessConfig) error {
+	return config.WriteJSON(path, cfg)
+}
+
+func (c *AutoBrightnessConfig) Validate() error {
+	if len(c.Curve) < 2 {
+		return fmt.Errorf("curve needs at least two points")
+	}
+	for i, p := range c.Curve {
+		if p.Lux < 0 || p.Percent < 0 || p.Percent > 100 {
+			return fmt.Errorf("invalid curve point: %v lux, %d%%", p.Lux, p.Percent)
+		}
+		if i > 0 && p.Lux <= c.Curve[i-1].Lux {
+			return fmt.Errorf("curve lux values must be increasing")
+		}
+	}
+	if c.Hysteresis < 0 || c.Hysteresis > 50 {
+		return fmt.Errorf("hysteresis out of range: %d", c.Hysteresis)
+	}
+	if c.Smoothing <= 0 || c.Smoothing > 1 {
+		return fmt.Errorf("smoothing must be in (0, 1]: %v", c.Smoothing)
+	}
+	return nil
+}
+
+// curvePercent maps lux onto the curve, interpolating on a log scale since
+// perceived brightness follows the logarithm of illuminance.
+func curvePercent(curve []CurvePoint, lux float64) int {
+	if len(curve) == 0 {
+		return 100
+	}
+	if lux <= curve[0].Lux {
+		return curve[0].Percent
+	}
+	last := curve[len(curve)-1]
+	if lux >= last.Lux {
+		return last.Percent
+	}
+
+	for i := 1; i < len(curve); i++ {
+		lo, hi := curve[i-1], curve[i]
+		if lux > hi.Lux {
+			continue
+		}
+		t := (math.Log1p(lux) - math.Log1p(lo.Lux)) / (math.Log1p(hi.Lux) - math.Log1p(lo.Lux))
+		return lo.Percent + int(math.Round(t*float64(hi.Percent-lo.Percent)))
+	}
+	return last.Percent
+}
+
+// learnCurve moves the point closest to lux (on a log scale) to percent and
+// clamps its neighbours so the curve stays monotonic.
+func learnCurve(curve []CurvePoint, lux float64, percent int) []CurvePoint {
+	learned := slices.Clone(curve)
+	if len(learned) == 0 {
+		return learned
+	}
+
+	nearest := 0
+	for i, p := range learned {
+		if math.Abs(math.Log1p(p.Lux)-math.Log1p(lux)) < math.Abs(math.Log1p(learned[nearest].Lux)-math.Log1p(lux)) {
+			nearest = i
+		}
+	}
+	learned[nearest].Percent = percent
+
+	for i := nearest - 1; i >= 0; i-- {
+		learned[i].Percent = min(learned[i].Percent, learned[i+1].Percent)
+	}
+	for i := nearest + 1; i < len(learned); i++ {
+		learned[i].Percent = max(learned[i].Percent, learned[i-1].Percent)
+	}
+	return learned
+}
+
+type alsSensor struct {
+	path string
+	name string
+}
+
+// findALSSensor returns the first IIO device under basePath that reports
+// illuminance.
+func findALSSensor(basePath string) (*alsSensor, error) {
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		devicePath := filepath.Join(basePath, entry.Name())
+		for _, file := range []string{"in_illuminance_raw", "in_illuminance_input"} {
+			if _, err := os.Stat(filepath.Join(devicePath, file)); err == nil {
+				name := entry.Name()
+				if data, err := os.ReadFile(filepath.Join(devicePath, "name")); err == nil {
+					name = strings.TrimSpace(string(data))
+				}
+				return &alsSensor{path: devicePath, name: name}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no illuminance sensor in %s", basePath)
+}
+
+func (s *alsSensor) readLux() (float64, error) {
+	if value, err := readFloat(filepath.Join(s.path, "in_illuminance_input")); err == nil {
+		return value, nil
+	}
+
+	raw, err := readFloat(filepath.Join(s.path, "in_illuminance_raw"))
+	if err != nil {
+		return 0, err
+	}
+
+	scale := 1.0
+	if v, err := readFloat(filepath.Join(s.path, "in_illuminance_scale")); err == nil {
+		scale = v
+	}
+	offset := 0.0
+	if v, err := readFloat(filepath.Join(s.path, "in_illuminance_offset")); err == nil {
+		offset = v
+	}
+
+	return math.Max(0, (raw+offset)*scale), nil
+}
+
+func readFloat(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+}
+
+type autoBrightness struct {
+	mutex       sync.Mutex
+	config      AutoBrightnessConfig
+	sensor      *alsSensor
+	smoothedLux float64
+	hasLux      bool
+	lastTarget  int
+	stop        chan struct{}
+	configPath  string
+	saveTimer   *time.Timer
+}
+
+func newAutoBrightness(sensor *alsSensor) *autoBrightness {
+	return &autoBrightness{
+		config:     DefaultAutoBrightnessConfig(),
+		sensor:     sensor,
+		lastTarget: -1,
+	}
+}
+
+// step feeds a lux sample through the smoothing filter and returns the curve
+// target, and whether it moved far enough from the last one to apply.
+func (a *autoBrightness) step(lux float64) (int, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.hasLux {
+		a.smoothedLux = lux
+		a.hasLux = true
+	} else {
+		a.smoothedLux += a.config.Smoothing * (lux - a.smoothedLux)
+	}
+
+	target := curvePercent(a.config.Curve, a.smoothedLux)
+	if a.lastTarget >= 0 && abs(target-a.lastTarget) < a.config.Hysteresis {
+		return a.lastTarget, false
+	}
+	a.lastTarget = target
+	return target, true
+}
+
+func (a *autoBrightness) learn(percent int) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.config.Enabled || !a.hasLux {
+		return
+	}
+	a.config.Curve = learnCurve(a.config.Curve, a.smoothedLux, percent)
+	a.lastTarget = percent
+	log.Debugf("Auto brightness learned %d%% at %.0f lux", percent, a.smoothedLux)
+	a.scheduleSaveLocked()
+}
+
+// scheduleSaveLocked saves the config shortly after the last change, since
+// learning runs on every manual brightness change. a.mutex must be held.
+func (a *autoBrightness) scheduleSaveLocked() {
+	if a.configPath == "" {
+		return
+	}
+	if a.saveTimer != nil {
+		a.saveTimer.Reset(autoBrightnessSaveDelay)
+		return
+	}
+	a.saveTimer = time.AfterFunc(autoBrightnessSaveDelay, a.save)
+}
+
+func (a *autoBrightness) save() {
+	a.mutex.Lock()
+	path := a.configPath
+	cfg := a.config
+	cfg.Curve = slices.Clone(a.config.Curve)
+	a.mutex.Unlock()
+
+	if err := SaveAutoBrightnessConfig(path, cfg); err != nil {
+		log.Warnf("Failed to save auto brightness config: %v", err)
+	}
+}
+
+func (a *autoBrightness) status() AutoBrightnessState {
+	if a == nil {
+		return AutoBrightnessState{}
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	config := a.config
+	config.Curve = slices.Clone(a.config.Curve)
+	state := AutoBrightnessState{
+		Available: a.sensor != nil,
+		Lux:       math.Round(a.smoothedLux),
+		Target:    max(a.lastTarget, 0),
+		Config:    config,
+	}
+	if a.sensor != nil {
+		state.Sensor = a.sensor.name
+	}
+	return state
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func (m *Manager) initAutoBrightness() {
+	sensor, err := findALSSensor("/sys/bus/iio/devices")
+	if err != nil {
+		log.Debugf("No ambient light sensor: %v", err)
+	} else {
+		log.Infof("Ambient light sensor found: %s", sensor.name)
+	}
+	m.auto = newAutoBrightness(sensor)
+}
+
+// SetAutoBrightnessConfigPath makes later config changes, including learned
+// curve points, get saved to path.
+func (m *Manager) SetAutoBrightnessConfigPath(path string) {
+	if m.auto == nil {
+		return
+	}
+	m.auto.mutex.Lock()
+	m.auto.configPath = path
+	m.auto.mutex.Unlock()
+}
+
+func (m *Manager) GetAutoBrightness() AutoBrightnessState {
+	return m.auto.status()
+}
+
+// SetAutoBrightness replaces the auto brightness config, starting or stopping
+// the sensor loop as needed.
+func (m *Manager) SetAutoBrightness(config AutoBrightnessConfig) error {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	a := m.auto
+	if a == nil {
+		return fmt.Errorf("auto brightness not initialized")
+	}
+	a.mutex.Lock()
+	if config.Enabled && a.sensor == nil {
+		a.mutex.Unlock()
+		return fmt.Errorf("no ambient light sensor available")
+	}
+
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+	a.config = config
+	a.hasLux = false
+	a.lastTarget = -1
+
+	if config.Enabled {
+		a.stop = make(chan struct{})
+		go m.autoBrightnessLoop(a.stop, config.Interval)
+	}
+	a.scheduleSaveLocked()
+	a.mutex.Unlock()
+
+	m.updateState()
+	return nil
+}
+
+func (m *Manager) autoBrightnessLoop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.autoBrightnessTick()
+
+		select {
+		case <-stop:
+			return
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) autoBrightnessTick() {
+	lux, err := m.auto.sensor.readLux()
+	if err != nil {
+		log.Debugf("Failed to read ambient light: %v", err)
+		return
+	}
+
+	target, apply := m.auto.step(lux)
+	if !apply {
+		return
+	}
+
+	for _, deviceID := range m.autoBrightnessDevices() {
+		if !m.cancelAutoFade(deviceID) {
+			continue
+		}
+		exponential, exponent := m.deviceCurve(deviceID)
+		if err := m.startFade(deviceID, target, exponential, exponent, autoBrightnessFadeDuration, true); err != nil {
+			log.Debugf("Auto brightness failed for %s: %v", deviceID, err)
+		}
+	}
+}
+
+// autoBrightnessDevices returns the configured devices, or every backlight
+// and DDC monitor when none are configured.
+func (m *Manager) autoBrightnessDevices() []string {
+	if m.auto == nil {
+		return nil
+	}
+	m.auto.mutex.Lock()
+	enabled := m.auto.config.Enabled
+	configured := slices.Clone(m.auto.config.Devices)
+	m.auto.mutex.Unlock()
+
+	if !enabled {
+		return nil
+	}
+	if len(configured) > 0 {
+		return configured
+	}
+
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+
+	var devices []string
+	for _, dev := range m.state.Devices {
+		if dev.Class == ClassBacklight || dev.Class == ClassDDC {
+			devices = append(devices, dev.ID)
+		}
+	}
+	return devices
+}
@@ -0,0 +1,189 @@
+package brightness
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindALSSensor(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	accelDir := filepath.Join(tmpDir, "iio:device0")
+	if err := os.MkdirAll(accelDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(accelDir, "in_accel_x_raw"), []byte("12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	alsDir := filepath.Join(tmpDir, "iio:device1")
+	if err := os.MkdirAll(alsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"name":                  "als\n",
+		"in_illuminance_raw":    "200\n",
+		"in_illuminance_scale":  "0.5\n",
+		"in_illuminance_offset": "10\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(alsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sensor, err := findALSSensor(tmpDir)
+	if err != nil {
+		t.Fatalf("findALSSensor() error = %v", err)
+	}
+	if sensor.name != "als" {
+		t.Errorf("sensor name = %q, want %q", sensor.name, "als")
+	}
+
+	lux, err := sensor.readLux()
+	if err != nil {
+		t.Fatalf("readLux() error = %v", err)
+	}
+	if lux != 105 {
+		t.Errorf("readLux() = %v, want 105", lux)
+	}
+
+	if _, err := findALSSensor(accelDir); err == nil {
+		t.Error("expected error when no illuminance sensor exists")
+	}
+}
+
+func TestCurvePercent(t *testing.T) {
+	curve := []CurvePoint{
+		{Lux: 0, Percent: 10},
+		{Lux: 100, Percent: 50},
+		{Lux: 1000, Percent: 100},
+	}
+
+	tests := []struct {
+		lux  float64
+		want int
+	}{
+		{0, 10},
+		{100, 50},
+		{5000, 100},
+	}
+	for _, tt := range tests {
+		if got := curvePercent(curve, tt.lux); got != tt.want {
+			t.Errorf("curvePercent(%v) = %d, want %d", tt.lux, got, tt.want)
+		}
+	}
+
+	mid := curvePercent(curve, 300)
+	if mid <= 50 || mid >= 100 {
+		t.Errorf("curvePercent(300) = %d, want between 50 and 100", mid)
+	}
+}
+
+func TestLearnCurveStaysMonotonic(t *testing.T) {
+	curve := []CurvePoint{
+		{Lux: 0, Percent: 10},
+		{Lux: 100, Percent: 50},
+		{Lux: 1000, Percent: 80},
+		{Lux: 10000, Percent: 100},
+	}
+
+	learned := learnCurve(curve, 120, 90)
+
+	if learned[1].Percent != 90 {
+		t.Errorf("learned point = %d, want 90", learned[1].Percent)
+	}
+	for i := 1; i < len(learned); i++ {
+		if learned[i].Percent < learned[i-1].Percent {
+			t.Errorf("curve not monotonic: %+v", learned)
+		}
+	}
+	if curve[1].Percent != 50 {
+		t.Error("learnCurve modified its input")
+	}
+}
+
+func TestAutoBrightnessStepHysteresis(t *testing.T) {
+	a := newAutoBrightness(nil)
+	a.config.Curve = []CurvePoint{{Lux: 0, Percent: 0}, {Lux: 1000, Percent: 100}}
+	a.config.Hysteresis = 5
+	a.config.Smoothing = 1
+
+	target, apply := a.step(100)
+	if !apply {
+		t.Fatal("first sample should apply")
+	}
+
+	if _, apply := a.step(105); apply {
+		t.Error("small change should be held back by hysteresis")
+	}
+
+	next, apply := a.step(1000)
+	if !apply || next != 100 || next == target {
+		t.Errorf("step(1000) = %d, %v, want 100, true", next, apply)
+	}
+}
+
+func TestAutoBrightnessStepSmoothing(t *testing.T) {
+	a := newAutoBrightness(nil)
+	a.config.Curve = []CurvePoint{{Lux: 0, Percent: 0}, {Lux: 1000, Percent: 100}}
+	a.config.Hysteresis = 0
+	a.config.Smoothing = 0.5
+
+	a.step(0)
+	a.step(1000)
+	if a.smoothedLux != 500 {
+		t.Errorf("smoothedLux = %v, want 500", a.smoothedLux)
+	}
+}
+
+func TestAutoBrightnessConfigValidate(t *testing.T) {
+	config := DefaultAutoBrightnessConfig()
+	if err := config.Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+
+	config.Curve = []CurvePoint{{Lux: 100, Percent: 10}, {Lux: 50, Percent: 20}}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for decreasing lux")
+	}
+}
+
+func TestAutoBrightnessConfigPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "DankMaterialShell", "auto-brightness.json")
+
+	config, err := LoadAutoBrightnessConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAutoBrightnessConfig() missing file error = %v", err)
+	}
+	if len(config.Curve) != len(DefaultAutoBrightnessConfig().Curve) {
+		t.Error("missing file did not yield the default config")
+	}
+
+	config.Enabled = true
+	config.Curve = learnCurve(config.Curve, 300, 80)
+	if err := SaveAutoBrightnessConfig(path, config); err != nil {
+		t.Fatalf("SaveAutoBrightnessConfig() error = %v", err)
+	}
+
+	loaded, err := LoadAutoBrightnessConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAutoBrightnessConfig() error = %v", err)
+	}
+	if !loaded.Enabled || loaded.Interval != time.Second {
+		t.Errorf("loaded config = %+v", loaded)
+	}
+	if !slices.Equal(loaded.Curve, config.Curve) {
+		t.Errorf("learned point not persisted: %v", loaded.Curve)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"curve":[{"lux":10,"percent":5},{"lux":1,"percent":6}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAutoBrightnessConfig(path); err == nil {
+		t.Error("expected error for an invalid curve")
+	}
+}
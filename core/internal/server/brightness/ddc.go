@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -27,7 +30,7 @@ func NewDDCBackend() (*DDCBackend, error) {
 		devices:         make(map[string]*ddcDevice),
 		scanInterval:    30 * time.Second,
 		debounceTimers:  make(map[string]*time.Timer),
-		debouncePending: make(map[string]ddcPendingSet),
+		debouncePending: make(map[string][]ddcPendingSet),
 	}
 
 	if err := b.scanI2CDevices(); err != nil {
@@ -53,10 +56,7 @@ func (b *DDCBackend) scanI2CDevices() error {
 		return nil
 	}
 
-	b.devicesMutex.Lock()
-	defer b.devicesMutex.Unlock()
-
-	b.devices = make(map[string]*ddcDevice)
+	found := make(map[string]*ddcDevice)
 
 	for i := 0; i < 32; i++ {
 		busPath := fmt.Sprintf("/dev/i2c-%d", i)
@@ -77,18 +77,98 @@ func (b *DDCBackend) scanI2CDevices() error {
 
 		id := fmt.Sprintf("ddc:i2c-%d", i)
 		dev.id = id
-		b.devices[id] = dev
+		found[id] = dev
 		log.Debugf("found DDC device on i2c-%d", i)
 	}
 
+	b.devicesMutex.Lock()
+	b.mergeDevices(found, ddcBusConnected)
+	b.devicesMutex.Unlock()
+
 	b.lastScan = time.Now()
 
 	return nil
 }
 
+// mergeDevices folds a scan into the known devices, keeping their cached
+// capabilities. Monitors in standby often ignore DDC/CI, so a known device
+// that did not answer is only dropped once its bus is disconnected.
+// b.devicesMutex must be held.
+func (b *DDCBackend) mergeDevices(found map[string]*ddcDevice, connected func(bus int) bool) {
+	for id, dev := range b.devices {
+		probed, ok := found[id]
+		switch {
+		case ok:
+			dev.name = probed.name
+			if probed.max > 0 {
+				dev.max = probed.max
+				dev.lastBrightness = probed.lastBrightness
+			}
+		case !connected(dev.bus):
+			log.Debugf("DDC device on i2c-%d disconnected", dev.bus)
+			delete(b.devices, id)
+		}
+	}
+
+	for id, dev := range found {
+		if _, ok := b.devices[id]; !ok {
+			b.devices[id] = dev
+		}
+	}
+}
+
+// ddcBusConnected reports whether an i2c bus still exists and, when it
+// belongs to a drm connector, whether that connector is connected.
+func ddcBusConnected(bus int) bool {
+	if _, err := os.Stat(fmt.Sprintf("/dev/i2c-%d", bus)); err != nil {
+		return false
+	}
+
+	adapter, err := filepath.EvalSymlinks(fmt.Sprintf("/sys/class/i2c-adapter/i2c-%d", bus))
+	if err != nil {
+		return true
+	}
+	status, err := os.ReadFile(filepath.Join(filepath.Dir(adapter), "status"))
+	if err != nil {
+		return true
+	}
+	return strings.TrimSpace(string(status)) != "disconnected"
+}
+
+func (b *DDCBackend) forceRescan() {
+	b.scanMutex.Lock()
+	b.lastScan = time.Time{}
+	b.scanMutex.Unlock()
+
+	if err := b.scanI2CDevices(); err != nil {
+		log.Debugf("DDC rescan error: %v", err)
+	}
+}
+
+// busLock returns the mutex serializing all traffic on an i2c bus. Monitors
+// drop or garble replies when transactions interleave.
+func (b *DDCBackend) busLock(bus int) *sync.Mutex {
+	b.busLocksMutex.Lock()
+	defer b.busLocksMutex.Unlock()
+
+	if b.busLocks == nil {
+		b.busLocks = make(map[int]*sync.Mutex)
+	}
+	lock, ok := b.busLocks[bus]
+	if !ok {
+		lock = &sync.Mutex{}
+		b.busLocks[bus] = lock
+	}
+	return lock
+}
+
 func (b *DDCBackend) probeDDCDevice(bus int) (*ddcDevice, error) {
 	busPath := fmt.Sprintf("/dev/i2c-%d", bus)
 
+	lock := b.busLock(bus)
+	lock.Lock()
+	defer lock.Unlock()
+
 	fd, err := syscall.Open(busPath, syscall.O_RDWR, 0)
 	if err != nil {
 		return nil, err
@@ -201,44 +281,106 @@ func (b *DDCBackend) SetBrightnessWithExponent(id string, value int, exponential
 		return fmt.Errorf("value out of range: %d", value)
 	}
 
+	b.queueVCPSet(id, VCP_BRIGHTNESS, value, callback)
+	return nil
+}
+
+// queueVCPSet debounces writes per device. Each device has a single timer
+// and a single queue, so a burst of changes only sends the last value of each
+// feature, and the writes for one monitor never run concurrently.
+func (b *DDCBackend) queueVCPSet(id string, vcp byte, value int, callback func()) {
 	b.debounceMutex.Lock()
 	defer b.debounceMutex.Unlock()
 
-	b.debouncePending[id] = ddcPendingSet{
-		percent:  value,
-		callback: callback,
+	set := ddcPendingSet{vcp: vcp, value: value, callback: callback}
+	pending := b.debouncePending[id]
+	if i := slices.IndexFunc(pending, func(p ddcPendingSet) bool { return p.vcp == vcp }); i >= 0 {
+		pending[i] = set
+	} else {
+		pending = append(pending, set)
 	}
+	b.debouncePending[id] = pending
 
 	if timer, exists := b.debounceTimers[id]; exists {
 		timer.Reset(200 * time.Millisecond)
-	} else {
-		b.debounceTimers[id] = time.AfterFunc(200*time.Millisecond, func() {
-			b.debounceMutex.Lock()
-			pending, exists := b.debouncePending[id]
-			if exists {
-				delete(b.debouncePending, id)
-			}
-			b.debounceMutex.Unlock()
+		return
+	}
 
-			if !exists {
-				return
-			}
+	b.debounceTimers[id] = time.AfterFunc(200*time.Millisecond, func() {
+		b.flushVCPSets(id)
+	})
+}
 
-			err := b.setBrightnessImmediateWithExponent(id, pending.percent)
-			if err != nil {
-				log.Debugf("Failed to set brightness for %s: %v", id, err)
-			}
+// flushVCPSets writes every queued feature of a device. The bus lock is taken
+// before the queue is read, so a later flush cannot overtake this one.
+// Callbacks run after the lock is released, since they may rescan the bus.
+func (b *DDCBackend) flushVCPSets(id string) {
+	for _, p := range b.writeQueuedVCPSets(id) {
+		if p.callback != nil {
+			p.callback()
+		}
+	}
+}
 
-			if pending.callback != nil {
-				pending.callback()
-			}
-		})
+func (b *DDCBackend) writeQueuedVCPSets(id string) []ddcPendingSet {
+	b.devicesMutex.RLock()
+	dev, ok := b.devices[id]
+	b.devicesMutex.RUnlock()
+
+	if ok {
+		lock := b.busLock(dev.bus)
+		lock.Lock()
+		defer lock.Unlock()
 	}
 
-	return nil
+	b.debounceMutex.Lock()
+	pending := b.debouncePending[id]
+	delete(b.debouncePending, id)
+	b.debounceMutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var fd int
+	var err error
+	if ok {
+		fd, err = b.openBus(dev)
+	} else {
+		err = fmt.Errorf("device not found: %s", id)
+	}
+	if err == nil {
+		defer syscall.Close(fd)
+	}
+
+	for _, p := range pending {
+		setErr := err
+		if setErr == nil {
+			setErr = b.writeVCP(fd, dev, p.vcp, p.value)
+		}
+		if setErr != nil {
+			log.Debugf("Failed to set VCP 0x%02x for %s: %v", p.vcp, id, setErr)
+		}
+	}
+	return pending
 }
 
-func (b *DDCBackend) setBrightnessImmediateWithExponent(id string, value int) error {
+// dropVCPSet discards a queued write of one feature, e.g. when a fade takes
+// over the device's brightness.
+func (b *DDCBackend) dropVCPSet(id string, vcp byte) {
+	b.debounceMutex.Lock()
+	defer b.debounceMutex.Unlock()
+
+	pending := slices.DeleteFunc(b.debouncePending[id], func(p ddcPendingSet) bool { return p.vcp == vcp })
+	if len(pending) == 0 {
+		delete(b.debouncePending, id)
+		return
+	}
+	b.debouncePending[id] = pending
+}
+
+// withDevice runs fn with the device's bus open and locked.
+func (b *DDCBackend) withDevice(id string, fn func(fd int, dev *ddcDevice) error) error {
 	b.devicesMutex.RLock()
 	dev, ok := b.devices[id]
 	b.devicesMutex.RUnlock()
@@ -247,35 +389,58 @@ func (b *DDCBackend) setBrightnessImmediateWithExponent(id string, value int) er
 		return fmt.Errorf("device not found: %s", id)
 	}
 
-	busPath := fmt.Sprintf("/dev/i2c-%d", dev.bus)
+	lock := b.busLock(dev.bus)
+	lock.Lock()
+	defer lock.Unlock()
 
-	fd, err := syscall.Open(busPath, syscall.O_RDWR, 0)
+	fd, err := b.openBus(dev)
 	if err != nil {
-		return fmt.Errorf("open i2c device: %w", err)
+		return err
 	}
 	defer syscall.Close(fd)
 
+	return fn(fd, dev)
+}
+
+func (b *DDCBackend) openBus(dev *ddcDevice) (int, error) {
+	fd, err := syscall.Open(fmt.Sprintf("/dev/i2c-%d", dev.bus), syscall.O_RDWR, 0)
+	if err != nil {
+		return -1, fmt.Errorf("open i2c device: %w", err)
+	}
+
 	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), I2C_SLAVE, uintptr(dev.addr)); errno != 0 {
-		return fmt.Errorf("set i2c slave addr: %w", errno)
+		syscall.Close(fd)
+		return -1, fmt.Errorf("set i2c slave addr: %w", errno)
 	}
 
+	return fd, nil
+}
+
+func (b *DDCBackend) writeVCP(fd int, dev *ddcDevice, vcp byte, value int) error {
+	if vcp != VCP_BRIGHTNESS {
+		if err := b.setVCPFeature(fd, vcp, value); err != nil {
+			return fmt.Errorf("set vcp feature: %w", err)
+		}
+		return nil
+	}
+
+	b.devicesMutex.RLock()
 	max := dev.max
+	b.devicesMutex.RUnlock()
+
 	if max == 0 {
 		cap, err := b.getVCPFeature(fd, VCP_BRIGHTNESS)
 		if err != nil {
 			return fmt.Errorf("get current capability: %w", err)
 		}
 		max = cap.max
-		b.devicesMutex.Lock()
-		dev.max = max
-		b.devicesMutex.Unlock()
 	}
 
 	if err := b.setVCPFeature(fd, VCP_BRIGHTNESS, value); err != nil {
 		return fmt.Errorf("set vcp feature: %w", err)
 	}
 
-	log.Debugf("set %s to %d/%d", id, value, max)
+	log.Debugf("set %s to %d/%d", dev.id, value, max)
 
 	b.devicesMutex.Lock()
 	dev.max = max
@@ -285,6 +450,16 @@ func (b *DDCBackend) setBrightnessImmediateWithExponent(id string, value int) er
 	return nil
 }
 
+// setBrightnessImmediateWithExponent writes brightness without waiting for
+// the debounce. It holds the same bus lock as the queue and drops any queued
+// brightness write, which would otherwise land after this one.
+func (b *DDCBackend) setBrightnessImmediateWithExponent(id string, value int) error {
+	return b.withDevice(id, func(fd int, dev *ddcDevice) error {
+		b.dropVCPSet(id, VCP_BRIGHTNESS)
+		return b.writeVCP(fd, dev, VCP_BRIGHTNESS, value)
+	})
+}
+
 func (b *DDCBackend) getVCPFeature(fd int, vcp byte) (*ddcCapability, error) {
 	for flushTry := 0; flushTry < 3; flushTry++ {
 		dummy := make([]byte, 32)
@@ -0,0 +1,310 @@
+package brightness
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DDCCI_CAPS_REQUEST = 0xF3
+	DDCCI_CAPS_REPLY   = 0xE3
+
+	VCP_COLOR_PRESET = 0x14
+	VCP_CONTRAST     = 0x12
+	VCP_INPUT_SOURCE = 0x60
+	VCP_AUDIO_VOLUME = 0x62
+	VCP_AUDIO_MUTE   = 0x8D
+	VCP_POWER_MODE   = 0xD6
+)
+
+type DDCFeature struct {
+	VCP    int    `json:"vcp"`
+	Name   string `json:"name,omitempty"`
+	Values []int  `json:"values,omitempty"`
+}
+
+type DDCCapabilities struct {
+	Device   string       `json:"device"`
+	Model    string       `json:"model,omitempty"`
+	Raw      string       `json:"raw"`
+	Features []DDCFeature `json:"features"`
+}
+
+type DDCFeatureValue struct {
+	Device    string `json:"device"`
+	VCP       int    `json:"vcp"`
+	Feature   string `json:"feature,omitempty"`
+	Current   int    `json:"current"`
+	Max       int    `json:"max"`
+	ValueName string `json:"valueName,omitempty"`
+}
+
+var ddcFeatureNames = map[byte]string{
+	VCP_BRIGHTNESS:   "brightness",
+	VCP_CONTRAST:     "contrast",
+	VCP_COLOR_PRESET: "colorPreset",
+	VCP_INPUT_SOURCE: "input",
+	VCP_AUDIO_VOLUME: "volume",
+	VCP_AUDIO_MUTE:   "mute",
+	VCP_POWER_MODE:   "power",
+}
+
+// ddcValueNames holds MCCS names for the non-continuous features we expose.
+var ddcValueNames = map[byte]map[int]string{
+	VCP_INPUT_SOURCE: {
+		0x01: "VGA-1", 0x02: "VGA-2", 0x03: "DVI-1", 0x04: "DVI-2",
+		0x05: "Composite-1", 0x06: "Composite-2", 0x07: "S-Video-1", 0x08: "S-Video-2",
+		0x09: "Tuner-1", 0x0A: "Tuner-2", 0x0B: "Tuner-3", 0x0C: "Component-1",
+		0x0D: "Component-2", 0x0E: "Component-3", 0x0F: "DisplayPort-1", 0x10: "DisplayPort-2",
+		0x11: "HDMI-1", 0x12: "HDMI-2", 0x1B: "USB-C",
+	},
+	VCP_COLOR_PRESET: {
+		0x01: "sRGB", 0x02: "native", 0x03: "4000K", 0x04: "5000K", 0x05: "6500K",
+		0x06: "7500K", 0x07: "8200K", 0x08: "9300K", 0x09: "10000K", 0x0A: "11500K",
+		0x0B: "user-1", 0x0C: "user-2", 0x0D: "user-3",
+	},
+	VCP_AUDIO_MUTE: {0x01: "muted", 0x02: "unmuted"},
+	VCP_POWER_MODE: {0x01: "on", 0x02: "standby", 0x03: "suspend", 0x04: "off", 0x05: "off-button"},
+}
+
+// ParseDDCFeature accepts a feature name such as "input" or a VCP code in
+// hex ("0x60") or decimal.
+func ParseDDCFeature(feature string) (byte, error) {
+	for vcp, name := range ddcFeatureNames {
+		if strings.EqualFold(name, feature) {
+			return vcp, nil
+		}
+	}
+
+	code, err := strconv.ParseUint(feature, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown DDC feature: %s", feature)
+	}
+	return byte(code), nil
+}
+
+// ParseDDCValue accepts a value name for the feature, e.g. "HDMI-1" for the
+// input source, or a number.
+func ParseDDCValue(vcp byte, value string) (int, error) {
+	for code, name := range ddcValueNames[vcp] {
+		if strings.EqualFold(name, value) {
+			return code, nil
+		}
+	}
+
+	v, err := strconv.ParseInt(value, 0, 32)
+	if err != nil || v < 0 || v > 0xFFFF {
+		return 0, fmt.Errorf("invalid value for VCP 0x%02x: %s", vcp, value)
+	}
+	return int(v), nil
+}
+
+// parseCapabilities extracts the model and the vcp(...) section from an MCCS
+// capabilities string, e.g. "(prot(monitor)model(U2720Q)vcp(10 12 60(0F 11)))".
+func parseCapabilities(raw string) (string, []DDCFeature) {
+	model := strings.TrimSpace(capabilitiesSection(raw, "model"))
+	vcp := capabilitiesSection(raw, "vcp")
+
+	var features []DDCFeature
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(vcp))
+	for i := 0; i < len(tokens); i++ {
+		code, err := strconv.ParseUint(tokens[i], 16, 8)
+		if err != nil {
+			continue
+		}
+
+		feature := DDCFeature{VCP: int(code), Name: ddcFeatureNames[byte(code)]}
+		if i+1 < len(tokens) && tokens[i+1] == "(" {
+			i += 2
+			for ; i < len(tokens) && tokens[i] != ")"; i++ {
+				if v, err := strconv.ParseUint(tokens[i], 16, 16); err == nil {
+					feature.Values = append(feature.Values, int(v))
+				}
+			}
+		}
+		features = append(features, feature)
+	}
+
+	return model, features
+}
+
+// capabilitiesSection returns the balanced-parenthesis body following key.
+func capabilitiesSection(raw, key string) string {
+	lower := strings.ToLower(raw)
+	start := strings.Index(lower, key+"(")
+	if start < 0 {
+		return ""
+	}
+	start += len(key) + 1
+
+	depth := 1
+	for i := start; i < len(raw); i++ {
+		switch raw[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return raw[start:i]
+			}
+		}
+	}
+	return raw[start:]
+}
+
+func (b *DDCBackend) GetCapabilities(id string) (*DDCCapabilities, error) {
+	b.devicesMutex.RLock()
+	dev, ok := b.devices[id]
+	var cached *DDCCapabilities
+	if ok {
+		cached = dev.capabilities
+	}
+	b.devicesMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("device not found: %s", id)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	var caps *DDCCapabilities
+	err := b.withDevice(id, func(fd int, dev *ddcDevice) error {
+		raw, err := b.readCapabilities(fd)
+		if err != nil {
+			return err
+		}
+
+		model, features := parseCapabilities(raw)
+		caps = &DDCCapabilities{Device: id, Model: model, Raw: raw, Features: features}
+
+		b.devicesMutex.Lock()
+		dev.capabilities = caps
+		b.devicesMutex.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return caps, nil
+}
+
+// readCapabilities fetches the capabilities string in 32-byte fragments until
+// the monitor returns an empty one.
+func (b *DDCBackend) readCapabilities(fd int) (string, error) {
+	var caps []byte
+
+	for offset := 0; offset < 4096; {
+		var fragment []byte
+		var err error
+		for attempt := 0; attempt < 3; attempt++ {
+			fragment, err = b.readCapabilitiesFragment(fd, offset)
+			if err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err != nil {
+			return "", fmt.Errorf("read capabilities at offset %d: %w", offset, err)
+		}
+		if len(fragment) == 0 {
+			break
+		}
+
+		caps = append(caps, fragment...)
+		offset += len(fragment)
+	}
+
+	return strings.TrimRight(string(caps), "\x00"), nil
+}
+
+func (b *DDCBackend) readCapabilitiesFragment(fd int, offset int) ([]byte, error) {
+	payload := []byte{
+		DDC_SOURCE_ADDR,
+		0x83,
+		DDCCI_CAPS_REQUEST,
+		byte(offset >> 8),
+		byte(offset & 0xFF),
+	}
+	payload = append(payload, ddcciChecksum(payload))
+
+	n, err := syscall.Write(fd, payload)
+	if err != nil || n != len(payload) {
+		return nil, fmt.Errorf("write i2c: %w", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	response := make([]byte, 40)
+	n, err = syscall.Read(fd, response)
+	if err != nil || n < 6 {
+		return nil, fmt.Errorf("read i2c: %w", err)
+	}
+
+	length := int(response[1] & 0x7F)
+	if response[0] != 0x6E || response[2] != DDCCI_CAPS_REPLY || length < 3 || length+2 > n {
+		return nil, fmt.Errorf("invalid capabilities response")
+	}
+
+	replyOffset := int(response[3])<<8 | int(response[4])
+	if replyOffset != offset {
+		return nil, fmt.Errorf("capabilities offset mismatch: wanted %d, got %d", offset, replyOffset)
+	}
+
+	return slices.Clone(response[5 : 2+length]), nil
+}
+
+func (b *DDCBackend) GetFeature(id string, vcp byte) (*DDCFeatureValue, error) {
+	var cap *ddcCapability
+	err := b.withDevice(id, func(fd int, dev *ddcDevice) error {
+		var err error
+		cap, err = b.getVCPFeature(fd, vcp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current := cap.current
+	if _, named := ddcValueNames[vcp]; named {
+		// Non-continuous features only carry the value in the low byte.
+		current &= 0xFF
+	}
+
+	return &DDCFeatureValue{
+		Device:    id,
+		VCP:       int(vcp),
+		Feature:   ddcFeatureNames[vcp],
+		Current:   current,
+		Max:       cap.max,
+		ValueName: ddcValueNames[vcp][current],
+	}, nil
+}
+
+// SetFeature queues a write of any VCP feature through the same per-device
+// debouncing used for brightness.
+func (b *DDCBackend) SetFeature(id string, vcp byte, value int, callback func()) error {
+	b.devicesMutex.RLock()
+	_, ok := b.devices[id]
+	b.devicesMutex.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("device not found: %s", id)
+	}
+
+	if value < 0 || value > 0xFFFF {
+		return fmt.Errorf("value out of range: %d", value)
+	}
+
+	if vcp == VCP_BRIGHTNESS {
+		return b.SetBrightness(id, value, false, callback)
+	}
+
+	b.queueVCPSet(id, vcp, value, callback)
+	return nil
+}
@@ -0,0 +1,156 @@
+package brightness
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	raw := "(prot(monitor)type(LCD)model(U2720Q)cmds(01 02 03 07 0C E3 F3)vcp(02 04 10 12 14(01 05 08 0B) 60(0F 11 12 1B) 62 8D(01 02) D6(01 04 05))mccs_ver(2.1))"
+
+	model, features := parseCapabilities(raw)
+	if model != "U2720Q" {
+		t.Errorf("model = %q, want %q", model, "U2720Q")
+	}
+
+	byCode := make(map[int]DDCFeature)
+	for _, f := range features {
+		byCode[f.VCP] = f
+	}
+
+	if len(features) != 9 {
+		t.Errorf("expected 9 features, got %d: %+v", len(features), features)
+	}
+	if f := byCode[VCP_INPUT_SOURCE]; f.Name != "input" || len(f.Values) != 4 || f.Values[3] != 0x1B {
+		t.Errorf("input feature = %+v", f)
+	}
+	if f := byCode[VCP_CONTRAST]; f.Name != "contrast" || len(f.Values) != 0 {
+		t.Errorf("contrast feature = %+v", f)
+	}
+	if f := byCode[VCP_POWER_MODE]; len(f.Values) != 3 {
+		t.Errorf("power feature = %+v", f)
+	}
+}
+
+func TestParseDDCFeatureAndValue(t *testing.T) {
+	tests := []struct {
+		feature string
+		want    byte
+	}{
+		{"input", VCP_INPUT_SOURCE},
+		{"Volume", VCP_AUDIO_VOLUME},
+		{"0xD6", VCP_POWER_MODE},
+		{"18", VCP_CONTRAST},
+	}
+	for _, tt := range tests {
+		got, err := ParseDDCFeature(tt.feature)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDDCFeature(%q) = 0x%02x, %v, want 0x%02x", tt.feature, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDDCFeature("sharpness-ish"); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+
+	if v, err := ParseDDCValue(VCP_INPUT_SOURCE, "hdmi-1"); err != nil || v != 0x11 {
+		t.Errorf("ParseDDCValue(input, hdmi-1) = %d, %v", v, err)
+	}
+	if v, err := ParseDDCValue(VCP_CONTRAST, "75"); err != nil || v != 75 {
+		t.Errorf("ParseDDCValue(contrast, 75) = %d, %v", v, err)
+	}
+	if _, err := ParseDDCValue(VCP_POWER_MODE, "sleepy"); err == nil {
+		t.Error("expected error for unknown value name")
+	}
+}
+
+func TestDDCSetFeatureDebounces(t *testing.T) {
+	b := &DDCBackend{
+		devices:         map[string]*ddcDevice{"ddc:i2c-99": {bus: 99, addr: DDCCI_ADDR}},
+		debounceTimers:  make(map[string]*time.Timer),
+		debouncePending: make(map[string][]ddcPendingSet),
+	}
+
+	var calls atomic.Int32
+	for _, v := range []int{10, 20, 30} {
+		if err := b.SetFeature("ddc:i2c-99", VCP_CONTRAST, v, func() { calls.Add(1) }); err != nil {
+			t.Fatalf("SetFeature() error = %v", err)
+		}
+	}
+	if err := b.SetFeature("ddc:i2c-99", VCP_INPUT_SOURCE, 0x11, func() { calls.Add(1) }); err != nil {
+		t.Fatalf("SetFeature() error = %v", err)
+	}
+
+	time.Sleep(400 * time.Millisecond)
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected one write per feature, got %d", got)
+	}
+
+	if err := b.SetFeature("ddc:i2c-0", VCP_CONTRAST, 10, nil); err == nil {
+		t.Error("expected error for unknown device")
+	}
+}
+
+func TestDDCQueueIsPerDevice(t *testing.T) {
+	b := &DDCBackend{
+		devices:         map[string]*ddcDevice{"ddc:i2c-99": {bus: 99, addr: DDCCI_ADDR}},
+		debounceTimers:  make(map[string]*time.Timer),
+		debouncePending: make(map[string][]ddcPendingSet),
+	}
+
+	b.queueVCPSet("ddc:i2c-99", VCP_BRIGHTNESS, 40, nil)
+	b.queueVCPSet("ddc:i2c-99", VCP_CONTRAST, 50, nil)
+	b.queueVCPSet("ddc:i2c-99", VCP_BRIGHTNESS, 60, nil)
+
+	b.debounceMutex.Lock()
+	timers := len(b.debounceTimers)
+	pending := slices.Clone(b.debouncePending["ddc:i2c-99"])
+	b.debounceMutex.Unlock()
+
+	if timers != 1 {
+		t.Errorf("expected one timer per device, got %d", timers)
+	}
+	want := []int{60, 50}
+	if len(pending) != len(want) {
+		t.Fatalf("pending = %+v, want values %v", pending, want)
+	}
+	for i, p := range pending {
+		if p.value != want[i] {
+			t.Errorf("pending[%d] = %d, want %d", i, p.value, want[i])
+		}
+	}
+
+	if b.busLock(99) != b.busLock(99) || b.busLock(99) == b.busLock(98) {
+		t.Error("bus locks must be shared per bus")
+	}
+}
+
+func TestDDCDropVCPSetKeepsOtherFeatures(t *testing.T) {
+	b := &DDCBackend{
+		devices:         map[string]*ddcDevice{"ddc:i2c-99": {bus: 99, addr: DDCCI_ADDR}},
+		debounceTimers:  make(map[string]*time.Timer),
+		debouncePending: make(map[string][]ddcPendingSet),
+	}
+
+	b.queueVCPSet("ddc:i2c-99", VCP_BRIGHTNESS, 40, nil)
+	b.queueVCPSet("ddc:i2c-99", VCP_CONTRAST, 50, nil)
+	b.dropVCPSet("ddc:i2c-99", VCP_BRIGHTNESS)
+
+	b.debounceMutex.Lock()
+	pending := slices.Clone(b.debouncePending["ddc:i2c-99"])
+	b.debounceMutex.Unlock()
+
+	if len(pending) != 1 || pending[0].vcp != VCP_CONTRAST {
+		t.Errorf("pending = %+v, want only the contrast write", pending)
+	}
+
+	b.dropVCPSet("ddc:i2c-99", VCP_CONTRAST)
+	b.debounceMutex.Lock()
+	_, exists := b.debouncePending["ddc:i2c-99"]
+	b.debounceMutex.Unlock()
+	if exists {
+		t.Error("empty queue should be removed")
+	}
+}
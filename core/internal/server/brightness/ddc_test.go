@@ -133,3 +133,35 @@ func TestDDCBackend_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestDDCBackend_MergeDevices(t *testing.T) {
+	caps := &DDCCapabilities{Device: "ddc:i2c-3", Model: "U2720Q"}
+	standby := &ddcDevice{bus: 3, id: "ddc:i2c-3", max: 100, lastBrightness: 40, capabilities: caps}
+	awake := &ddcDevice{bus: 4, id: "ddc:i2c-4", max: 100, lastBrightness: 10}
+	b := &DDCBackend{
+		devices: map[string]*ddcDevice{
+			"ddc:i2c-3": standby,
+			"ddc:i2c-4": awake,
+			"ddc:i2c-5": {bus: 5, id: "ddc:i2c-5"},
+		},
+	}
+
+	found := map[string]*ddcDevice{
+		"ddc:i2c-4": {bus: 4, id: "ddc:i2c-4", name: "DP-2", max: 100, lastBrightness: 70},
+		"ddc:i2c-6": {bus: 6, id: "ddc:i2c-6", name: "HDMI-1"},
+	}
+	b.mergeDevices(found, func(bus int) bool { return bus != 5 })
+
+	if b.devices["ddc:i2c-3"] != standby || standby.capabilities != caps {
+		t.Error("unresponsive but connected monitor lost its entry or capabilities")
+	}
+	if b.devices["ddc:i2c-4"] != awake || awake.lastBrightness != 70 || awake.name != "DP-2" {
+		t.Errorf("responsive monitor not updated in place: %+v", b.devices["ddc:i2c-4"])
+	}
+	if _, ok := b.devices["ddc:i2c-5"]; ok {
+		t.Error("disconnected monitor kept")
+	}
+	if _, ok := b.devices["ddc:i2c-6"]; !ok {
+		t.Error("new monitor not added")
+	}
+}
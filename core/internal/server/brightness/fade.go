@@ -0,0 +1,231 @@
+package brightness
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const (
+	maxFadeDuration = time.Minute
+
+	// DDC writes take tens of milliseconds and monitors drop commands sent
+	// back to back, so DDC fades step far less often than backlights.
+	sysfsFadeInterval = 33 * time.Millisecond
+	ddcFadeInterval   = 250 * time.Millisecond
+
+	autoBrightnessFadeDuration = 500 * time.Millisecond
+)
+
+type fade struct {
+	target int
+	// auto marks fades started by auto brightness, which it may replace.
+	// Fades the user started are left to finish.
+	auto   bool
+	cancel chan struct{}
+	done   chan struct{}
+}
+
+// FadeBrightness is a manual change: it cancels any fade running on the
+// device, and auto brightness learns from it when it drives the device.
+// A zero duration sets the brightness immediately.
+func (m *Manager) FadeBrightness(deviceID string, percent int, exponential bool, exponent float64, duration time.Duration) error {
+	if percent < 0 {
+		return fmt.Errorf("percent out of range: %d", percent)
+	}
+	duration = min(duration, maxFadeDuration)
+
+	m.cancelFade(deviceID)
+	m.rememberCurve(deviceID, exponential, exponent)
+
+	var err error
+	if duration <= 0 {
+		err = m.setBrightness(deviceID, percent, exponential, exponent)
+	} else {
+		err = m.startFade(deviceID, percent, exponential, exponent, duration, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(m.autoBrightnessDevices(), deviceID) {
+		m.auto.learn(percent)
+	}
+	return nil
+}
+
+// FadeIncrement steps from the target of a running fade, so repeated key
+// presses during a fade accumulate instead of restarting from mid-fade.
+func (m *Manager) FadeIncrement(deviceID string, step int, exponential bool, exponent float64, duration time.Duration) error {
+	current, ok := m.fadeTarget(deviceID)
+	if !ok {
+		dev, found := m.device(deviceID)
+		if !found {
+			return fmt.Errorf("device not found: %s", deviceID)
+		}
+		current = dev.CurrentPercent
+	}
+
+	return m.FadeBrightness(deviceID, max(0, min(100, current+step)), exponential, exponent, duration)
+}
+
+func (m *Manager) device(deviceID string) (Device, bool) {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+
+	for _, dev := range m.state.Devices {
+		if dev.ID == deviceID {
+			return dev, true
+		}
+	}
+	return Device{}, false
+}
+
+func (m *Manager) fadeTarget(deviceID string) (int, bool) {
+	m.fadeMutex.Lock()
+	defer m.fadeMutex.Unlock()
+
+	f, ok := m.fades[deviceID]
+	if !ok {
+		return 0, false
+	}
+	return f.target, true
+}
+
+func (m *Manager) cancelFade(deviceID string) {
+	m.fadeMutex.Lock()
+	f, ok := m.fades[deviceID]
+	if ok {
+		delete(m.fades, deviceID)
+		close(f.cancel)
+	}
+	m.fadeMutex.Unlock()
+
+	if ok {
+		<-f.done
+	}
+}
+
+// cancelAutoFade cancels a fade started by auto brightness. It returns false
+// and leaves the fade running when the user started it.
+func (m *Manager) cancelAutoFade(deviceID string) bool {
+	m.fadeMutex.Lock()
+	f, ok := m.fades[deviceID]
+	if ok && !f.auto {
+		m.fadeMutex.Unlock()
+		return false
+	}
+	if ok {
+		delete(m.fades, deviceID)
+		close(f.cancel)
+	}
+	m.fadeMutex.Unlock()
+
+	if ok {
+		<-f.done
+	}
+	return true
+}
+
+func (m *Manager) startFade(deviceID string, target int, exponential bool, exponent float64, duration time.Duration, auto bool) error {
+	dev, ok := m.device(deviceID)
+	if !ok {
+		return fmt.Errorf("device not found: %s", deviceID)
+	}
+
+	f := &fade{
+		target: target,
+		auto:   auto,
+		cancel: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	m.fadeMutex.Lock()
+	if auto && m.fades[deviceID] != nil {
+		// The user started a fade since auto brightness checked.
+		m.fadeMutex.Unlock()
+		return nil
+	}
+	if m.fades == nil {
+		m.fades = make(map[string]*fade)
+	}
+	m.fades[deviceID] = f
+	m.fadeMutex.Unlock()
+
+	if dev.Class == ClassDDC && m.ddcBackend != nil {
+		m.ddcBackend.dropVCPSet(deviceID, VCP_BRIGHTNESS)
+	}
+
+	go m.runFade(f, dev, exponential, exponent, duration)
+	return nil
+}
+
+func (m *Manager) runFade(f *fade, dev Device, exponential bool, exponent float64, duration time.Duration) {
+	defer close(f.done)
+	defer func() {
+		m.fadeMutex.Lock()
+		if m.fades[dev.ID] == f {
+			delete(m.fades, dev.ID)
+		}
+		m.fadeMutex.Unlock()
+	}()
+
+	interval := sysfsFadeInterval
+	if dev.Class == ClassDDC {
+		interval = ddcFadeInterval
+	}
+	steps := max(1, int(duration/interval))
+
+	ticker := time.NewTicker(duration / time.Duration(steps))
+	defer ticker.Stop()
+
+	start := dev.CurrentPercent
+	last := start
+	for i := 1; i <= steps; i++ {
+		select {
+		case <-f.cancel:
+			log.Debugf("Fade on %s cancelled at %d%%", dev.ID, last)
+			return
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		percent := start + (f.target-start)*i/steps
+		if percent == last && i < steps {
+			continue
+		}
+		last = percent
+
+		if err := m.fadeStep(dev, percent, exponential, exponent); err != nil {
+			log.Debugf("Fade on %s failed: %v", dev.ID, err)
+			return
+		}
+	}
+
+	if dev.Class == ClassDDC {
+		m.updateState()
+	}
+}
+
+// fadeStep writes one intermediate value and publishes it right away on the
+// update stream. DDC writes skip the debounce, which would otherwise hold
+// every step back until the fade ends, but still go through the device's
+// bus lock and queue.
+func (m *Manager) fadeStep(dev Device, percent int, exponential bool, exponent float64) error {
+	if dev.Class == ClassDDC {
+		if _, err := m.setStatePercent(dev.ID, percent); err != nil {
+			return err
+		}
+		if err := m.ddcBackend.setBrightnessImmediateWithExponent(dev.ID, percent); err != nil {
+			return err
+		}
+	} else if err := m.setBrightness(dev.ID, percent, exponential, exponent); err != nil {
+		return err
+	}
+
+	m.broadcastDeviceUpdate(dev.ID)
+	return nil
+}
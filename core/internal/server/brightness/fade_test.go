@@ -0,0 +1,180 @@
+package brightness
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newFadeTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	backlightDir := filepath.Join(tmpDir, "backlight", "test_backlight")
+	if err := os.MkdirAll(backlightDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(backlightDir, "max_brightness"), []byte("100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(backlightDir, "brightness"), []byte("10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sysfs := &SysfsBackend{
+		basePath:    tmpDir,
+		classes:     []string{"backlight"},
+		deviceCache: make(map[string]*sysfsDevice),
+	}
+	if err := sysfs.scanDevices(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		sysfsBackend:      sysfs,
+		sysfsReady:        true,
+		subscribers:       make(map[string]chan State),
+		updateSubscribers: make(map[string]chan DeviceUpdate),
+		stopChan:          make(chan struct{}),
+	}
+	m.state = State{
+		Devices: []Device{
+			{
+				Class:          ClassBacklight,
+				ID:             "backlight:test_backlight",
+				Name:           "test_backlight",
+				Current:        10,
+				Max:            100,
+				CurrentPercent: 10,
+				Backend:        "sysfs",
+			},
+		},
+	}
+
+	return m, filepath.Join(backlightDir, "brightness")
+}
+
+func TestManager_FadeBrightnessPublishesSteps(t *testing.T) {
+	m, brightnessPath := newFadeTestManager(t)
+	updates := m.SubscribeUpdates("test")
+
+	if err := m.FadeBrightness("backlight:test_backlight", 90, false, 1.2, 300*time.Millisecond); err != nil {
+		t.Fatalf("FadeBrightness() error = %v", err)
+	}
+
+	var seen []int
+	timeout := time.After(2 * time.Second)
+	for len(seen) == 0 || seen[len(seen)-1] != 90 {
+		select {
+		case update := <-updates:
+			seen = append(seen, update.Device.CurrentPercent)
+		case <-timeout:
+			t.Fatalf("fade did not reach target, saw %v", seen)
+		}
+	}
+
+	if len(seen) < 3 {
+		t.Errorf("expected intermediate updates, saw %v", seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] < seen[i-1] {
+			t.Errorf("fade went backwards: %v", seen)
+		}
+	}
+
+	data, _ := os.ReadFile(brightnessPath)
+	if string(data) != "90" {
+		t.Errorf("brightness file = %q, want %q", data, "90")
+	}
+}
+
+func TestManager_FadeCancelledByNewerRequest(t *testing.T) {
+	m, brightnessPath := newFadeTestManager(t)
+
+	if err := m.FadeBrightness("backlight:test_backlight", 100, false, 1.2, 5*time.Second); err != nil {
+		t.Fatalf("FadeBrightness() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if err := m.SetBrightness("backlight:test_backlight", 30); err != nil {
+		t.Fatalf("SetBrightness() error = %v", err)
+	}
+	if _, fading := m.fadeTarget("backlight:test_backlight"); fading {
+		t.Error("fade still running after a newer request")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	data, _ := os.ReadFile(brightnessPath)
+	if string(data) != "30" {
+		t.Errorf("brightness file = %q, want %q", data, "30")
+	}
+}
+
+func TestManager_FadeIncrementUsesFadeTarget(t *testing.T) {
+	m, _ := newFadeTestManager(t)
+
+	if err := m.FadeBrightness("backlight:test_backlight", 50, false, 1.2, 5*time.Second); err != nil {
+		t.Fatalf("FadeBrightness() error = %v", err)
+	}
+	if err := m.FadeIncrement("backlight:test_backlight", 10, false, 1.2, 5*time.Second); err != nil {
+		t.Fatalf("FadeIncrement() error = %v", err)
+	}
+
+	if target, _ := m.fadeTarget("backlight:test_backlight"); target != 60 {
+		t.Errorf("fade target = %d, want 60", target)
+	}
+	m.cancelFade("backlight:test_backlight")
+}
+
+func newAutoBrightnessTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	m, brightnessPath := newFadeTestManager(t)
+
+	sensorDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sensorDir, "in_illuminance_input"), []byte("100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.auto = newAutoBrightness(&alsSensor{path: sensorDir, name: "als"})
+	m.auto.config.Enabled = true
+	m.auto.config.Curve = []CurvePoint{{Lux: 0, Percent: 50}, {Lux: 1000, Percent: 50}}
+	return m, brightnessPath
+}
+
+func TestManager_AutoBrightnessTickSkipsUserFade(t *testing.T) {
+	m, _ := newAutoBrightnessTestManager(t)
+
+	if err := m.FadeBrightness("backlight:test_backlight", 100, false, 0, 5*time.Second); err != nil {
+		t.Fatalf("FadeBrightness() error = %v", err)
+	}
+	defer m.cancelFade("backlight:test_backlight")
+	time.Sleep(50 * time.Millisecond)
+
+	m.autoBrightnessTick()
+	target, fading := m.fadeTarget("backlight:test_backlight")
+	if !fading || target != 100 {
+		t.Errorf("fadeTarget() = %d, %v; want the user fade to 100 to keep running", target, fading)
+	}
+}
+
+func TestManager_AutoBrightnessTickFadesWithDeviceCurve(t *testing.T) {
+	m, brightnessPath := newAutoBrightnessTestManager(t)
+	m.rememberCurve("backlight:test_backlight", true, 2.0)
+
+	m.autoBrightnessTick()
+	if target, fading := m.fadeTarget("backlight:test_backlight"); !fading || target != 50 {
+		t.Errorf("fadeTarget() = %d, %v; want an auto fade to 50", target, fading)
+	}
+
+	dev, err := m.sysfsBackend.GetDevice("backlight:test_backlight")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strconv.Itoa(m.sysfsBackend.PercentToValueWithExponent(50, dev, true, 2.0))
+	time.Sleep(autoBrightnessFadeDuration + 200*time.Millisecond)
+	data, _ := os.ReadFile(brightnessPath)
+	if string(data) != want {
+		t.Errorf("brightness file = %q, want %q", data, want)
+	}
+}
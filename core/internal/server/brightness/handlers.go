@@ -3,10 +3,16 @@ package brightness
 import (
 	"encoding/json"
 	"net"
+	"time"
 
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 )
 
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
 func HandleRequest(conn net.Conn, req Request, m *Manager) {
 	switch req.Method {
 	case "brightness.getState":
@@ -17,18 +23,26 @@ func HandleRequest(conn net.Conn, req Request, m *Manager) {
 		handleIncrement(conn, req, m)
 	case "brightness.decrement":
 		handleDecrement(conn, req, m)
+	case "brightness.setAutoBrightness":
+		handleSetAutoBrightness(conn, req, m)
+	case "ddc.getCapabilities":
+		handleDDCGetCapabilities(conn, req, m)
+	case "ddc.getFeature":
+		handleDDCGetFeature(conn, req, m)
+	case "ddc.setFeature":
+		handleDDCSetFeature(conn, req, m)
 	case "brightness.rescan":
 		handleRescan(conn, req, m)
 	case "brightness.subscribe":
 		handleSubscribe(conn, req, m)
 	default:
-		models.RespondError(conn, req.ID.(int), "unknown method: "+req.Method)
+		models.RespondError(conn, req.ID, "unknown method: "+req.Method)
 	}
 }
 
 func handleGetState(conn net.Conn, req Request, m *Manager) {
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
 }
 
 func handleSetBrightness(conn net.Conn, req Request, m *Manager) {
@@ -36,14 +50,14 @@ func handleSetBrightness(conn net.Conn, req Request, m *Manager) {
 
 	device, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID.(int), "missing or invalid device parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid device parameter"))
 		return
 	}
 	params.Device = device
 
 	percentFloat, ok := req.Params["percent"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID.(int), "missing or invalid percent parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid percent parameter"))
 		return
 	}
 	params.Percent = int(percentFloat)
@@ -58,19 +72,19 @@ func handleSetBrightness(conn net.Conn, req Request, m *Manager) {
 		exponent = exponentFloat
 	}
 
-	if err := m.SetBrightnessWithExponent(params.Device, params.Percent, params.Exponential, exponent); err != nil {
-		models.RespondError(conn, req.ID.(int), err.Error())
+	if err := m.FadeBrightness(params.Device, params.Percent, params.Exponential, exponent, durationParam(req)); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
 		return
 	}
 
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
 }
 
 func handleIncrement(conn net.Conn, req Request, m *Manager) {
 	device, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID.(int), "missing or invalid device parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid device parameter"))
 		return
 	}
 
@@ -89,19 +103,19 @@ func handleIncrement(conn net.Conn, req Request, m *Manager) {
 		exponent = exponentFloat
 	}
 
-	if err := m.IncrementBrightnessWithExponent(device, step, exponential, exponent); err != nil {
-		models.RespondError(conn, req.ID.(int), err.Error())
+	if err := m.FadeIncrement(device, step, exponential, exponent, durationParam(req)); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
 		return
 	}
 
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
 }
 
 func handleDecrement(conn net.Conn, req Request, m *Manager) {
 	device, ok := req.Params["device"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID.(int), "missing or invalid device parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid device parameter"))
 		return
 	}
 
@@ -120,19 +134,159 @@ func handleDecrement(conn net.Conn, req Request, m *Manager) {
 		exponent = exponentFloat
 	}
 
-	if err := m.IncrementBrightnessWithExponent(device, -step, exponential, exponent); err != nil {
-		models.RespondError(conn, req.ID.(int), err.Error())
+	if err := m.FadeIncrement(device, -step, exponential, exponent, durationParam(req)); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
 		return
 	}
 
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
+}
+
+func durationParam(req Request) time.Duration {
+	durationMs, _ := req.Params["durationMs"].(float64)
+	return time.Duration(durationMs) * time.Millisecond
+}
+
+func handleSetAutoBrightness(conn net.Conn, req Request, m *Manager) {
+	config := m.GetAutoBrightness().Config
+
+	if enabled, ok := req.Params["enabled"].(bool); ok {
+		config.Enabled = enabled
+	}
+	if hysteresis, ok := req.Params["hysteresis"].(float64); ok {
+		config.Hysteresis = int(hysteresis)
+	}
+	if smoothing, ok := req.Params["smoothing"].(float64); ok {
+		config.Smoothing = smoothing
+	}
+	if devices, ok := req.Params["devices"].([]interface{}); ok {
+		config.Devices = nil
+		for _, d := range devices {
+			if id, ok := d.(string); ok {
+				config.Devices = append(config.Devices, id)
+			}
+		}
+	}
+	if points, ok := req.Params["curve"].([]interface{}); ok {
+		curve := make([]CurvePoint, 0, len(points))
+		for _, p := range points {
+			point, ok := p.(map[string]interface{})
+			if !ok {
+				models.RespondError(conn, req.ID, "invalid curve parameter: expected objects with lux and percent")
+				return
+			}
+			lux, okLux := point["lux"].(float64)
+			percent, okPercent := point["percent"].(float64)
+			if !okLux || !okPercent {
+				models.RespondError(conn, req.ID, "invalid curve parameter: expected objects with lux and percent")
+				return
+			}
+			curve = append(curve, CurvePoint{Lux: lux, Percent: int(percent)})
+		}
+		config.Curve = curve
+	}
+
+	if err := m.SetAutoBrightness(config); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, m.GetAutoBrightness())
+}
+
+func handleDDCGetCapabilities(conn net.Conn, req Request, m *Manager) {
+	device, ok := req.Params["device"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid device parameter"))
+		return
+	}
+
+	caps, err := m.GetDDCCapabilities(device)
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, caps)
+}
+
+func ddcFeatureParam(req Request) (string, byte, error) {
+	device, ok := req.Params["device"].(string)
+	if !ok {
+		return "", 0, models.NewParamError("missing or invalid device parameter")
+	}
+
+	var vcp byte
+	switch feature := req.Params["feature"].(type) {
+	case string:
+		code, err := ParseDDCFeature(feature)
+		if err != nil {
+			return "", 0, models.NewParamError("%s", err)
+		}
+		vcp = code
+	case float64:
+		if feature < 0 || feature > 0xFF {
+			return "", 0, models.NewParamError("invalid feature parameter")
+		}
+		vcp = byte(feature)
+	default:
+		return "", 0, models.NewParamError("missing or invalid feature parameter")
+	}
+
+	return device, vcp, nil
+}
+
+func handleDDCGetFeature(conn net.Conn, req Request, m *Manager) {
+	device, vcp, err := ddcFeatureParam(req)
+	if err != nil {
+		models.RespondErr(conn, req.ID, err)
+		return
+	}
+
+	value, err := m.GetDDCFeature(device, vcp)
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, value)
+}
+
+func handleDDCSetFeature(conn net.Conn, req Request, m *Manager) {
+	device, vcp, err := ddcFeatureParam(req)
+	if err != nil {
+		models.RespondErr(conn, req.ID, err)
+		return
+	}
+
+	var value int
+	switch v := req.Params["value"].(type) {
+	case string:
+		value, err = ParseDDCValue(vcp, v)
+		if err != nil {
+			models.RespondErr(conn, req.ID, models.NewParamError("%s", err))
+			return
+		}
+	case float64:
+		value = int(v)
+	default:
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid value parameter"))
+		return
+	}
+
+	if err := m.SetDDCFeature(device, vcp, value); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "feature queued"})
 }
 
 func handleRescan(conn net.Conn, req Request, m *Manager) {
 	m.Rescan()
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
 }
 
 func handleSubscribe(conn net.Conn, req Request, m *Manager) {
@@ -146,7 +300,7 @@ func handleSubscribe(conn net.Conn, req Request, m *Manager) {
 
 	initialState := m.GetState()
 	if err := json.NewEncoder(conn).Encode(models.Response[State]{
-		ID:     req.ID.(int),
+		ID:     req.ID,
 		Result: &initialState,
 	}); err != nil {
 		return
@@ -154,7 +308,7 @@ func handleSubscribe(conn net.Conn, req Request, m *Manager) {
 
 	for state := range ch {
 		if err := json.NewEncoder(conn).Encode(models.Response[State]{
-			ID:     req.ID.(int),
+			ID:     req.ID,
 			Result: &state,
 		}); err != nil {
 			return
@@ -2,6 +2,7 @@ package brightness
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -21,9 +22,12 @@ func NewManagerWithOptions(exponential bool) (*Manager, error) {
 		exponential:       exponential,
 	}
 
+	m.initAutoBrightness()
+
 	go m.initLogind()
 	go m.initSysfs()
 	go m.initDDC()
+	go m.initUevents()
 
 	return m, nil
 }
@@ -112,6 +116,10 @@ func stateChanged(old, new State) bool {
 		return true
 	}
 
+	if old.Auto.Available != new.Auto.Available || !reflect.DeepEqual(old.Auto.Config, new.Auto.Config) {
+		return true
+	}
+
 	oldMap := make(map[string]Device)
 	for _, d := range old.Devices {
 		oldMap[d.ID] = d
@@ -157,7 +165,7 @@ func (m *Manager) updateState() {
 
 	m.stateMutex.Lock()
 	oldState := m.state
-	newState := State{Devices: allDevices}
+	newState := State{Devices: allDevices, Auto: m.auto.status()}
 
 	if !stateChanged(oldState, newState) {
 		m.stateMutex.Unlock()
@@ -179,42 +187,46 @@ func (m *Manager) SetBrightnessWithMode(deviceID string, percent int, exponentia
 }
 
 func (m *Manager) SetBrightnessWithExponent(deviceID string, percent int, exponential bool, exponent float64) error {
-	if percent < 0 {
-		return fmt.Errorf("percent out of range: %d", percent)
-	}
-
-	log.Debugf("SetBrightness: %s to %d%%", deviceID, percent)
+	return m.FadeBrightness(deviceID, percent, exponential, exponent, 0)
+}
 
-	m.stateMutex.Lock()
-	currentState := m.state
-	var found bool
-	var deviceClass DeviceClass
-	var deviceIndex int
+type brightnessCurve struct {
+	exponential bool
+	exponent    float64
+}
 
-	log.Debugf("Current state has %d devices", len(currentState.Devices))
+// rememberCurve records the curve a client last set a device with, so
+// changes the manager makes on its own follow the same curve.
+func (m *Manager) rememberCurve(deviceID string, exponential bool, exponent float64) {
+	m.curveMutex.Lock()
+	defer m.curveMutex.Unlock()
+	if m.curves == nil {
+		m.curves = make(map[string]brightnessCurve)
+	}
+	m.curves[deviceID] = brightnessCurve{exponential: exponential, exponent: exponent}
+}
 
-	for i, dev := range currentState.Devices {
-		if dev.ID == deviceID {
-			found = true
-			deviceClass = dev.Class
-			deviceIndex = i
-			break
-		}
+func (m *Manager) deviceCurve(deviceID string) (bool, float64) {
+	m.curveMutex.Lock()
+	defer m.curveMutex.Unlock()
+	if curve, ok := m.curves[deviceID]; ok {
+		return curve.exponential, curve.exponent
 	}
+	return m.exponential, 1.2
+}
 
-	if !found {
-		m.stateMutex.Unlock()
-		log.Debugf("Device not found in state: %s", deviceID)
-		return fmt.Errorf("device not found: %s", deviceID)
+func (m *Manager) setBrightness(deviceID string, percent int, exponential bool, exponent float64) error {
+	if percent < 0 {
+		return fmt.Errorf("percent out of range: %d", percent)
 	}
 
-	newDevices := make([]Device, len(currentState.Devices))
-	copy(newDevices, currentState.Devices)
-	newDevices[deviceIndex].CurrentPercent = percent
-	m.state = State{Devices: newDevices}
-	m.stateMutex.Unlock()
+	log.Debugf("SetBrightness: %s to %d%%", deviceID, percent)
+
+	deviceClass, err := m.setStatePercent(deviceID, percent)
+	if err != nil {
+		return err
+	}
 
-	var err error
 	if deviceClass == ClassDDC {
 		log.Debugf("Calling DDC backend for %s", deviceID)
 		err = m.ddcBackend.SetBrightnessWithExponent(deviceID, percent, exponential, exponent, func() {
@@ -241,6 +253,29 @@ func (m *Manager) SetBrightnessWithExponent(deviceID string, percent int, expone
 	return nil
 }
 
+// setStatePercent records percent for a device ahead of the hardware write so
+// updates report the requested value right away.
+func (m *Manager) setStatePercent(deviceID string, percent int) (DeviceClass, error) {
+	m.stateMutex.Lock()
+	defer m.stateMutex.Unlock()
+
+	currentState := m.state
+	for i, dev := range currentState.Devices {
+		if dev.ID != deviceID {
+			continue
+		}
+
+		newDevices := make([]Device, len(currentState.Devices))
+		copy(newDevices, currentState.Devices)
+		newDevices[i].CurrentPercent = percent
+		m.state = State{Devices: newDevices, Auto: currentState.Auto}
+		return dev.Class, nil
+	}
+
+	log.Debugf("Device not found in state: %s", deviceID)
+	return "", fmt.Errorf("device not found: %s", deviceID)
+}
+
 func (m *Manager) IncrementBrightness(deviceID string, step int) error {
 	return m.IncrementBrightnessWithMode(deviceID, step, m.exponential)
 }
@@ -250,34 +285,7 @@ func (m *Manager) IncrementBrightnessWithMode(deviceID string, step int, exponen
 }
 
 func (m *Manager) IncrementBrightnessWithExponent(deviceID string, step int, exponential bool, exponent float64) error {
-	m.stateMutex.RLock()
-	currentState := m.state
-	m.stateMutex.RUnlock()
-
-	var currentPercent int
-	var found bool
-
-	for _, dev := range currentState.Devices {
-		if dev.ID == deviceID {
-			currentPercent = dev.CurrentPercent
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return fmt.Errorf("device not found: %s", deviceID)
-	}
-
-	newPercent := currentPercent + step
-	if newPercent > 100 {
-		newPercent = 100
-	}
-	if newPercent < 0 {
-		newPercent = 0
-	}
-
-	return m.SetBrightnessWithExponent(deviceID, newPercent, exponential, exponent)
+	return m.FadeIncrement(deviceID, step, exponential, exponent, 0)
 }
 
 func (m *Manager) DecrementBrightness(deviceID string, step int) error {
@@ -377,3 +385,38 @@ func (m *Manager) broadcastDeviceUpdate(deviceID string) {
 		}
 	}
 }
+
+func (m *Manager) ddc() (*DDCBackend, error) {
+	if !m.ddcReady || m.ddcBackend == nil {
+		return nil, fmt.Errorf("DDC backend not available")
+	}
+	return m.ddcBackend, nil
+}
+
+func (m *Manager) GetDDCCapabilities(deviceID string) (*DDCCapabilities, error) {
+	ddc, err := m.ddc()
+	if err != nil {
+		return nil, err
+	}
+	return ddc.GetCapabilities(deviceID)
+}
+
+func (m *Manager) GetDDCFeature(deviceID string, vcp byte) (*DDCFeatureValue, error) {
+	ddc, err := m.ddc()
+	if err != nil {
+		return nil, err
+	}
+	return ddc.GetFeature(deviceID, vcp)
+}
+
+func (m *Manager) SetDDCFeature(deviceID string, vcp byte, value int) error {
+	if vcp == VCP_BRIGHTNESS {
+		return m.SetBrightnessWithMode(deviceID, value, false)
+	}
+
+	ddc, err := m.ddc()
+	if err != nil {
+		return err
+	}
+	return ddc.SetFeature(deviceID, vcp, value, nil)
+}
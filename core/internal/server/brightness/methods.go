@@ -0,0 +1,92 @@
+package brightness
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "brightness.getState",
+		Description: "Get current brightness state for all devices",
+	},
+	{
+		Name:        "brightness.setBrightness",
+		Description: "Set device brightness",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+			{Name: "percent", Type: models.ParamNumber, Required: true},
+			{Name: "exponential", Type: models.ParamBool},
+			{Name: "exponent", Type: models.ParamNumber},
+			{Name: "durationMs", Type: models.ParamNumber, Description: "fade to the target over this many milliseconds"},
+		},
+	},
+	{
+		Name:        "brightness.increment",
+		Description: "Increment device brightness",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+			{Name: "step", Type: models.ParamNumber},
+			{Name: "exponential", Type: models.ParamBool},
+			{Name: "exponent", Type: models.ParamNumber},
+			{Name: "durationMs", Type: models.ParamNumber, Description: "fade to the target over this many milliseconds"},
+		},
+	},
+	{
+		Name:        "brightness.decrement",
+		Description: "Decrement device brightness",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+			{Name: "step", Type: models.ParamNumber},
+			{Name: "exponential", Type: models.ParamBool},
+			{Name: "exponent", Type: models.ParamNumber},
+			{Name: "durationMs", Type: models.ParamNumber, Description: "fade to the target over this many milliseconds"},
+		},
+	},
+	{
+		Name:        "brightness.setAutoBrightness",
+		Description: "Configure ambient light sensor driven brightness",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool},
+			{Name: "devices", Type: models.ParamArray, Description: "device ids, defaults to all backlights and DDC monitors"},
+			{Name: "curve", Type: models.ParamArray, Description: `[{"lux": 0, "percent": 5}, ...] with increasing lux`},
+			{Name: "hysteresis", Type: models.ParamNumber, Description: "minimum percent change before adjusting"},
+			{Name: "smoothing", Type: models.ParamNumber, Description: "lux smoothing factor in (0, 1]"},
+		},
+		Notes: []string{"Manual brightness changes on driven devices adjust the curve at the current light level."},
+	},
+	{
+		Name:        "ddc.getCapabilities",
+		Description: "Read a DDC monitor's capabilities string and supported VCP codes",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "ddc.getFeature",
+		Description: "Read a DDC/CI VCP feature",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+			{Name: "feature", Type: models.ParamAny, Required: true, Description: "brightness, contrast, input, volume, mute, power, colorPreset or a VCP code"},
+		},
+	},
+	{
+		Name:        "ddc.setFeature",
+		Description: "Write a DDC/CI VCP feature (debounced per device and feature)",
+		Params: []models.ParamSpec{
+			{Name: "device", Type: models.ParamString, Required: true},
+			{Name: "feature", Type: models.ParamAny, Required: true},
+			{Name: "value", Type: models.ParamAny, Required: true, Description: "number or value name, e.g. HDMI-1, standby, sRGB"},
+		},
+	},
+	{
+		Name:        "brightness.rescan",
+		Description: "Rescan for brightness devices (hotplug is normally picked up automatically)",
+	},
+	{
+		Name:        "brightness.subscribe",
+		Description: "Subscribe to brightness state changes",
+		Streaming:   true,
+		Notes: []string{
+			"brightness       : Full device list (on rescan, DDC discovery, device changes)",
+			"brightness.update: Single device update (on brightness change and each fade step)",
+		},
+	},
+}
@@ -29,6 +29,8 @@ func (b *SysfsBackend) scanDevices() error {
 	b.deviceCacheMutex.Lock()
 	defer b.deviceCacheMutex.Unlock()
 
+	b.deviceCache = make(map[string]*sysfsDevice)
+
 	for _, class := range b.classes {
 		classPath := filepath.Join(b.basePath, class)
 		entries, err := os.ReadDir(classPath)
@@ -24,7 +24,8 @@ type Device struct {
 }
 
 type State struct {
-	Devices []Device `json:"devices"`
+	Devices []Device            `json:"devices"`
+	Auto    AutoBrightnessState `json:"autoBrightness"`
 }
 
 type DeviceUpdate struct {
@@ -32,7 +33,7 @@ type DeviceUpdate struct {
 }
 
 type Request struct {
-	ID     interface{}            `json:"id"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params"`
 }
@@ -47,6 +48,16 @@ type Manager struct {
 	ddcReady    bool
 
 	exponential bool
+	curves      map[string]brightnessCurve
+	curveMutex  sync.Mutex
+
+	auto *autoBrightness
+
+	fades     map[string]*fade
+	fadeMutex sync.Mutex
+
+	hotplugMutex   sync.Mutex
+	ddcRescanTimer *time.Timer
 
 	stateMutex sync.RWMutex
 	state      State
@@ -87,13 +98,17 @@ type DDCBackend struct {
 	lastScan     time.Time
 	scanInterval time.Duration
 
+	busLocksMutex sync.Mutex
+	busLocks      map[int]*sync.Mutex
+
 	debounceMutex   sync.Mutex
 	debounceTimers  map[string]*time.Timer
-	debouncePending map[string]ddcPendingSet
+	debouncePending map[string][]ddcPendingSet
 }
 
 type ddcPendingSet struct {
-	percent  int
+	vcp      byte
+	value    int
 	callback func()
 }
 
@@ -104,6 +119,7 @@ type ddcDevice struct {
 	name           string
 	max            int
 	lastBrightness int
+	capabilities   *DDCCapabilities
 }
 
 type ddcCapability struct {
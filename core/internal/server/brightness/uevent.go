@@ -0,0 +1,177 @@
+package brightness
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"golang.org/x/sys/unix"
+)
+
+// Monitors need a moment after a drm hotplug before they answer DDC/CI.
+var ddcHotplugDelay = 2 * time.Second
+
+type Uevent struct {
+	Action    string
+	DevPath   string
+	Subsystem string
+	Env       map[string]string
+}
+
+// UeventReader yields kernel uevents. The netlink implementation is swapped
+// out in tests to replay recorded events.
+type UeventReader interface {
+	ReadUevent() (*Uevent, error)
+	Close() error
+}
+
+type netlinkUeventReader struct {
+	fd  int
+	buf []byte
+}
+
+func newNetlinkUeventReader() (*netlinkUeventReader, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("netlink socket: %w", err)
+	}
+
+	addr := &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1}
+	if err := unix.Bind(fd, addr); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("netlink bind: %w", err)
+	}
+
+	return &netlinkUeventReader{fd: fd, buf: make([]byte, 16384)}, nil
+}
+
+func (r *netlinkUeventReader) ReadUevent() (*Uevent, error) {
+	for {
+		n, _, err := unix.Recvfrom(r.fd, r.buf, 0)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return nil, err
+		}
+
+		ev, err := parseUevent(r.buf[:n])
+		if err != nil {
+			continue
+		}
+		return ev, nil
+	}
+}
+
+func (r *netlinkUeventReader) Close() error {
+	return unix.Close(r.fd)
+}
+
+// parseUevent decodes a kernel uevent: "action@devpath" followed by
+// NUL-separated KEY=VALUE pairs. udevd's own "libudev" messages are rejected.
+func parseUevent(data []byte) (*Uevent, error) {
+	fields := bytes.Split(data, []byte{0})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty uevent")
+	}
+
+	header := string(fields[0])
+	action, devPath, ok := strings.Cut(header, "@")
+	if !ok {
+		return nil, fmt.Errorf("not a kernel uevent: %q", header)
+	}
+
+	ev := &Uevent{
+		Action:  action,
+		DevPath: devPath,
+		Env:     make(map[string]string),
+	}
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(string(field), "=")
+		if !ok {
+			continue
+		}
+		ev.Env[key] = value
+	}
+	ev.Subsystem = ev.Env["SUBSYSTEM"]
+
+	return ev, nil
+}
+
+func (m *Manager) initUevents() {
+	reader, err := newNetlinkUeventReader()
+	if err != nil {
+		log.Debugf("Brightness hotplug unavailable, use brightness.rescan: %v", err)
+		return
+	}
+
+	go func() {
+		<-m.stopChan
+		reader.Close()
+	}()
+
+	m.watchUevents(reader)
+}
+
+func (m *Manager) watchUevents(reader UeventReader) {
+	for {
+		ev, err := reader.ReadUevent()
+		if err != nil {
+			select {
+			case <-m.stopChan:
+			default:
+				log.Debugf("Uevent reader stopped: %v", err)
+			}
+			return
+		}
+		m.handleUevent(ev)
+	}
+}
+
+func (m *Manager) handleUevent(ev *Uevent) {
+	switch ev.Subsystem {
+	case "backlight", "leds":
+		if ev.Action != "add" && ev.Action != "remove" {
+			return
+		}
+		if !m.sysfsReady || m.sysfsBackend == nil {
+			return
+		}
+		log.Debugf("Brightness uevent: %s %s", ev.Action, ev.DevPath)
+		if err := m.sysfsBackend.scanDevices(); err != nil {
+			log.Debugf("Failed to rescan sysfs devices: %v", err)
+		}
+		m.updateState()
+	case "drm", "i2c-dev":
+		// drm also sends change events for mode sets and DPMS; only those
+		// flagged HOTPLUG=1 mean a monitor came or went.
+		hotplug := ev.Action == "change" && ev.Env["HOTPLUG"] == "1"
+		if ev.Action != "add" && ev.Action != "remove" && !hotplug {
+			return
+		}
+		log.Debugf("Display uevent: %s %s", ev.Action, ev.DevPath)
+		m.scheduleDDCRescan()
+	}
+}
+
+// scheduleDDCRescan collapses the burst of drm/i2c events a single monitor
+// plug produces into one DDC probe.
+func (m *Manager) scheduleDDCRescan() {
+	if !m.ddcReady || m.ddcBackend == nil {
+		return
+	}
+
+	m.hotplugMutex.Lock()
+	defer m.hotplugMutex.Unlock()
+
+	if m.ddcRescanTimer != nil {
+		m.ddcRescanTimer.Reset(ddcHotplugDelay)
+		return
+	}
+	m.ddcRescanTimer = time.AfterFunc(ddcHotplugDelay, func() {
+		m.ddcBackend.forceRescan()
+		m.updateState()
+	})
+}
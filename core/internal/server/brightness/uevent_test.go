@@ -0,0 +1,139 @@
+package brightness
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type replayUeventReader struct {
+	events [][]byte
+}
+
+func (r *replayUeventReader) ReadUevent() (*Uevent, error) {
+	for len(r.events) > 0 {
+		data := r.events[0]
+		r.events = r.events[1:]
+		if ev, err := parseUevent(data); err == nil {
+			return ev, nil
+		}
+	}
+	return nil, io.EOF
+}
+
+func (r *replayUeventReader) Close() error {
+	return nil
+}
+
+func recordedUevent(header string, env ...string) []byte {
+	return []byte(header + "\x00" + strings.Join(env, "\x00") + "\x00")
+}
+
+func TestParseUevent(t *testing.T) {
+	ev, err := parseUevent(recordedUevent(
+		"add@/devices/pci0000:00/0000:00:02.0/drm/card1/card1-eDP-1/intel_backlight",
+		"ACTION=add",
+		"DEVPATH=/devices/pci0000:00/0000:00:02.0/drm/card1/card1-eDP-1/intel_backlight",
+		"SUBSYSTEM=backlight",
+		"SEQNUM=4711",
+	))
+	if err != nil {
+		t.Fatalf("parseUevent() error = %v", err)
+	}
+	if ev.Action != "add" || ev.Subsystem != "backlight" || ev.Env["SEQNUM"] != "4711" {
+		t.Errorf("parseUevent() = %+v", ev)
+	}
+
+	if _, err := parseUevent([]byte("libudev\x00\xfe\xed\xca\xfe")); err == nil {
+		t.Error("expected libudev messages to be rejected")
+	}
+}
+
+func TestManager_UeventHotplug(t *testing.T) {
+	tmpDir := t.TempDir()
+	addBacklight := func(name string) {
+		dir := filepath.Join(tmpDir, "backlight", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "max_brightness"), []byte("100\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "brightness"), []byte("40\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addBacklight("intel_backlight")
+
+	sysfs := &SysfsBackend{
+		basePath:    tmpDir,
+		classes:     []string{"backlight", "leds"},
+		deviceCache: make(map[string]*sysfsDevice),
+	}
+	if err := sysfs.scanDevices(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		sysfsBackend:      sysfs,
+		sysfsReady:        true,
+		subscribers:       make(map[string]chan State),
+		updateSubscribers: make(map[string]chan DeviceUpdate),
+		stopChan:          make(chan struct{}),
+	}
+	m.updateState()
+	states := m.Subscribe("test")
+
+	addBacklight("ddcci5")
+	reader := &replayUeventReader{events: [][]byte{
+		recordedUevent("change@/devices/virtual/backlight/ddcci5", "ACTION=change", "SUBSYSTEM=backlight"),
+		recordedUevent("add@/devices/virtual/backlight/ddcci5", "ACTION=add", "SUBSYSTEM=backlight"),
+		recordedUevent("change@/devices/pci0000:00/0000:00:02.0/drm/card1", "ACTION=change", "SUBSYSTEM=drm", "HOTPLUG=1"),
+	}}
+	m.watchUevents(reader)
+
+	select {
+	case state := <-states:
+		if len(state.Devices) != 2 {
+			t.Errorf("expected 2 devices after hotplug, got %d", len(state.Devices))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no state broadcast after hotplug")
+	}
+
+	if err := os.RemoveAll(filepath.Join(tmpDir, "backlight", "ddcci5")); err != nil {
+		t.Fatal(err)
+	}
+	m.watchUevents(&replayUeventReader{events: [][]byte{
+		recordedUevent("remove@/devices/virtual/backlight/ddcci5", "ACTION=remove", "SUBSYSTEM=backlight"),
+	}})
+
+	if _, err := sysfs.GetDevice("backlight:ddcci5"); err == nil {
+		t.Error("removed device still cached")
+	}
+	if got := len(m.GetState().Devices); got != 1 {
+		t.Errorf("expected 1 device after removal, got %d", got)
+	}
+}
+
+func TestManager_DDCRescanOnlyOnHotplug(t *testing.T) {
+	m := &Manager{
+		ddcBackend: &DDCBackend{devices: make(map[string]*ddcDevice)},
+		ddcReady:   true,
+		stopChan:   make(chan struct{}),
+	}
+
+	m.handleUevent(&Uevent{Action: "change", Subsystem: "drm", Env: map[string]string{}})
+	if m.ddcRescanTimer != nil {
+		t.Error("drm change without HOTPLUG=1 scheduled a rescan")
+	}
+
+	m.handleUevent(&Uevent{Action: "change", Subsystem: "drm", Env: map[string]string{"HOTPLUG": "1"}})
+	if m.ddcRescanTimer == nil {
+		t.Fatal("drm hotplug did not schedule a rescan")
+	}
+	m.ddcRescanTimer.Stop()
+}
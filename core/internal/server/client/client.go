@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+const dialTimeout = 2 * time.Second
+
+type Response = models.Response[json.RawMessage]
+
+type Client struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	capabilities []string
+	writeMutex   sync.Mutex
+	nextID       int
+}
+
+func Dial(socketPath string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", socketPath, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", socketPath, err)
+	}
+
+	c := &Client{
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, 64*1024),
+	}
+
+	// The server greets every connection with its capabilities.
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read server greeting: %w", err)
+	}
+
+	var greeting struct {
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := json.Unmarshal(line, &greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("invalid server greeting: %w", err)
+	}
+	c.capabilities = greeting.Capabilities
+
+	return c, nil
+}
+
+func (c *Client) Capabilities() []string {
+	return c.capabilities
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) send(method string, params map[string]interface{}) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	c.nextID++
+	req := models.Request{ID: c.nextID, Method: method, Params: params}
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	return c.nextID, nil
+}
+
+func (c *Client) readResponse(id int) (*Response, error) {
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		var resp Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+		// IDs decode as float64; the client only ever sends small integers.
+		if respID, ok := resp.ID.(float64); ok && int(respID) == id {
+			return &resp, nil
+		}
+	}
+}
+
+// Call sends a request and waits for its response. Server-side errors are
+// returned as errors; the raw result is returned otherwise.
+func (c *Client) Call(method string, params map[string]interface{}) (json.RawMessage, error) {
+	id, err := c.send(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.readResponse(id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+	if resp.Result == nil {
+		return nil, nil
+	}
+	return *resp.Result, nil
+}
+
+// Stream sends a request and calls fn for every response the server sends
+// for it until the server closes the connection or fn returns an error.
+func (c *Client) Stream(method string, params map[string]interface{}, fn func(json.RawMessage) error) error {
+	id, err := c.send(method, params)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := c.readResponse(id)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		var result json.RawMessage
+		if resp.Result != nil {
+			result = *resp.Result
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) Subscribe(services []string, fn func(json.RawMessage) error) error {
+	params := map[string]interface{}{}
+	if len(services) > 0 {
+		params["services"] = services
+	}
+	return c.Stream("subscribe", params, fn)
+}
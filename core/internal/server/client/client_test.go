@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestServer(t *testing.T, handle func(conn net.Conn, req models.Request)) string {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte(`{"capabilities":["plugins","power"]}` + "\n"))
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var req models.Request
+					if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+						return
+					}
+					handle(conn, req)
+				}
+			}()
+		}
+	}()
+
+	return socketPath
+}
+
+func TestDialReadsCapabilities(t *testing.T) {
+	socketPath := startTestServer(t, func(conn net.Conn, req models.Request) {})
+
+	c, err := Dial(socketPath)
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.Equal(t, []string{"plugins", "power"}, c.Capabilities())
+}
+
+func TestCall(t *testing.T) {
+	socketPath := startTestServer(t, func(conn net.Conn, req models.Request) {
+		switch req.Method {
+		case "ping":
+			json.NewEncoder(conn).Encode(map[string]interface{}{"id": req.ID, "result": "pong"})
+		case "power.setProfile":
+			assert.Equal(t, "performance", req.Params["profile"])
+			json.NewEncoder(conn).Encode(map[string]interface{}{"id": req.ID, "error": "profile not available"})
+		}
+	})
+
+	c, err := Dial(socketPath)
+	require.NoError(t, err)
+	defer c.Close()
+
+	result, err := c.Call("ping", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"pong"`, string(result))
+
+	_, err = c.Call("power.setProfile", map[string]interface{}{"profile": "performance"})
+	assert.EqualError(t, err, "profile not available")
+}
+
+func TestSubscribe(t *testing.T) {
+	socketPath := startTestServer(t, func(conn net.Conn, req models.Request) {
+		assert.Equal(t, "subscribe", req.Method)
+		for i := 0; i < 3; i++ {
+			json.NewEncoder(conn).Encode(map[string]interface{}{
+				"id":     req.ID,
+				"result": map[string]interface{}{"service": "power", "data": i},
+			})
+		}
+		conn.Close()
+	})
+
+	c, err := Dial(socketPath)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var events []string
+	err = c.Subscribe([]string{"power"}, func(event json.RawMessage) error {
+		events = append(events, string(event))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"data":0,"service":"power"}`,
+		`{"data":1,"service":"power"}`,
+		`{"data":2,"service":"power"}`,
+	}, events)
+}
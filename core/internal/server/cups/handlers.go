@@ -9,7 +9,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -58,7 +58,7 @@ func handleGetPrinters(conn net.Conn, req Request, manager *Manager) {
 func handleGetJobs(conn net.Conn, req Request, manager *Manager) {
 	printerName, ok := req.Params["printerName"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'printerName' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'printerName' parameter"))
 		return
 	}
 
@@ -74,7 +74,7 @@ func handleGetJobs(conn net.Conn, req Request, manager *Manager) {
 func handlePausePrinter(conn net.Conn, req Request, manager *Manager) {
 	printerName, ok := req.Params["printerName"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'printerName' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'printerName' parameter"))
 		return
 	}
 
@@ -88,7 +88,7 @@ func handlePausePrinter(conn net.Conn, req Request, manager *Manager) {
 func handleResumePrinter(conn net.Conn, req Request, manager *Manager) {
 	printerName, ok := req.Params["printerName"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'printerName' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'printerName' parameter"))
 		return
 	}
 
@@ -102,7 +102,7 @@ func handleResumePrinter(conn net.Conn, req Request, manager *Manager) {
 func handleCancelJob(conn net.Conn, req Request, manager *Manager) {
 	jobIDFloat, ok := req.Params["jobID"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'jobid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'jobid' parameter"))
 		return
 	}
 	jobID := int(jobIDFloat)
@@ -117,7 +117,7 @@ func handleCancelJob(conn net.Conn, req Request, manager *Manager) {
 func handlePurgeJobs(conn net.Conn, req Request, manager *Manager) {
 	printerName, ok := req.Params["printerName"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'printerName' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'printerName' parameter"))
 		return
 	}
 
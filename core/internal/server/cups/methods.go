@@ -0,0 +1,51 @@
+package cups
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "cups.getPrinters",
+		Description: "Get printers list",
+	},
+	{
+		Name:        "cups.getJobs",
+		Description: "Get non-completed jobs list",
+		Params: []models.ParamSpec{
+			{Name: "printerName", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "cups.pausePrinter",
+		Description: "Pause printer",
+		Params: []models.ParamSpec{
+			{Name: "printerName", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "cups.resumePrinter",
+		Description: "Resume printer",
+		Params: []models.ParamSpec{
+			{Name: "printerName", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "cups.cancelJob",
+		Description: "Cancel job",
+		Params: []models.ParamSpec{
+			{Name: "printerName", Type: models.ParamString, Required: true},
+			{Name: "jobID", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "cups.purgeJobs",
+		Description: "Cancel all jobs",
+		Params: []models.ParamSpec{
+			{Name: "printerName", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "cups.subscribe",
+		Description: "Subscribe to printer and job changes",
+		Streaming:   true,
+	},
+}
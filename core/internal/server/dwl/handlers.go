@@ -9,7 +9,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -49,19 +49,19 @@ func handleGetState(conn net.Conn, req Request, manager *Manager) {
 func handleSetTags(conn net.Conn, req Request, manager *Manager) {
 	output, ok := req.Params["output"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'output' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'output' parameter"))
 		return
 	}
 
 	tagmask, ok := req.Params["tagmask"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'tagmask' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'tagmask' parameter"))
 		return
 	}
 
 	toggleTagset, ok := req.Params["toggleTagset"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'toggleTagset' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'toggleTagset' parameter"))
 		return
 	}
 
@@ -76,19 +76,19 @@ func handleSetTags(conn net.Conn, req Request, manager *Manager) {
 func handleSetClientTags(conn net.Conn, req Request, manager *Manager) {
 	output, ok := req.Params["output"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'output' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'output' parameter"))
 		return
 	}
 
 	andTags, ok := req.Params["andTags"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'andTags' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'andTags' parameter"))
 		return
 	}
 
 	xorTags, ok := req.Params["xorTags"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'xorTags' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'xorTags' parameter"))
 		return
 	}
 
@@ -103,13 +103,13 @@ func handleSetClientTags(conn net.Conn, req Request, manager *Manager) {
 func handleSetLayout(conn net.Conn, req Request, manager *Manager) {
 	output, ok := req.Params["output"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'output' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'output' parameter"))
 		return
 	}
 
 	index, ok := req.Params["index"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'index' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'index' parameter"))
 		return
 	}
 
@@ -0,0 +1,41 @@
+package dwl
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "dwl.getState",
+		Description: "Get current dwl state (tags, windows, layouts)",
+	},
+	{
+		Name:        "dwl.setTags",
+		Description: "Set active tags",
+		Params: []models.ParamSpec{
+			{Name: "output", Type: models.ParamString, Required: true},
+			{Name: "tagmask", Type: models.ParamNumber, Required: true},
+			{Name: "toggleTagset", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "dwl.setClientTags",
+		Description: "Set focused client tags",
+		Params: []models.ParamSpec{
+			{Name: "output", Type: models.ParamString, Required: true},
+			{Name: "andTags", Type: models.ParamNumber, Required: true},
+			{Name: "xorTags", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "dwl.setLayout",
+		Description: "Set layout",
+		Params: []models.ParamSpec{
+			{Name: "output", Type: models.ParamString, Required: true},
+			{Name: "index", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "dwl.subscribe",
+		Description: "Subscribe to dwl state changes",
+		Streaming:   true,
+	},
+}
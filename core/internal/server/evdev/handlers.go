@@ -7,7 +7,7 @@ import (
 )
 
 type Request struct {
-	ID     interface{}            `json:"id"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params"`
 }
@@ -17,11 +17,11 @@ func HandleRequest(conn net.Conn, req Request, m *Manager) {
 	case "evdev.getState":
 		handleGetState(conn, req, m)
 	default:
-		models.RespondError(conn, req.ID.(int), "unknown method: "+req.Method)
+		models.RespondError(conn, req.ID, "unknown method: "+req.Method)
 	}
 }
 
 func handleGetState(conn net.Conn, req Request, m *Manager) {
 	state := m.GetState()
-	models.Respond(conn, req.ID.(int), state)
+	models.Respond(conn, req.ID, state)
 }
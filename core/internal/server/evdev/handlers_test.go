@@ -3,14 +3,12 @@ package evdev
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	mocks "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/evdev"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 )
 
@@ -43,15 +41,8 @@ func (m *mockNetConn) Close() error {
 
 func TestHandleRequest(t *testing.T) {
 	t.Run("getState request", func(t *testing.T) {
-		mockDevice := mocks.NewMockEvdevDevice(t)
-		mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-		m := &Manager{
-			device:      mockDevice,
-			state:       State{Available: true, CapsLock: true},
-			subscribers: make(map[string]chan State),
-			closeChan:   make(chan struct{}),
-		}
+		m := newManager()
+		m.state = State{Available: true, CapsLock: true}
 
 		conn := newMockNetConn()
 		req := Request{
@@ -66,22 +57,15 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Available)
 		assert.True(t, resp.Result.CapsLock)
 	})
 
 	t.Run("unknown method", func(t *testing.T) {
-		mockDevice := mocks.NewMockEvdevDevice(t)
-		mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-		m := &Manager{
-			device:      mockDevice,
-			state:       State{Available: true, CapsLock: false},
-			subscribers: make(map[string]chan State),
-			closeChan:   make(chan struct{}),
-		}
+		m := newManager()
+		m.state = State{Available: true, CapsLock: false}
 
 		conn := newMockNetConn()
 		req := Request{
@@ -96,22 +80,15 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 456, resp.ID)
+		assert.EqualValues(t, 456, resp.ID)
 		assert.NotEmpty(t, resp.Error)
 		assert.Contains(t, resp.Error, "unknown method")
 	})
 }
 
 func TestHandleGetState(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	conn := newMockNetConn()
 	req := Request{
@@ -126,7 +103,7 @@ func TestHandleGetState(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 789, resp.ID)
+	assert.EqualValues(t, 789, resp.ID)
 	assert.NotNil(t, resp.Result)
 	assert.True(t, resp.Result.Available)
 	assert.False(t, resp.Result.CapsLock)
@@ -1,100 +1,281 @@
 package evdev
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
 	evdev "github.com/holoplot/go-evdev"
+	"golang.org/x/sys/unix"
 )
 
 const (
-	evKeyType      = 0x01
-	evLedType      = 0x11
-	keyCapslockKey = 58
-	ledCapslockKey = 1
-	keyStateOn     = 1
+	evKeyType        = 0x01
+	evSwType         = 0x05
+	evLedType        = 0x11
+	keyCapslockKey   = 58
+	keyNumlockKey    = 69
+	keyScrolllockKey = 70
+	ledNumlockKey    = 0
+	ledCapslockKey   = 1
+	ledScrolllockKey = 2
+	swLid            = 0x00
+	swTabletMode     = 0x01
+	keyStateOn       = 1
 )
 
+const (
+	inputDir           = "/dev/input"
+	inputDevicePattern = inputDir + "/event*"
+)
+
+var lockKeyLEDs = map[evdev.EvCode]evdev.EvCode{
+	keyCapslockKey:   ledCapslockKey,
+	keyNumlockKey:    ledNumlockKey,
+	keyScrolllockKey: ledScrolllockKey,
+}
+
 type EvdevDevice interface {
 	Name() (string, error)
 	Path() string
 	Close() error
 	ReadOne() (*evdev.InputEvent, error)
 	State(t evdev.EvType) (evdev.StateMap, error)
+	CapableEvents(t evdev.EvType) []evdev.EvCode
+}
+
+// lockState is indexed by LED code: num, caps and scroll lock.
+type lockState [ledScrolllockKey + 1]bool
+
+func (l *lockState) set(led evdev.EvCode, on bool) bool {
+	if int(led) >= len(l) || l[led] == on {
+		return false
+	}
+	l[led] = on
+	return true
+}
+
+type inputDevice struct {
+	device     EvdevDevice
+	name       string
+	keyboard   bool
+	hasLid     bool
+	hasTablet  bool
+	leds       lockState
+	lidClosed  bool
+	tabletMode bool
 }
 
 type Manager struct {
-	device      EvdevDevice
+	devices     map[string]*inputDevice
+	locks       lockState
 	state       State
 	stateMutex  sync.RWMutex
 	subscribers map[string]chan State
 	subMutex    sync.RWMutex
+	watcher     *os.File
 	closeChan   chan struct{}
 	closeOnce   sync.Once
 }
 
+func newManager() *Manager {
+	return &Manager{
+		devices:     make(map[string]*inputDevice),
+		state:       State{Available: true, Keyboards: []KeyboardState{}},
+		subscribers: make(map[string]chan State),
+		closeChan:   make(chan struct{}),
+	}
+}
+
 func NewManager() (*Manager, error) {
-	device, err := findKeyboard()
+	m := newManager()
+
+	watcher, err := watchInputDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to find keyboard: %w", err)
+		log.Warnf("Input device hotplug unavailable: %v", err)
 	}
 
-	initialCapsLock := readInitialCapsLockState(device)
+	matches, err := filepath.Glob(inputDevicePattern)
+	if err != nil {
+		if watcher != nil {
+			watcher.Close()
+		}
+		return nil, fmt.Errorf("failed to glob input devices: %w", err)
+	}
 
-	m := &Manager{
-		device:      device,
-		state:       State{Available: true, CapsLock: initialCapsLock},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
+	for _, path := range matches {
+		m.openDevice(path)
+	}
+
+	if watcher == nil {
+		if len(m.devices) == 0 {
+			return nil, fmt.Errorf("no keyboard or switch devices found")
+		}
+		return m, nil
 	}
 
-	go m.monitorCapsLock()
+	m.watcher = watcher
+	go m.watchHotplug()
 
 	return m, nil
 }
 
-func readInitialCapsLockState(device EvdevDevice) bool {
+func readLEDState(device EvdevDevice) lockState {
+	var leds lockState
+
 	ledStates, err := device.State(evLedType)
 	if err != nil {
 		log.Debugf("Could not read LED state: %v", err)
-		return false
+		return leds
 	}
 
-	return ledStates[ledCapslockKey]
+	for led := range leds {
+		leds[led] = ledStates[evdev.EvCode(led)]
+	}
+	return leds
 }
 
-func findKeyboard() (EvdevDevice, error) {
-	pattern := "/dev/input/event*"
-	matches, err := filepath.Glob(pattern)
+func (m *Manager) openDevice(path string) {
+	m.stateMutex.RLock()
+	_, tracked := m.devices[path]
+	m.stateMutex.RUnlock()
+	if tracked {
+		return
+	}
+
+	device, err := evdev.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to glob input devices: %w", err)
+		log.Debugf("Could not open input device %s: %v", path, err)
+		return
 	}
 
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no input devices found")
+	if !m.addDevice(device) {
+		device.Close()
 	}
+}
 
-	for _, path := range matches {
-		device, err := evdev.Open(path)
-		if err != nil {
-			continue
+// addDevice starts tracking a keyboard or switch device. It returns false if
+// the device is neither or is already tracked, leaving it to the caller to
+// close it.
+func (m *Manager) addDevice(device EvdevDevice) bool {
+	path := device.Path()
+	name, _ := device.Name()
+
+	switches := device.CapableEvents(evSwType)
+	dev := &inputDevice{
+		device:    device,
+		name:      name,
+		keyboard:  isKeyboard(device) || slices.Contains(device.CapableEvents(evLedType), ledCapslockKey),
+		hasLid:    slices.Contains(switches, swLid),
+		hasTablet: slices.Contains(switches, swTabletMode),
+	}
+	if !dev.keyboard && !dev.hasLid && !dev.hasTablet {
+		return false
+	}
+
+	if dev.keyboard {
+		dev.leds = readLEDState(device)
+	}
+	if dev.hasLid || dev.hasTablet {
+		if swStates, err := device.State(evSwType); err == nil {
+			dev.lidClosed = dev.hasLid && swStates[swLid]
+			dev.tabletMode = dev.hasTablet && swStates[swTabletMode]
 		}
+	}
 
-		if isKeyboard(device) {
-			deviceName, _ := device.Name()
-			log.Debugf("Found keyboard: %s at %s", deviceName, path)
-			return device, nil
+	m.stateMutex.Lock()
+	if _, exists := m.devices[path]; exists {
+		m.stateMutex.Unlock()
+		return false
+	}
+	if dev.keyboard && !m.hasKeyboardLocked() {
+		m.locks = dev.leds
+	}
+	m.devices[path] = dev
+	state := m.updateStateLocked()
+	m.stateMutex.Unlock()
+
+	log.Debugf("Tracking input device: %s at %s (keyboard=%v lid=%v tablet=%v)", name, path, dev.keyboard, dev.hasLid, dev.hasTablet)
+	m.notifySubscribers(state)
+
+	go m.monitorDevice(path, device)
+	return true
+}
+
+func (m *Manager) removeDevice(path string, device EvdevDevice) {
+	m.stateMutex.Lock()
+	dev, ok := m.devices[path]
+	if !ok || (device != nil && dev.device != device) {
+		m.stateMutex.Unlock()
+		return
+	}
+	delete(m.devices, path)
+	state := m.updateStateLocked()
+	m.stateMutex.Unlock()
+
+	if err := dev.device.Close(); err != nil && !isClosedError(err) {
+		log.Warnf("Error closing evdev device %s: %v", path, err)
+	}
+
+	log.Debugf("Input device removed: %s at %s", dev.name, path)
+	m.notifySubscribers(state)
+}
+
+func (m *Manager) hasKeyboardLocked() bool {
+	for _, dev := range m.devices {
+		if dev.keyboard {
+			return true
 		}
+	}
+	return false
+}
 
-		device.Close()
+func (m *Manager) updateStateLocked() State {
+	paths := make([]string, 0, len(m.devices))
+	for path := range m.devices {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	state := State{
+		Available:  true,
+		CapsLock:   m.locks[ledCapslockKey],
+		NumLock:    m.locks[ledNumlockKey],
+		ScrollLock: m.locks[ledScrolllockKey],
+		Keyboards:  []KeyboardState{},
 	}
 
-	return nil, fmt.Errorf("no keyboard device found")
+	for _, path := range paths {
+		dev := m.devices[path]
+		if dev.keyboard {
+			state.Keyboards = append(state.Keyboards, KeyboardState{
+				Path:       path,
+				Name:       dev.name,
+				CapsLock:   dev.leds[ledCapslockKey],
+				NumLock:    dev.leds[ledNumlockKey],
+				ScrollLock: dev.leds[ledScrolllockKey],
+			})
+		}
+		if dev.hasLid {
+			state.HasLid = true
+			state.LidClosed = state.LidClosed || dev.lidClosed
+		}
+		if dev.hasTablet {
+			state.HasTabletMode = true
+			state.TabletMode = state.TabletMode || dev.tabletMode
+		}
+	}
+
+	m.state = state
+	return state
 }
 
 func isKeyboard(device EvdevDevice) bool {
@@ -117,7 +298,7 @@ func isKeyboard(device EvdevDevice) bool {
 	}
 }
 
-func (m *Manager) monitorCapsLock() {
+func (m *Manager) monitorDevice(path string, device EvdevDevice) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Errorf("Panic in evdev monitor: %v", r)
@@ -131,11 +312,13 @@ func (m *Manager) monitorCapsLock() {
 		default:
 		}
 
-		event, err := m.device.ReadOne()
+		event, err := device.ReadOne()
 		if err != nil {
-			if !isClosedError(err) {
-				log.Warnf("Failed to read evdev event: %v", err)
+			if isClosedError(err) || errors.Is(err, syscall.ENODEV) {
+				m.removeDevice(path, device)
+				return
 			}
+			log.Warnf("Failed to read evdev event: %v", err)
 			time.Sleep(100 * time.Millisecond)
 			continue
 		}
@@ -144,9 +327,20 @@ func (m *Manager) monitorCapsLock() {
 			continue
 		}
 
-		if event.Type == evKeyType && event.Code == keyCapslockKey && event.Value == keyStateOn {
-			m.toggleCapsLock()
+		m.handleEvent(path, event)
+	}
+}
+
+func (m *Manager) handleEvent(path string, event *evdev.InputEvent) {
+	switch event.Type {
+	case evKeyType:
+		if event.Value == keyStateOn {
+			m.toggleLock(event.Code)
 		}
+	case evLedType:
+		m.setLED(path, event.Code, event.Value != 0)
+	case evSwType:
+		m.setSwitch(path, event.Code, event.Value != 0)
 	}
 }
 
@@ -166,16 +360,143 @@ func isClosedError(err error) bool {
 	}
 }
 
-func (m *Manager) toggleCapsLock() {
+// toggleLock tracks lock keys on keyboards whose LEDs are not driven by the
+// compositor. LED events, when they arrive, override the toggled state.
+func (m *Manager) toggleLock(key evdev.EvCode) {
+	led, ok := lockKeyLEDs[key]
+	if !ok {
+		return
+	}
+
 	m.stateMutex.Lock()
-	m.state.CapsLock = !m.state.CapsLock
-	newState := m.state
+	m.locks.set(led, !m.locks[led])
+	newState := m.updateStateLocked()
+	m.stateMutex.Unlock()
+
+	log.Debugf("Lock key %d toggled: caps=%v num=%v scroll=%v", key, newState.CapsLock, newState.NumLock, newState.ScrollLock)
+	m.notifySubscribers(newState)
+}
+
+func (m *Manager) setLED(path string, led evdev.EvCode, on bool) {
+	m.stateMutex.Lock()
+	dev, ok := m.devices[path]
+	if !ok || !dev.keyboard {
+		m.stateMutex.Unlock()
+		return
+	}
+
+	changed := dev.leds.set(led, on)
+	changed = m.locks.set(led, on) || changed
+	if !changed {
+		m.stateMutex.Unlock()
+		return
+	}
+	newState := m.updateStateLocked()
+	m.stateMutex.Unlock()
+
+	m.notifySubscribers(newState)
+}
+
+func (m *Manager) setSwitch(path string, code evdev.EvCode, on bool) {
+	m.stateMutex.Lock()
+	dev, ok := m.devices[path]
+	if !ok {
+		m.stateMutex.Unlock()
+		return
+	}
+
+	var field *bool
+	switch {
+	case code == swLid && dev.hasLid:
+		field = &dev.lidClosed
+	case code == swTabletMode && dev.hasTablet:
+		field = &dev.tabletMode
+	}
+	if field == nil || *field == on {
+		m.stateMutex.Unlock()
+		return
+	}
+	*field = on
+	newState := m.updateStateLocked()
 	m.stateMutex.Unlock()
 
-	log.Debugf("Caps lock toggled: %v", newState.CapsLock)
+	log.Debugf("Switch %d on %s: %v", code, path, on)
 	m.notifySubscribers(newState)
 }
 
+func watchInputDir() (*os.File, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	if _, err := unix.InotifyAddWatch(fd, inputDir, unix.IN_CREATE|unix.IN_ATTRIB|unix.IN_DELETE); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to watch %s: %w", inputDir, err)
+	}
+
+	return os.NewFile(uintptr(fd), "inotify"), nil
+}
+
+type inotifyEvent struct {
+	mask uint32
+	name string
+}
+
+func parseInotifyEvents(buf []byte) []inotifyEvent {
+	var events []inotifyEvent
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		mask := binary.NativeEndian.Uint32(buf[offset+4:])
+		nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+		nameStart := offset + unix.SizeofInotifyEvent
+		if nameStart+nameLen > len(buf) {
+			break
+		}
+		events = append(events, inotifyEvent{
+			mask: mask,
+			name: strings.TrimRight(string(buf[nameStart:nameStart+nameLen]), "\x00"),
+		})
+		offset = nameStart + nameLen
+	}
+	return events
+}
+
+func (m *Manager) watchHotplug() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := m.watcher.Read(buf)
+		if err != nil {
+			select {
+			case <-m.closeChan:
+			default:
+				log.Warnf("Input device hotplug watcher stopped: %v", err)
+			}
+			return
+		}
+
+		for _, event := range parseInotifyEvents(buf[:n]) {
+			m.handleHotplug(event)
+		}
+	}
+}
+
+// handleHotplug reacts to device nodes appearing or disappearing. Nodes are
+// usually created before udev grants access, so attribute changes retry the
+// open.
+func (m *Manager) handleHotplug(event inotifyEvent) {
+	if !strings.HasPrefix(event.name, "event") {
+		return
+	}
+
+	path := filepath.Join(inputDir, event.name)
+	switch {
+	case event.mask&unix.IN_DELETE != 0:
+		m.removeDevice(path, nil)
+	case event.mask&(unix.IN_CREATE|unix.IN_ATTRIB) != 0:
+		m.openDevice(path)
+	}
+}
+
 func (m *Manager) GetState() State {
 	m.stateMutex.RLock()
 	defer m.stateMutex.RUnlock()
@@ -217,9 +538,18 @@ func (m *Manager) Close() {
 	m.closeOnce.Do(func() {
 		close(m.closeChan)
 
-		if m.device != nil {
-			if err := m.device.Close(); err != nil && !isClosedError(err) {
-				log.Warnf("Error closing evdev device: %v", err)
+		if m.watcher != nil {
+			m.watcher.Close()
+		}
+
+		m.stateMutex.Lock()
+		devices := m.devices
+		m.devices = make(map[string]*inputDevice)
+		m.stateMutex.Unlock()
+
+		for path, dev := range devices {
+			if err := dev.device.Close(); err != nil && !isClosedError(err) {
+				log.Warnf("Error closing evdev device %s: %v", path, err)
 			}
 		}
 
@@ -241,8 +571,7 @@ func InitializeManager() (*Manager, error) {
 }
 
 func hasInputGroupAccess() bool {
-	pattern := "/dev/input/event*"
-	matches, err := filepath.Glob(pattern)
+	matches, err := filepath.Glob(inputDevicePattern)
 	if err != nil || len(matches) == 0 {
 		return false
 	}
@@ -1,26 +1,24 @@
 package evdev
 
 import (
+	"encoding/binary"
 	"errors"
+	"os"
+	"sync"
 	"testing"
 
 	evdev "github.com/holoplot/go-evdev"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 
 	mocks "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/evdev"
 )
 
 func TestManager_Creation(t *testing.T) {
 	t.Run("manager created successfully with caps lock off", func(t *testing.T) {
-		mockDevice := mocks.NewMockEvdevDevice(t)
-		mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-		m := &Manager{
-			device:      mockDevice,
-			state:       State{Available: true, CapsLock: false},
-			subscribers: make(map[string]chan State),
-			closeChan:   make(chan struct{}),
-		}
+		m := newManager()
+		m.state = State{Available: true, CapsLock: false}
 
 		assert.NotNil(t, m)
 		assert.True(t, m.state.Available)
@@ -28,15 +26,8 @@ func TestManager_Creation(t *testing.T) {
 	})
 
 	t.Run("manager created successfully with caps lock on", func(t *testing.T) {
-		mockDevice := mocks.NewMockEvdevDevice(t)
-		mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-		m := &Manager{
-			device:      mockDevice,
-			state:       State{Available: true, CapsLock: true},
-			subscribers: make(map[string]chan State),
-			closeChan:   make(chan struct{}),
-		}
+		m := newManager()
+		m.state = State{Available: true, CapsLock: true}
 
 		assert.NotNil(t, m)
 		assert.True(t, m.state.Available)
@@ -45,15 +36,8 @@ func TestManager_Creation(t *testing.T) {
 }
 
 func TestManager_GetState(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	state := m.GetState()
 	assert.True(t, state.Available)
@@ -61,15 +45,8 @@ func TestManager_GetState(t *testing.T) {
 }
 
 func TestManager_Subscribe(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	ch := m.Subscribe("test-client")
 	assert.NotNil(t, ch)
@@ -77,15 +54,8 @@ func TestManager_Subscribe(t *testing.T) {
 }
 
 func TestManager_Unsubscribe(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	ch := m.Subscribe("test-client")
 	assert.Len(t, m.subscribers, 1)
@@ -101,45 +71,41 @@ func TestManager_Unsubscribe(t *testing.T) {
 	}
 }
 
-func TestManager_ToggleCapsLock(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+func TestManager_ToggleLock(t *testing.T) {
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	ch := m.Subscribe("test-client")
 
 	go func() {
-		m.toggleCapsLock()
+		m.toggleLock(keyCapslockKey)
 	}()
 
 	newState := <-ch
 	assert.True(t, newState.CapsLock)
 
 	go func() {
-		m.toggleCapsLock()
+		m.toggleLock(keyCapslockKey)
 	}()
 
 	newState = <-ch
 	assert.False(t, newState.CapsLock)
+
+	go func() {
+		m.toggleLock(keyNumlockKey)
+	}()
+
+	newState = <-ch
+	assert.True(t, newState.NumLock)
+	assert.False(t, newState.ScrollLock)
 }
 
 func TestManager_Close(t *testing.T) {
 	mockDevice := mocks.NewMockEvdevDevice(t)
 	mockDevice.EXPECT().Close().Return(nil).Once()
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
 
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.devices["/dev/input/event0"] = &inputDevice{device: mockDevice, keyboard: true}
 
 	ch1 := m.Subscribe("client1")
 	ch2 := m.Subscribe("client2")
@@ -161,6 +127,7 @@ func TestManager_Close(t *testing.T) {
 	}
 
 	assert.Len(t, m.subscribers, 0)
+	assert.Empty(t, m.devices)
 
 	m.Close()
 }
@@ -197,7 +164,7 @@ func TestIsKeyboard_ErrorHandling(t *testing.T) {
 	assert.False(t, result)
 }
 
-func TestManager_MonitorCapsLock(t *testing.T) {
+func TestManager_MonitorDevice(t *testing.T) {
 	t.Run("caps lock key press toggles state", func(t *testing.T) {
 		mockDevice := mocks.NewMockEvdevDevice(t)
 
@@ -211,16 +178,12 @@ func TestManager_MonitorCapsLock(t *testing.T) {
 		mockDevice.EXPECT().ReadOne().Return(nil, errors.New("stop")).Maybe()
 		mockDevice.EXPECT().Close().Return(nil).Maybe()
 
-		m := &Manager{
-			device:      mockDevice,
-			state:       State{Available: true, CapsLock: false},
-			subscribers: make(map[string]chan State),
-			closeChan:   make(chan struct{}),
-		}
+		m := newManager()
+		m.devices["/dev/input/event0"] = &inputDevice{device: mockDevice, keyboard: true}
 
 		ch := m.Subscribe("test")
 
-		go m.monitorCapsLock()
+		go m.monitorDevice("/dev/input/event0", mockDevice)
 
 		state := <-ch
 		assert.True(t, state.CapsLock)
@@ -250,16 +213,8 @@ func TestIsClosedError(t *testing.T) {
 }
 
 func TestNotifySubscribers(t *testing.T) {
-	mockDevice := mocks.NewMockEvdevDevice(t)
-	mockDevice.EXPECT().ReadOne().Return(nil, errors.New("test")).Maybe()
-	mockDevice.EXPECT().Close().Return(nil).Maybe()
-
-	m := &Manager{
-		device:      mockDevice,
-		state:       State{Available: true, CapsLock: false},
-		subscribers: make(map[string]chan State),
-		closeChan:   make(chan struct{}),
-	}
+	m := newManager()
+	m.state = State{Available: true, CapsLock: false}
 
 	ch1 := m.Subscribe("client1")
 	ch2 := m.Subscribe("client2")
@@ -276,35 +231,28 @@ func TestNotifySubscribers(t *testing.T) {
 	m.Close()
 }
 
-func TestReadInitialCapsLockState(t *testing.T) {
-	t.Run("caps lock is on", func(t *testing.T) {
-		mockDevice := mocks.NewMockEvdevDevice(t)
-		ledState := evdev.StateMap{
-			ledCapslockKey: true,
-		}
-		mockDevice.EXPECT().State(evdev.EvType(evLedType)).Return(ledState, nil).Once()
-
-		result := readInitialCapsLockState(mockDevice)
-		assert.True(t, result)
-	})
-
-	t.Run("caps lock is off", func(t *testing.T) {
+func TestReadLEDState(t *testing.T) {
+	t.Run("lock LEDs are read", func(t *testing.T) {
 		mockDevice := mocks.NewMockEvdevDevice(t)
 		ledState := evdev.StateMap{
-			ledCapslockKey: false,
+			ledCapslockKey:   true,
+			ledNumlockKey:    true,
+			ledScrolllockKey: false,
 		}
 		mockDevice.EXPECT().State(evdev.EvType(evLedType)).Return(ledState, nil).Once()
 
-		result := readInitialCapsLockState(mockDevice)
-		assert.False(t, result)
+		result := readLEDState(mockDevice)
+		assert.True(t, result[ledCapslockKey])
+		assert.True(t, result[ledNumlockKey])
+		assert.False(t, result[ledScrolllockKey])
 	})
 
 	t.Run("error reading LED state", func(t *testing.T) {
 		mockDevice := mocks.NewMockEvdevDevice(t)
 		mockDevice.EXPECT().State(evdev.EvType(evLedType)).Return(nil, errors.New("read error")).Once()
 
-		result := readInitialCapsLockState(mockDevice)
-		assert.False(t, result)
+		result := readLEDState(mockDevice)
+		assert.Equal(t, lockState{}, result)
 	})
 }
 
@@ -312,3 +260,146 @@ func TestHasInputGroupAccess(t *testing.T) {
 	result := hasInputGroupAccess()
 	t.Logf("hasInputGroupAccess: %v", result)
 }
+
+type testDevice struct {
+	*mocks.MockEvdevDevice
+	events chan *evdev.InputEvent
+}
+
+func newTestDevice(t *testing.T, path, name string, leds, switches evdev.StateMap) *testDevice {
+	mockDevice := mocks.NewMockEvdevDevice(t)
+	d := &testDevice{MockEvdevDevice: mockDevice, events: make(chan *evdev.InputEvent, 8)}
+
+	var swCodes []evdev.EvCode
+	for code := range switches {
+		swCodes = append(swCodes, code)
+	}
+
+	var closeOnce sync.Once
+	mockDevice.EXPECT().Path().Return(path).Maybe()
+	mockDevice.EXPECT().Name().Return(name, nil).Maybe()
+	mockDevice.EXPECT().CapableEvents(evdev.EvType(evSwType)).Return(swCodes).Maybe()
+	mockDevice.EXPECT().CapableEvents(evdev.EvType(evLedType)).Return(nil).Maybe()
+	mockDevice.EXPECT().State(evdev.EvType(evLedType)).Return(leds, nil).Maybe()
+	mockDevice.EXPECT().State(evdev.EvType(evSwType)).Return(switches, nil).Maybe()
+	mockDevice.EXPECT().ReadOne().RunAndReturn(func() (*evdev.InputEvent, error) {
+		event, ok := <-d.events
+		if !ok {
+			return nil, os.ErrClosed
+		}
+		return event, nil
+	}).Maybe()
+	mockDevice.EXPECT().Close().RunAndReturn(func() error {
+		closeOnce.Do(func() { close(d.events) })
+		return nil
+	}).Maybe()
+
+	return d
+}
+
+func TestManager_AddDevice(t *testing.T) {
+	m := newManager()
+	defer m.Close()
+
+	mouse := newTestDevice(t, "/dev/input/event2", "Logitech Mouse", nil, nil)
+	assert.False(t, m.addDevice(mouse))
+
+	internal := newTestDevice(t, "/dev/input/event3", "AT Translated Set 2 keyboard", evdev.StateMap{ledNumlockKey: true}, nil)
+	external := newTestDevice(t, "/dev/input/event7", "USB kbd", evdev.StateMap{}, nil)
+	require.True(t, m.addDevice(internal))
+	require.True(t, m.addDevice(external))
+	assert.False(t, m.addDevice(external), "already tracked")
+
+	state := m.GetState()
+	assert.True(t, state.NumLock, "seeded from the first keyboard")
+	require.Len(t, state.Keyboards, 2)
+	assert.Equal(t, KeyboardState{Path: "/dev/input/event3", Name: "AT Translated Set 2 keyboard", NumLock: true}, state.Keyboards[0])
+	assert.Equal(t, "/dev/input/event7", state.Keyboards[1].Path)
+	assert.False(t, state.HasLid)
+}
+
+func TestManager_LEDEvents(t *testing.T) {
+	m := newManager()
+	defer m.Close()
+
+	kbd := newTestDevice(t, "/dev/input/event3", "USB kbd", evdev.StateMap{}, nil)
+	require.True(t, m.addDevice(kbd))
+	ch := m.Subscribe("test")
+
+	kbd.events <- &evdev.InputEvent{Type: evLedType, Code: ledScrolllockKey, Value: 1}
+	state := <-ch
+	assert.True(t, state.ScrollLock)
+	assert.True(t, state.Keyboards[0].ScrollLock)
+
+	kbd.events <- &evdev.InputEvent{Type: evLedType, Code: ledScrolllockKey, Value: 1}
+	kbd.events <- &evdev.InputEvent{Type: evLedType, Code: ledCapslockKey, Value: 1}
+	state = <-ch
+	assert.True(t, state.CapsLock, "unchanged LEDs are not broadcast")
+}
+
+func TestManager_Switches(t *testing.T) {
+	m := newManager()
+	defer m.Close()
+
+	lid := newTestDevice(t, "/dev/input/event0", "Lid Switch", nil, evdev.StateMap{swLid: false})
+	tablet := newTestDevice(t, "/dev/input/event5", "Intel HID switches", nil, evdev.StateMap{swTabletMode: true})
+	require.True(t, m.addDevice(lid))
+	require.True(t, m.addDevice(tablet))
+
+	state := m.GetState()
+	assert.True(t, state.HasLid)
+	assert.False(t, state.LidClosed)
+	assert.True(t, state.HasTabletMode)
+	assert.True(t, state.TabletMode)
+	assert.Empty(t, state.Keyboards)
+
+	ch := m.Subscribe("test")
+	lid.events <- &evdev.InputEvent{Type: evSwType, Code: swLid, Value: 1}
+	state = <-ch
+	assert.True(t, state.LidClosed)
+
+	tablet.events <- &evdev.InputEvent{Type: evSwType, Code: swTabletMode, Value: 0}
+	state = <-ch
+	assert.False(t, state.TabletMode)
+}
+
+func TestManager_DeviceRemoved(t *testing.T) {
+	m := newManager()
+	defer m.Close()
+
+	kbd := newTestDevice(t, "/dev/input/event3", "USB kbd", evdev.StateMap{}, nil)
+	lid := newTestDevice(t, "/dev/input/event0", "Lid Switch", nil, evdev.StateMap{swLid: false})
+	require.True(t, m.addDevice(kbd))
+	require.True(t, m.addDevice(lid))
+	ch := m.Subscribe("test")
+
+	kbd.Close()
+	state := <-ch
+	assert.Empty(t, state.Keyboards)
+	assert.True(t, state.HasLid)
+
+	m.handleHotplug(inotifyEvent{mask: unix.IN_DELETE, name: "event0"})
+	state = <-ch
+	assert.False(t, state.HasLid)
+	assert.Empty(t, m.devices)
+}
+
+func TestParseInotifyEvents(t *testing.T) {
+	record := func(mask uint32, name string) []byte {
+		nameLen := (len(name) + 16) &^ 15
+		buf := make([]byte, unix.SizeofInotifyEvent+nameLen)
+		binary.NativeEndian.PutUint32(buf[4:], mask)
+		binary.NativeEndian.PutUint32(buf[12:], uint32(nameLen))
+		copy(buf[unix.SizeofInotifyEvent:], name)
+		return buf
+	}
+
+	buf := append(record(unix.IN_CREATE, "event12"), record(unix.IN_DELETE, "js0")...)
+	events := parseInotifyEvents(buf)
+	assert.Equal(t, []inotifyEvent{
+		{mask: unix.IN_CREATE, name: "event12"},
+		{mask: unix.IN_DELETE, name: "js0"},
+	}, events)
+
+	assert.Empty(t, parseInotifyEvents(buf[:8]))
+}
@@ -0,0 +1,13 @@
+package evdev
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "evdev.getState",
+		Description: "Get current evdev state (lock keys, per-keyboard LEDs, lid and tablet mode switches)",
+		Notes: []string{
+			"Input devices are tracked as they are added to or removed from /dev/input",
+		},
+	},
+}
@@ -1,6 +1,21 @@
 package evdev
 
+type KeyboardState struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	CapsLock   bool   `json:"capsLock"`
+	NumLock    bool   `json:"numLock"`
+	ScrollLock bool   `json:"scrollLock"`
+}
+
 type State struct {
-	Available bool `json:"available"`
-	CapsLock  bool `json:"capsLock"`
+	Available     bool            `json:"available"`
+	CapsLock      bool            `json:"capsLock"`
+	NumLock       bool            `json:"numLock"`
+	ScrollLock    bool            `json:"scrollLock"`
+	HasLid        bool            `json:"hasLid"`
+	LidClosed     bool            `json:"lidClosed"`
+	HasTabletMode bool            `json:"hasTabletMode"`
+	TabletMode    bool            `json:"tabletMode"`
+	Keyboards     []KeyboardState `json:"keyboards"`
 }
@@ -9,7 +9,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -56,7 +56,7 @@ func handleActivateWorkspace(conn net.Conn, req Request, manager *Manager) {
 
 	workspaceID, ok := req.Params["workspaceID"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'workspaceID' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'workspaceID' parameter"))
 		return
 	}
 
@@ -76,7 +76,7 @@ func handleDeactivateWorkspace(conn net.Conn, req Request, manager *Manager) {
 
 	workspaceID, ok := req.Params["workspaceID"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'workspaceID' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'workspaceID' parameter"))
 		return
 	}
 
@@ -96,7 +96,7 @@ func handleRemoveWorkspace(conn net.Conn, req Request, manager *Manager) {
 
 	workspaceID, ok := req.Params["workspaceID"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'workspaceID' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'workspaceID' parameter"))
 		return
 	}
 
@@ -111,13 +111,13 @@ func handleRemoveWorkspace(conn net.Conn, req Request, manager *Manager) {
 func handleCreateWorkspace(conn net.Conn, req Request, manager *Manager) {
 	groupID, ok := req.Params["groupID"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'groupID' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'groupID' parameter"))
 		return
 	}
 
 	workspaceName, ok := req.Params["name"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'name' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
 		return
 	}
 
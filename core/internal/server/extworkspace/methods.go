@@ -0,0 +1,47 @@
+package extworkspace
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "extworkspace.getState",
+		Description: "Get current workspace state (groups, workspaces)",
+	},
+	{
+		Name:        "extworkspace.activateWorkspace",
+		Description: "Activate workspace",
+		Params: []models.ParamSpec{
+			{Name: "groupID", Type: models.ParamString, Required: true},
+			{Name: "workspaceID", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "extworkspace.deactivateWorkspace",
+		Description: "Deactivate workspace",
+		Params: []models.ParamSpec{
+			{Name: "groupID", Type: models.ParamString, Required: true},
+			{Name: "workspaceID", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "extworkspace.removeWorkspace",
+		Description: "Remove workspace",
+		Params: []models.ParamSpec{
+			{Name: "groupID", Type: models.ParamString, Required: true},
+			{Name: "workspaceID", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "extworkspace.createWorkspace",
+		Description: "Create workspace",
+		Params: []models.ParamSpec{
+			{Name: "groupID", Type: models.ParamString, Required: true},
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "extworkspace.subscribe",
+		Description: "Subscribe to workspace state changes",
+		Streaming:   true,
+	},
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -52,7 +52,7 @@ func handleGetState(conn net.Conn, req Request, manager *Manager) {
 func handleSetIconFile(conn net.Conn, req Request, manager *Manager) {
 	iconPath, ok := req.Params["path"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'path' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'path' parameter"))
 		return
 	}
 
@@ -67,7 +67,7 @@ func handleSetIconFile(conn net.Conn, req Request, manager *Manager) {
 func handleSetRealName(conn net.Conn, req Request, manager *Manager) {
 	name, ok := req.Params["name"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'name' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
 		return
 	}
 
@@ -82,7 +82,7 @@ func handleSetRealName(conn net.Conn, req Request, manager *Manager) {
 func handleSetEmail(conn net.Conn, req Request, manager *Manager) {
 	email, ok := req.Params["email"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'email' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'email' parameter"))
 		return
 	}
 
@@ -97,7 +97,7 @@ func handleSetEmail(conn net.Conn, req Request, manager *Manager) {
 func handleSetLanguage(conn net.Conn, req Request, manager *Manager) {
 	language, ok := req.Params["language"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'language' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'language' parameter"))
 		return
 	}
 
@@ -112,7 +112,7 @@ func handleSetLanguage(conn net.Conn, req Request, manager *Manager) {
 func handleSetLocation(conn net.Conn, req Request, manager *Manager) {
 	location, ok := req.Params["location"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'location' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'location' parameter"))
 		return
 	}
 
@@ -127,7 +127,7 @@ func handleSetLocation(conn net.Conn, req Request, manager *Manager) {
 func handleGetUserIconFile(conn net.Conn, req Request, manager *Manager) {
 	username, ok := req.Params["username"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'username' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'username' parameter"))
 		return
 	}
 
@@ -153,7 +153,7 @@ func handleGetColorScheme(conn net.Conn, req Request, manager *Manager) {
 func handleSetIconTheme(conn net.Conn, req Request, manager *Manager) {
 	iconTheme, ok := req.Params["iconTheme"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'iconTheme' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'iconTheme' parameter"))
 		return
 	}
 
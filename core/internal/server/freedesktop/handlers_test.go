@@ -67,7 +67,7 @@ func TestRespondError_Freedesktop(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Equal(t, "test error", resp.Error)
 	assert.Nil(t, resp.Result)
 }
@@ -81,7 +81,7 @@ func TestRespond_Freedesktop(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.True(t, resp.Result.Success)
@@ -114,7 +114,7 @@ func TestHandleGetState(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.True(t, resp.Result.Accounts.Available)
@@ -143,7 +143,7 @@ func TestHandleSetIconFile(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'path' parameter")
 	})
 
@@ -178,7 +178,7 @@ func TestHandleSetIconFile(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -210,7 +210,7 @@ func TestHandleSetIconFile(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "accounts service not available")
 	})
 }
@@ -235,7 +235,7 @@ func TestHandleSetRealName(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'name' parameter")
 	})
 
@@ -270,7 +270,7 @@ func TestHandleSetRealName(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -298,7 +298,7 @@ func TestHandleSetEmail(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'email' parameter")
 	})
 
@@ -333,7 +333,7 @@ func TestHandleSetEmail(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -361,7 +361,7 @@ func TestHandleSetLanguage(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'language' parameter")
 	})
 }
@@ -386,7 +386,7 @@ func TestHandleSetLocation(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'location' parameter")
 	})
 }
@@ -411,7 +411,7 @@ func TestHandleGetUserIconFile(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'username' parameter")
 	})
 
@@ -440,7 +440,7 @@ func TestHandleGetUserIconFile(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "accounts service not available")
 	})
 }
@@ -465,7 +465,7 @@ func TestHandleGetColorScheme(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "settings portal not available")
 	})
 
@@ -496,7 +496,7 @@ func TestHandleGetColorScheme(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.Equal(t, uint32(1), (*resp.Result)["colorScheme"])
@@ -527,7 +527,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "unknown method")
 	})
 
@@ -544,7 +544,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 	})
 
@@ -573,7 +573,7 @@ func TestHandleRequest(t *testing.T) {
 			err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 			require.NoError(t, err)
 
-			assert.Equal(t, 123, resp.ID)
+			assert.EqualValues(t, 123, resp.ID)
 			// Will have errors due to missing params or service unavailable
 			// but the method routing should work
 		}
@@ -0,0 +1,63 @@
+package freedesktop
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "freedesktop.getState",
+		Description: "Get accounts & settings state",
+	},
+	{
+		Name:        "freedesktop.accounts.setIconFile",
+		Description: "Set profile icon",
+		Params: []models.ParamSpec{
+			{Name: "path", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.accounts.setRealName",
+		Description: "Set real name",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.accounts.setEmail",
+		Description: "Set email",
+		Params: []models.ParamSpec{
+			{Name: "email", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.accounts.setLanguage",
+		Description: "Set language",
+		Params: []models.ParamSpec{
+			{Name: "language", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.accounts.setLocation",
+		Description: "Set location",
+		Params: []models.ParamSpec{
+			{Name: "location", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.accounts.getUserIconFile",
+		Description: "Get user icon",
+		Params: []models.ParamSpec{
+			{Name: "username", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "freedesktop.settings.getColorScheme",
+		Description: "Get color scheme",
+	},
+	{
+		Name:        "freedesktop.settings.setIconTheme",
+		Description: "Set icon theme",
+		Params: []models.ParamSpec{
+			{Name: "iconTheme", Type: models.ParamString, Required: true},
+		},
+	},
+}
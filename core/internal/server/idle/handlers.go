@@ -0,0 +1,145 @@
+package idle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type Request struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req Request, manager *Manager) {
+	if manager == nil {
+		models.RespondError(conn, req.ID, "idle manager not initialized")
+		return
+	}
+
+	switch req.Method {
+	case "idle.getState":
+		models.Respond(conn, req.ID, manager.GetState())
+	case "idle.setTimeout":
+		handleSetTimeout(conn, req, manager)
+	case "idle.removeTimeout":
+		handleRemoveTimeout(conn, req, manager)
+	case "idle.inhibit":
+		handleInhibit(conn, req, manager)
+	case "idle.uninhibit":
+		handleUninhibit(conn, req, manager)
+	case "idle.subscribe":
+		handleSubscribe(conn, req, manager)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func handleSetTimeout(conn net.Conn, req Request, manager *Manager) {
+	name, ok := req.Params["name"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
+		return
+	}
+
+	seconds, ok := req.Params["seconds"].(float64)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'seconds' parameter"))
+		return
+	}
+
+	timeout := Timeout{Name: name, Seconds: int(seconds)}
+	if raw, ok := req.Params["actions"]; ok {
+		data, err := json.Marshal(raw)
+		if err == nil {
+			err = json.Unmarshal(data, &timeout.Actions)
+		}
+		if err != nil {
+			models.RespondErr(conn, req.ID, models.NewParamError("invalid 'actions' parameter: %v", err))
+			return
+		}
+	}
+
+	if err := manager.SetTimeout(timeout); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "timeout set"})
+}
+
+func handleRemoveTimeout(conn net.Conn, req Request, manager *Manager) {
+	name, ok := req.Params["name"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
+		return
+	}
+
+	if err := manager.RemoveTimeout(name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "timeout removed"})
+}
+
+func handleInhibit(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+	reason, _ := req.Params["reason"].(string)
+
+	if err := manager.Inhibit(id, reason); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "idle inhibited"})
+}
+
+func handleUninhibit(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	if err := manager.Uninhibit(id); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "idle uninhibited"})
+}
+
+func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
+	clientID := fmt.Sprintf("client-%p", conn)
+	eventChan := manager.Subscribe(clientID)
+	defer manager.Unsubscribe(clientID)
+
+	initial := Event{Type: EventState, State: manager.GetState()}
+	if err := json.NewEncoder(conn).Encode(models.Response[Event]{
+		ID:     req.ID,
+		Result: &initial,
+	}); err != nil {
+		return
+	}
+
+	for event := range eventChan {
+		if err := json.NewEncoder(conn).Encode(models.Response[Event]{
+			Result: &event,
+		}); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,528 @@
+package idle
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/proto/ext_idle_notify"
+	wlclient "github.com/yaslama/go-wayland/wayland/client"
+)
+
+const inhibitorPollInterval = 30 * time.Second
+
+func NewManager(display *wlclient.Display) (*Manager, error) {
+	m := newManager()
+	m.display = display
+
+	m.wg.Add(1)
+	go m.waylandActor()
+
+	if err := m.setupRegistry(); err != nil {
+		close(m.stopChan)
+		m.wg.Wait()
+		return nil, err
+	}
+
+	m.wg.Add(1)
+	go m.inhibitorPoller()
+
+	return m, nil
+}
+
+func newManager() *Manager {
+	return &Manager{
+		cmdq:        make(chan cmd, 128),
+		stopChan:    make(chan struct{}),
+		timeouts:    make(map[string]*timeoutState),
+		inhibitors:  make(map[string]Inhibitor),
+		subscribers: make(map[string]chan Event),
+	}
+}
+
+func (m *Manager) post(fn func()) {
+	select {
+	case m.cmdq <- cmd{fn: fn}:
+	default:
+		log.Warn("Idle actor command queue full, dropping command")
+	}
+}
+
+func (m *Manager) waylandActor() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case c := <-m.cmdq:
+			c.fn()
+		}
+	}
+}
+
+func (m *Manager) setupRegistry() error {
+	ctx := m.display.Context()
+
+	registry, err := m.display.GetRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to get registry: %w", err)
+	}
+	m.registry = registry
+
+	registry.SetGlobalHandler(func(e wlclient.RegistryGlobalEvent) {
+		switch e.Interface {
+		case ext_idle_notify.ExtIdleNotifierV1InterfaceName:
+			notifier := ext_idle_notify.NewExtIdleNotifierV1(ctx)
+			version := e.Version
+			if version > 2 {
+				version = 2
+			}
+			if err := registry.Bind(e.Name, e.Interface, version, notifier); err != nil {
+				log.Errorf("Idle: failed to bind notifier: %v", err)
+				return
+			}
+			m.notifier = notifier
+			log.Info("Idle: notifier bound successfully")
+		case "wl_seat":
+			if m.seat != nil {
+				return
+			}
+			seat := wlclient.NewSeat(ctx)
+			if err := registry.Bind(e.Name, e.Interface, 1, seat); err != nil {
+				log.Errorf("Idle: failed to bind seat: %v", err)
+				return
+			}
+			m.seat = seat
+		}
+	})
+
+	if err := m.display.Roundtrip(); err != nil {
+		return fmt.Errorf("roundtrip failed: %w", err)
+	}
+
+	if m.notifier == nil {
+		return fmt.Errorf("%s not available", ext_idle_notify.ExtIdleNotifierV1InterfaceName)
+	}
+	if m.seat == nil {
+		return fmt.Errorf("no wl_seat available")
+	}
+	return nil
+}
+
+// SetSession attaches the logind session used for the idle hint, locking,
+// suspend and systemd inhibitors. It may be called once loginctl becomes available.
+func (m *Manager) SetSession(session Session) {
+	m.sessionMutex.Lock()
+	m.session = session
+	m.sessionMutex.Unlock()
+}
+
+func (m *Manager) getSession() Session {
+	m.sessionMutex.RLock()
+	defer m.sessionMutex.RUnlock()
+	return m.session
+}
+
+func (m *Manager) GetState() State {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	state := State{
+		Available:       m.notifier != nil,
+		Idle:            m.idleHint,
+		Inhibited:       len(m.inhibitors) > 0 || m.systemInhibited,
+		SystemInhibited: m.systemInhibited,
+		Inhibitors:      make([]Inhibitor, 0, len(m.inhibitors)),
+		Timeouts:        make([]TimeoutStatus, 0, len(m.timeouts)),
+	}
+	for _, inhibitor := range m.inhibitors {
+		state.Inhibitors = append(state.Inhibitors, inhibitor)
+	}
+	slices.SortFunc(state.Inhibitors, func(a, b Inhibitor) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, ts := range m.timeouts {
+		config := ts.config
+		config.Actions = slices.Clone(ts.config.Actions)
+		state.Timeouts = append(state.Timeouts, TimeoutStatus{
+			Timeout:    config,
+			Idle:       ts.idle,
+			Suppressed: ts.suppressed,
+		})
+	}
+	slices.SortFunc(state.Timeouts, func(a, b TimeoutStatus) int {
+		if a.Seconds != b.Seconds {
+			return a.Seconds - b.Seconds
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return state
+}
+
+// SetTimeout adds or replaces a named timeout. A replaced timeout that had
+// already idled is resumed first.
+func (m *Manager) SetTimeout(timeout Timeout) error {
+	if err := timeout.Validate(); err != nil {
+		return err
+	}
+
+	ts := &timeoutState{config: timeout}
+	m.mutex.Lock()
+	old := m.timeouts[timeout.Name]
+	m.timeouts[timeout.Name] = ts
+	m.mutex.Unlock()
+
+	if old != nil {
+		m.retire(old)
+	}
+	if m.notifier != nil {
+		m.post(func() {
+			m.createNotification(ts)
+		})
+	}
+
+	m.broadcast(Event{Type: EventState})
+	return nil
+}
+
+func (m *Manager) RemoveTimeout(name string) error {
+	m.mutex.Lock()
+	ts, ok := m.timeouts[name]
+	delete(m.timeouts, name)
+	m.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("timeout not found: %s", name)
+	}
+
+	m.retire(ts)
+	m.broadcast(Event{Type: EventState})
+	return nil
+}
+
+func (m *Manager) retire(ts *timeoutState) {
+	m.mutex.Lock()
+	active := ts.idle && !ts.suppressed
+	ts.idle = false
+	ts.suppressed = false
+	notification := ts.notification
+	ts.notification = nil
+	m.mutex.Unlock()
+
+	if notification != nil {
+		m.post(func() {
+			m.wlMutex.Lock()
+			notification.Destroy()
+			m.wlMutex.Unlock()
+		})
+	}
+
+	if active {
+		m.runResumeActions(ts.config.Actions)
+		m.syncIdleHint()
+		m.broadcast(Event{Type: EventResumed, Timeout: ts.config.Name, Actions: ts.config.Actions})
+	}
+}
+
+func (m *Manager) createNotification(ts *timeoutState) {
+	m.mutex.Lock()
+	current := m.timeouts[ts.config.Name] == ts
+	m.mutex.Unlock()
+	if !current {
+		return
+	}
+
+	m.wlMutex.Lock()
+	notification, err := m.notifier.GetIdleNotification(uint32(ts.config.Seconds*1000), m.seat)
+	if err == nil {
+		// These run on the shared Wayland dispatch goroutine; the handlers
+		// make logind calls, so hand them to the actor.
+		notification.SetIdledHandler(func(ext_idle_notify.ExtIdleNotificationV1IdledEvent) {
+			m.post(func() { m.handleIdled(ts) })
+		})
+		notification.SetResumedHandler(func(ext_idle_notify.ExtIdleNotificationV1ResumedEvent) {
+			m.post(func() { m.handleResumed(ts) })
+		})
+	}
+	m.wlMutex.Unlock()
+
+	if err != nil {
+		log.Errorf("Idle: failed to create notification for %s: %v", ts.config.Name, err)
+		return
+	}
+
+	m.mutex.Lock()
+	ts.notification = notification
+	m.mutex.Unlock()
+}
+
+func (m *Manager) handleIdled(ts *timeoutState) {
+	m.mutex.Lock()
+	if m.timeouts[ts.config.Name] != ts || ts.idle {
+		m.mutex.Unlock()
+		return
+	}
+	ts.idle = true
+	inhibited := len(m.inhibitors) > 0
+	m.mutex.Unlock()
+
+	if !inhibited {
+		inhibited = m.checkSystemInhibited()
+	}
+
+	if inhibited {
+		m.mutex.Lock()
+		ts.suppressed = true
+		m.mutex.Unlock()
+		log.Debugf("Idle: %s idled while inhibited", ts.config.Name)
+		m.broadcast(Event{Type: EventState})
+		return
+	}
+
+	m.fire(ts)
+}
+
+func (m *Manager) handleResumed(ts *timeoutState) {
+	m.mutex.Lock()
+	if m.timeouts[ts.config.Name] != ts || !ts.idle {
+		m.mutex.Unlock()
+		return
+	}
+	active := !ts.suppressed
+	ts.idle = false
+	ts.suppressed = false
+	m.mutex.Unlock()
+
+	if !active {
+		m.broadcast(Event{Type: EventState})
+		return
+	}
+
+	m.runResumeActions(ts.config.Actions)
+	m.syncIdleHint()
+	m.broadcast(Event{Type: EventResumed, Timeout: ts.config.Name, Actions: ts.config.Actions})
+}
+
+func (m *Manager) fire(ts *timeoutState) {
+	log.Debugf("Idle: %s idled", ts.config.Name)
+	m.runActions(ts.config.Actions)
+	m.syncIdleHint()
+	m.broadcast(Event{Type: EventIdled, Timeout: ts.config.Name, Actions: ts.config.Actions})
+}
+
+// reevaluate fires timeouts that idled while inhibited once no inhibitor is
+// left, since the compositor will not send idled for them again.
+func (m *Manager) reevaluate() {
+	m.mutex.Lock()
+	var pending []*timeoutState
+	for _, ts := range m.timeouts {
+		if ts.idle && ts.suppressed {
+			pending = append(pending, ts)
+		}
+	}
+	inhibited := len(m.inhibitors) > 0
+	m.mutex.Unlock()
+
+	if len(pending) == 0 || inhibited || m.checkSystemInhibited() {
+		return
+	}
+
+	slices.SortFunc(pending, func(a, b *timeoutState) int {
+		return a.config.Seconds - b.config.Seconds
+	})
+	for _, ts := range pending {
+		m.mutex.Lock()
+		ready := m.timeouts[ts.config.Name] == ts && ts.idle && ts.suppressed
+		if ready {
+			ts.suppressed = false
+		}
+		m.mutex.Unlock()
+
+		if ready {
+			m.fire(ts)
+		}
+	}
+}
+
+func (m *Manager) checkSystemInhibited() bool {
+	var inhibited bool
+	if session := m.getSession(); session != nil {
+		var err error
+		inhibited, err = session.IdleInhibited()
+		if err != nil {
+			log.Debugf("Idle: failed to query logind inhibitors: %v", err)
+		}
+	}
+
+	m.mutex.Lock()
+	m.systemInhibited = inhibited
+	m.mutex.Unlock()
+	return inhibited
+}
+
+func (m *Manager) inhibitorPoller() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(inhibitorPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.reevaluate()
+		}
+	}
+}
+
+// Inhibit blocks idle actions until the matching Uninhibit.
+func (m *Manager) Inhibit(id, reason string) error {
+	if id == "" {
+		return fmt.Errorf("inhibitor id is required")
+	}
+
+	m.mutex.Lock()
+	m.inhibitors[id] = Inhibitor{ID: id, Reason: reason}
+	m.mutex.Unlock()
+
+	m.broadcast(Event{Type: EventState})
+	return nil
+}
+
+func (m *Manager) Uninhibit(id string) error {
+	m.mutex.Lock()
+	_, ok := m.inhibitors[id]
+	delete(m.inhibitors, id)
+	m.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("inhibitor not found: %s", id)
+	}
+
+	m.reevaluate()
+	m.broadcast(Event{Type: EventState})
+	return nil
+}
+
+// syncIdleHint sets the logind IdleHint while any timeout is idle and not
+// suppressed by an inhibitor.
+func (m *Manager) syncIdleHint() {
+	m.mutex.Lock()
+	idle := false
+	for _, ts := range m.timeouts {
+		if ts.idle && !ts.suppressed {
+			idle = true
+			break
+		}
+	}
+	changed := idle != m.idleHint
+	m.idleHint = idle
+	m.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+	if session := m.getSession(); session != nil {
+		if err := session.SetIdleHint(idle); err != nil {
+			log.Warnf("Idle: failed to set idle hint: %v", err)
+		}
+	}
+}
+
+func (m *Manager) runActions(actions []Action) {
+	for _, action := range actions {
+		switch action.Type {
+		case ActionLock:
+			session := m.getSession()
+			if session == nil {
+				log.Warn("Idle: cannot lock without a logind session")
+				continue
+			}
+			if err := session.Lock(); err != nil {
+				log.Warnf("Idle: lock failed: %v", err)
+			}
+		case ActionSuspend:
+			session := m.getSession()
+			if session == nil {
+				log.Warn("Idle: cannot suspend without a logind session")
+				continue
+			}
+			if err := session.Suspend(); err != nil {
+				log.Warnf("Idle: suspend failed: %v", err)
+			}
+		case ActionCommand:
+			if action.Command != "" {
+				runCommand(action.Command)
+			}
+		}
+	}
+}
+
+func (m *Manager) runResumeActions(actions []Action) {
+	for _, action := range actions {
+		if action.Type == ActionCommand && action.ResumeCommand != "" {
+			runCommand(action.ResumeCommand)
+		}
+	}
+}
+
+func runCommand(command string) {
+	cmd := exec.Command("sh", "-c", command)
+	if err := cmd.Start(); err != nil {
+		log.Warnf("Idle: failed to run %q: %v", command, err)
+		return
+	}
+	go cmd.Wait()
+}
+
+func (m *Manager) broadcast(event Event) {
+	event.State = m.GetState()
+
+	m.subMutex.RLock()
+	defer m.subMutex.RUnlock()
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Warn("Idle: subscriber channel full, dropping event")
+		}
+	}
+}
+
+func (m *Manager) Close() {
+	close(m.stopChan)
+	m.wg.Wait()
+
+	m.subMutex.Lock()
+	for _, ch := range m.subscribers {
+		close(ch)
+	}
+	m.subscribers = make(map[string]chan Event)
+	m.subMutex.Unlock()
+
+	m.mutex.Lock()
+	hinted := m.idleHint
+	for _, ts := range m.timeouts {
+		if ts.notification != nil {
+			ts.notification.Destroy()
+			ts.notification = nil
+		}
+	}
+	m.timeouts = make(map[string]*timeoutState)
+	m.idleHint = false
+	m.mutex.Unlock()
+
+	if m.notifier != nil {
+		m.notifier.Destroy()
+	}
+
+	if session := m.getSession(); hinted && session != nil {
+		session.SetIdleHint(false)
+	}
+}
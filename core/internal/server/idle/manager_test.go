@@ -0,0 +1,195 @@
+package idle
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSession struct {
+	mu        sync.Mutex
+	hints     []bool
+	locks     int
+	suspends  int
+	inhibited bool
+}
+
+func (s *fakeSession) SetIdleHint(idle bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hints = append(s.hints, idle)
+	return nil
+}
+
+func (s *fakeSession) Lock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locks++
+	return nil
+}
+
+func (s *fakeSession) Suspend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suspends++
+	return nil
+}
+
+func (s *fakeSession) IdleInhibited() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inhibited, nil
+}
+
+func newTestManager(t *testing.T, session *fakeSession) *Manager {
+	t.Helper()
+	m := newManager()
+	if session != nil {
+		m.SetSession(session)
+	}
+	require.NoError(t, m.SetTimeout(Timeout{Name: "dim", Seconds: 60, Actions: []Action{{Type: ActionDim}}}))
+	require.NoError(t, m.SetTimeout(Timeout{Name: "lock", Seconds: 300, Actions: []Action{{Type: ActionLock}}}))
+	return m
+}
+
+func TestAction_UnmarshalJSON(t *testing.T) {
+	var actions []Action
+	err := json.Unmarshal([]byte(`["lock", {"type": "command", "command": "notify-send idle"}]`), &actions)
+	require.NoError(t, err)
+	assert.Equal(t, []Action{
+		{Type: ActionLock},
+		{Type: ActionCommand, Command: "notify-send idle"},
+	}, actions)
+}
+
+func TestTimeout_Validate(t *testing.T) {
+	assert.NoError(t, Timeout{Name: "lock", Seconds: 300, Actions: []Action{{Type: ActionLock}}}.Validate())
+	assert.Error(t, Timeout{Seconds: 300}.Validate())
+	assert.Error(t, Timeout{Name: "lock", Seconds: 0}.Validate())
+	assert.Error(t, Timeout{Name: "lock", Seconds: maxTimeoutSeconds + 1}.Validate())
+	assert.Error(t, Timeout{Name: "lock", Seconds: 10, Actions: []Action{{Type: "reboot"}}}.Validate())
+	assert.Error(t, Timeout{Name: "run", Seconds: 10, Actions: []Action{{Type: ActionCommand}}}.Validate())
+}
+
+func TestManager_IdledAndResumed(t *testing.T) {
+	session := &fakeSession{}
+	m := newTestManager(t, session)
+	events := m.Subscribe("test")
+
+	m.handleIdled(m.timeouts["dim"])
+	event := <-events
+	assert.Equal(t, EventIdled, event.Type)
+	assert.Equal(t, "dim", event.Timeout)
+	assert.True(t, event.State.Idle)
+
+	m.handleIdled(m.timeouts["lock"])
+	event = <-events
+	assert.Equal(t, EventIdled, event.Type)
+	assert.Equal(t, 1, session.locks)
+
+	m.handleResumed(m.timeouts["lock"])
+	event = <-events
+	assert.Equal(t, EventResumed, event.Type)
+	assert.True(t, event.State.Idle, "dim is still idle")
+
+	m.handleResumed(m.timeouts["dim"])
+	event = <-events
+	assert.Equal(t, EventResumed, event.Type)
+	assert.False(t, event.State.Idle)
+
+	assert.Equal(t, []bool{true, false}, session.hints)
+}
+
+func TestManager_InhibitorSuppressesActions(t *testing.T) {
+	session := &fakeSession{}
+	m := newTestManager(t, session)
+	require.NoError(t, m.Inhibit("video", "watching"))
+	events := m.Subscribe("test")
+
+	m.handleIdled(m.timeouts["lock"])
+	event := <-events
+	assert.Equal(t, EventState, event.Type)
+	assert.True(t, event.State.Inhibited)
+	assert.Equal(t, 0, session.locks)
+	assert.Empty(t, session.hints)
+
+	require.NoError(t, m.Uninhibit("video"))
+	event = <-events
+	assert.Equal(t, EventIdled, event.Type)
+	assert.Equal(t, "lock", event.Timeout)
+	assert.Equal(t, 1, session.locks)
+	assert.Equal(t, []bool{true}, session.hints)
+
+	assert.Error(t, m.Uninhibit("video"))
+}
+
+func TestManager_SystemInhibitor(t *testing.T) {
+	session := &fakeSession{inhibited: true}
+	m := newTestManager(t, session)
+
+	m.handleIdled(m.timeouts["lock"])
+	state := m.GetState()
+	assert.True(t, state.SystemInhibited)
+	assert.True(t, state.Timeouts[1].Suppressed)
+	assert.Equal(t, 0, session.locks)
+
+	m.reevaluate()
+	assert.Equal(t, 0, session.locks)
+
+	session.inhibited = false
+	m.reevaluate()
+	assert.Equal(t, 1, session.locks)
+	assert.False(t, m.GetState().Timeouts[1].Suppressed)
+
+	m.handleResumed(m.timeouts["lock"])
+	assert.Equal(t, []bool{true, false}, session.hints)
+}
+
+func TestManager_ResumeWhileSuppressed(t *testing.T) {
+	session := &fakeSession{}
+	m := newTestManager(t, session)
+	require.NoError(t, m.Inhibit("caffeine", ""))
+
+	m.handleIdled(m.timeouts["dim"])
+	m.handleResumed(m.timeouts["dim"])
+	require.NoError(t, m.Uninhibit("caffeine"))
+
+	assert.False(t, m.GetState().Timeouts[0].Idle)
+	assert.Empty(t, session.hints)
+}
+
+func TestManager_ReplaceIdleTimeout(t *testing.T) {
+	session := &fakeSession{}
+	m := newTestManager(t, session)
+	old := m.timeouts["dim"]
+	m.handleIdled(old)
+
+	require.NoError(t, m.SetTimeout(Timeout{Name: "dim", Seconds: 120}))
+	assert.Equal(t, []bool{true, false}, session.hints)
+
+	m.handleResumed(old)
+	assert.Equal(t, []bool{true, false}, session.hints, "events from the replaced notification are ignored")
+
+	state := m.GetState()
+	require.Len(t, state.Timeouts, 2)
+	assert.Equal(t, "dim", state.Timeouts[0].Name)
+	assert.Equal(t, 120, state.Timeouts[0].Seconds)
+
+	assert.NoError(t, m.RemoveTimeout("dim"))
+	assert.Error(t, m.RemoveTimeout("dim"))
+}
+
+func TestManager_SuspendThroughSession(t *testing.T) {
+	session := &fakeSession{}
+	m := newTestManager(t, session)
+	require.NoError(t, m.SetTimeout(Timeout{Name: "suspend", Seconds: 900, Actions: []Action{{Type: ActionSuspend}}}))
+	events := m.Subscribe("test")
+
+	m.handleIdled(m.timeouts["suspend"])
+	event := <-events
+	assert.Equal(t, EventIdled, event.Type)
+	assert.Equal(t, 1, session.suspends)
+}
@@ -0,0 +1,47 @@
+package idle
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "idle.getState",
+		Description: "Get idle timeouts, inhibitors and idle status",
+	},
+	{
+		Name:        "idle.setTimeout",
+		Description: "Add or replace a named idle timeout",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true, Description: "e.g. dim, lock, dpms, suspend"},
+			{Name: "seconds", Type: models.ParamNumber, Required: true},
+			{Name: "actions", Type: models.ParamArray, Description: `action types ("dim", "lock", "dpmsOff", "suspend") or {"type": "command", "command": ..., "resumeCommand": ...}`},
+		},
+		Notes: []string{"lock, suspend and command actions run in the server; dim and dpmsOff are left to subscribers"},
+	},
+	{
+		Name:        "idle.removeTimeout",
+		Description: "Remove a named idle timeout",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "idle.inhibit",
+		Description: "Suppress idle actions until uninhibited",
+		Params: []models.ParamSpec{
+			{Name: "id", Type: models.ParamString, Required: true},
+			{Name: "reason", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "idle.uninhibit",
+		Description: "Release an idle inhibitor",
+		Params: []models.ParamSpec{
+			{Name: "id", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "idle.subscribe",
+		Description: "Subscribe to idled/resumed events and state changes",
+		Streaming:   true,
+	},
+}
@@ -0,0 +1,178 @@
+package idle
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/proto/ext_idle_notify"
+	wlclient "github.com/yaslama/go-wayland/wayland/client"
+)
+
+type ActionType string
+
+const (
+	ActionDim     ActionType = "dim"
+	ActionLock    ActionType = "lock"
+	ActionDPMSOff ActionType = "dpmsOff"
+	ActionSuspend ActionType = "suspend"
+	ActionCommand ActionType = "command"
+)
+
+// Action is run when its timeout idles. Lock, suspend and command actions are
+// executed here; dim and dpmsOff are left to subscribers since they depend on
+// the compositor.
+type Action struct {
+	Type          ActionType `json:"type"`
+	Command       string     `json:"command,omitempty"`
+	ResumeCommand string     `json:"resumeCommand,omitempty"`
+}
+
+// UnmarshalJSON also accepts a bare action type, e.g. "lock".
+func (a *Action) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*a = Action{Type: ActionType(name)}
+		return nil
+	}
+
+	type plain Action
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*a = Action(p)
+	return nil
+}
+
+func (a Action) Validate() error {
+	switch a.Type {
+	case ActionDim, ActionLock, ActionDPMSOff, ActionSuspend:
+		return nil
+	case ActionCommand:
+		if a.Command == "" && a.ResumeCommand == "" {
+			return fmt.Errorf("command action needs a command")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action type: %s", a.Type)
+	}
+}
+
+type Timeout struct {
+	Name    string   `json:"name"`
+	Seconds int      `json:"seconds"`
+	Actions []Action `json:"actions"`
+}
+
+const maxTimeoutSeconds = 24 * 60 * 60
+
+func (t Timeout) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("timeout name is required")
+	}
+	if t.Seconds < 1 || t.Seconds > maxTimeoutSeconds {
+		return fmt.Errorf("timeout out of range: %ds", t.Seconds)
+	}
+	for _, action := range t.Actions {
+		if err := action.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TimeoutStatus struct {
+	Timeout
+	Idle       bool `json:"idle"`
+	Suppressed bool `json:"suppressed"`
+}
+
+type Inhibitor struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type State struct {
+	Available       bool            `json:"available"`
+	Idle            bool            `json:"idle"`
+	Inhibited       bool            `json:"inhibited"`
+	SystemInhibited bool            `json:"systemInhibited"`
+	Inhibitors      []Inhibitor     `json:"inhibitors"`
+	Timeouts        []TimeoutStatus `json:"timeouts"`
+}
+
+type EventType string
+
+const (
+	EventState   EventType = "state"
+	EventIdled   EventType = "idled"
+	EventResumed EventType = "resumed"
+)
+
+type Event struct {
+	Type    EventType `json:"type"`
+	Timeout string    `json:"timeout,omitempty"`
+	Actions []Action  `json:"actions,omitempty"`
+	State   State     `json:"state"`
+}
+
+// Session is the logind side of idle handling, implemented by loginctl.Manager.
+type Session interface {
+	SetIdleHint(idle bool) error
+	Lock() error
+	Suspend() error
+	IdleInhibited() (bool, error)
+}
+
+type cmd struct {
+	fn func()
+}
+
+type timeoutState struct {
+	config       Timeout
+	notification *ext_idle_notify.ExtIdleNotificationV1
+	idle         bool
+	suppressed   bool
+}
+
+type Manager struct {
+	display  *wlclient.Display
+	registry *wlclient.Registry
+	notifier *ext_idle_notify.ExtIdleNotifierV1
+	seat     *wlclient.Seat
+
+	wlMutex  sync.Mutex
+	cmdq     chan cmd
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+
+	mutex           sync.Mutex
+	timeouts        map[string]*timeoutState
+	inhibitors      map[string]Inhibitor
+	systemInhibited bool
+	idleHint        bool
+
+	sessionMutex sync.RWMutex
+	session      Session
+
+	subscribers map[string]chan Event
+	subMutex    sync.RWMutex
+}
+
+func (m *Manager) Subscribe(id string) chan Event {
+	ch := make(chan Event, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
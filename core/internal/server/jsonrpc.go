@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+// A connection switches to JSON-RPC 2.0 framing when its first message is a
+// batch or carries "jsonrpc": "2.0". Handlers keep writing the legacy
+// {id, result, error} frames and rpcResponseWriter translates them.
+
+const jsonrpcVersion = "2.0"
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcSubscriptionParams struct {
+	Subscription json.RawMessage `json:"subscription"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	Error        *rpcError       `json:"error,omitempty"`
+}
+
+var rpcNullID = json.RawMessage("null")
+
+func isJSONRPCMessage(line []byte) bool {
+	if len(line) > 0 && line[0] == '[' {
+		return true
+	}
+
+	var probe struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	return json.Unmarshal(line, &probe) == nil && probe.JSONRPC == jsonrpcVersion
+}
+
+func newRPCError(id json.RawMessage, code int, msg string) rpcResponse {
+	if len(id) == 0 {
+		id = rpcNullID
+	}
+	return rpcResponse{
+		JSONRPC: jsonrpcVersion,
+		ID:      id,
+		Error:   &rpcError{Code: code, Message: msg},
+	}
+}
+
+// rpcErrorCode maps legacy error frames to JSON-RPC codes. Handlers set a
+// code for typed errors such as models.ParamError; anything else is a
+// server error.
+func rpcErrorCode(code int) int {
+	if code != 0 {
+		return code
+	}
+	return models.ErrCodeServer
+}
+
+type rpcConn struct {
+	conn       net.Conn
+	writeMutex sync.Mutex
+}
+
+func (c *rpcConn) write(v interface{}) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return json.NewEncoder(c.conn).Encode(v)
+}
+
+func (c *rpcConn) handleMessage(line []byte) {
+	if line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err != nil {
+			c.write(newRPCError(nil, models.ErrCodeParse, "parse error"))
+			return
+		}
+		if len(batch) == 0 {
+			c.write(newRPCError(nil, models.ErrCodeInvalidRequest, "empty batch"))
+			return
+		}
+		go c.handleBatch(batch)
+		return
+	}
+
+	req, params, errResp := parseRPCRequest(line)
+	if errResp != nil {
+		c.write(errResp)
+		return
+	}
+	go c.dispatch(req, params, c.write, false)
+}
+
+func (c *rpcConn) handleBatch(batch []json.RawMessage) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	responses := []interface{}{}
+
+	collect := func(v interface{}) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		responses = append(responses, v)
+		return nil
+	}
+
+	for _, raw := range batch {
+		req, params, errResp := parseRPCRequest(raw)
+		if errResp != nil {
+			collect(errResp)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.dispatch(req, params, collect, true)
+		}()
+	}
+	wg.Wait()
+
+	// A batch of notifications gets no reply at all.
+	if len(responses) > 0 {
+		c.write(responses)
+	}
+}
+
+func parseRPCRequest(raw []byte) (*rpcRequest, map[string]interface{}, *rpcResponse) {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		if !json.Valid(raw) {
+			resp := newRPCError(nil, models.ErrCodeParse, "parse error")
+			return nil, nil, &resp
+		}
+		resp := newRPCError(nil, models.ErrCodeInvalidRequest, "invalid request")
+		return nil, nil, &resp
+	}
+
+	if req.JSONRPC != jsonrpcVersion || req.Method == "" {
+		resp := newRPCError(req.ID, models.ErrCodeInvalidRequest, "invalid request")
+		return nil, nil, &resp
+	}
+
+	var params map[string]interface{}
+	if len(req.Params) > 0 && !bytes.Equal(req.Params, rpcNullID) {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp := newRPCError(req.ID, models.ErrCodeInvalidParams, "params must be an object")
+			return nil, nil, &resp
+		}
+	}
+
+	return &req, params, nil
+}
+
+func (c *rpcConn) dispatch(req *rpcRequest, params map[string]interface{}, out func(interface{}) error, inBatch bool) {
+	notification := len(req.ID) == 0
+
+	if entry, ok := methodIndex[req.Method]; ok && entry.spec.Streaming {
+		switch {
+		case notification:
+			return
+		case inBatch:
+			out(newRPCError(req.ID, models.ErrCodeInvalidRequest, fmt.Sprintf("streaming method %s cannot be batched", req.Method)))
+			return
+		}
+	}
+
+	if notification {
+		out = func(interface{}) error { return nil }
+	}
+
+	id, _ := models.DecodeID(req.ID)
+
+	w := &rpcResponseWriter{
+		Conn:   c.conn,
+		out:    out,
+		id:     req.ID,
+		method: req.Method,
+	}
+	RouteRequest(w, models.Request{ID: id, Method: req.Method, Params: params})
+}
+
+// rpcResponseWriter turns the legacy frames a handler writes into a JSON-RPC
+// response. Streaming handlers keep writing after the first frame; those
+// frames become notifications named after the method, carrying the request
+// id as "subscription".
+type rpcResponseWriter struct {
+	net.Conn
+	out    func(interface{}) error
+	id     json.RawMessage
+	method string
+
+	mutex sync.Mutex
+	buf   []byte
+	sent  bool
+}
+
+func (w *rpcResponseWriter) Write(b []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := w.emit(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *rpcResponseWriter) emit(line []byte) error {
+	var legacy struct {
+		Result json.RawMessage `json:"result"`
+		Error  string          `json:"error"`
+		Code   int             `json:"code"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(line, &legacy); err != nil {
+		return err
+	}
+
+	var rpcErr *rpcError
+	if legacy.Error != "" {
+		rpcErr = &rpcError{Code: rpcErrorCode(legacy.Code), Message: legacy.Error, Data: legacy.Data}
+	}
+
+	if w.sent {
+		return w.out(rpcNotification{
+			JSONRPC: jsonrpcVersion,
+			Method:  w.method,
+			Params:  rpcSubscriptionParams{Subscription: w.id, Result: legacy.Result, Error: rpcErr},
+		})
+	}
+	w.sent = true
+
+	resp := rpcResponse{JSONRPC: jsonrpcVersion, ID: w.id, Error: rpcErr}
+	if rpcErr == nil {
+		resp.Result = legacy.Result
+		if len(resp.Result) == 0 {
+			resp.Result = rpcNullID
+		}
+	}
+	return w.out(resp)
+}
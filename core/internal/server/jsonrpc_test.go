@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSocketClient struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func newTestSocketClient(t *testing.T) *testSocketClient {
+	serverConn, clientConn := net.Pipe()
+	go handleConnection(serverConn)
+	t.Cleanup(func() { clientConn.Close() })
+
+	c := &testSocketClient{conn: clientConn, scanner: bufio.NewScanner(clientConn)}
+
+	var greeting Capabilities
+	require.NoError(t, json.Unmarshal(c.readLine(t), &greeting))
+	assert.Contains(t, greeting.Protocols, "jsonrpc-2.0")
+	return c
+}
+
+func (c *testSocketClient) send(t *testing.T, msg string) {
+	_, err := c.conn.Write([]byte(msg + "\n"))
+	require.NoError(t, err)
+}
+
+func (c *testSocketClient) readLine(t *testing.T) []byte {
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	require.True(t, c.scanner.Scan(), "no response: %v", c.scanner.Err())
+	return append([]byte{}, c.scanner.Bytes()...)
+}
+
+func TestLegacyStringID(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `{"id":"3f2b-uuid","method":"ping"}`)
+
+	var resp models.Response[string]
+	require.NoError(t, json.Unmarshal(c.readLine(t), &resp))
+	assert.Equal(t, "3f2b-uuid", resp.ID)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, "pong", *resp.Result)
+}
+
+func TestLargeIDsRoundTrip(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `{"id":9007199254740993,"method":"ping"}`)
+	assert.JSONEq(t, `{"id":9007199254740993,"result":"pong"}`, string(c.readLine(t)))
+
+	c = newTestSocketClient(t)
+	c.send(t, `{"jsonrpc":"2.0","id":9007199254740993,"method":"ping"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":9007199254740993,"result":"pong"}`, string(c.readLine(t)))
+}
+
+func TestLegacyUnknownMethodCode(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `{"id":7,"method":"nope"}`)
+
+	var resp models.Response[any]
+	require.NoError(t, json.Unmarshal(c.readLine(t), &resp))
+	assert.EqualValues(t, 7, resp.ID)
+	assert.Equal(t, "unknown method: nope", resp.Error)
+	assert.Equal(t, models.ErrCodeMethodNotFound, resp.Code)
+}
+
+func TestJSONRPCRequest(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `{"jsonrpc":"2.0","id":"a1","method":"ping"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"a1","result":"pong"}`, string(c.readLine(t)))
+}
+
+func TestJSONRPCErrors(t *testing.T) {
+	c := newTestSocketClient(t)
+
+	c.send(t, `{"jsonrpc":"2.0","id":1,"method":"nope"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"unknown method: nope"}}`, string(c.readLine(t)))
+
+	c.send(t, `{"jsonrpc":"2.0","id":2,"method":"describe","params":{"method":42}}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"error":{"code":-32602,"message":"missing or invalid 'method' parameter"}}`, string(c.readLine(t)))
+
+	c.send(t, `{"jsonrpc":"2.0","id":3,"method":"power.getState"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"error":{"code":-32000,"message":"power manager not initialized"}}`, string(c.readLine(t)))
+
+	c.send(t, `{"jsonrpc":"2.0","id":4,"method":"ping","params":[1,2]}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":4,"error":{"code":-32602,"message":"params must be an object"}}`, string(c.readLine(t)))
+
+	c.send(t, `{"jsonrpc":"2.0",`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`, string(c.readLine(t)))
+}
+
+func TestJSONRPCNotificationGetsNoResponse(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `{"jsonrpc":"2.0","method":"ping"}`)
+	c.send(t, `{"jsonrpc":"2.0","id":9,"method":"ping"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":9,"result":"pong"}`, string(c.readLine(t)))
+}
+
+func TestJSONRPCBatch(t *testing.T) {
+	c := newTestSocketClient(t)
+	c.send(t, `[`+
+		`{"jsonrpc":"2.0","id":1,"method":"ping"},`+
+		`{"jsonrpc":"2.0","method":"ping"},`+
+		`{"jsonrpc":"2.0","id":"x","method":"nope"},`+
+		`{"jsonrpc":"2.0","id":4,"method":"subscribe"},`+
+		`{"id":5,"method":"ping"}`+
+		`]`)
+
+	var responses []struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(c.readLine(t), &responses))
+	require.Len(t, responses, 4)
+
+	byID := make(map[string]int)
+	for i, r := range responses {
+		byID[string(r.ID)] = i
+	}
+
+	assert.JSONEq(t, `"pong"`, string(responses[byID["1"]].Result))
+	assert.Equal(t, models.ErrCodeMethodNotFound, responses[byID[`"x"`]].Error.Code)
+	assert.Equal(t, models.ErrCodeInvalidRequest, responses[byID["4"]].Error.Code)
+	assert.Equal(t, models.ErrCodeInvalidRequest, responses[byID["5"]].Error.Code)
+}
+
+func TestRPCResponseWriterStreaming(t *testing.T) {
+	var frames []string
+	w := &rpcResponseWriter{
+		out: func(v interface{}) error {
+			data, _ := json.Marshal(v)
+			frames = append(frames, string(data))
+			return nil
+		},
+		id:     json.RawMessage(`"sub"`),
+		method: "power.subscribe",
+	}
+
+	models.Respond(w, "sub", map[string]int{"n": 1})
+	models.Respond(w, "sub", map[string]int{"n": 2})
+
+	require.Len(t, frames, 2)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"sub","result":{"n":1}}`, frames[0])
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"power.subscribe","params":{"subscription":"sub","result":{"n":2}}}`, frames[1])
+}
+
+func TestRPCResponseWriterErrorData(t *testing.T) {
+	var frames []string
+	w := &rpcResponseWriter{
+		out: func(v interface{}) error {
+			data, _ := json.Marshal(v)
+			frames = append(frames, string(data))
+			return nil
+		},
+		id:     json.RawMessage(`7`),
+		method: "loginctl.powerOff",
+	}
+
+	models.RespondErrorData(w, 7, models.ErrCodeServer, "poweroff failed", map[string]string{"reason": "inhibited"})
+
+	require.Len(t, frames, 1)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"error":{"code":-32000,"message":"poweroff failed","data":{"reason":"inhibited"}}}`, frames[0])
+}
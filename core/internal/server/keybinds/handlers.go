@@ -0,0 +1,136 @@
+package keybinds
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req models.Request) {
+	handleRequest(conn, req, keybinds.GetDefaultRegistry())
+}
+
+func handleRequest(conn net.Conn, req models.Request, registry *keybinds.Registry) {
+	switch req.Method {
+	case "keybinds.list":
+		models.Respond(conn, req.ID, registry.List())
+	case "keybinds.get":
+		handleGet(conn, req, registry)
+	case "keybinds.set":
+		handleSet(conn, req, registry)
+	case "keybinds.remove":
+		handleRemove(conn, req, registry)
+	case "keybinds.check":
+		handleCheck(conn, req, registry)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func getProvider(conn net.Conn, req models.Request, registry *keybinds.Registry) (keybinds.Provider, bool) {
+	name, ok := req.Params["provider"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'provider' parameter"))
+		return nil, false
+	}
+
+	provider, err := registry.Get(name)
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return nil, false
+	}
+	return provider, true
+}
+
+func getWritableProvider(conn net.Conn, req models.Request, registry *keybinds.Registry) (keybinds.WritableProvider, bool) {
+	provider, ok := getProvider(conn, req, registry)
+	if !ok {
+		return nil, false
+	}
+
+	writable, ok := provider.(keybinds.WritableProvider)
+	if !ok {
+		models.RespondError(conn, req.ID, fmt.Sprintf("provider %s does not support editing keybinds", provider.Name()))
+		return nil, false
+	}
+	return writable, true
+}
+
+func handleGet(conn net.Conn, req models.Request, registry *keybinds.Registry) {
+	provider, ok := getProvider(conn, req, registry)
+	if !ok {
+		return
+	}
+
+	sheet, err := provider.GetCheatSheet()
+	if err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to get cheatsheet: %v", err))
+		return
+	}
+	models.Respond(conn, req.ID, sheet)
+}
+
+func handleSet(conn net.Conn, req models.Request, registry *keybinds.Registry) {
+	key, ok := req.Params["key"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'key' parameter"))
+		return
+	}
+	action, ok := req.Params["action"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'action' parameter"))
+		return
+	}
+	description, _ := req.Params["description"].(string)
+
+	provider, ok := getWritableProvider(conn, req, registry)
+	if !ok {
+		return
+	}
+
+	if err := provider.SetBind(key, action, description); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to set keybind: %v", err))
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: fmt.Sprintf("bound %s", key)})
+}
+
+func handleRemove(conn net.Conn, req models.Request, registry *keybinds.Registry) {
+	key, ok := req.Params["key"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'key' parameter"))
+		return
+	}
+
+	provider, ok := getWritableProvider(conn, req, registry)
+	if !ok {
+		return
+	}
+
+	if err := provider.RemoveBind(key); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to remove keybind: %v", err))
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: fmt.Sprintf("removed %s", key)})
+}
+
+func handleCheck(conn net.Conn, req models.Request, registry *keybinds.Registry) {
+	provider, ok := getWritableProvider(conn, req, registry)
+	if !ok {
+		return
+	}
+
+	conflicts, err := provider.CheckConflicts()
+	if err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to check keybinds: %v", err))
+		return
+	}
+	models.Respond(conn, req.ID, conflicts)
+}
@@ -0,0 +1,108 @@
+package keybinds
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/keybinds/providers"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/net"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newCapturingConn(t *testing.T, written *[]byte) *net.MockConn {
+	conn := net.NewMockConn(t)
+	conn.EXPECT().Write(mock.Anything).RunAndReturn(func(b []byte) (int, error) {
+		*written = append(*written, b...)
+		return len(b), nil
+	}).Maybe()
+	return conn
+}
+
+func newTestRegistry(t *testing.T) (*keybinds.Registry, string) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config")
+	require.NoError(t, os.WriteFile(configFile, []byte("bindsym Mod4+Return exec kitty\nbindsym Mod4+Return exec foot\n"), 0644))
+
+	registry := keybinds.NewRegistry()
+	require.NoError(t, registry.Register(providers.NewSwayProvider(tmpDir)))
+	require.NoError(t, registry.Register(providers.NewNiriProvider(tmpDir)))
+	return registry, configFile
+}
+
+func TestHandleSetMissingKey(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	var written []byte
+	conn := newCapturingConn(t, &written)
+
+	handleRequest(conn, models.Request{
+		ID:     1,
+		Method: "keybinds.set",
+		Params: map[string]interface{}{"provider": "sway", "action": "exec foot"},
+	}, registry)
+
+	var resp models.Response[SuccessResult]
+	require.NoError(t, json.Unmarshal(written, &resp))
+	assert.Contains(t, resp.Error, "missing or invalid 'key' parameter")
+}
+
+func TestHandleSetNotWritable(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	var written []byte
+	conn := newCapturingConn(t, &written)
+
+	handleRequest(conn, models.Request{
+		ID:     1,
+		Method: "keybinds.set",
+		Params: map[string]interface{}{"provider": "niri", "key": "Mod+T", "action": "spawn"},
+	}, registry)
+
+	var resp models.Response[SuccessResult]
+	require.NoError(t, json.Unmarshal(written, &resp))
+	assert.Contains(t, resp.Error, "does not support editing")
+}
+
+func TestHandleSet(t *testing.T) {
+	registry, configFile := newTestRegistry(t)
+	var written []byte
+	conn := newCapturingConn(t, &written)
+
+	handleRequest(conn, models.Request{
+		ID:     1,
+		Method: "keybinds.set",
+		Params: map[string]interface{}{"provider": "sway", "key": "Mod4+q", "action": "kill"},
+	}, registry)
+
+	var resp models.Response[SuccessResult]
+	require.NoError(t, json.Unmarshal(written, &resp))
+	require.NotNil(t, resp.Result)
+	assert.True(t, resp.Result.Success)
+
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "bindsym Mod4+q kill")
+}
+
+func TestHandleCheck(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	var written []byte
+	conn := newCapturingConn(t, &written)
+
+	handleRequest(conn, models.Request{
+		ID:     1,
+		Method: "keybinds.check",
+		Params: map[string]interface{}{"provider": "sway"},
+	}, registry)
+
+	var resp models.Response[[]keybinds.Conflict]
+	require.NoError(t, json.Unmarshal(written, &resp))
+	require.NotNil(t, resp.Result)
+	require.Len(t, *resp.Result, 1)
+	assert.Equal(t, "SUPER+return", (*resp.Result)[0].Key)
+	assert.Len(t, (*resp.Result)[0].Bindings, 2)
+}
@@ -0,0 +1,43 @@
+package keybinds
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "keybinds.list",
+		Description: "List keybind providers",
+	},
+	{
+		Name:        "keybinds.get",
+		Description: "Get cheatsheet",
+		Params: []models.ParamSpec{
+			{Name: "provider", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "keybinds.set",
+		Description: "Add or rebind a key",
+		Params: []models.ParamSpec{
+			{Name: "provider", Type: models.ParamString, Required: true},
+			{Name: "key", Type: models.ParamString, Required: true},
+			{Name: "action", Type: models.ParamString, Required: true},
+			{Name: "description", Type: models.ParamString},
+		},
+		Notes: []string{"Rebinding keeps the existing comment (or bindd description) unless description is given."},
+	},
+	{
+		Name:        "keybinds.remove",
+		Description: "Remove a keybind",
+		Params: []models.ParamSpec{
+			{Name: "provider", Type: models.ParamString, Required: true},
+			{Name: "key", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "keybinds.check",
+		Description: "Report conflicting keybinds",
+		Params: []models.ParamSpec{
+			{Name: "provider", Type: models.ParamString, Required: true},
+		},
+	},
+}
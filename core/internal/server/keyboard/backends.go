@@ -0,0 +1,291 @@
+package keyboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type commandRunner func(name string, args ...string) ([]byte, error)
+
+func runCommand(name string, args ...string) ([]byte, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+type commandStream struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (s *commandStream) Close() error {
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	return s.cmd.Wait()
+}
+
+func startStream(name string, args ...string) (io.ReadCloser, error) {
+	cmd := exec.Command(name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &commandStream{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+func DetectBackend() (Backend, error) {
+	switch {
+	case os.Getenv("NIRI_SOCKET") != "":
+		return &niriBackend{run: runCommand, registry: loadRegistry()}, nil
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return &hyprlandBackend{run: runCommand, registry: loadRegistry()}, nil
+	case os.Getenv("SWAYSOCK") != "":
+		return &swayBackend{run: runCommand, registry: loadRegistry()}, nil
+	default:
+		return nil, fmt.Errorf("no supported compositor detected (niri, Hyprland or sway)")
+	}
+}
+
+func parseTarget(target string) (string, error) {
+	switch target {
+	case "next", "prev":
+		return target, nil
+	}
+	if idx, err := strconv.Atoi(target); err == nil && idx >= 0 {
+		return target, nil
+	}
+	return "", fmt.Errorf("invalid layout target: %s (expected next, prev or an index)", target)
+}
+
+const niriKeyboardName = "default"
+
+type niriBackend struct {
+	run      commandRunner
+	registry *xkbRegistry
+}
+
+func (b *niriBackend) Name() string { return "niri" }
+
+func (b *niriBackend) Keyboards() ([]Keyboard, error) {
+	out, err := b.run("niri", "msg", "--json", "keyboard-layouts")
+	if err != nil {
+		return nil, err
+	}
+	return parseNiriLayouts(out, b.registry)
+}
+
+// SwitchLayout only switches globally, niri keeps a single layout state for
+// all keyboards. Naming any keyboard but the "default" one it reports is an
+// error rather than silently switching every keyboard.
+func (b *niriBackend) SwitchLayout(keyboard, target string) error {
+	if keyboard != "" && keyboard != niriKeyboardName {
+		return fmt.Errorf("niri does not support per-keyboard layouts")
+	}
+	_, err := b.run("niri", "msg", "action", "switch-layout", target)
+	return err
+}
+
+func (b *niriBackend) Events() (io.ReadCloser, error) {
+	return startStream("niri", "msg", "--json", "event-stream")
+}
+
+func (b *niriBackend) IsLayoutEvent(line string) bool {
+	return strings.Contains(line, "KeyboardLayout")
+}
+
+func parseNiriLayouts(data []byte, registry *xkbRegistry) ([]Keyboard, error) {
+	var layouts struct {
+		Names      []string `json:"names"`
+		CurrentIdx int      `json:"current_idx"`
+	}
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return nil, fmt.Errorf("failed to parse niri keyboard layouts: %w", err)
+	}
+
+	kbd := Keyboard{Name: niriKeyboardName, Layouts: make([]Layout, 0, len(layouts.Names)), ActiveIndex: -1}
+	for _, name := range layouts.Names {
+		kbd.Layouts = append(kbd.Layouts, registry.fromDescription(name))
+	}
+	if layouts.CurrentIdx >= 0 && layouts.CurrentIdx < len(layouts.Names) {
+		kbd.ActiveIndex = layouts.CurrentIdx
+		kbd.ActiveLayout = layouts.Names[layouts.CurrentIdx]
+	}
+	return []Keyboard{kbd}, nil
+}
+
+type hyprlandBackend struct {
+	run      commandRunner
+	registry *xkbRegistry
+}
+
+func (b *hyprlandBackend) Name() string { return "hyprland" }
+
+func (b *hyprlandBackend) Keyboards() ([]Keyboard, error) {
+	out, err := b.run("hyprctl", "-j", "devices")
+	if err != nil {
+		return nil, err
+	}
+	return parseHyprlandDevices(out, b.registry)
+}
+
+func (b *hyprlandBackend) SwitchLayout(keyboard, target string) error {
+	if keyboard == "" {
+		keyboard = "all"
+	}
+	out, err := b.run("hyprctl", "switchxkblayout", keyboard, target)
+	if err != nil {
+		return err
+	}
+	if reply := strings.TrimSpace(string(out)); reply != "ok" {
+		return fmt.Errorf("hyprctl: %s", reply)
+	}
+	return nil
+}
+
+func (b *hyprlandBackend) Events() (io.ReadCloser, error) {
+	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	paths := []string{filepath.Join("/tmp/hypr", signature, ".socket2.sock")}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		paths = append([]string{filepath.Join(runtimeDir, "hypr", signature, ".socket2.sock")}, paths...)
+	}
+
+	var lastErr error
+	for _, path := range paths {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to connect to Hyprland event socket: %w", lastErr)
+}
+
+func (b *hyprlandBackend) IsLayoutEvent(line string) bool {
+	return strings.HasPrefix(line, "activelayout>>") || strings.HasPrefix(line, "configreloaded>>")
+}
+
+func parseHyprlandDevices(data []byte, registry *xkbRegistry) ([]Keyboard, error) {
+	var devices struct {
+		Keyboards []struct {
+			Name         string `json:"name"`
+			Layout       string `json:"layout"`
+			Variant      string `json:"variant"`
+			ActiveKeymap string `json:"active_keymap"`
+			Main         bool   `json:"main"`
+		} `json:"keyboards"`
+	}
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return nil, fmt.Errorf("failed to parse Hyprland devices: %w", err)
+	}
+
+	keyboards := make([]Keyboard, 0, len(devices.Keyboards))
+	for _, device := range devices.Keyboards {
+		kbd := Keyboard{
+			Name:         device.Name,
+			Layouts:      registry.fromCodes(device.Layout, device.Variant),
+			ActiveIndex:  -1,
+			ActiveLayout: device.ActiveKeymap,
+			Main:         device.Main,
+		}
+		for i, layout := range kbd.Layouts {
+			if layout.Name == device.ActiveKeymap {
+				kbd.ActiveIndex = i
+				break
+			}
+		}
+		keyboards = append(keyboards, kbd)
+	}
+	return keyboards, nil
+}
+
+type swayBackend struct {
+	run      commandRunner
+	registry *xkbRegistry
+}
+
+func (b *swayBackend) Name() string { return "sway" }
+
+func (b *swayBackend) Keyboards() ([]Keyboard, error) {
+	out, err := b.run("swaymsg", "-t", "get_inputs", "-r")
+	if err != nil {
+		return nil, err
+	}
+	return parseSwayInputs(out, b.registry)
+}
+
+func (b *swayBackend) SwitchLayout(keyboard, target string) error {
+	if keyboard == "" {
+		keyboard = "type:keyboard"
+	}
+	_, err := b.run("swaymsg", "input", keyboard, "xkb_switch_layout", target)
+	return err
+}
+
+func (b *swayBackend) Events() (io.ReadCloser, error) {
+	return startStream("swaymsg", "-t", "subscribe", "-m", "-r", `["input"]`)
+}
+
+// IsLayoutEvent skips input events for added devices and libinput settings,
+// which do not change any layout.
+func (b *swayBackend) IsLayoutEvent(line string) bool {
+	var event struct {
+		Change string `json:"change"`
+	}
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return false
+	}
+	return event.Change == "xkb_layout" || event.Change == "xkb_keymap"
+}
+
+func parseSwayInputs(data []byte, registry *xkbRegistry) ([]Keyboard, error) {
+	var inputs []struct {
+		Identifier           string   `json:"identifier"`
+		Type                 string   `json:"type"`
+		XkbLayoutNames       []string `json:"xkb_layout_names"`
+		XkbActiveLayoutIndex *int     `json:"xkb_active_layout_index"`
+		XkbActiveLayoutName  string   `json:"xkb_active_layout_name"`
+	}
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("failed to parse sway inputs: %w", err)
+	}
+
+	keyboards := []Keyboard{}
+	for _, input := range inputs {
+		if input.Type != "keyboard" {
+			continue
+		}
+
+		kbd := Keyboard{
+			Name:         input.Identifier,
+			Layouts:      make([]Layout, 0, len(input.XkbLayoutNames)),
+			ActiveIndex:  -1,
+			ActiveLayout: input.XkbActiveLayoutName,
+		}
+		for _, name := range input.XkbLayoutNames {
+			kbd.Layouts = append(kbd.Layouts, registry.fromDescription(name))
+		}
+		if idx := input.XkbActiveLayoutIndex; idx != nil && *idx >= 0 && *idx < len(kbd.Layouts) {
+			kbd.ActiveIndex = *idx
+		}
+		keyboards = append(keyboards, kbd)
+	}
+	return keyboards, nil
+}
@@ -0,0 +1,151 @@
+package keyboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistryXML = `<?xml version="1.0" encoding="UTF-8"?>
+<xkbConfigRegistry version="1.1">
+  <layoutList>
+    <layout>
+      <configItem>
+        <name>us</name>
+        <shortDescription>en</shortDescription>
+        <description>English (US)</description>
+      </configItem>
+      <variantList>
+        <variant>
+          <configItem>
+            <name>dvorak</name>
+            <description>English (Dvorak)</description>
+          </configItem>
+        </variant>
+      </variantList>
+    </layout>
+    <layout>
+      <configItem>
+        <name>ru</name>
+        <shortDescription>ru</shortDescription>
+        <description>Russian</description>
+      </configItem>
+    </layout>
+  </layoutList>
+</xkbConfigRegistry>`
+
+func testRegistry(t *testing.T) *xkbRegistry {
+	t.Helper()
+	registry, err := parseXKBRegistry(strings.NewReader(testRegistryXML))
+	require.NoError(t, err)
+	return registry
+}
+
+func TestXKBRegistry(t *testing.T) {
+	registry := testRegistry(t)
+
+	assert.Equal(t, Layout{Name: "Russian", Short: "ru"}, registry.fromDescription("Russian"))
+	assert.Equal(t, Layout{Name: "English (Dvorak)", Short: "en"}, registry.fromDescription("English (Dvorak)"))
+	assert.Equal(t, Layout{Name: "Klingon"}, registry.fromDescription("Klingon"))
+
+	assert.Equal(t, []Layout{
+		{Name: "English (Dvorak)", Short: "en"},
+		{Name: "Russian", Short: "ru"},
+		{Name: "tlh", Short: "tlh"},
+	}, registry.fromCodes("us,ru,tlh", "dvorak,,"))
+	assert.Empty(t, registry.fromCodes("", ""))
+}
+
+func TestParseNiriLayouts(t *testing.T) {
+	keyboards, err := parseNiriLayouts([]byte(`{"names":["English (US)","Russian"],"current_idx":1}`), testRegistry(t))
+	require.NoError(t, err)
+	require.Len(t, keyboards, 1)
+	assert.Equal(t, Keyboard{
+		Name:         "default",
+		Layouts:      []Layout{{Name: "English (US)", Short: "en"}, {Name: "Russian", Short: "ru"}},
+		ActiveIndex:  1,
+		ActiveLayout: "Russian",
+	}, keyboards[0])
+
+	_, err = parseNiriLayouts([]byte(`not json`), testRegistry(t))
+	assert.Error(t, err)
+}
+
+func TestParseHyprlandDevices(t *testing.T) {
+	data := `{
+		"mice": [{"address": "0x1", "name": "logitech-mouse"}],
+		"keyboards": [
+			{"address": "0x2", "name": "at-translated-set-2-keyboard", "layout": "us,ru", "variant": "", "active_keymap": "Russian", "main": true},
+			{"address": "0x3", "name": "power-button", "layout": "us,ru", "variant": "", "active_keymap": "English (US)", "main": false}
+		]
+	}`
+
+	keyboards, err := parseHyprlandDevices([]byte(data), testRegistry(t))
+	require.NoError(t, err)
+	require.Len(t, keyboards, 2)
+	assert.Equal(t, "at-translated-set-2-keyboard", keyboards[0].Name)
+	assert.True(t, keyboards[0].Main)
+	assert.Equal(t, 1, keyboards[0].ActiveIndex)
+	assert.Equal(t, "Russian", keyboards[0].ActiveLayout)
+	assert.Equal(t, 0, keyboards[1].ActiveIndex)
+}
+
+func TestParseSwayInputs(t *testing.T) {
+	data := `[
+		{"identifier": "1:1:AT_Translated_Set_2_keyboard", "name": "AT Translated Set 2 keyboard", "type": "keyboard",
+		 "xkb_layout_names": ["English (US)", "Russian"], "xkb_active_layout_index": 0, "xkb_active_layout_name": "English (US)"},
+		{"identifier": "2:7:SynPS/2_Synaptics_TouchPad", "type": "touchpad"}
+	]`
+
+	keyboards, err := parseSwayInputs([]byte(data), testRegistry(t))
+	require.NoError(t, err)
+	require.Len(t, keyboards, 1)
+	assert.Equal(t, "1:1:AT_Translated_Set_2_keyboard", keyboards[0].Name)
+	assert.Equal(t, 0, keyboards[0].ActiveIndex)
+	assert.Equal(t, "en", keyboards[0].Layouts[0].Short)
+}
+
+func TestParseTarget(t *testing.T) {
+	for _, target := range []string{"next", "prev", "0", "3"} {
+		_, err := parseTarget(target)
+		assert.NoError(t, err, target)
+	}
+	for _, target := range []string{"", "-1", "russian"} {
+		_, err := parseTarget(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestBackendSwitchLayoutCommands(t *testing.T) {
+	var calls [][]string
+	record := func(reply string) commandRunner {
+		return func(name string, args ...string) ([]byte, error) {
+			calls = append(calls, append([]string{name}, args...))
+			return []byte(reply), nil
+		}
+	}
+
+	require.NoError(t, (&niriBackend{run: record("")}).SwitchLayout("default", "next"))
+	assert.Error(t, (&niriBackend{run: record("")}).SwitchLayout("AT Translated Set 2 keyboard", "next"))
+	require.NoError(t, (&hyprlandBackend{run: record("ok")}).SwitchLayout("", "1"))
+	require.NoError(t, (&swayBackend{run: record("")}).SwitchLayout("", "prev"))
+	assert.Error(t, (&hyprlandBackend{run: record("device not found")}).SwitchLayout("nope", "next"))
+
+	assert.Equal(t, [][]string{
+		{"niri", "msg", "action", "switch-layout", "next"},
+		{"hyprctl", "switchxkblayout", "all", "1"},
+		{"swaymsg", "input", "type:keyboard", "xkb_switch_layout", "prev"},
+		{"hyprctl", "switchxkblayout", "nope", "next"},
+	}, calls)
+}
+
+func TestSwayIsLayoutEvent(t *testing.T) {
+	b := &swayBackend{}
+	assert.True(t, b.IsLayoutEvent(`{"change":"xkb_layout","input":{"identifier":"1:1:AT_Translated_Set_2_keyboard"}}`))
+	assert.True(t, b.IsLayoutEvent(`{"change":"xkb_keymap","input":{}}`))
+	assert.False(t, b.IsLayoutEvent(`{"change":"added","input":{}}`))
+	assert.False(t, b.IsLayoutEvent(`{"change":"libinput_config","input":{}}`))
+	assert.False(t, b.IsLayoutEvent(`not json`))
+}
@@ -0,0 +1,83 @@
+package keyboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type Request struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req Request, manager *Manager) {
+	if manager == nil {
+		models.RespondError(conn, req.ID, "keyboard manager not initialized")
+		return
+	}
+
+	switch req.Method {
+	case "keyboard.getState":
+		models.Respond(conn, req.ID, manager.GetState())
+	case "keyboard.switchLayout":
+		handleSwitchLayout(conn, req, manager)
+	case "keyboard.subscribe":
+		handleSubscribe(conn, req, manager)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func handleSwitchLayout(conn net.Conn, req Request, manager *Manager) {
+	var target string
+	switch layout := req.Params["layout"].(type) {
+	case string:
+		target = layout
+	case float64:
+		target = strconv.Itoa(int(layout))
+	default:
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'layout' parameter"))
+		return
+	}
+
+	keyboard, _ := req.Params["keyboard"].(string)
+
+	if err := manager.SwitchLayout(keyboard, target); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, manager.GetState())
+}
+
+func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
+	clientID := fmt.Sprintf("client-%p", conn)
+	stateChan := manager.Subscribe(clientID)
+	defer manager.Unsubscribe(clientID)
+
+	initialState := manager.GetState()
+	if err := json.NewEncoder(conn).Encode(models.Response[State]{
+		ID:     req.ID,
+		Result: &initialState,
+	}); err != nil {
+		return
+	}
+
+	for state := range stateChan {
+		if err := json.NewEncoder(conn).Encode(models.Response[State]{
+			Result: &state,
+		}); err != nil {
+			return
+		}
+	}
+}
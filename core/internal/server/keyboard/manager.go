@@ -0,0 +1,206 @@
+package keyboard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const eventRetryInterval = 3 * time.Second
+
+func InitializeManager() (*Manager, error) {
+	backend, err := DetectBackend()
+	if err != nil {
+		return nil, err
+	}
+	return NewManager(backend)
+}
+
+func NewManager(backend Backend) (*Manager, error) {
+	m := newManager(backend)
+	if err := m.refresh(); err != nil {
+		return nil, fmt.Errorf("failed to query keyboard layouts: %w", err)
+	}
+
+	m.wg.Add(1)
+	go m.watchEvents()
+
+	return m, nil
+}
+
+func newManager(backend Backend) *Manager {
+	return &Manager{
+		backend:     backend,
+		state:       State{Compositor: backend.Name(), CurrentIndex: -1, Layouts: []Layout{}, Keyboards: []Keyboard{}},
+		subscribers: make(map[string]chan State),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+func (m *Manager) GetState() State {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+	return m.state
+}
+
+func (m *Manager) SwitchLayout(keyboard, target string) error {
+	target, err := parseTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if err := m.backend.SwitchLayout(keyboard, target); err != nil {
+		return fmt.Errorf("failed to switch layout: %w", err)
+	}
+
+	if err := m.refresh(); err != nil {
+		log.Debugf("Failed to refresh keyboard layouts: %v", err)
+	}
+	return nil
+}
+
+func (m *Manager) refresh() error {
+	keyboards, err := m.backend.Keyboards()
+	if err != nil {
+		return err
+	}
+	state := buildState(m.backend.Name(), keyboards)
+
+	m.stateMutex.Lock()
+	changed := !reflect.DeepEqual(m.state, state)
+	m.state = state
+	m.stateMutex.Unlock()
+
+	if changed {
+		m.notifySubscribers(state)
+	}
+	return nil
+}
+
+// buildState picks the main keyboard: the one the compositor marks as main,
+// otherwise the first keyboard with layouts.
+func buildState(compositor string, keyboards []Keyboard) State {
+	state := State{
+		Available:    true,
+		Compositor:   compositor,
+		Layouts:      []Layout{},
+		CurrentIndex: -1,
+		Keyboards:    keyboards,
+	}
+
+	main := -1
+	for i, kbd := range keyboards {
+		if kbd.Main {
+			main = i
+			break
+		}
+		if main < 0 && len(kbd.Layouts) > 0 {
+			main = i
+		}
+	}
+	if main < 0 {
+		return state
+	}
+
+	kbd := &keyboards[main]
+	kbd.Main = true
+	state.Layouts = kbd.Layouts
+	state.CurrentIndex = kbd.ActiveIndex
+	state.CurrentLayout = kbd.ActiveLayout
+	if kbd.ActiveIndex >= 0 {
+		state.CurrentShort = kbd.Layouts[kbd.ActiveIndex].Short
+	}
+	return state
+}
+
+// watchEvents refreshes on layout events from the compositor, reconnecting
+// if the event stream ends.
+func (m *Manager) watchEvents() {
+	defer m.wg.Done()
+
+	for {
+		stream, err := m.backend.Events()
+		if err != nil {
+			log.Debugf("Keyboard layout events unavailable: %v", err)
+		} else if m.setStream(stream) {
+			if err := m.refresh(); err != nil {
+				log.Debugf("Failed to refresh keyboard layouts: %v", err)
+			}
+			m.readEvents(stream)
+			m.setStream(nil)
+			stream.Close()
+		}
+
+		select {
+		case <-m.stopChan:
+			return
+		case <-time.After(eventRetryInterval):
+		}
+	}
+}
+
+func (m *Manager) readEvents(stream io.Reader) {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if !m.backend.IsLayoutEvent(scanner.Text()) {
+			continue
+		}
+		if err := m.refresh(); err != nil {
+			log.Debugf("Failed to refresh keyboard layouts: %v", err)
+		}
+	}
+}
+
+func (m *Manager) setStream(stream io.ReadCloser) bool {
+	m.streamMutex.Lock()
+	defer m.streamMutex.Unlock()
+
+	select {
+	case <-m.stopChan:
+		if stream != nil {
+			stream.Close()
+		}
+		return false
+	default:
+	}
+
+	m.stream = stream
+	return true
+}
+
+func (m *Manager) notifySubscribers(state State) {
+	m.subMutex.RLock()
+	defer m.subMutex.RUnlock()
+
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		m.streamMutex.Lock()
+		close(m.stopChan)
+		if m.stream != nil {
+			m.stream.Close()
+		}
+		m.streamMutex.Unlock()
+
+		m.wg.Wait()
+
+		m.subMutex.Lock()
+		for id, ch := range m.subscribers {
+			close(ch)
+			delete(m.subscribers, id)
+		}
+		m.subMutex.Unlock()
+	})
+}
@@ -0,0 +1,132 @@
+package keyboard
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	mu        sync.Mutex
+	keyboards []Keyboard
+	switches  []string
+	streams   chan *io.PipeWriter
+}
+
+func newFakeBackend(keyboards ...Keyboard) *fakeBackend {
+	return &fakeBackend{keyboards: keyboards, streams: make(chan *io.PipeWriter, 4)}
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Keyboards() ([]Keyboard, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.keyboards == nil {
+		return nil, errors.New("compositor gone")
+	}
+	keyboards := make([]Keyboard, len(b.keyboards))
+	copy(keyboards, b.keyboards)
+	return keyboards, nil
+}
+
+func (b *fakeBackend) SwitchLayout(keyboard, target string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.switches = append(b.switches, keyboard+":"+target)
+	b.keyboards[0].ActiveIndex = 1
+	b.keyboards[0].ActiveLayout = b.keyboards[0].Layouts[1].Name
+	return nil
+}
+
+func (b *fakeBackend) Events() (io.ReadCloser, error) {
+	r, w := io.Pipe()
+	b.streams <- w
+	return r, nil
+}
+
+func (b *fakeBackend) IsLayoutEvent(line string) bool {
+	return line == "layout"
+}
+
+func (b *fakeBackend) setActive(idx int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keyboards[0].ActiveIndex = idx
+	b.keyboards[0].ActiveLayout = b.keyboards[0].Layouts[idx].Name
+}
+
+func testKeyboard() Keyboard {
+	return Keyboard{
+		Name:         "at-translated-set-2-keyboard",
+		Layouts:      []Layout{{Name: "English (US)", Short: "en"}, {Name: "Russian", Short: "ru"}},
+		ActiveIndex:  0,
+		ActiveLayout: "English (US)",
+	}
+}
+
+func TestBuildState(t *testing.T) {
+	state := buildState("fake", []Keyboard{{Name: "virtual", Layouts: []Layout{}, ActiveIndex: -1}, testKeyboard()})
+	assert.True(t, state.Available)
+	assert.Equal(t, 0, state.CurrentIndex)
+	assert.Equal(t, "English (US)", state.CurrentLayout)
+	assert.Equal(t, "en", state.CurrentShort)
+	assert.False(t, state.Keyboards[0].Main)
+	assert.True(t, state.Keyboards[1].Main)
+
+	main := testKeyboard()
+	main.Name = "usb-keyboard"
+	main.Main = true
+	main.ActiveIndex = 1
+	state = buildState("fake", []Keyboard{testKeyboard(), main})
+	assert.Equal(t, "ru", state.CurrentShort)
+	assert.False(t, state.Keyboards[0].Main)
+
+	state = buildState("fake", []Keyboard{})
+	assert.Equal(t, -1, state.CurrentIndex)
+	assert.Empty(t, state.Layouts)
+}
+
+func TestManager_EventsRefreshState(t *testing.T) {
+	backend := newFakeBackend(testKeyboard())
+	m, err := NewManager(backend)
+	require.NoError(t, err)
+	defer m.Close()
+
+	ch := m.Subscribe("test")
+	events := <-backend.streams
+
+	backend.setActive(1)
+	_, err = io.WriteString(events, "workspace\nlayout\n")
+	require.NoError(t, err)
+
+	state := <-ch
+	assert.Equal(t, 1, state.CurrentIndex)
+	assert.Equal(t, "Russian", state.CurrentLayout)
+
+	events.Close()
+	backend.setActive(0)
+	m.Close()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestManager_SwitchLayout(t *testing.T) {
+	backend := newFakeBackend(testKeyboard())
+	m := newManager(backend)
+
+	assert.Error(t, m.SwitchLayout("", "russian"))
+	require.NoError(t, m.SwitchLayout("", "next"))
+	assert.Equal(t, []string{":next"}, backend.switches)
+	assert.Equal(t, "Russian", m.GetState().CurrentLayout)
+}
+
+func TestNewManager_BackendUnavailable(t *testing.T) {
+	_, err := NewManager(newFakeBackend())
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package keyboard
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "keyboard.getState",
+		Description: "Get keyboard layouts and the active layout per keyboard",
+		Notes:       []string{"Top-level layouts and current* fields follow the compositor's main keyboard"},
+	},
+	{
+		Name:        "keyboard.switchLayout",
+		Description: "Switch the active keyboard layout",
+		Params: []models.ParamSpec{
+			{Name: "layout", Type: models.ParamString, Required: true, Description: `"next", "prev" or a layout index`},
+			{Name: "keyboard", Type: models.ParamString, Description: "keyboard name from getState; defaults to all keyboards; niri only supports switching all keyboards"},
+		},
+	},
+	{
+		Name:        "keyboard.subscribe",
+		Description: "Subscribe to keyboard layout changes",
+		Streaming:   true,
+	},
+}
@@ -0,0 +1,70 @@
+package keyboard
+
+import (
+	"io"
+	"sync"
+)
+
+type Layout struct {
+	Name  string `json:"name"`
+	Short string `json:"short"`
+}
+
+type Keyboard struct {
+	Name         string   `json:"name"`
+	Layouts      []Layout `json:"layouts"`
+	ActiveIndex  int      `json:"activeIndex"`
+	ActiveLayout string   `json:"activeLayout"`
+	Main         bool     `json:"main"`
+}
+
+// State mirrors the main keyboard at the top level so a layout indicator
+// does not have to pick one itself.
+type State struct {
+	Available     bool       `json:"available"`
+	Compositor    string     `json:"compositor"`
+	Layouts       []Layout   `json:"layouts"`
+	CurrentIndex  int        `json:"currentIndex"`
+	CurrentLayout string     `json:"currentLayout"`
+	CurrentShort  string     `json:"currentShort"`
+	Keyboards     []Keyboard `json:"keyboards"`
+}
+
+// Backend talks to the running compositor's IPC.
+type Backend interface {
+	Name() string
+	Keyboards() ([]Keyboard, error)
+	SwitchLayout(keyboard, target string) error
+	Events() (io.ReadCloser, error)
+	IsLayoutEvent(line string) bool
+}
+
+type Manager struct {
+	backend     Backend
+	state       State
+	stateMutex  sync.RWMutex
+	subscribers map[string]chan State
+	subMutex    sync.RWMutex
+	stream      io.ReadCloser
+	streamMutex sync.Mutex
+	stopChan    chan struct{}
+	wg          sync.WaitGroup
+	closeOnce   sync.Once
+}
+
+func (m *Manager) Subscribe(id string) chan State {
+	ch := make(chan State, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
@@ -0,0 +1,123 @@
+package keyboard
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+var xkbRegistryPaths = []string{
+	"/usr/share/X11/xkb/rules/evdev.xml",
+	"/usr/share/X11/xkb/rules/base.xml",
+}
+
+type xkbConfigItem struct {
+	Name             string `xml:"name"`
+	ShortDescription string `xml:"shortDescription"`
+	Description      string `xml:"description"`
+}
+
+type xkbRegistryFile struct {
+	Layouts []struct {
+		ConfigItem xkbConfigItem `xml:"configItem"`
+		Variants   []struct {
+			ConfigItem xkbConfigItem `xml:"configItem"`
+		} `xml:"variantList>variant"`
+	} `xml:"layoutList>layout"`
+}
+
+// xkbRegistry maps XKB layout codes ("us", "us(dvorak)") to the descriptions
+// compositors report, and descriptions back to short indicator labels.
+type xkbRegistry struct {
+	byCode        map[string]Layout
+	byDescription map[string]string
+}
+
+var (
+	defaultRegistry     *xkbRegistry
+	defaultRegistryOnce sync.Once
+)
+
+func loadRegistry() *xkbRegistry {
+	defaultRegistryOnce.Do(func() {
+		for _, path := range xkbRegistryPaths {
+			f, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			registry, err := parseXKBRegistry(f)
+			f.Close()
+			if err == nil {
+				defaultRegistry = registry
+				return
+			}
+		}
+		defaultRegistry = &xkbRegistry{byCode: map[string]Layout{}, byDescription: map[string]string{}}
+	})
+	return defaultRegistry
+}
+
+func parseXKBRegistry(r io.Reader) (*xkbRegistry, error) {
+	var file xkbRegistryFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	registry := &xkbRegistry{
+		byCode:        make(map[string]Layout),
+		byDescription: make(map[string]string),
+	}
+	for _, layout := range file.Layouts {
+		item := layout.ConfigItem
+		registry.add(item.Name, item.Description, item.ShortDescription)
+		for _, variant := range layout.Variants {
+			short := variant.ConfigItem.ShortDescription
+			if short == "" {
+				short = item.ShortDescription
+			}
+			registry.add(item.Name+"("+variant.ConfigItem.Name+")", variant.ConfigItem.Description, short)
+		}
+	}
+	return registry, nil
+}
+
+func (r *xkbRegistry) add(code, description, short string) {
+	r.byCode[code] = Layout{Name: description, Short: short}
+	if _, exists := r.byDescription[description]; !exists {
+		r.byDescription[description] = short
+	}
+}
+
+func (r *xkbRegistry) fromDescription(description string) Layout {
+	return Layout{Name: description, Short: r.byDescription[description]}
+}
+
+// fromCodes resolves comma separated XKB layout and variant lists, as found
+// in compositor configs, to layouts.
+func (r *xkbRegistry) fromCodes(layouts, variants string) []Layout {
+	if layouts == "" {
+		return []Layout{}
+	}
+
+	codes := strings.Split(layouts, ",")
+	variantList := strings.Split(variants, ",")
+	result := make([]Layout, 0, len(codes))
+	for i, code := range codes {
+		code = strings.TrimSpace(code)
+		key := code
+		if i < len(variantList) && strings.TrimSpace(variantList[i]) != "" {
+			key = code + "(" + strings.TrimSpace(variantList[i]) + ")"
+		}
+
+		if layout, ok := r.byCode[key]; ok {
+			result = append(result, layout)
+		} else if layout, ok := r.byCode[code]; ok {
+			result = append(result, layout)
+		} else {
+			result = append(result, Layout{Name: key, Short: code})
+		}
+	}
+	return result
+}
@@ -2,6 +2,8 @@ package loginctl
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 )
 
 func (m *Manager) Lock() error {
@@ -59,6 +61,19 @@ func (m *Manager) SetIdleHint(idle bool) error {
 	return nil
 }
 
+// IdleInhibited reports whether logind holds a block-mode idle inhibitor.
+func (m *Manager) IdleInhibited() (bool, error) {
+	if m.managerObj == nil {
+		return false, fmt.Errorf("manager object not available")
+	}
+	prop, err := m.managerObj.GetProperty(dbusManagerInterface + ".BlockInhibited")
+	if err != nil {
+		return false, fmt.Errorf("failed to read inhibitors: %w", err)
+	}
+	what, _ := prop.Value().(string)
+	return slices.Contains(strings.Split(what, ":"), "idle"), nil
+}
+
 func (m *Manager) Terminate() error {
 	err := m.sessionObj.Call(dbusSessionInterface+".Terminate", 0).Err
 	if err != nil {
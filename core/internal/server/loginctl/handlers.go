@@ -2,14 +2,16 @@ package loginctl
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -39,6 +41,30 @@ func HandleRequest(conn net.Conn, req Request, manager *Manager) {
 		handleLockerReady(conn, req, manager)
 	case "loginctl.terminate":
 		handleTerminate(conn, req, manager)
+	case "loginctl.inhibit":
+		handleInhibit(conn, req, manager)
+	case "loginctl.releaseInhibit":
+		handleReleaseInhibit(conn, req, manager)
+	case "loginctl.listInhibitors":
+		handleListInhibitors(conn, req, manager)
+	case "loginctl.powerOff":
+		handlePower(conn, req, manager, PowerOff)
+	case "loginctl.reboot":
+		handlePower(conn, req, manager, PowerReboot)
+	case "loginctl.suspend":
+		handlePower(conn, req, manager, PowerSuspend)
+	case "loginctl.hibernate":
+		handlePower(conn, req, manager, PowerHibernate)
+	case "loginctl.hybridSleep":
+		handlePower(conn, req, manager, PowerHybridSleep)
+	case "loginctl.suspendThenHibernate":
+		handlePower(conn, req, manager, PowerSuspendThenHibernate)
+	case "loginctl.getPowerCapabilities":
+		handleGetPowerCapabilities(conn, req, manager)
+	case "loginctl.scheduleShutdown":
+		handleScheduleShutdown(conn, req, manager)
+	case "loginctl.cancelScheduledShutdown":
+		handleCancelScheduledShutdown(conn, req, manager)
 	case "loginctl.subscribe":
 		handleSubscribe(conn, req, manager)
 	default:
@@ -78,7 +104,7 @@ func handleActivate(conn net.Conn, req Request, manager *Manager) {
 func handleSetIdleHint(conn net.Conn, req Request, manager *Manager) {
 	idle, ok := req.Params["idle"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'idle' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'idle' parameter"))
 		return
 	}
 
@@ -92,7 +118,7 @@ func handleSetIdleHint(conn net.Conn, req Request, manager *Manager) {
 func handleSetLockBeforeSuspend(conn net.Conn, req Request, manager *Manager) {
 	enabled, ok := req.Params["enabled"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'enabled' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'enabled' parameter"))
 		return
 	}
 
@@ -103,7 +129,7 @@ func handleSetLockBeforeSuspend(conn net.Conn, req Request, manager *Manager) {
 func handleSetSleepInhibitorEnabled(conn net.Conn, req Request, manager *Manager) {
 	enabled, ok := req.Params["enabled"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'enabled' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'enabled' parameter"))
 		return
 	}
 
@@ -136,6 +162,109 @@ func handleTerminate(conn net.Conn, req Request, manager *Manager) {
 	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "terminated"})
 }
 
+func handleInhibit(conn net.Conn, req Request, manager *Manager) {
+	inhibitReq := InhibitRequest{}
+	inhibitReq.What, _ = req.Params["what"].(string)
+	inhibitReq.Why, _ = req.Params["why"].(string)
+	inhibitReq.Mode, _ = req.Params["mode"].(string)
+	if timeout, ok := req.Params["timeout"].(float64); ok {
+		inhibitReq.Timeout = time.Duration(timeout * float64(time.Second))
+	}
+	if pid, ok := req.Params["pid"].(float64); ok {
+		inhibitReq.WatchPID = int(pid)
+	}
+
+	held, err := manager.Inhibit(inhibitReq)
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, held)
+}
+
+func handleReleaseInhibit(conn net.Conn, req Request, manager *Manager) {
+	handle, ok := req.Params["handle"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'handle' parameter"))
+		return
+	}
+
+	if err := manager.ReleaseInhibit(handle); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "inhibitor released"})
+}
+
+func handleListInhibitors(conn net.Conn, req Request, manager *Manager) {
+	inhibitors, err := manager.ListInhibitors()
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, inhibitors)
+}
+
+func handlePower(conn net.Conn, req Request, manager *Manager, action PowerAction) {
+	interactive, _ := req.Params["interactive"].(bool)
+
+	if err := manager.Power(action, interactive); err != nil {
+		var perr *PowerError
+		if errors.As(err, &perr) {
+			models.RespondErrorData(conn, req.ID, models.ErrCodeServer, err.Error(), perr)
+			return
+		}
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: string(action) + " requested"})
+}
+
+func handleGetPowerCapabilities(conn net.Conn, req Request, manager *Manager) {
+	caps, err := manager.PowerCapabilities()
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, caps)
+}
+
+func handleScheduleShutdown(conn net.Conn, req Request, manager *Manager) {
+	kind, ok := req.Params["type"].(string)
+	if !ok {
+		kind = "poweroff"
+	}
+
+	var at time.Time
+	if delay, ok := req.Params["delay"].(float64); ok {
+		at = time.Now().Add(time.Duration(delay * float64(time.Second)))
+	} else if ts, ok := req.Params["at"].(float64); ok {
+		at = time.Unix(int64(ts), 0)
+	} else {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'delay' or 'at' parameter"))
+		return
+	}
+
+	if err := manager.ScheduleShutdown(kind, at); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "shutdown scheduled"})
+}
+
+func handleCancelScheduledShutdown(conn net.Conn, req Request, manager *Manager) {
+	cancelled, err := manager.CancelScheduledShutdown()
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+	if !cancelled {
+		models.Respond(conn, req.ID, SuccessResult{Success: false, Message: "no shutdown scheduled"})
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "scheduled shutdown cancelled"})
+}
+
 func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
 	clientID := fmt.Sprintf("client-%p", conn)
 	stateChan := manager.Subscribe(clientID)
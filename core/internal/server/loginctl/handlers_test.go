@@ -51,7 +51,7 @@ func TestRespondError_Loginctl(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Equal(t, "test error", resp.Error)
 	assert.Nil(t, resp.Result)
 }
@@ -65,7 +65,7 @@ func TestRespond_Loginctl(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.True(t, resp.Result.Success)
@@ -94,7 +94,7 @@ func TestHandleGetState(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.Equal(t, "1", resp.Result.SessionID)
@@ -122,7 +122,7 @@ func TestHandleLock(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -148,7 +148,7 @@ func TestHandleLock(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "failed to lock session")
 	})
 }
@@ -173,7 +173,7 @@ func TestHandleUnlock(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -199,7 +199,7 @@ func TestHandleUnlock(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "failed to unlock session")
 	})
 }
@@ -224,7 +224,7 @@ func TestHandleActivate(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -250,7 +250,7 @@ func TestHandleActivate(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "failed to activate session")
 	})
 }
@@ -275,7 +275,7 @@ func TestHandleSetIdleHint(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'idle' parameter")
 	})
 
@@ -305,7 +305,7 @@ func TestHandleSetIdleHint(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -338,7 +338,7 @@ func TestHandleSetIdleHint(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "failed to set idle hint")
 	})
 }
@@ -363,7 +363,7 @@ func TestHandleTerminate(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 		require.NotNil(t, resp.Result)
 		assert.True(t, resp.Result.Success)
@@ -389,7 +389,7 @@ func TestHandleTerminate(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "failed to terminate session")
 	})
 }
@@ -416,7 +416,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "unknown method")
 	})
 
@@ -433,7 +433,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 	})
 
@@ -456,7 +456,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 	})
 }
 
@@ -488,7 +488,7 @@ func TestHandleSubscribe(t *testing.T) {
 		var resp models.Response[SessionEvent]
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		if err == nil {
-			assert.Equal(t, 123, resp.ID)
+			assert.EqualValues(t, 123, resp.ID)
 			require.NotNil(t, resp.Result)
 			assert.Equal(t, EventStateChanged, resp.Result.Type)
 			assert.Equal(t, "1", resp.Result.Data.SessionID)
@@ -0,0 +1,269 @@
+package loginctl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	managerPollInterval = 5 * time.Second
+	watchPIDInterval    = 2 * time.Second
+)
+
+var inhibitWhats = []string{
+	"shutdown",
+	"sleep",
+	"idle",
+	"handle-power-key",
+	"handle-suspend-key",
+	"handle-hibernate-key",
+	"handle-lid-switch",
+}
+
+type InhibitRequest struct {
+	What     string
+	Why      string
+	Mode     string
+	Timeout  time.Duration
+	WatchPID int
+}
+
+type heldInhibitor struct {
+	seq  uint64
+	info HeldInhibitor
+	file *os.File
+	stop chan struct{}
+}
+
+func (r *InhibitRequest) normalize() error {
+	if r.What == "" {
+		r.What = "idle:sleep"
+	}
+	for _, what := range strings.Split(r.What, ":") {
+		if !slices.Contains(inhibitWhats, what) {
+			return fmt.Errorf("invalid inhibit type: %s", what)
+		}
+	}
+
+	if r.Mode == "" {
+		r.Mode = "block"
+	}
+	if r.Mode != "block" && r.Mode != "delay" {
+		return fmt.Errorf("invalid inhibit mode: %s", r.Mode)
+	}
+
+	if r.Why == "" {
+		r.Why = "Requested by user"
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("invalid timeout: %v", r.Timeout)
+	}
+	if r.WatchPID < 0 {
+		return fmt.Errorf("invalid pid: %d", r.WatchPID)
+	}
+	return nil
+}
+
+// Inhibit takes a logind inhibitor lock that is held until ReleaseInhibit,
+// the timeout expires or the watched process exits, whichever comes first.
+func (m *Manager) Inhibit(req InhibitRequest) (HeldInhibitor, error) {
+	if err := req.normalize(); err != nil {
+		return HeldInhibitor{}, err
+	}
+	if req.WatchPID > 0 && !processAlive(req.WatchPID) {
+		return HeldInhibitor{}, fmt.Errorf("process not running: %d", req.WatchPID)
+	}
+	if m.managerObj == nil {
+		return HeldInhibitor{}, fmt.Errorf("manager object not available")
+	}
+
+	file, err := m.inhibit(req.What, "DankMaterialShell", req.Why, req.Mode)
+	if err != nil {
+		return HeldInhibitor{}, fmt.Errorf("failed to inhibit: %w", err)
+	}
+
+	m.heldMu.Lock()
+	if m.held == nil {
+		m.held = make(map[string]*heldInhibitor)
+	}
+	m.nextHandle++
+	h := &heldInhibitor{
+		seq: m.nextHandle,
+		info: HeldInhibitor{
+			Handle:   fmt.Sprintf("inhibit-%d", m.nextHandle),
+			What:     req.What,
+			Why:      req.Why,
+			Mode:     req.Mode,
+			WatchPID: req.WatchPID,
+		},
+		file: file,
+		stop: make(chan struct{}),
+	}
+	if req.Timeout > 0 {
+		h.info.ExpiresAt = time.Now().Add(req.Timeout).Unix()
+	}
+	m.held[h.info.Handle] = h
+	m.heldMu.Unlock()
+
+	if req.Timeout > 0 || req.WatchPID > 0 {
+		go m.superviseInhibitor(h.info.Handle, h.stop, req.Timeout, req.WatchPID)
+	}
+
+	m.refreshInhibitors()
+	return h.info, nil
+}
+
+func (m *Manager) ReleaseInhibit(handle string) error {
+	m.heldMu.Lock()
+	h, ok := m.held[handle]
+	delete(m.held, handle)
+	m.heldMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("inhibitor not found: %s", handle)
+	}
+
+	close(h.stop)
+	h.file.Close()
+
+	m.refreshInhibitors()
+	return nil
+}
+
+func (m *Manager) superviseInhibitor(handle string, stop chan struct{}, timeout time.Duration, pid int) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var poll <-chan time.Time
+	if pid > 0 {
+		ticker := time.NewTicker(watchPIDInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-m.stopChan:
+			return
+		case <-expired:
+			m.ReleaseInhibit(handle)
+			return
+		case <-poll:
+			if !processAlive(pid) {
+				m.ReleaseInhibit(handle)
+				return
+			}
+		}
+	}
+}
+
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func (m *Manager) ListInhibitors() ([]Inhibitor, error) {
+	if m.managerObj == nil {
+		return nil, fmt.Errorf("manager object not available")
+	}
+
+	var raw []struct {
+		What string
+		Who  string
+		Why  string
+		Mode string
+		UID  uint32
+		PID  uint32
+	}
+	if err := m.managerObj.Call(dbusManagerInterface+".ListInhibitors", 0).Store(&raw); err != nil {
+		return nil, fmt.Errorf("failed to list inhibitors: %w", err)
+	}
+
+	inhibitors := make([]Inhibitor, 0, len(raw))
+	for _, r := range raw {
+		inhibitors = append(inhibitors, Inhibitor{
+			What: r.What,
+			Who:  r.Who,
+			Why:  r.Why,
+			Mode: r.Mode,
+			UID:  r.UID,
+			PID:  r.PID,
+		})
+	}
+	return inhibitors, nil
+}
+
+func (m *Manager) heldInhibitors() []HeldInhibitor {
+	m.heldMu.Lock()
+	held := make([]*heldInhibitor, 0, len(m.held))
+	for _, h := range m.held {
+		held = append(held, h)
+	}
+	m.heldMu.Unlock()
+
+	slices.SortFunc(held, func(a, b *heldInhibitor) int {
+		return int(a.seq) - int(b.seq)
+	})
+
+	infos := make([]HeldInhibitor, 0, len(held))
+	for _, h := range held {
+		infos = append(infos, h.info)
+	}
+	return infos
+}
+
+// refreshInhibitors updates the inhibitor lists in the session state. logind
+// does not signal inhibitor changes, so managerStatePoller also calls it.
+func (m *Manager) refreshInhibitors() {
+	inhibitors, err := m.ListInhibitors()
+	held := m.heldInhibitors()
+
+	m.stateMutex.Lock()
+	if err == nil {
+		m.state.Inhibitors = inhibitors
+	}
+	m.state.HeldInhibitors = held
+	m.stateMutex.Unlock()
+
+	m.notifySubscribers()
+}
+
+func (m *Manager) managerStatePoller() {
+	defer m.notifierWg.Done()
+
+	ticker := time.NewTicker(managerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.refreshInhibitors()
+			m.refreshScheduledShutdown()
+		}
+	}
+}
+
+func (m *Manager) releaseHeldInhibitors() {
+	m.heldMu.Lock()
+	held := m.held
+	m.held = nil
+	m.heldMu.Unlock()
+
+	for _, h := range held {
+		close(h.stop)
+		h.file.Close()
+	}
+}
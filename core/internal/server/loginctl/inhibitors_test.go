@@ -0,0 +1,126 @@
+package loginctl
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	mockdbus "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listInhibitorsCall(entries ...[]interface{}) *dbus.Call {
+	if entries == nil {
+		entries = [][]interface{}{}
+	}
+	return &dbus.Call{Body: []interface{}{entries}}
+}
+
+func inhibitCall(t *testing.T) *dbus.Call {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+	t.Cleanup(func() { r.Close() })
+
+	fd, err := syscall.Dup(int(w.Fd()))
+	require.NoError(t, err)
+	return &dbus.Call{Body: []interface{}{dbus.UnixFD(fd)}}
+}
+
+func TestInhibitRequest_Normalize(t *testing.T) {
+	req := InhibitRequest{}
+	require.NoError(t, req.normalize())
+	assert.Equal(t, "idle:sleep", req.What)
+	assert.Equal(t, "block", req.Mode)
+	assert.NotEmpty(t, req.Why)
+
+	req = InhibitRequest{What: "sleep:handle-lid-switch", Mode: "delay"}
+	assert.NoError(t, req.normalize())
+
+	req = InhibitRequest{What: "idle:coffee"}
+	assert.Error(t, req.normalize())
+
+	req = InhibitRequest{Mode: "forever"}
+	assert.Error(t, req.normalize())
+
+	req = InhibitRequest{Timeout: -time.Second}
+	assert.Error(t, req.normalize())
+}
+
+func TestManager_ListInhibitors(t *testing.T) {
+	mockManagerObj := mockdbus.NewMockBusObject(t)
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListInhibitors", dbus.Flags(0)).Return(listInhibitorsCall(
+		[]interface{}{"sleep", "firefox", "Playing video", "block", uint32(1000), uint32(4242)},
+	))
+
+	manager := &Manager{state: &SessionState{}, managerObj: mockManagerObj}
+	inhibitors, err := manager.ListInhibitors()
+	require.NoError(t, err)
+	assert.Equal(t, []Inhibitor{
+		{What: "sleep", Who: "firefox", Why: "Playing video", Mode: "block", UID: 1000, PID: 4242},
+	}, inhibitors)
+}
+
+func TestManager_InhibitAndRelease(t *testing.T) {
+	mockManagerObj := mockdbus.NewMockBusObject(t)
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".Inhibit", dbus.Flags(0), "idle", "DankMaterialShell", "Caffeine", "block").Return(inhibitCall(t))
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListInhibitors", dbus.Flags(0)).Return(listInhibitorsCall())
+
+	manager := &Manager{
+		state:      &SessionState{},
+		stopChan:   make(chan struct{}),
+		managerObj: mockManagerObj,
+	}
+
+	held, err := manager.Inhibit(InhibitRequest{What: "idle", Why: "Caffeine"})
+	require.NoError(t, err)
+	assert.Equal(t, "inhibit-1", held.Handle)
+	assert.Zero(t, held.ExpiresAt)
+
+	state := manager.GetState()
+	require.Len(t, state.HeldInhibitors, 1)
+	assert.Equal(t, held, state.HeldInhibitors[0])
+
+	require.NoError(t, manager.ReleaseInhibit(held.Handle))
+	assert.Empty(t, manager.GetState().HeldInhibitors)
+	assert.Error(t, manager.ReleaseInhibit(held.Handle))
+}
+
+func TestManager_InhibitTimeout(t *testing.T) {
+	mockManagerObj := mockdbus.NewMockBusObject(t)
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".Inhibit", dbus.Flags(0), "idle:sleep", "DankMaterialShell", "Requested by user", "block").Return(inhibitCall(t))
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListInhibitors", dbus.Flags(0)).Return(listInhibitorsCall())
+
+	manager := &Manager{
+		state:      &SessionState{},
+		stopChan:   make(chan struct{}),
+		managerObj: mockManagerObj,
+	}
+
+	held, err := manager.Inhibit(InhibitRequest{Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+	assert.NotZero(t, held.ExpiresAt)
+
+	assert.Eventually(t, func() bool {
+		return len(manager.GetState().HeldInhibitors) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_InhibitDeadPID(t *testing.T) {
+	manager := &Manager{state: &SessionState{}}
+	_, err := manager.Inhibit(InhibitRequest{WatchPID: 1 << 30})
+	assert.Error(t, err)
+}
+
+func TestStateChangedMeaningfully_Inhibitors(t *testing.T) {
+	old := &SessionState{}
+	new := &SessionState{Inhibitors: []Inhibitor{{What: "sleep", Who: "firefox"}}}
+	assert.True(t, stateChangedMeaningfully(old, new))
+
+	new = &SessionState{HeldInhibitors: []HeldInhibitor{{Handle: "inhibit-1"}}}
+	assert.True(t, stateChangedMeaningfully(old, new))
+}
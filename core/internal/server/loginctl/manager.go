@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ func NewManager() (*Manager, error) {
 		conn:        conn,
 		dirty:       make(chan struct{}, 1),
 		signals:     make(chan *dbus.Signal, 256),
+		held:        make(map[string]*heldInhibitor),
 	}
 	m.sleepInhibitorEnabled.Store(true)
 
@@ -44,8 +46,12 @@ func NewManager() (*Manager, error) {
 		fmt.Fprintf(os.Stderr, "sleep inhibitor unavailable: %v\n", err)
 	}
 
-	m.notifierWg.Add(1)
+	m.refreshInhibitors()
+	m.refreshScheduledShutdown()
+
+	m.notifierWg.Add(2)
 	go m.notifier()
+	go m.managerStatePoller()
 
 	if err := m.startSignalPump(); err != nil {
 		m.Close()
@@ -342,6 +348,15 @@ func stateChangedMeaningfully(old, new *SessionState) bool {
 	if old.PreparingForSleep != new.PreparingForSleep {
 		return true
 	}
+	if !slices.Equal(old.Inhibitors, new.Inhibitors) || !slices.Equal(old.HeldInhibitors, new.HeldInhibitors) {
+		return true
+	}
+	if (old.ScheduledShutdown == nil) != (new.ScheduledShutdown == nil) {
+		return true
+	}
+	if old.ScheduledShutdown != nil && *old.ScheduledShutdown != *new.ScheduledShutdown {
+		return true
+	}
 	return false
 }
 
@@ -583,6 +598,7 @@ func (m *Manager) Close() {
 	m.stopSignalPump()
 
 	m.releaseSleepInhibitor()
+	m.releaseHeldInhibitors()
 
 	m.subMutex.Lock()
 	for _, ch := range m.subscribers {
@@ -0,0 +1,137 @@
+package loginctl
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var powerParams = []models.ParamSpec{
+	{Name: "interactive", Type: models.ParamBool, Description: "allow polkit to prompt for authorization"},
+}
+
+var powerNotes = []string{"refusals carry error data {action, reason, message, inhibitors?, users?}; reason is one of inhibited, users_logged_in, auth_required, access_denied, in_progress, not_supported, not_allowed, failed"}
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "loginctl.getState",
+		Description: "Get current session state",
+	},
+	{
+		Name:        "loginctl.lock",
+		Description: "Lock session",
+	},
+	{
+		Name:        "loginctl.unlock",
+		Description: "Unlock session",
+	},
+	{
+		Name:        "loginctl.activate",
+		Description: "Activate session",
+	},
+	{
+		Name:        "loginctl.setIdleHint",
+		Description: "Set idle hint",
+		Params: []models.ParamSpec{
+			{Name: "idle", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "loginctl.setLockBeforeSuspend",
+		Description: "Set lock before suspend",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "loginctl.setSleepInhibitorEnabled",
+		Description: "Enable/disable sleep inhibitor",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "loginctl.lockerReady",
+		Description: "Signal locker UI is ready (releases sleep inhibitor)",
+	},
+	{
+		Name:        "loginctl.terminate",
+		Description: "Terminate session",
+	},
+	{
+		Name:        "loginctl.inhibit",
+		Description: "Take a logind inhibitor lock and return its handle",
+		Params: []models.ParamSpec{
+			{Name: "what", Type: models.ParamString, Description: `colon-separated, e.g. "idle:sleep" (default)`},
+			{Name: "why", Type: models.ParamString},
+			{Name: "mode", Type: models.ParamString, Enum: []string{"block", "delay"}},
+			{Name: "timeout", Type: models.ParamNumber, Description: "seconds until the lock is released automatically"},
+			{Name: "pid", Type: models.ParamNumber, Description: "release the lock when this process exits"},
+		},
+	},
+	{
+		Name:        "loginctl.releaseInhibit",
+		Description: "Release an inhibitor lock taken with loginctl.inhibit",
+		Params: []models.ParamSpec{
+			{Name: "handle", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "loginctl.listInhibitors",
+		Description: "List all logind inhibitor locks",
+	},
+	{
+		Name:        "loginctl.powerOff",
+		Description: "Power off the system",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.reboot",
+		Description: "Reboot the system",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.suspend",
+		Description: "Suspend the system",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.hibernate",
+		Description: "Hibernate the system",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.hybridSleep",
+		Description: "Suspend and hibernate the system at once",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.suspendThenHibernate",
+		Description: "Suspend, then hibernate after the configured delay",
+		Params:      powerParams,
+		Notes:       powerNotes,
+	},
+	{
+		Name:        "loginctl.getPowerCapabilities",
+		Description: "Get logind's yes/no/challenge/na answer for each power action",
+	},
+	{
+		Name:        "loginctl.scheduleShutdown",
+		Description: "Schedule a shutdown, shown as scheduledShutdown in the session state",
+		Params: []models.ParamSpec{
+			{Name: "type", Type: models.ParamString, Enum: shutdownTypes, Description: "defaults to poweroff"},
+			{Name: "delay", Type: models.ParamNumber, Description: "seconds from now"},
+			{Name: "at", Type: models.ParamNumber, Description: "unix timestamp, used when delay is not given"},
+		},
+	},
+	{
+		Name:        "loginctl.cancelScheduledShutdown",
+		Description: "Cancel a scheduled shutdown",
+	},
+	{
+		Name:        "loginctl.subscribe",
+		Description: "Subscribe to session state changes",
+		Streaming:   true,
+	},
+}
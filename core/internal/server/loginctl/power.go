@@ -0,0 +1,262 @@
+package loginctl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type PowerAction string
+
+const (
+	PowerOff                  PowerAction = "poweroff"
+	PowerReboot               PowerAction = "reboot"
+	PowerSuspend              PowerAction = "suspend"
+	PowerHibernate            PowerAction = "hibernate"
+	PowerHybridSleep          PowerAction = "hybrid-sleep"
+	PowerSuspendThenHibernate PowerAction = "suspend-then-hibernate"
+)
+
+type powerActionSpec struct {
+	method      string
+	can         string
+	inhibitWhat string
+}
+
+var powerActions = map[PowerAction]powerActionSpec{
+	PowerOff:                  {method: "PowerOff", can: "CanPowerOff", inhibitWhat: "shutdown"},
+	PowerReboot:               {method: "Reboot", can: "CanReboot", inhibitWhat: "shutdown"},
+	PowerSuspend:              {method: "Suspend", can: "CanSuspend", inhibitWhat: "sleep"},
+	PowerHibernate:            {method: "Hibernate", can: "CanHibernate", inhibitWhat: "sleep"},
+	PowerHybridSleep:          {method: "HybridSleep", can: "CanHybridSleep", inhibitWhat: "sleep"},
+	PowerSuspendThenHibernate: {method: "SuspendThenHibernate", can: "CanSuspendThenHibernate", inhibitWhat: "sleep"},
+}
+
+var shutdownTypes = []string{"poweroff", "reboot", "halt", "dry-poweroff", "dry-reboot", "dry-halt"}
+
+const (
+	PowerErrorInhibited      = "inhibited"
+	PowerErrorUsersLoggedIn  = "users_logged_in"
+	PowerErrorAuthRequired   = "auth_required"
+	PowerErrorAccessDenied   = "access_denied"
+	PowerErrorInProgress     = "in_progress"
+	PowerErrorNotSupported   = "not_supported"
+	PowerErrorNotAllowed     = "not_allowed"
+	PowerErrorUnknownFailure = "failed"
+)
+
+// PowerError describes why logind refused a power action, in a form the
+// client can act on.
+type PowerError struct {
+	Action     PowerAction `json:"action"`
+	Reason     string      `json:"reason"`
+	Message    string      `json:"message"`
+	DBusError  string      `json:"dbusError,omitempty"`
+	Inhibitors []Inhibitor `json:"inhibitors,omitempty"`
+	Users      []string    `json:"users,omitempty"`
+}
+
+func (e *PowerError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Action, e.Message)
+}
+
+type ScheduledShutdown struct {
+	Type string `json:"type"`
+	At   int64  `json:"at"`
+}
+
+// PowerCapabilities maps each power action to logind's answer: "yes", "no",
+// "challenge" or "na".
+func (m *Manager) PowerCapabilities() (map[PowerAction]string, error) {
+	if m.managerObj == nil {
+		return nil, fmt.Errorf("manager object not available")
+	}
+
+	caps := make(map[PowerAction]string, len(powerActions))
+	for action, spec := range powerActions {
+		var result string
+		if err := m.managerObj.Call(dbusManagerInterface+"."+spec.can, 0).Store(&result); err != nil {
+			result = "na"
+		}
+		caps[action] = result
+	}
+	return caps, nil
+}
+
+func (m *Manager) Power(action PowerAction, interactive bool) error {
+	spec, ok := powerActions[action]
+	if !ok {
+		return fmt.Errorf("unknown power action: %s", action)
+	}
+	if m.managerObj == nil {
+		return fmt.Errorf("manager object not available")
+	}
+
+	var can string
+	if err := m.managerObj.Call(dbusManagerInterface+"."+spec.can, 0).Store(&can); err == nil {
+		switch can {
+		case "na":
+			return &PowerError{Action: action, Reason: PowerErrorNotSupported, Message: "not supported on this system"}
+		case "no":
+			return &PowerError{Action: action, Reason: PowerErrorNotAllowed, Message: "not allowed for this user"}
+		}
+	}
+
+	err := m.managerObj.Call(dbusManagerInterface+"."+spec.method, 0, interactive).Err
+	if err != nil {
+		return m.classifyPowerError(action, spec, err)
+	}
+	return nil
+}
+
+// Suspend suspends without interactive authorization, for callers acting
+// on the user's behalf such as idle timeouts.
+func (m *Manager) Suspend() error {
+	return m.Power(PowerSuspend, false)
+}
+
+// classifyPowerError turns a logind error into a PowerError. Without
+// interactive authorization, logind reports both blocking inhibitors and
+// other logged-in users as an authorization failure, so those are checked
+// explicitly.
+func (m *Manager) classifyPowerError(action PowerAction, spec powerActionSpec, err error) *PowerError {
+	perr := &PowerError{Action: action, Reason: PowerErrorUnknownFailure, Message: err.Error()}
+
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) {
+		perr.DBusError = dbusErr.Name
+		if len(dbusErr.Body) > 0 {
+			if msg, ok := dbusErr.Body[0].(string); ok {
+				perr.Message = msg
+			}
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(perr.DBusError, ".OperationInProgress"):
+		perr.Reason = PowerErrorInProgress
+		return perr
+	case strings.HasSuffix(perr.DBusError, ".SleepVerbNotSupported"):
+		perr.Reason = PowerErrorNotSupported
+		return perr
+	case strings.Contains(perr.DBusError, "Inhibit"):
+		perr.Reason = PowerErrorInhibited
+	case perr.DBusError == "org.freedesktop.DBus.Error.InteractiveAuthorizationRequired":
+		perr.Reason = PowerErrorAuthRequired
+	case perr.DBusError == "org.freedesktop.DBus.Error.AccessDenied":
+		perr.Reason = PowerErrorAccessDenied
+	default:
+		return perr
+	}
+
+	if blocking := m.blockingInhibitors(spec.inhibitWhat); len(blocking) > 0 {
+		perr.Reason = PowerErrorInhibited
+		perr.Inhibitors = blocking
+		return perr
+	}
+	if perr.Reason != PowerErrorInhibited {
+		if users := m.otherUsers(); len(users) > 0 {
+			perr.Reason = PowerErrorUsersLoggedIn
+			perr.Users = users
+		}
+	}
+	return perr
+}
+
+func (m *Manager) blockingInhibitors(what string) []Inhibitor {
+	inhibitors, err := m.ListInhibitors()
+	if err != nil {
+		return nil
+	}
+
+	var blocking []Inhibitor
+	for _, inhibitor := range inhibitors {
+		if inhibitor.Mode == "block" && slices.Contains(strings.Split(inhibitor.What, ":"), what) {
+			blocking = append(blocking, inhibitor)
+		}
+	}
+	return blocking
+}
+
+func (m *Manager) otherUsers() []string {
+	var sessions []struct {
+		ID   string
+		UID  uint32
+		User string
+		Seat string
+		Path dbus.ObjectPath
+	}
+	if err := m.managerObj.Call(dbusManagerInterface+".ListSessions", 0).Store(&sessions); err != nil {
+		return nil
+	}
+
+	uid := uint32(os.Getuid())
+	var users []string
+	for _, session := range sessions {
+		if session.UID != uid && !slices.Contains(users, session.User) {
+			users = append(users, session.User)
+		}
+	}
+	return users
+}
+
+func (m *Manager) ScheduleShutdown(kind string, at time.Time) error {
+	if !slices.Contains(shutdownTypes, kind) {
+		return fmt.Errorf("invalid shutdown type: %s", kind)
+	}
+	if !at.After(time.Now()) {
+		return fmt.Errorf("shutdown time must be in the future")
+	}
+	if m.managerObj == nil {
+		return fmt.Errorf("manager object not available")
+	}
+
+	if err := m.managerObj.Call(dbusManagerInterface+".ScheduleShutdown", 0, kind, uint64(at.UnixMicro())).Err; err != nil {
+		return fmt.Errorf("failed to schedule shutdown: %w", err)
+	}
+	m.refreshScheduledShutdown()
+	return nil
+}
+
+func (m *Manager) CancelScheduledShutdown() (bool, error) {
+	if m.managerObj == nil {
+		return false, fmt.Errorf("manager object not available")
+	}
+
+	var cancelled bool
+	if err := m.managerObj.Call(dbusManagerInterface+".CancelScheduledShutdown", 0).Store(&cancelled); err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled shutdown: %w", err)
+	}
+	m.refreshScheduledShutdown()
+	return cancelled, nil
+}
+
+func (m *Manager) refreshScheduledShutdown() {
+	if m.managerObj == nil {
+		return
+	}
+	prop, err := m.managerObj.GetProperty(dbusManagerInterface + ".ScheduledShutdown")
+	if err != nil {
+		return
+	}
+
+	var scheduled *ScheduledShutdown
+	if fields, ok := prop.Value().([]interface{}); ok && len(fields) == 2 {
+		kind, _ := fields[0].(string)
+		usec, _ := fields[1].(uint64)
+		if kind != "" && usec > 0 {
+			scheduled = &ScheduledShutdown{Type: kind, At: time.UnixMicro(int64(usec)).Unix()}
+		}
+	}
+
+	m.stateMutex.Lock()
+	m.state.ScheduledShutdown = scheduled
+	m.stateMutex.Unlock()
+
+	m.notifySubscribers()
+}
@@ -0,0 +1,122 @@
+package loginctl
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	mockdbus "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Power(t *testing.T) {
+	t.Run("not supported", func(t *testing.T) {
+		mockManagerObj := mockdbus.NewMockBusObject(t)
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".CanHibernate", dbus.Flags(0)).Return(&dbus.Call{Body: []interface{}{"na"}})
+
+		manager := &Manager{state: &SessionState{}, managerObj: mockManagerObj}
+		err := manager.Power(PowerHibernate, false)
+
+		var perr *PowerError
+		require.ErrorAs(t, err, &perr)
+		assert.Equal(t, PowerErrorNotSupported, perr.Reason)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockManagerObj := mockdbus.NewMockBusObject(t)
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".CanSuspend", dbus.Flags(0)).Return(&dbus.Call{Body: []interface{}{"yes"}})
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".Suspend", dbus.Flags(0), false).Return(&dbus.Call{})
+
+		manager := &Manager{state: &SessionState{}, managerObj: mockManagerObj}
+		assert.NoError(t, manager.Power(PowerSuspend, false))
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		manager := &Manager{state: &SessionState{}}
+		assert.Error(t, manager.Power("explode", false))
+	})
+}
+
+func TestManager_ClassifyPowerError(t *testing.T) {
+	authErr := dbus.Error{
+		Name: "org.freedesktop.DBus.Error.InteractiveAuthorizationRequired",
+		Body: []interface{}{"Interactive authentication required."},
+	}
+
+	t.Run("blocked by inhibitor", func(t *testing.T) {
+		mockManagerObj := mockdbus.NewMockBusObject(t)
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListInhibitors", dbus.Flags(0)).Return(listInhibitorsCall(
+			[]interface{}{"shutdown:sleep", "packagekit", "Updating", "block", uint32(0), uint32(99)},
+			[]interface{}{"sleep", "DankMaterialShell", "Lock before suspend", "delay", uint32(1000), uint32(42)},
+		))
+
+		manager := &Manager{state: &SessionState{}, managerObj: mockManagerObj}
+		perr := manager.classifyPowerError(PowerOff, powerActions[PowerOff], authErr)
+
+		assert.Equal(t, PowerErrorInhibited, perr.Reason)
+		assert.Equal(t, "Interactive authentication required.", perr.Message)
+		require.Len(t, perr.Inhibitors, 1)
+		assert.Equal(t, "packagekit", perr.Inhibitors[0].Who)
+	})
+
+	t.Run("other users logged in", func(t *testing.T) {
+		mockManagerObj := mockdbus.NewMockBusObject(t)
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListInhibitors", dbus.Flags(0)).Return(listInhibitorsCall())
+		mockManagerObj.EXPECT().Call(dbusManagerInterface+".ListSessions", dbus.Flags(0)).Return(&dbus.Call{Body: []interface{}{[][]interface{}{
+			{"1", uint32(os.Getuid()), "me", "seat0", dbus.ObjectPath("/org/freedesktop/login1/session/_31")},
+			{"2", uint32(os.Getuid() + 1), "guest", "", dbus.ObjectPath("/org/freedesktop/login1/session/_32")},
+		}}})
+
+		manager := &Manager{state: &SessionState{}, managerObj: mockManagerObj}
+		perr := manager.classifyPowerError(PowerReboot, powerActions[PowerReboot], authErr)
+
+		assert.Equal(t, PowerErrorUsersLoggedIn, perr.Reason)
+		assert.Equal(t, []string{"guest"}, perr.Users)
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		manager := &Manager{state: &SessionState{}}
+		perr := manager.classifyPowerError(PowerSuspend, powerActions[PowerSuspend], dbus.Error{
+			Name: "org.freedesktop.login1.OperationInProgress",
+			Body: []interface{}{"There's already a shutdown or sleep operation in progress"},
+		})
+		assert.Equal(t, PowerErrorInProgress, perr.Reason)
+		assert.Equal(t, "org.freedesktop.login1.OperationInProgress", perr.DBusError)
+	})
+}
+
+func TestManager_ScheduleShutdown(t *testing.T) {
+	manager := &Manager{state: &SessionState{}}
+	assert.Error(t, manager.ScheduleShutdown("explode", time.Now().Add(time.Hour)))
+	assert.Error(t, manager.ScheduleShutdown("poweroff", time.Now().Add(-time.Hour)))
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockManagerObj := mockdbus.NewMockBusObject(t)
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".ScheduleShutdown", dbus.Flags(0), "reboot", uint64(at.UnixMicro())).Return(&dbus.Call{})
+	mockManagerObj.EXPECT().GetProperty(dbusManagerInterface+".ScheduledShutdown").Return(dbus.MakeVariant([]interface{}{"reboot", uint64(at.UnixMicro())}), nil)
+
+	manager.managerObj = mockManagerObj
+	require.NoError(t, manager.ScheduleShutdown("reboot", at))
+
+	scheduled := manager.GetState().ScheduledShutdown
+	require.NotNil(t, scheduled)
+	assert.Equal(t, ScheduledShutdown{Type: "reboot", At: at.Unix()}, *scheduled)
+}
+
+func TestManager_CancelScheduledShutdown(t *testing.T) {
+	mockManagerObj := mockdbus.NewMockBusObject(t)
+	mockManagerObj.EXPECT().Call(dbusManagerInterface+".CancelScheduledShutdown", dbus.Flags(0)).Return(&dbus.Call{Body: []interface{}{true}})
+	mockManagerObj.EXPECT().GetProperty(dbusManagerInterface+".ScheduledShutdown").Return(dbus.MakeVariant([]interface{}{"", uint64(0)}), nil)
+
+	manager := &Manager{
+		state:      &SessionState{ScheduledShutdown: &ScheduledShutdown{Type: "poweroff", At: 1}},
+		managerObj: mockManagerObj,
+	}
+
+	cancelled, err := manager.CancelScheduledShutdown()
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+	assert.Nil(t, manager.GetState().ScheduledShutdown)
+}
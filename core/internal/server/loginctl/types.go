@@ -29,6 +29,30 @@ type SessionState struct {
 	Seat              string `json:"seat"`
 	VTNr              uint32 `json:"vtnr"`
 	PreparingForSleep bool   `json:"preparingForSleep"`
+
+	Inhibitors        []Inhibitor        `json:"inhibitors"`
+	HeldInhibitors    []HeldInhibitor    `json:"heldInhibitors"`
+	ScheduledShutdown *ScheduledShutdown `json:"scheduledShutdown,omitempty"`
+}
+
+// Inhibitor is a lock as reported by logind's ListInhibitors.
+type Inhibitor struct {
+	What string `json:"what"`
+	Who  string `json:"who"`
+	Why  string `json:"why"`
+	Mode string `json:"mode"`
+	UID  uint32 `json:"uid"`
+	PID  uint32 `json:"pid"`
+}
+
+// HeldInhibitor is a lock taken through Inhibit and owned by this manager.
+type HeldInhibitor struct {
+	Handle    string `json:"handle"`
+	What      string `json:"what"`
+	Why       string `json:"why"`
+	Mode      string `json:"mode"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	WatchPID  int    `json:"watchPid,omitempty"`
 }
 
 type EventType string
@@ -73,4 +97,7 @@ type Manager struct {
 	lockTimer             *time.Timer
 	sleepInhibitorEnabled atomic.Bool
 	fallbackDelay         time.Duration
+	heldMu                sync.Mutex
+	held                  map[string]*heldInhibitor
+	nextHandle            uint64
 }
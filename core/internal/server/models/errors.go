@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ParamError reports a request rejected because of its params. It is
+// answered with ErrCodeInvalidParams; any other handler error is a server
+// error.
+type ParamError struct {
+	msg string
+}
+
+func (e *ParamError) Error() string {
+	return e.msg
+}
+
+func NewParamError(format string, args ...interface{}) error {
+	return &ParamError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ErrorCode maps an error returned by a handler to a JSON-RPC error code.
+func ErrorCode(err error) int {
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		return ErrCodeInvalidParams
+	}
+	return ErrCodeServer
+}
+
+// RespondErr sends err with the code its type maps to.
+func RespondErr(conn net.Conn, id interface{}, err error) {
+	RespondErrorCode(conn, id, ErrorCode(err), err.Error())
+}
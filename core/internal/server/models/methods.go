@@ -0,0 +1,102 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+type ParamType string
+
+const (
+	ParamString ParamType = "string"
+	ParamNumber ParamType = "number"
+	ParamBool   ParamType = "boolean"
+	ParamObject ParamType = "object"
+	ParamArray  ParamType = "array"
+	ParamAny    ParamType = "any"
+)
+
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Required    bool      `json:"required"`
+	Enum        []string  `json:"enum,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// MethodSpec describes a socket method. Packages export their specs so the
+// server can route, validate and document calls from a single source.
+type MethodSpec struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Params      []ParamSpec `json:"params"`
+	Streaming   bool        `json:"streaming,omitempty"`
+	Notes       []string    `json:"notes,omitempty"`
+}
+
+// Validate checks params against the spec. Unknown params are ignored and
+// null values count as absent, so older clients keep working.
+func (m MethodSpec) Validate(params map[string]interface{}) error {
+	for _, p := range m.Params {
+		value, ok := params[p.Name]
+		if !ok || value == nil {
+			if p.Required {
+				return NewParamError("missing or invalid '%s' parameter", p.Name)
+			}
+			continue
+		}
+
+		if !p.Type.matches(value) {
+			if p.Required {
+				return NewParamError("missing or invalid '%s' parameter", p.Name)
+			}
+			return NewParamError("invalid '%s' parameter: expected %s", p.Name, p.Type)
+		}
+
+		if len(p.Enum) > 0 {
+			if s, _ := value.(string); !slices.Contains(p.Enum, s) {
+				return NewParamError("invalid '%s' parameter: must be one of %s", p.Name, strings.Join(p.Enum, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// ParamSummary renders the params the way the startup docs list them,
+// e.g. "device, step?".
+func (m MethodSpec) ParamSummary() string {
+	parts := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		part := p.Name
+		if !p.Required {
+			part += "?"
+		}
+		if len(p.Enum) > 0 {
+			part += " [" + strings.Join(p.Enum, "|") + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (t ParamType) matches(value interface{}) bool {
+	switch t {
+	case ParamString:
+		_, ok := value.(string)
+		return ok
+	case ParamNumber:
+		_, ok := value.(float64)
+		return ok
+	case ParamBool:
+		_, ok := value.(bool)
+		return ok
+	case ParamObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case ParamArray:
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
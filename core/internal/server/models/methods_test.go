@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodSpecValidate(t *testing.T) {
+	spec := MethodSpec{
+		Name: "test.method",
+		Params: []ParamSpec{
+			{Name: "device", Type: ParamString, Required: true},
+			{Name: "step", Type: ParamNumber},
+			{Name: "mode", Type: ParamString, Enum: []string{"auto", "manual"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"device": "a", "step": 5.0}, ""},
+		{"extra params ignored", map[string]interface{}{"device": "a", "other": true}, ""},
+		{"null optional", map[string]interface{}{"device": "a", "step": nil}, ""},
+		{"missing required", map[string]interface{}{}, "missing or invalid 'device' parameter"},
+		{"wrong required type", map[string]interface{}{"device": 1.0}, "missing or invalid 'device' parameter"},
+		{"wrong optional type", map[string]interface{}{"device": "a", "step": "5"}, "invalid 'step' parameter: expected number"},
+		{"enum mismatch", map[string]interface{}{"device": "a", "mode": "off"}, "invalid 'mode' parameter: must be one of auto, manual"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := spec.Validate(tt.params)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMethodSpecParamSummary(t *testing.T) {
+	spec := MethodSpec{
+		Params: []ParamSpec{
+			{Name: "device", Type: ParamString, Required: true},
+			{Name: "step", Type: ParamNumber},
+			{Name: "preference", Type: ParamString, Required: true, Enum: []string{"auto", "wifi"}},
+		},
+	}
+	assert.Equal(t, "device, step?, preference [auto|wifi]", spec.ParamSummary())
+}
+
+func TestErrorCode(t *testing.T) {
+	spec := MethodSpec{Params: []ParamSpec{{Name: "device", Type: ParamString, Required: true}}}
+
+	assert.Equal(t, ErrCodeInvalidParams, ErrorCode(spec.Validate(nil)))
+	assert.Equal(t, ErrCodeInvalidParams, ErrorCode(fmt.Errorf("ddc: %w", NewParamError("invalid feature parameter"))))
+	assert.Equal(t, ErrCodeServer, ErrorCode(errors.New("missing or invalid device")))
+}
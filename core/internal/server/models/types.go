@@ -1,6 +1,7 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"net"
 
@@ -8,24 +9,83 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
 
+// UnmarshalJSON decodes numeric ids as json.Number so they are echoed back
+// exactly; float64 would round ids above 2^53.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	type request Request
+	var aux struct {
+		request
+		ID json.RawMessage `json:"id,omitempty"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	id, err := DecodeID(aux.ID)
+	if err != nil {
+		return err
+	}
+	*r = Request(aux.request)
+	r.ID = id
+	return nil
+}
+
+// DecodeID turns a raw request id into a string, json.Number or nil.
+func DecodeID(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var id interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
 type Response[T any] struct {
-	ID     int    `json:"id,omitempty"`
-	Result *T     `json:"result,omitempty"`
-	Error  string `json:"error,omitempty"`
+	ID     interface{} `json:"id,omitempty"`
+	Result *T          `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Code   int         `json:"code,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// JSON-RPC 2.0 error codes. Legacy clients see them as the optional "code"
+// field next to "error".
+const (
+	ErrCodeParse          = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+	ErrCodeServer         = -32000
+)
+
+func RespondError(conn net.Conn, id interface{}, errMsg string) {
+	RespondErrorCode(conn, id, 0, errMsg)
+}
+
+func RespondErrorCode(conn net.Conn, id interface{}, code int, errMsg string) {
+	RespondErrorData(conn, id, code, errMsg, nil)
 }
 
-func RespondError(conn net.Conn, id int, errMsg string) {
-	log.Errorf("DMS API Error: id=%d error=%s", id, errMsg)
-	resp := Response[any]{ID: id, Error: errMsg}
+// RespondErrorData sends an error with machine-readable details, which
+// JSON-RPC clients receive as the error's "data" member.
+func RespondErrorData(conn net.Conn, id interface{}, code int, errMsg string, data interface{}) {
+	log.Errorf("DMS API Error: id=%v error=%s", id, errMsg)
+	resp := Response[any]{ID: id, Error: errMsg, Code: code, Data: data}
 	json.NewEncoder(conn).Encode(resp)
 }
 
-func Respond[T any](conn net.Conn, id int, result T) {
+func Respond[T any](conn net.Conn, id interface{}, result T) {
 	resp := Response[T]{ID: id, Result: &result}
 	json.NewEncoder(conn).Encode(resp)
 }
@@ -27,6 +27,16 @@ type Backend interface {
 	DisconnectVPN(uuidOrName string) error
 	DisconnectAllVPN() error
 	ClearVPNCredentials(uuidOrName string) error
+	ImportVPN(imp *VPNImport) (*VPNProfile, error)
+
+	GetConnectionSettings(uuid string) (*ConnectionSettings, error)
+	UpdateConnectionSettings(uuid string, settings ConnectionSettings) error
+
+	StartHotspot(ssid, password, band string, channel uint32) error
+	StopHotspot() error
+	GetHotspotState() (*HotspotState, error)
+
+	CheckConnectivity() (Connectivity, error)
 
 	GetCurrentState() (*BackendState, error)
 
@@ -61,5 +71,7 @@ type BackendState struct {
 	ConnectingSSID         string
 	IsConnectingVPN        bool
 	ConnectingVPNUUID      string
+	Hotspot                HotspotState
+	Connectivity           Connectivity
 	LastError              string
 }
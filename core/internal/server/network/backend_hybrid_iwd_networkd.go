@@ -84,6 +84,8 @@ func (b *HybridIwdNetworkdBackend) GetCurrentState() (*BackendState, error) {
 	merged.EthernetDevice = ls.EthernetDevice
 	merged.EthernetConnectionUuid = ls.EthernetConnectionUuid
 	merged.WiredConnections = ls.WiredConnections
+	merged.VPNProfiles = ls.VPNProfiles
+	merged.VPNActive = ls.VPNActive
 
 	if ls.EthernetConnected && ls.EthernetIP != "" {
 		merged.NetworkStatus = StatusEthernet
@@ -154,27 +156,55 @@ func (b *HybridIwdNetworkdBackend) ActivateWiredConnection(uuid string) error {
 }
 
 func (b *HybridIwdNetworkdBackend) ListVPNProfiles() ([]VPNProfile, error) {
-	return []VPNProfile{}, nil
+	return b.l3.ListVPNProfiles()
 }
 
 func (b *HybridIwdNetworkdBackend) ListActiveVPN() ([]VPNActive, error) {
-	return []VPNActive{}, nil
+	return b.l3.ListActiveVPN()
 }
 
 func (b *HybridIwdNetworkdBackend) ConnectVPN(uuidOrName string, singleActive bool) error {
-	return fmt.Errorf("VPN not supported in hybrid mode")
+	return b.l3.ConnectVPN(uuidOrName, singleActive)
 }
 
 func (b *HybridIwdNetworkdBackend) DisconnectVPN(uuidOrName string) error {
-	return fmt.Errorf("VPN not supported in hybrid mode")
+	return b.l3.DisconnectVPN(uuidOrName)
 }
 
 func (b *HybridIwdNetworkdBackend) DisconnectAllVPN() error {
-	return fmt.Errorf("VPN not supported in hybrid mode")
+	return b.l3.DisconnectAllVPN()
 }
 
 func (b *HybridIwdNetworkdBackend) ClearVPNCredentials(uuidOrName string) error {
-	return fmt.Errorf("VPN not supported in hybrid mode")
+	return b.l3.ClearVPNCredentials(uuidOrName)
+}
+
+func (b *HybridIwdNetworkdBackend) GetConnectionSettings(uuid string) (*ConnectionSettings, error) {
+	return b.l3.GetConnectionSettings(uuid)
+}
+
+func (b *HybridIwdNetworkdBackend) UpdateConnectionSettings(uuid string, settings ConnectionSettings) error {
+	return b.l3.UpdateConnectionSettings(uuid, settings)
+}
+
+func (b *HybridIwdNetworkdBackend) CheckConnectivity() (Connectivity, error) {
+	return b.l3.CheckConnectivity()
+}
+
+func (b *HybridIwdNetworkdBackend) ImportVPN(imp *VPNImport) (*VPNProfile, error) {
+	return b.l3.ImportVPN(imp)
+}
+
+func (b *HybridIwdNetworkdBackend) StartHotspot(ssid, password, band string, channel uint32) error {
+	return b.wifi.StartHotspot(ssid, password, band, channel)
+}
+
+func (b *HybridIwdNetworkdBackend) StopHotspot() error {
+	return b.wifi.StopHotspot()
+}
+
+func (b *HybridIwdNetworkdBackend) GetHotspotState() (*HotspotState, error) {
+	return b.wifi.GetHotspotState()
 }
 
 func (b *HybridIwdNetworkdBackend) GetPromptBroker() PromptBroker {
@@ -107,9 +107,10 @@ func TestHybridIwdNetworkdBackend_WiredDelegation(t *testing.T) {
 	assert.Empty(t, conns)
 }
 
-func TestHybridIwdNetworkdBackend_VPNNotSupported(t *testing.T) {
+func TestHybridIwdNetworkdBackend_VPNDelegation(t *testing.T) {
 	wifi, _ := NewIWDBackend()
 	l3, _ := NewSystemdNetworkdBackend()
+	l3.unitDir = t.TempDir()
 	hybrid, _ := NewHybridIwdNetworkdBackend(wifi, l3)
 
 	profiles, err := hybrid.ListVPNProfiles()
@@ -122,7 +123,7 @@ func TestHybridIwdNetworkdBackend_VPNNotSupported(t *testing.T) {
 
 	err = hybrid.ConnectVPN("test", false)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not supported")
+	assert.Contains(t, err.Error(), "not found")
 }
 
 func TestHybridIwdNetworkdBackend_PromptBrokerDelegation(t *testing.T) {
@@ -131,6 +131,12 @@ func (b *IWDBackend) discoverDevices() error {
 		}
 	}
 
+	if b.stationPath == "" && b.devicePath != "" {
+		if _, inAPMode := objects[b.devicePath][iwdAccessPointInterface]; inAPMode {
+			b.stationPath = b.devicePath
+		}
+	}
+
 	if b.stationPath == "" || b.devicePath == "" {
 		return fmt.Errorf("no WiFi device found")
 	}
@@ -0,0 +1,129 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const iwdAccessPointInterface = "net.connman.iwd.AccessPoint"
+
+func (b *IWDBackend) setDeviceMode(mode string) error {
+	obj := b.conn.Object(iwdBusName, b.devicePath)
+	call := obj.Call(dbusPropertiesInterface+".Set", 0, iwdDeviceInterface, "Mode", dbus.MakeVariant(mode))
+	return call.Err
+}
+
+func (b *IWDBackend) StartHotspot(ssid, password, band string, channel uint32) error {
+	cfg, err := newHotspotConfig(ssid, password, band, channel)
+	if err != nil {
+		return err
+	}
+	if cfg.Password == "" {
+		return fmt.Errorf("open hotspots not supported by iwd, a password is required")
+	}
+	if cfg.Band != "" || cfg.Channel != 0 {
+		return fmt.Errorf("band and channel selection not supported by iwd")
+	}
+	if b.devicePath == "" {
+		return fmt.Errorf("no WiFi device available")
+	}
+
+	if err := b.setDeviceMode("ap"); err != nil {
+		return fmt.Errorf("failed to switch device to AP mode: %w", err)
+	}
+
+	obj := b.conn.Object(iwdBusName, b.devicePath)
+	if call := obj.Call(iwdAccessPointInterface+".Start", 0, cfg.SSID, cfg.Password); call.Err != nil {
+		b.setDeviceMode("station")
+		return fmt.Errorf("failed to start hotspot: %w", call.Err)
+	}
+
+	b.refreshHotspotState()
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+	return nil
+}
+
+func (b *IWDBackend) StopHotspot() error {
+	if b.devicePath == "" {
+		return fmt.Errorf("no WiFi device available")
+	}
+
+	b.stateMutex.RLock()
+	active := b.state.Hotspot.Active
+	b.stateMutex.RUnlock()
+	if !active {
+		return fmt.Errorf("hotspot not active")
+	}
+
+	obj := b.conn.Object(iwdBusName, b.devicePath)
+	if call := obj.Call(iwdAccessPointInterface+".Stop", 0); call.Err != nil {
+		return fmt.Errorf("failed to stop hotspot: %w", call.Err)
+	}
+	if err := b.setDeviceMode("station"); err != nil {
+		return fmt.Errorf("failed to switch device to station mode: %w", err)
+	}
+
+	b.refreshHotspotState()
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+	return nil
+}
+
+func (b *IWDBackend) GetHotspotState() (*HotspotState, error) {
+	b.stateMutex.RLock()
+	defer b.stateMutex.RUnlock()
+	hotspot := b.state.Hotspot
+	return &hotspot, nil
+}
+
+// refreshHotspotState reads the device mode and, in AP mode, the
+// AccessPoint properties, reporting whether a hotspot is running and whether
+// anything changed.
+func (b *IWDBackend) refreshHotspotState() (active, changed bool) {
+	hotspot := HotspotState{Supported: true}
+
+	obj := b.conn.Object(iwdBusName, b.devicePath)
+	var mode string
+	if v, err := obj.GetProperty(iwdDeviceInterface + ".Mode"); err == nil {
+		v.Store(&mode)
+	}
+
+	if mode == "ap" {
+		var started bool
+		if v, err := obj.GetProperty(iwdAccessPointInterface + ".Started"); err == nil {
+			v.Store(&started)
+		}
+		if started {
+			hotspot.Active = true
+			if v, err := obj.GetProperty(iwdAccessPointInterface + ".Name"); err == nil {
+				v.Store(&hotspot.SSID)
+			}
+			if v, err := obj.GetProperty(iwdAccessPointInterface + ".Frequency"); err == nil {
+				var freq uint32
+				if v.Store(&freq) == nil {
+					hotspot.Channel = frequencyToChannel(freq)
+					hotspot.Band = frequencyBand(freq)
+				}
+			}
+		}
+	}
+
+	b.stateMutex.Lock()
+	defer b.stateMutex.Unlock()
+
+	if hotspot.Active {
+		hotspot.Device = b.state.WiFiDevice
+		b.state.WiFiConnected = false
+		b.state.WiFiSSID = ""
+		b.state.WiFiSignal = 0
+	}
+	if b.state.Hotspot == hotspot {
+		return hotspot.Active, false
+	}
+	b.state.Hotspot = hotspot
+	return hotspot.Active, true
+}
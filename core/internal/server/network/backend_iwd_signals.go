@@ -87,8 +87,20 @@ func (b *IWDBackend) signalHandler(sigChan chan *dbus.Signal) {
 			stateChanged := false
 
 			switch iface {
+			case iwdAccessPointInterface:
+				if sig.Path == b.devicePath {
+					if _, hotspotChanged := b.refreshHotspotState(); hotspotChanged {
+						stateChanged = true
+					}
+				}
+
 			case iwdDeviceInterface:
 				if sig.Path == b.devicePath {
+					if _, ok := changed["Mode"]; ok {
+						if _, hotspotChanged := b.refreshHotspotState(); hotspotChanged {
+							stateChanged = true
+						}
+					}
 					if poweredVar, ok := changed["Powered"]; ok {
 						if powered, ok := poweredVar.Value().(bool); ok {
 							b.stateMutex.Lock()
@@ -45,3 +45,19 @@ func (b *IWDBackend) DisconnectAllVPN() error {
 func (b *IWDBackend) ClearVPNCredentials(uuidOrName string) error {
 	return fmt.Errorf("VPN not supported by iwd backend")
 }
+
+func (b *IWDBackend) ImportVPN(imp *VPNImport) (*VPNProfile, error) {
+	return nil, fmt.Errorf("VPN not supported by iwd backend")
+}
+
+func (b *IWDBackend) GetConnectionSettings(uuid string) (*ConnectionSettings, error) {
+	return nil, fmt.Errorf("connection settings not supported by iwd backend")
+}
+
+func (b *IWDBackend) UpdateConnectionSettings(uuid string, settings ConnectionSettings) error {
+	return fmt.Errorf("connection settings not supported by iwd backend")
+}
+
+func (b *IWDBackend) CheckConnectivity() (Connectivity, error) {
+	return ConnectivityUnknown, fmt.Errorf("connectivity check not supported by iwd backend")
+}
@@ -24,6 +24,10 @@ func (b *IWDBackend) updateState() error {
 		}
 	}
 
+	if active, _ := b.refreshHotspotState(); active {
+		return nil
+	}
+
 	if b.stationPath == "" {
 		return nil
 	}
@@ -35,6 +35,8 @@ type SystemdNetworkdBackend struct {
 	stopChan      chan struct{}
 	signals       chan *dbus.Signal
 	sigWG         sync.WaitGroup
+	unitDir       string
+	runCommand    func(name string, args ...string) error
 }
 
 func NewSystemdNetworkdBackend() (*SystemdNetworkdBackend, error) {
@@ -45,7 +47,9 @@ func NewSystemdNetworkdBackend() (*SystemdNetworkdBackend, error) {
 			Backend:      "networkd",
 			WiFiNetworks: []WiFiNetwork{},
 		},
-		stopChan: make(chan struct{}),
+		stopChan:   make(chan struct{}),
+		unitDir:    networkdUnitDir,
+		runCommand: runPrivileged,
 	}, nil
 }
 
@@ -107,6 +111,8 @@ func (b *SystemdNetworkdBackend) enumerateLinks() error {
 }
 
 func (b *SystemdNetworkdBackend) updateState() error {
+	vpnProfiles, vpnActive := b.wireGuardState()
+
 	b.linksMutex.RLock()
 	defer b.linksMutex.RUnlock()
 
@@ -114,7 +120,7 @@ func (b *SystemdNetworkdBackend) updateState() error {
 	var wifiIface *linkInfo
 
 	for name, link := range b.links {
-		if b.isVirtualInterface(name) {
+		if isVirtualInterface(name) {
 			continue
 		}
 
@@ -130,7 +136,7 @@ func (b *SystemdNetworkdBackend) updateState() error {
 			if wifiIface == nil || link.opState == "routable" || link.opState == "carrier" {
 				wifiIface = link
 			}
-		} else if !b.isVirtualInterface(name) {
+		} else if !isVirtualInterface(name) {
 			if wiredIface == nil || link.opState == "routable" || link.opState == "carrier" {
 				wiredIface = link
 			}
@@ -139,7 +145,7 @@ func (b *SystemdNetworkdBackend) updateState() error {
 
 	var wiredConns []WiredConnection
 	for name, link := range b.links {
-		if b.isVirtualInterface(name) || strings.HasPrefix(name, "wlan") || strings.HasPrefix(name, "wlp") {
+		if isVirtualInterface(name) || strings.HasPrefix(name, "wlan") || strings.HasPrefix(name, "wlp") {
 			continue
 		}
 
@@ -162,6 +168,8 @@ func (b *SystemdNetworkdBackend) updateState() error {
 	b.state.WiFiConnected = false
 	b.state.WiFiIP = ""
 	b.state.WiredConnections = wiredConns
+	b.state.VPNProfiles = vpnProfiles
+	b.state.VPNActive = vpnActive
 
 	if wiredIface != nil {
 		b.state.EthernetDevice = wiredIface.name
@@ -196,10 +204,10 @@ func (b *SystemdNetworkdBackend) updateState() error {
 	return nil
 }
 
-func (b *SystemdNetworkdBackend) isVirtualInterface(name string) bool {
+func isVirtualInterface(name string) bool {
 	virtualPrefixes := []string{
 		"lo", "docker", "veth", "virbr", "br-", "vnet", "tun", "tap",
-		"vboxnet", "vmnet", "kube", "cni", "flannel", "cali",
+		"vboxnet", "vmnet", "kube", "cni", "flannel", "cali", "wg",
 	}
 	for _, prefix := range virtualPrefixes {
 		if strings.HasPrefix(name, prefix) {
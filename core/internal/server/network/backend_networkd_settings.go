@@ -0,0 +1,449 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+type unitSection struct {
+	name    string
+	entries [][2]string
+}
+
+func parseUnit(content string) []unitSection {
+	var sections []unitSection
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, unitSection{name: line[1 : len(line)-1]})
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || len(sections) == 0 {
+			continue
+		}
+		cur := &sections[len(sections)-1]
+		cur.entries = append(cur.entries, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+	}
+	return sections
+}
+
+func renderUnit(sections []unitSection) string {
+	var sb strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "[%s]\n", section.name)
+		for _, kv := range section.entries {
+			fmt.Fprintf(&sb, "%s=%s\n", kv[0], kv[1])
+		}
+	}
+	return sb.String()
+}
+
+// networkdManagedKeys lists the keys rewritten from ConnectionSettings; any
+// other setting found in the original file is carried over.
+var networkdManagedKeys = map[string]map[string]bool{
+	"Link":         {"MTUBytes": true, "MACAddress": true},
+	"Network":      {"DHCP": true, "LinkLocalAddressing": true, "IPv6AcceptRA": true, "Address": true, "Gateway": true, "DNS": true, "Domains": true},
+	"DHCPv4":       {"UseDNS": true, "RouteMetric": true},
+	"IPv6AcceptRA": {"UseDNS": true, "RouteMetric": true},
+}
+
+func networkdIfaceName(uuid string) string {
+	if _, iface, ok := strings.Cut(uuid, ":"); ok {
+		return iface
+	}
+	return uuid
+}
+
+func matchesNetworkUnit(sections []unitSection, iface string) bool {
+	matched := false
+	for _, section := range sections {
+		if section.name != "Match" {
+			continue
+		}
+		for _, kv := range section.entries {
+			if kv[0] != "Name" {
+				continue
+			}
+			for _, pattern := range strings.Fields(kv[1]) {
+				if ok, _ := filepath.Match(pattern, iface); ok {
+					matched = true
+				}
+			}
+		}
+	}
+	return matched
+}
+
+func (b *SystemdNetworkdBackend) networkSettingsPath(iface string) string {
+	return filepath.Join(b.unitDir, "10-dms-"+iface+".network")
+}
+
+// findNetworkUnit returns the .network file networkd applies to iface: the
+// first match in lexical order, which is ours once settings were edited.
+func (b *SystemdNetworkdBackend) findNetworkUnit(iface string) (string, []unitSection) {
+	paths, _ := filepath.Glob(filepath.Join(b.unitDir, "*.network"))
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sections := parseUnit(string(data))
+		if matchesNetworkUnit(sections, iface) {
+			return path, sections
+		}
+	}
+	return "", nil
+}
+
+func unitValues(sections []unitSection, section, key string) []string {
+	var values []string
+	for _, s := range sections {
+		if s.name != section {
+			continue
+		}
+		for _, kv := range s.entries {
+			if kv[0] != key {
+				continue
+			}
+			if kv[1] == "" {
+				values = nil
+				continue
+			}
+			values = append(values, kv[1])
+		}
+	}
+	return values
+}
+
+func unitValue(sections []unitSection, section, key, fallback string) string {
+	values := unitValues(sections, section, key)
+	if len(values) == 0 {
+		return fallback
+	}
+	return values[len(values)-1]
+}
+
+func unitBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "yes", "true", "on", "1":
+		return true
+	}
+	return false
+}
+
+func networkdReadSettings(sections []unitSection) (IPSettings, IPSettings, uint32) {
+	ipv4 := IPSettings{Addresses: []string{}, DNS: []string{}, DNSSearch: []string{}, RouteMetric: -1}
+	ipv6 := IPSettings{Addresses: []string{}, DNS: []string{}, DNSSearch: []string{}, RouteMetric: -1}
+	family := func(value string) *IPSettings {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			ip, _, _ = net.ParseCIDR(value)
+		}
+		if ip == nil {
+			return nil
+		}
+		if ip.To4() != nil {
+			return &ipv4
+		}
+		return &ipv6
+	}
+
+	for _, addr := range unitValues(sections, "Network", "Address") {
+		if s := family(addr); s != nil {
+			s.Addresses = append(s.Addresses, addr)
+		}
+	}
+	for _, gw := range unitValues(sections, "Network", "Gateway") {
+		if s := family(gw); s != nil {
+			s.Gateway = gw
+		}
+	}
+	for _, section := range sections {
+		if section.name != "Route" || unitValue([]unitSection{section}, "Route", "Destination", "") != "" {
+			continue
+		}
+		gw := unitValue([]unitSection{section}, "Route", "Gateway", "")
+		if s := family(gw); s != nil {
+			s.Gateway = gw
+			if metric, err := strconv.ParseInt(unitValue([]unitSection{section}, "Route", "Metric", ""), 10, 64); err == nil {
+				s.RouteMetric = metric
+			}
+		}
+	}
+	for _, line := range unitValues(sections, "Network", "DNS") {
+		for _, dns := range strings.Fields(line) {
+			if s := family(dns); s != nil {
+				s.DNS = append(s.DNS, dns)
+			}
+		}
+	}
+
+	dhcp := strings.ToLower(unitValue(sections, "Network", "DHCP", "no"))
+	lla := strings.ToLower(unitValue(sections, "Network", "LinkLocalAddressing", "ipv6"))
+	acceptRA := unitValue(sections, "Network", "IPv6AcceptRA", "yes")
+
+	switch {
+	case len(ipv4.Addresses) > 0:
+		ipv4.Method = IPMethodManual
+	case dhcp == "ipv4" || unitBool(dhcp) || dhcp == "both":
+		ipv4.Method = IPMethodAuto
+	case lla == "ipv4" || unitBool(lla):
+		ipv4.Method = IPMethodLinkLocal
+	default:
+		ipv4.Method = IPMethodDisabled
+	}
+
+	switch {
+	case len(ipv6.Addresses) > 0:
+		ipv6.Method = IPMethodManual
+	case lla == "no" || lla == "ipv4" || lla == "false":
+		ipv6.Method = IPMethodDisabled
+	case unitBool(acceptRA) || dhcp == "ipv6" || unitBool(dhcp):
+		ipv6.Method = IPMethodAuto
+	default:
+		ipv6.Method = IPMethodLinkLocal
+	}
+
+	var domains []string
+	for _, line := range unitValues(sections, "Network", "Domains") {
+		domains = append(domains, strings.Fields(line)...)
+	}
+	if ipv4.Method != IPMethodDisabled {
+		ipv4.DNSSearch = append(ipv4.DNSSearch, domains...)
+	} else {
+		ipv6.DNSSearch = append(ipv6.DNSSearch, domains...)
+	}
+
+	ipv4.IgnoreAutoDNS = !unitBool(unitValue(sections, "DHCPv4", "UseDNS", "yes"))
+	ipv6.IgnoreAutoDNS = !unitBool(unitValue(sections, "IPv6AcceptRA", "UseDNS", "yes"))
+	if ipv4.Method == IPMethodAuto {
+		if metric, err := strconv.ParseInt(unitValue(sections, "DHCPv4", "RouteMetric", ""), 10, 64); err == nil {
+			ipv4.RouteMetric = metric
+		}
+	}
+	if ipv6.Method == IPMethodAuto {
+		if metric, err := strconv.ParseInt(unitValue(sections, "IPv6AcceptRA", "RouteMetric", ""), 10, 64); err == nil {
+			ipv6.RouteMetric = metric
+		}
+	}
+
+	var mtu uint32
+	if v, err := strconv.ParseUint(unitValue(sections, "Link", "MTUBytes", ""), 10, 32); err == nil {
+		mtu = uint32(v)
+	}
+
+	return ipv4, ipv6, mtu
+}
+
+// renderNetworkdSettings builds a .network unit for iface from cs, keeping
+// unmanaged settings from the unit it replaces.
+func renderNetworkdSettings(iface string, cs ConnectionSettings, original []unitSection) (string, error) {
+	switch cs.MACRandomization {
+	case "", MACDefault, MACPermanent, MACPreserve:
+	default:
+		return "", fmt.Errorf("MAC randomization not supported by networkd backend")
+	}
+	if cs.Proxy.Method == ProxyAuto {
+		return "", fmt.Errorf("proxy settings not supported by networkd backend")
+	}
+
+	link := unitSection{name: "Link"}
+	if cs.MTU != 0 {
+		link.entries = append(link.entries, [2]string{"MTUBytes", strconv.FormatUint(uint64(cs.MTU), 10)})
+	}
+
+	dhcp := "no"
+	if cs.IPv4.Method == IPMethodAuto {
+		dhcp = "ipv4"
+	}
+	v4LL := cs.IPv4.Method == IPMethodLinkLocal
+	v6LL := cs.IPv6.Method != IPMethodDisabled
+	lla := "no"
+	switch {
+	case v4LL && v6LL:
+		lla = "yes"
+	case v4LL:
+		lla = "ipv4"
+	case v6LL:
+		lla = "ipv6"
+	}
+	acceptRA := "no"
+	if cs.IPv6.Method == IPMethodAuto {
+		acceptRA = "yes"
+	}
+
+	network := unitSection{name: "Network", entries: [][2]string{
+		{"DHCP", dhcp},
+		{"LinkLocalAddressing", lla},
+		{"IPv6AcceptRA", acceptRA},
+	}}
+	var routes []unitSection
+	dhcp4 := unitSection{name: "DHCPv4"}
+	ra := unitSection{name: "IPv6AcceptRA"}
+
+	var domains []string
+	seen := make(map[string]bool)
+	for _, ip := range []IPSettings{cs.IPv4, cs.IPv6} {
+		for _, addr := range ip.Addresses {
+			network.entries = append(network.entries, [2]string{"Address", addr})
+		}
+		if ip.Gateway != "" {
+			if ip.RouteMetric >= 0 {
+				routes = append(routes, unitSection{name: "Route", entries: [][2]string{
+					{"Gateway", ip.Gateway},
+					{"Metric", strconv.FormatInt(ip.RouteMetric, 10)},
+				}})
+			} else {
+				network.entries = append(network.entries, [2]string{"Gateway", ip.Gateway})
+			}
+		}
+		for _, dns := range ip.DNS {
+			network.entries = append(network.entries, [2]string{"DNS", dns})
+		}
+		for _, domain := range ip.DNSSearch {
+			if !seen[domain] {
+				seen[domain] = true
+				domains = append(domains, domain)
+			}
+		}
+	}
+	if len(domains) > 0 {
+		network.entries = append(network.entries, [2]string{"Domains", strings.Join(domains, " ")})
+	}
+
+	if cs.IPv4.Method == IPMethodAuto {
+		if cs.IPv4.IgnoreAutoDNS {
+			dhcp4.entries = append(dhcp4.entries, [2]string{"UseDNS", "no"})
+		}
+		if cs.IPv4.RouteMetric >= 0 {
+			dhcp4.entries = append(dhcp4.entries, [2]string{"RouteMetric", strconv.FormatInt(cs.IPv4.RouteMetric, 10)})
+		}
+	}
+	if cs.IPv6.Method == IPMethodAuto {
+		if cs.IPv6.IgnoreAutoDNS {
+			ra.entries = append(ra.entries, [2]string{"UseDNS", "no"})
+		}
+		if cs.IPv6.RouteMetric >= 0 {
+			ra.entries = append(ra.entries, [2]string{"RouteMetric", strconv.FormatInt(cs.IPv6.RouteMetric, 10)})
+		}
+	}
+
+	managed := map[string]*unitSection{"Link": &link, "Network": &network, "DHCPv4": &dhcp4, "IPv6AcceptRA": &ra}
+	var extra []unitSection
+	for _, section := range original {
+		switch section.name {
+		case "Match":
+			continue
+		case "Route":
+			if unitValue([]unitSection{section}, "Route", "Destination", "") == "" {
+				continue
+			}
+		}
+		target, ok := managed[section.name]
+		if !ok {
+			extra = append(extra, section)
+			continue
+		}
+		for _, kv := range section.entries {
+			if !networkdManagedKeys[section.name][kv[0]] {
+				target.entries = append(target.entries, kv)
+			}
+		}
+	}
+
+	sections := []unitSection{{name: "Match", entries: [][2]string{{"Name", iface}}}}
+	for _, section := range []unitSection{link, network} {
+		if len(section.entries) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	sections = append(sections, routes...)
+	for _, section := range []unitSection{dhcp4, ra} {
+		if len(section.entries) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	sections = append(sections, extra...)
+
+	return renderUnit(sections), nil
+}
+
+func (b *SystemdNetworkdBackend) GetConnectionSettings(uuid string) (*ConnectionSettings, error) {
+	iface := networkdIfaceName(uuid)
+
+	b.linksMutex.RLock()
+	_, exists := b.links[iface]
+	b.linksMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("interface %s not found", iface)
+	}
+
+	_, sections := b.findNetworkUnit(iface)
+	ipv4, ipv6, mtu := networkdReadSettings(sections)
+
+	connType := "ethernet"
+	if strings.HasPrefix(iface, "wlan") || strings.HasPrefix(iface, "wlp") {
+		connType = "wifi"
+	}
+
+	return &ConnectionSettings{
+		UUID:             uuid,
+		ID:               iface,
+		Type:             connType,
+		Interface:        iface,
+		IPv4:             ipv4,
+		IPv6:             ipv6,
+		MTU:              mtu,
+		MACRandomization: MACDefault,
+		Proxy:            ProxySettings{Method: ProxyNone},
+	}, nil
+}
+
+func (b *SystemdNetworkdBackend) UpdateConnectionSettings(uuid string, cs ConnectionSettings) error {
+	iface := networkdIfaceName(uuid)
+
+	b.linksMutex.RLock()
+	_, exists := b.links[iface]
+	b.linksMutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("interface %s not found", iface)
+	}
+
+	_, original := b.findNetworkUnit(iface)
+	content, err := renderNetworkdSettings(iface, cs, original)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(b.networkSettingsPath(iface))
+	if err := b.installUnit(name, content); err != nil {
+		return fmt.Errorf("failed to install %s: %w", name, err)
+	}
+	if err := b.runCommand("networkctl", "reload"); err != nil {
+		return fmt.Errorf("networkctl reload: %w", err)
+	}
+	if err := b.runCommand("networkctl", "reconfigure", iface); err != nil {
+		log.Warnf("[UpdateConnectionSettings] networkctl reconfigure %s failed: %v", iface, err)
+	}
+
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package network
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSystemdNetworkdBackend_New(t *testing.T) {
@@ -54,32 +58,107 @@ func TestSystemdNetworkdBackend_WiFiNotSupported(t *testing.T) {
 	assert.Contains(t, err.Error(), "not supported")
 }
 
-func TestSystemdNetworkdBackend_VPNNotSupported(t *testing.T) {
+func TestSystemdNetworkdBackend_WireGuard(t *testing.T) {
 	backend, _ := NewSystemdNetworkdBackend()
+	backend.unitDir = t.TempDir()
+
+	var commands []string
+	backend.runCommand = func(name string, args ...string) error {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		if name == "install" {
+			data, err := os.ReadFile(args[len(args)-2])
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(args[len(args)-1], data, 0640)
+		}
+		return nil
+	}
 
 	profiles, err := backend.ListVPNProfiles()
 	assert.NoError(t, err)
 	assert.Empty(t, profiles)
 
+	cfg, err := parseWireGuardConfig(testWireGuardConf)
+	require.NoError(t, err)
+
+	_, err = backend.ImportVPN(&VPNImport{Name: "home", OpenVPN: &OpenVPNConfig{}})
+	assert.Error(t, err)
+
+	profile, err := backend.ImportVPN(&VPNImport{Name: "Office VPN", WireGuard: cfg})
+	require.NoError(t, err)
+	assert.Equal(t, "wireguard:Office-VPN", profile.UUID)
+	assert.FileExists(t, filepath.Join(backend.unitDir, "50-dms-Office-VPN.netdev"))
+	assert.FileExists(t, filepath.Join(backend.unitDir, "50-dms-Office-VPN.network"))
+	assert.Contains(t, commands, "networkctl reload")
+
+	_, err = backend.ImportVPN(&VPNImport{Name: "Office VPN", WireGuard: cfg})
+	assert.Error(t, err, "duplicate import")
+
+	profiles, err = backend.ListVPNProfiles()
+	assert.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "Office VPN", profiles[0].Name)
+
 	active, err := backend.ListActiveVPN()
 	assert.NoError(t, err)
 	assert.Empty(t, active)
 
-	err = backend.ConnectVPN("test", false)
+	commands = nil
+	assert.NoError(t, backend.ConnectVPN("Office VPN", false))
+	assert.NoError(t, backend.DisconnectVPN("wireguard:Office-VPN"))
+	assert.Equal(t, []string{"networkctl up Office-VPN", "networkctl down Office-VPN"}, commands)
+
+	assert.Error(t, backend.ConnectVPN("missing", false))
+
+	err = backend.ClearVPNCredentials("Office VPN")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not supported")
+}
+
+func TestNetworkdWireGuardUnits(t *testing.T) {
+	cfg, err := parseWireGuardConfig(testWireGuardConf)
+	require.NoError(t, err)
+
+	netdev, network, err := networkdWireGuardUnits("office", "office", cfg)
+	require.NoError(t, err)
+	assert.Contains(t, netdev, "Kind=wireguard")
+	assert.Contains(t, netdev, "Description=office")
+	assert.Contains(t, netdev, "PrivateKey="+testWGPrivateKey)
+	assert.Contains(t, netdev, "FirewallMark=0xca6c")
+	assert.Contains(t, netdev, "RouteTable=51820")
+	assert.Contains(t, netdev, "AllowedIPs=0.0.0.0/0,::/0")
+	assert.Contains(t, netdev, "PersistentKeepalive=25")
+	assert.Contains(t, network, "ActivationPolicy=manual")
+	assert.Contains(t, network, "Address=10.8.0.2/24")
+	assert.Contains(t, network, "Domains=~. corp.example")
+	assert.Contains(t, network, "InvertRule=yes")
+	assert.Contains(t, network, "SuppressPrefixLength=0")
+
+	cfg.Peers[0].AllowedIPs = []string{"10.8.0.0/24"}
+	netdev, network, err = networkdWireGuardUnits("office", "office", cfg)
+	require.NoError(t, err)
+	assert.Contains(t, netdev, "RouteTable=main")
+	assert.NotContains(t, netdev, "FirewallMark")
+	assert.NotContains(t, network, "RoutingPolicyRule")
+	assert.Contains(t, network, "Domains=corp.example")
+}
 
-	err = backend.DisconnectVPN("test")
+func TestNetworkdWireGuardUnitsRejectsControlCharacters(t *testing.T) {
+	cfg, err := parseWireGuardConfig(testWireGuardConf)
+	require.NoError(t, err)
+
+	_, _, err = networkdWireGuardUnits("office\n[Network]\nAddress=0.0.0.0/0", "office", cfg)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not supported")
 
-	err = backend.DisconnectAllVPN()
+	cfg.Peers[0].Endpoint = "vpn.example.com:51820\nPublicKey=attacker"
+	_, _, err = networkdWireGuardUnits("office", "office", cfg)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not supported")
 
-	err = backend.ClearVPNCredentials("test")
+	cfg.Peers[0].Endpoint = "vpn.example.com:51820"
+	cfg.DNS = append(cfg.DNS, "1.1.1.1\r")
+	_, _, err = networkdWireGuardUnits("office", "office", cfg)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not supported")
 }
 
 func TestSystemdNetworkdBackend_PromptBroker(t *testing.T) {
@@ -123,3 +202,65 @@ func TestSystemdNetworkdBackend_DisconnectEthernet(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not supported")
 }
+
+func TestSystemdNetworkdBackend_ConnectionSettings(t *testing.T) {
+	backend, _ := NewSystemdNetworkdBackend()
+	backend.unitDir = t.TempDir()
+	backend.links["enp3s0"] = &linkInfo{name: "enp3s0"}
+	backend.runCommand = func(name string, args ...string) error {
+		if name == "install" {
+			data, err := os.ReadFile(args[len(args)-2])
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(args[len(args)-1], data, 0640)
+		}
+		return nil
+	}
+
+	original := "[Match]\nName=en*\n\n[Network]\nDHCP=yes\nIPv6PrivacyExtensions=yes\n\n[DHCPv4]\nRouteMetric=50\n\n[Route]\nDestination=10.0.0.0/8\nGateway=192.168.1.254\n"
+	require.NoError(t, os.WriteFile(filepath.Join(backend.unitDir, "20-wired.network"), []byte(original), 0644))
+
+	cs, err := backend.GetConnectionSettings("wired:enp3s0")
+	require.NoError(t, err)
+	assert.Equal(t, "ethernet", cs.Type)
+	assert.Equal(t, IPMethodAuto, cs.IPv4.Method)
+	assert.Equal(t, int64(50), cs.IPv4.RouteMetric)
+	assert.Equal(t, IPMethodAuto, cs.IPv6.Method)
+
+	_, err = backend.GetConnectionSettings("wired:eth9")
+	assert.Error(t, err)
+
+	update := validConnectionSettings()
+	update.Proxy = ProxySettings{Method: ProxyNone}
+	require.NoError(t, backend.UpdateConnectionSettings("wired:enp3s0", update))
+
+	data, err := os.ReadFile(filepath.Join(backend.unitDir, "10-dms-enp3s0.network"))
+	require.NoError(t, err)
+	unit := string(data)
+	assert.Contains(t, unit, "Name=enp3s0")
+	assert.Contains(t, unit, "DHCP=no")
+	assert.Contains(t, unit, "Address=192.168.1.50/24")
+	assert.Contains(t, unit, "[Route]\nGateway=192.168.1.1\nMetric=100")
+	assert.Contains(t, unit, "Destination=10.0.0.0/8")
+	assert.Contains(t, unit, "IPv6PrivacyExtensions=yes")
+	assert.Contains(t, unit, "MTUBytes=1500")
+	assert.NotContains(t, unit, "RouteMetric=50")
+
+	cs, err = backend.GetConnectionSettings("wired:enp3s0")
+	require.NoError(t, err)
+	assert.Equal(t, IPMethodManual, cs.IPv4.Method)
+	assert.Equal(t, []string{"192.168.1.50/24"}, cs.IPv4.Addresses)
+	assert.Equal(t, "192.168.1.1", cs.IPv4.Gateway)
+	assert.Equal(t, int64(100), cs.IPv4.RouteMetric)
+	assert.Equal(t, []string{"1.1.1.1"}, cs.IPv4.DNS)
+	assert.Equal(t, []string{"home.arpa"}, cs.IPv4.DNSSearch)
+	assert.Equal(t, IPMethodAuto, cs.IPv6.Method)
+	assert.Equal(t, uint32(1500), cs.MTU)
+
+	update.MACRandomization = MACRandom
+	assert.Error(t, backend.UpdateConnectionSettings("wired:enp3s0", update))
+	update.MACRandomization = MACDefault
+	update.Proxy = ProxySettings{Method: ProxyAuto}
+	assert.Error(t, backend.UpdateConnectionSettings("wired:enp3s0", update))
+}
@@ -30,30 +30,22 @@ func (b *SystemdNetworkdBackend) ForgetWiFiNetwork(ssid string) error {
 	return fmt.Errorf("WiFi forget not supported by networkd backend")
 }
 
-func (b *SystemdNetworkdBackend) ListVPNProfiles() ([]VPNProfile, error) {
-	return []VPNProfile{}, nil
-}
-
-func (b *SystemdNetworkdBackend) ListActiveVPN() ([]VPNActive, error) {
-	return []VPNActive{}, nil
-}
-
-func (b *SystemdNetworkdBackend) ConnectVPN(uuidOrName string, singleActive bool) error {
-	return fmt.Errorf("VPN not supported by networkd backend")
+func (b *SystemdNetworkdBackend) SetWiFiAutoconnect(ssid string, autoconnect bool) error {
+	return fmt.Errorf("WiFi autoconnect not supported by networkd backend")
 }
 
-func (b *SystemdNetworkdBackend) DisconnectVPN(uuidOrName string) error {
-	return fmt.Errorf("VPN not supported by networkd backend")
+func (b *SystemdNetworkdBackend) StartHotspot(ssid, password, band string, channel uint32) error {
+	return fmt.Errorf("hotspot not supported by networkd backend")
 }
 
-func (b *SystemdNetworkdBackend) DisconnectAllVPN() error {
-	return fmt.Errorf("VPN not supported by networkd backend")
+func (b *SystemdNetworkdBackend) StopHotspot() error {
+	return fmt.Errorf("hotspot not supported by networkd backend")
 }
 
-func (b *SystemdNetworkdBackend) ClearVPNCredentials(uuidOrName string) error {
-	return fmt.Errorf("VPN not supported by networkd backend")
+func (b *SystemdNetworkdBackend) GetHotspotState() (*HotspotState, error) {
+	return &HotspotState{}, nil
 }
 
-func (b *SystemdNetworkdBackend) SetWiFiAutoconnect(ssid string, autoconnect bool) error {
-	return fmt.Errorf("WiFi autoconnect not supported by networkd backend")
+func (b *SystemdNetworkdBackend) CheckConnectivity() (Connectivity, error) {
+	return ConnectivityUnknown, fmt.Errorf("connectivity check not supported by networkd backend")
 }
@@ -0,0 +1,429 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const (
+	networkdUnitDir        = "/etc/systemd/network"
+	networkdWireGuardMark  = 0xca6c
+	networkdWireGuardTable = 51820
+)
+
+// runPrivileged runs a command as root, going through polkit when the
+// daemon itself is unprivileged.
+func runPrivileged(name string, args ...string) error {
+	if os.Geteuid() != 0 {
+		args = append([]string{name}, args...)
+		name = "pkexec"
+	}
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+type wireGuardUnit struct {
+	name  string
+	iface string
+}
+
+// checkUnitValues rejects values that would break out of their unit file
+// line. The units are installed as root, so a newline in an imported profile
+// must not be able to add directives.
+func checkUnitValues(field string, values ...string) error {
+	for _, value := range values {
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return fmt.Errorf("invalid %s %q: contains control characters", field, value)
+		}
+	}
+	return nil
+}
+
+func checkWireGuardUnitValues(name, iface string, cfg *WireGuardConfig) error {
+	checks := []error{
+		checkUnitValues("name", name),
+		checkUnitValues("interface", iface),
+		checkUnitValues("private key", cfg.PrivateKey),
+		checkUnitValues("address", cfg.Addresses...),
+		checkUnitValues("DNS server", cfg.DNS...),
+		checkUnitValues("domain", cfg.Domains...),
+	}
+	for _, peer := range cfg.Peers {
+		checks = append(checks,
+			checkUnitValues("peer public key", peer.PublicKey),
+			checkUnitValues("peer preshared key", peer.PresharedKey),
+			checkUnitValues("peer endpoint", peer.Endpoint),
+			checkUnitValues("peer allowed IP", peer.AllowedIPs...),
+		)
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// networkdWireGuardUnits renders the .netdev and .network units for cfg. Full
+// tunnels follow wg-quick: peer routes go to a separate table and a policy
+// rule sends everything not marked by WireGuard itself there.
+func networkdWireGuardUnits(name, iface string, cfg *WireGuardConfig) (string, string, error) {
+	if err := checkWireGuardUnitValues(name, iface, cfg); err != nil {
+		return "", "", err
+	}
+
+	fullTunnel := cfg.FullTunnel()
+	fwmark := cfg.FwMark
+	if fullTunnel && fwmark == 0 {
+		fwmark = networkdWireGuardMark
+	}
+
+	var netdev strings.Builder
+	fmt.Fprintf(&netdev, "[NetDev]\nName=%s\nKind=wireguard\nDescription=%s\n", iface, name)
+	if cfg.MTU != 0 {
+		fmt.Fprintf(&netdev, "MTUBytes=%d\n", cfg.MTU)
+	}
+	fmt.Fprintf(&netdev, "\n[WireGuard]\nPrivateKey=%s\n", cfg.PrivateKey)
+	if cfg.ListenPort != 0 {
+		fmt.Fprintf(&netdev, "ListenPort=%d\n", cfg.ListenPort)
+	}
+	if fwmark != 0 {
+		fmt.Fprintf(&netdev, "FirewallMark=0x%x\n", fwmark)
+	}
+	if fullTunnel {
+		fmt.Fprintf(&netdev, "RouteTable=%d\n", networkdWireGuardTable)
+	} else {
+		netdev.WriteString("RouteTable=main\n")
+	}
+	for _, peer := range cfg.Peers {
+		fmt.Fprintf(&netdev, "\n[WireGuardPeer]\nPublicKey=%s\n", peer.PublicKey)
+		if peer.PresharedKey != "" {
+			fmt.Fprintf(&netdev, "PresharedKey=%s\n", peer.PresharedKey)
+		}
+		if peer.Endpoint != "" {
+			fmt.Fprintf(&netdev, "Endpoint=%s\n", peer.Endpoint)
+		}
+		if len(peer.AllowedIPs) > 0 {
+			fmt.Fprintf(&netdev, "AllowedIPs=%s\n", strings.Join(peer.AllowedIPs, ","))
+		}
+		if peer.PersistentKeepalive != 0 {
+			fmt.Fprintf(&netdev, "PersistentKeepalive=%d\n", peer.PersistentKeepalive)
+		}
+	}
+
+	var network strings.Builder
+	fmt.Fprintf(&network, "[Match]\nName=%s\n\n[Link]\nActivationPolicy=manual\nRequiredForOnline=no\n\n[Network]\n", iface)
+	for _, addr := range cfg.Addresses {
+		fmt.Fprintf(&network, "Address=%s\n", addr)
+	}
+	for _, dns := range cfg.DNS {
+		fmt.Fprintf(&network, "DNS=%s\n", dns)
+	}
+	domains := cfg.Domains
+	if fullTunnel && len(cfg.DNS) > 0 {
+		domains = append([]string{"~."}, domains...)
+	}
+	if len(domains) > 0 {
+		fmt.Fprintf(&network, "Domains=%s\n", strings.Join(domains, " "))
+	}
+	if fullTunnel {
+		fmt.Fprintf(&network, "\n[RoutingPolicyRule]\nTable=%d\nFirewallMark=0x%x\nInvertRule=yes\nFamily=both\nPriority=32765\n", networkdWireGuardTable, fwmark)
+		network.WriteString("\n[RoutingPolicyRule]\nTable=main\nSuppressPrefixLength=0\nFamily=both\nPriority=32764\n")
+	}
+
+	return netdev.String(), network.String(), nil
+}
+
+// readWireGuardUnit returns the interface and description of a .netdev file
+// if it declares a WireGuard device.
+func readWireGuardUnit(path string) (wireGuardUnit, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return wireGuardUnit{}, false
+	}
+	defer f.Close()
+
+	var unit wireGuardUnit
+	var kind, section string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if section != "[NetDev]" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Name":
+			unit.iface = strings.TrimSpace(value)
+		case "Kind":
+			kind = strings.TrimSpace(value)
+		case "Description":
+			unit.name = strings.TrimSpace(value)
+		}
+	}
+
+	if kind != "wireguard" || unit.iface == "" {
+		return wireGuardUnit{}, false
+	}
+	if unit.name == "" {
+		unit.name = unit.iface
+	}
+	return unit, true
+}
+
+func (b *SystemdNetworkdBackend) wireGuardUnits() []wireGuardUnit {
+	paths, _ := filepath.Glob(filepath.Join(b.unitDir, "*.netdev"))
+
+	var units []wireGuardUnit
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		unit, ok := readWireGuardUnit(path)
+		if !ok || seen[unit.iface] {
+			continue
+		}
+		seen[unit.iface] = true
+		units = append(units, unit)
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return strings.ToLower(units[i].name) < strings.ToLower(units[j].name)
+	})
+	return units
+}
+
+func (b *SystemdNetworkdBackend) linkOperationalState(name string) string {
+	b.linksMutex.RLock()
+	link, ok := b.links[name]
+	b.linksMutex.RUnlock()
+	if !ok {
+		return ""
+	}
+	if b.conn == nil {
+		return link.opState
+	}
+
+	opStateVar, err := b.conn.Object(networkdBusName, link.path).GetProperty(networkdLinkIface + ".OperationalState")
+	if err != nil {
+		return link.opState
+	}
+	opState, _ := opStateVar.Value().(string)
+	return opState
+}
+
+func wireGuardLinkActive(opState string) bool {
+	switch opState {
+	case "", "off", "missing":
+		return false
+	}
+	return true
+}
+
+func (b *SystemdNetworkdBackend) wireGuardState() ([]VPNProfile, []VPNActive) {
+	profiles := []VPNProfile{}
+	active := []VPNActive{}
+	for _, unit := range b.wireGuardUnits() {
+		uuid := "wireguard:" + unit.iface
+		profiles = append(profiles, VPNProfile{Name: unit.name, UUID: uuid, Type: VPNTypeWireGuard})
+
+		opState := b.linkOperationalState(unit.iface)
+		if !wireGuardLinkActive(opState) {
+			continue
+		}
+		active = append(active, VPNActive{
+			Name:   unit.name,
+			UUID:   uuid,
+			Device: unit.iface,
+			State:  "activated",
+			Type:   VPNTypeWireGuard,
+		})
+	}
+	return profiles, active
+}
+
+func (b *SystemdNetworkdBackend) refreshVPNState() ([]VPNProfile, []VPNActive) {
+	profiles, active := b.wireGuardState()
+	b.stateMutex.Lock()
+	b.state.VPNProfiles = profiles
+	b.state.VPNActive = active
+	b.stateMutex.Unlock()
+	return profiles, active
+}
+
+func (b *SystemdNetworkdBackend) findWireGuardUnit(uuidOrName string) (wireGuardUnit, error) {
+	for _, unit := range b.wireGuardUnits() {
+		if "wireguard:"+unit.iface == uuidOrName || unit.name == uuidOrName || unit.iface == uuidOrName {
+			return unit, nil
+		}
+	}
+	return wireGuardUnit{}, fmt.Errorf("VPN profile not found: %s", uuidOrName)
+}
+
+func (b *SystemdNetworkdBackend) setWireGuardLink(iface string, up bool) error {
+	action := "down"
+	if up {
+		action = "up"
+	}
+	if err := b.runCommand("networkctl", action, iface); err != nil {
+		return fmt.Errorf("networkctl %s %s: %w", action, iface, err)
+	}
+	return nil
+}
+
+func (b *SystemdNetworkdBackend) notifyVPNChange() {
+	if b.conn != nil {
+		b.enumerateLinks()
+	}
+	b.refreshVPNState()
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+}
+
+func (b *SystemdNetworkdBackend) ListVPNProfiles() ([]VPNProfile, error) {
+	profiles, _ := b.refreshVPNState()
+	return profiles, nil
+}
+
+func (b *SystemdNetworkdBackend) ListActiveVPN() ([]VPNActive, error) {
+	_, active := b.refreshVPNState()
+	return active, nil
+}
+
+func (b *SystemdNetworkdBackend) ConnectVPN(uuidOrName string, singleActive bool) error {
+	unit, err := b.findWireGuardUnit(uuidOrName)
+	if err != nil {
+		return err
+	}
+
+	if singleActive {
+		_, active := b.refreshVPNState()
+		for _, vpn := range active {
+			if vpn.Device == unit.iface {
+				continue
+			}
+			if err := b.setWireGuardLink(vpn.Device, false); err != nil {
+				log.Warnf("[ConnectVPN] Failed to disconnect %s: %v", vpn.Name, err)
+			}
+		}
+	}
+
+	if err := b.setWireGuardLink(unit.iface, true); err != nil {
+		return err
+	}
+	b.notifyVPNChange()
+	return nil
+}
+
+func (b *SystemdNetworkdBackend) DisconnectVPN(uuidOrName string) error {
+	unit, err := b.findWireGuardUnit(uuidOrName)
+	if err != nil {
+		return err
+	}
+	if err := b.setWireGuardLink(unit.iface, false); err != nil {
+		return err
+	}
+	b.notifyVPNChange()
+	return nil
+}
+
+func (b *SystemdNetworkdBackend) DisconnectAllVPN() error {
+	_, active := b.refreshVPNState()
+
+	var lastErr error
+	for _, vpn := range active {
+		if err := b.setWireGuardLink(vpn.Device, false); err != nil {
+			lastErr = err
+		}
+	}
+	b.notifyVPNChange()
+	return lastErr
+}
+
+func (b *SystemdNetworkdBackend) ClearVPNCredentials(uuidOrName string) error {
+	return fmt.Errorf("VPN credentials not supported by networkd backend")
+}
+
+// installUnit writes content to a temporary file and installs it readable
+// only by root and systemd-network, since .netdev files hold private keys.
+func (b *SystemdNetworkdBackend) installUnit(name, content string) error {
+	tmp, err := os.CreateTemp("", "dms-"+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	dst := filepath.Join(b.unitDir, name)
+	return b.runCommand("install", "-D", "-m", "0640", "-g", "systemd-network", tmp.Name(), dst)
+}
+
+func (b *SystemdNetworkdBackend) ImportVPN(imp *VPNImport) (*VPNProfile, error) {
+	if imp.WireGuard == nil {
+		return nil, fmt.Errorf("only WireGuard profiles are supported by networkd backend")
+	}
+
+	iface := vpnInterfaceName(imp.Name)
+	for _, unit := range b.wireGuardUnits() {
+		if unit.iface == iface || unit.name == imp.Name {
+			return nil, fmt.Errorf("VPN profile %q already exists", imp.Name)
+		}
+	}
+	b.linksMutex.RLock()
+	_, exists := b.links[iface]
+	b.linksMutex.RUnlock()
+	if exists {
+		return nil, fmt.Errorf("interface %s already exists", iface)
+	}
+
+	netdev, network, err := networkdWireGuardUnits(imp.Name, iface, imp.WireGuard)
+	if err != nil {
+		return nil, err
+	}
+	base := "50-dms-" + iface
+	if err := b.installUnit(base+".netdev", netdev); err != nil {
+		return nil, fmt.Errorf("failed to install %s.netdev: %w", base, err)
+	}
+	if err := b.installUnit(base+".network", network); err != nil {
+		return nil, fmt.Errorf("failed to install %s.network: %w", base, err)
+	}
+	if err := b.runCommand("networkctl", "reload"); err != nil {
+		return nil, fmt.Errorf("networkctl reload: %w", err)
+	}
+
+	log.Infof("[ImportVPN] Installed networkd WireGuard units for %s", iface)
+	b.notifyVPNChange()
+
+	return &VPNProfile{
+		Name: imp.Name,
+		UUID: "wireguard:" + iface,
+		Type: VPNTypeWireGuard,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/Wifx/gonetworkmanager/v2"
+)
+
+func nmConnectivity(c gonetworkmanager.NmConnectivity) Connectivity {
+	switch c {
+	case gonetworkmanager.NmConnectivityNone:
+		return ConnectivityNone
+	case gonetworkmanager.NmConnectivityPortal:
+		return ConnectivityPortal
+	case gonetworkmanager.NmConnectivityLimited:
+		return ConnectivityLimited
+	case gonetworkmanager.NmConnectivityFull:
+		return ConnectivityFull
+	}
+	return ConnectivityUnknown
+}
+
+// CheckConnectivity asks NetworkManager to re-run its own check. It reports
+// unknown when checking is disabled in NetworkManager's configuration.
+func (b *NetworkManagerBackend) CheckConnectivity() (Connectivity, error) {
+	nm := b.nmConn.(gonetworkmanager.NetworkManager)
+
+	if err := nm.CheckConnectivity(); err != nil {
+		return ConnectivityUnknown, fmt.Errorf("connectivity check failed: %w", err)
+	}
+	value, err := nm.GetPropertyConnectivity()
+	if err != nil {
+		return ConnectivityUnknown, fmt.Errorf("failed to get connectivity: %w", err)
+	}
+
+	connectivity := nmConnectivity(value)
+	b.stateMutex.Lock()
+	b.state.Connectivity = connectivity
+	b.stateMutex.Unlock()
+	return connectivity, nil
+}
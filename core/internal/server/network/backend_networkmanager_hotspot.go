@@ -0,0 +1,194 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/Wifx/gonetworkmanager/v2"
+)
+
+const nmHotspotConnectionID = "Hotspot"
+
+func nmHotspotSettings(cfg hotspotConfig) map[string]map[string]interface{} {
+	wireless := map[string]interface{}{
+		"ssid": []byte(cfg.SSID),
+		"mode": "ap",
+	}
+	switch cfg.Band {
+	case HotspotBand24:
+		wireless["band"] = "bg"
+	case HotspotBand5:
+		wireless["band"] = "a"
+	}
+	if cfg.Channel != 0 {
+		wireless["channel"] = cfg.Channel
+	}
+
+	settings := map[string]map[string]interface{}{
+		"connection": {
+			"id":          nmHotspotConnectionID,
+			"type":        "802-11-wireless",
+			"autoconnect": false,
+		},
+		"802-11-wireless": wireless,
+		"ipv4":            {"method": "shared"},
+		"ipv6":            {"method": "ignore"},
+	}
+
+	if cfg.Password != "" {
+		wireless["security"] = "802-11-wireless-security"
+		settings["802-11-wireless-security"] = map[string]interface{}{
+			"key-mgmt": "wpa-psk",
+			"psk":      cfg.Password,
+			"proto":    []string{"rsn"},
+			"pairwise": []string{"ccmp"},
+			"group":    []string{"ccmp"},
+		}
+	}
+
+	return settings
+}
+
+// findHotspotConnection returns the saved AP-mode profile, preferring ours
+// over ones created elsewhere (e.g. nmcli device wifi hotspot).
+func (b *NetworkManagerBackend) findHotspotConnection() (gonetworkmanager.Connection, string, error) {
+	s := b.settings
+	if s == nil {
+		var err error
+		s, err = gonetworkmanager.NewSettings()
+		if err != nil {
+			return nil, "", err
+		}
+		b.settings = s
+	}
+
+	connections, err := s.(gonetworkmanager.Settings).ListConnections()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var found gonetworkmanager.Connection
+	var foundUUID string
+	for _, conn := range connections {
+		connSettings, err := conn.GetSettings()
+		if err != nil {
+			continue
+		}
+		wireless, ok := connSettings["802-11-wireless"]
+		if !ok {
+			continue
+		}
+		if mode, _ := wireless["mode"].(string); mode != "ap" {
+			continue
+		}
+
+		id, _ := connSettings["connection"]["id"].(string)
+		uuid, _ := connSettings["connection"]["uuid"].(string)
+		if found == nil || id == nmHotspotConnectionID {
+			found = conn
+			foundUUID = uuid
+		}
+		if id == nmHotspotConnectionID {
+			break
+		}
+	}
+
+	return found, foundUUID, nil
+}
+
+func (b *NetworkManagerBackend) StartHotspot(ssid, password, band string, channel uint32) error {
+	cfg, err := newHotspotConfig(ssid, password, band, channel)
+	if err != nil {
+		return err
+	}
+	if b.wifiDevice == nil {
+		return fmt.Errorf("no WiFi device available")
+	}
+
+	nm := b.nmConn.(gonetworkmanager.NetworkManager)
+	dev := b.wifiDevice.(gonetworkmanager.Device)
+	settings := nmHotspotSettings(cfg)
+
+	conn, uuid, err := b.findHotspotConnection()
+	if err != nil {
+		return fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	if conn != nil {
+		settings["connection"]["uuid"] = uuid
+		if err := conn.Update(settings); err != nil {
+			return fmt.Errorf("failed to update hotspot connection: %w", err)
+		}
+		if _, err := nm.ActivateConnection(conn, dev, nil); err != nil {
+			return fmt.Errorf("failed to activate hotspot: %w", err)
+		}
+	} else if _, err := nm.AddAndActivateConnection(settings, dev); err != nil {
+		return fmt.Errorf("failed to create hotspot: %w", err)
+	}
+
+	log.Infof("[StartHotspot] Hotspot %q activation initiated", cfg.SSID)
+	return nil
+}
+
+func (b *NetworkManagerBackend) StopHotspot() error {
+	if b.wifiDevice == nil {
+		return fmt.Errorf("no WiFi device available")
+	}
+
+	b.stateMutex.RLock()
+	active := b.state.Hotspot.Active
+	b.stateMutex.RUnlock()
+	if !active {
+		return fmt.Errorf("hotspot not active")
+	}
+
+	nm := b.nmConn.(gonetworkmanager.NetworkManager)
+	dev := b.wifiDevice.(gonetworkmanager.Device)
+
+	activeConn, err := dev.GetPropertyActiveConnection()
+	if err != nil || activeConn == nil {
+		return fmt.Errorf("failed to get active connection: %w", err)
+	}
+	if err := nm.DeactivateConnection(activeConn); err != nil {
+		return fmt.Errorf("failed to stop hotspot: %w", err)
+	}
+
+	b.updateWiFiState()
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+	return nil
+}
+
+func (b *NetworkManagerBackend) GetHotspotState() (*HotspotState, error) {
+	b.stateMutex.RLock()
+	defer b.stateMutex.RUnlock()
+	hotspot := b.state.Hotspot
+	return &hotspot, nil
+}
+
+// readHotspotState reports whether the wireless device is running an access
+// point and, if so, what it is broadcasting.
+func (b *NetworkManagerBackend) readHotspotState(iface string) HotspotState {
+	hotspot := HotspotState{Supported: true}
+	if b.wifiDev == nil {
+		return hotspot
+	}
+
+	w := b.wifiDev.(gonetworkmanager.DeviceWireless)
+	mode, err := w.GetPropertyMode()
+	if err != nil || mode != gonetworkmanager.Nm80211ModeAp {
+		return hotspot
+	}
+
+	hotspot.Active = true
+	hotspot.Device = iface
+	if ap, err := w.GetPropertyActiveAccessPoint(); err == nil && ap != nil && ap.GetPath() != "/" {
+		hotspot.SSID, _ = ap.GetPropertySSID()
+		if freq, err := ap.GetPropertyFrequency(); err == nil {
+			hotspot.Channel = frequencyToChannel(freq)
+			hotspot.Band = frequencyBand(freq)
+		}
+	}
+	return hotspot
+}
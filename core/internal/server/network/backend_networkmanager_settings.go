@@ -0,0 +1,298 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/Wifx/gonetworkmanager/v2"
+)
+
+func nmLinkSettingName(connType string) (string, string, error) {
+	switch connType {
+	case "802-3-ethernet":
+		return "802-3-ethernet", "ethernet", nil
+	case "802-11-wireless":
+		return "802-11-wireless", "wifi", nil
+	}
+	return "", "", fmt.Errorf("editing %s connections is not supported", connType)
+}
+
+func nmReadIPSettings(ip map[string]interface{}, v6 bool) IPSettings {
+	s := IPSettings{
+		Method:    IPMethodAuto,
+		Addresses: []string{},
+		DNS:       []string{},
+		DNSSearch: []string{},
+	}
+	if ip == nil {
+		return s
+	}
+
+	if method, ok := ip["method"].(string); ok {
+		switch method {
+		case "ignore":
+			s.Method = IPMethodDisabled
+		case "dhcp":
+			s.Method = IPMethodAuto
+		default:
+			s.Method = method
+		}
+	}
+
+	if data, ok := ip["address-data"].([]map[string]interface{}); ok {
+		for _, addr := range data {
+			address, _ := addr["address"].(string)
+			prefix, _ := addr["prefix"].(uint32)
+			if address != "" {
+				s.Addresses = append(s.Addresses, fmt.Sprintf("%s/%d", address, prefix))
+			}
+		}
+	}
+	s.Gateway, _ = ip["gateway"].(string)
+
+	if v6 {
+		if servers, ok := ip["dns"].([][]byte); ok {
+			for _, raw := range servers {
+				if len(raw) == net.IPv6len {
+					s.DNS = append(s.DNS, net.IP(raw).String())
+				}
+			}
+		}
+	} else if servers, ok := ip["dns"].([]uint32); ok {
+		for _, raw := range servers {
+			b := make(net.IP, net.IPv4len)
+			binary.LittleEndian.PutUint32(b, raw)
+			s.DNS = append(s.DNS, b.String())
+		}
+	}
+
+	if search, ok := ip["dns-search"].([]string); ok {
+		s.DNSSearch = append(s.DNSSearch, search...)
+	}
+	s.IgnoreAutoDNS, _ = ip["ignore-auto-dns"].(bool)
+
+	s.RouteMetric = -1
+	if metric, ok := ip["route-metric"].(int64); ok {
+		s.RouteMetric = metric
+	}
+
+	return s
+}
+
+func nmWriteIPSettings(ip map[string]interface{}, s IPSettings, v6 bool) {
+	for _, key := range []string{"addresses", "address-data", "gateway", "routes", "dns", "dns-search"} {
+		delete(ip, key)
+	}
+
+	ip["method"] = s.Method
+	if v6 && s.Method == IPMethodDisabled {
+		ip["method"] = "disabled"
+	}
+
+	if data := nmAddressData(s.Addresses, v6); len(data) > 0 {
+		ip["address-data"] = data
+	}
+	if s.Gateway != "" {
+		ip["gateway"] = s.Gateway
+	}
+
+	if v6 {
+		var servers [][]byte
+		for _, dns := range s.DNS {
+			servers = append(servers, []byte(net.ParseIP(dns).To16()))
+		}
+		if len(servers) > 0 {
+			ip["dns"] = servers
+		}
+	} else {
+		var servers []uint32
+		for _, dns := range s.DNS {
+			servers = append(servers, binary.LittleEndian.Uint32(net.ParseIP(dns).To4()))
+		}
+		if len(servers) > 0 {
+			ip["dns"] = servers
+		}
+	}
+
+	if len(s.DNSSearch) > 0 {
+		ip["dns-search"] = s.DNSSearch
+	}
+	ip["ignore-auto-dns"] = s.IgnoreAutoDNS
+	ip["route-metric"] = s.RouteMetric
+}
+
+func nmReadConnectionSettings(settings gonetworkmanager.ConnectionSettings) (*ConnectionSettings, error) {
+	connMeta := settings["connection"]
+	connType, _ := connMeta["type"].(string)
+	linkSetting, typeName, err := nmLinkSettingName(connType)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &ConnectionSettings{
+		Type:             typeName,
+		IPv4:             nmReadIPSettings(settings["ipv4"], false),
+		IPv6:             nmReadIPSettings(settings["ipv6"], true),
+		MACRandomization: MACDefault,
+		Proxy:            ProxySettings{Method: ProxyNone},
+	}
+	cs.UUID, _ = connMeta["uuid"].(string)
+	cs.ID, _ = connMeta["id"].(string)
+	cs.Interface, _ = connMeta["interface-name"].(string)
+
+	link := settings[linkSetting]
+	cs.MTU, _ = link["mtu"].(uint32)
+	if mac, ok := link["assigned-mac-address"].(string); ok && mac != "" {
+		cs.MACRandomization = mac
+	} else if mac, ok := link["cloned-mac-address"].(string); ok && mac != "" {
+		cs.MACRandomization = mac
+	}
+
+	if proxy, ok := settings["proxy"]; ok {
+		if method, _ := proxy["method"].(int32); method == 1 {
+			cs.Proxy.Method = ProxyAuto
+		}
+		cs.Proxy.PACURL, _ = proxy["pac-url"].(string)
+		cs.Proxy.BrowserOnly, _ = proxy["browser-only"].(bool)
+	}
+
+	return cs, nil
+}
+
+func nmApplyConnectionSettings(settings gonetworkmanager.ConnectionSettings, cs ConnectionSettings) error {
+	connType, _ := settings["connection"]["type"].(string)
+	linkSetting, _, err := nmLinkSettingName(connType)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"ipv4", "ipv6", linkSetting} {
+		if settings[name] == nil {
+			settings[name] = make(map[string]interface{})
+		}
+	}
+	nmWriteIPSettings(settings["ipv4"], cs.IPv4, false)
+	nmWriteIPSettings(settings["ipv6"], cs.IPv6, true)
+
+	link := settings[linkSetting]
+	link["mtu"] = cs.MTU
+	delete(link, "cloned-mac-address")
+	switch cs.MACRandomization {
+	case "", MACDefault:
+		delete(link, "assigned-mac-address")
+	default:
+		link["assigned-mac-address"] = cs.MACRandomization
+	}
+
+	proxy := map[string]interface{}{
+		"method":       int32(0),
+		"browser-only": cs.Proxy.BrowserOnly,
+	}
+	if cs.Proxy.Method == ProxyAuto {
+		proxy["method"] = int32(1)
+		if cs.Proxy.PACURL != "" {
+			proxy["pac-url"] = cs.Proxy.PACURL
+		}
+	}
+	settings["proxy"] = proxy
+
+	return nil
+}
+
+func (b *NetworkManagerBackend) findConnectionByUUIDOrSSID(uuidOrSSID string) (gonetworkmanager.Connection, error) {
+	s := b.settings
+	if s == nil {
+		var err error
+		s, err = gonetworkmanager.NewSettings()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get settings: %w", err)
+		}
+		b.settings = s
+	}
+
+	if conn, err := s.(gonetworkmanager.Settings).GetConnectionByUUID(uuidOrSSID); err == nil && conn != nil {
+		return conn, nil
+	}
+	conn, err := b.findConnection(uuidOrSSID)
+	if err != nil {
+		return nil, fmt.Errorf("connection %s not found", uuidOrSSID)
+	}
+	return conn, nil
+}
+
+func (b *NetworkManagerBackend) GetConnectionSettings(uuid string) (*ConnectionSettings, error) {
+	conn, err := b.findConnectionByUUIDOrSSID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := conn.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection settings: %w", err)
+	}
+	return nmReadConnectionSettings(settings)
+}
+
+func (b *NetworkManagerBackend) UpdateConnectionSettings(uuid string, cs ConnectionSettings) error {
+	conn, err := b.findConnectionByUUIDOrSSID(uuid)
+	if err != nil {
+		return err
+	}
+
+	settings, err := conn.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get connection settings: %w", err)
+	}
+	if err := nmApplyConnectionSettings(settings, cs); err != nil {
+		return err
+	}
+
+	// Update replaces the whole profile, so carry stored secrets over.
+	if _, ok := settings["802-11-wireless-security"]; ok {
+		if secrets, err := conn.GetSecrets("802-11-wireless-security"); err == nil {
+			for k, v := range secrets["802-11-wireless-security"] {
+				settings["802-11-wireless-security"][k] = v
+			}
+		}
+	}
+
+	if err := conn.Update(settings); err != nil {
+		return fmt.Errorf("failed to update connection: %w", err)
+	}
+
+	connUUID, _ := settings["connection"]["uuid"].(string)
+	b.reapplyConnection(conn, connUUID)
+
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+	return nil
+}
+
+// reapplyConnection pushes updated settings to devices the profile is active
+// on, so changes take effect without a reconnect.
+func (b *NetworkManagerBackend) reapplyConnection(conn gonetworkmanager.Connection, uuid string) {
+	nm := b.nmConn.(gonetworkmanager.NetworkManager)
+	activeConns, err := nm.GetPropertyActiveConnections()
+	if err != nil {
+		return
+	}
+
+	for _, activeConn := range activeConns {
+		activeUUID, err := activeConn.GetPropertyUUID()
+		if err != nil || activeUUID != uuid {
+			continue
+		}
+		devices, err := activeConn.GetPropertyDevices()
+		if err != nil {
+			continue
+		}
+		for _, dev := range devices {
+			if err := dev.Reapply(conn, 0, 0); err != nil {
+				log.Warnf("[UpdateConnectionSettings] Reapply failed, changes apply on reconnect: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/Wifx/gonetworkmanager/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNMConnectionSettings_RoundTrip(t *testing.T) {
+	settings := gonetworkmanager.ConnectionSettings{
+		"connection": {"id": "Home", "uuid": "uuid-1", "type": "802-11-wireless"},
+		"802-11-wireless": {
+			"ssid":               []byte("Home"),
+			"cloned-mac-address": "random",
+		},
+		"ipv4": {
+			"method":    "auto",
+			"addresses": [][]uint32{{1}},
+			"dns":       []uint32{0x01010101},
+		},
+		"ipv6": {"method": "ignore"},
+	}
+
+	cs, err := nmReadConnectionSettings(settings)
+	require.NoError(t, err)
+	assert.Equal(t, "wifi", cs.Type)
+	assert.Equal(t, IPMethodAuto, cs.IPv4.Method)
+	assert.Equal(t, []string{"1.1.1.1"}, cs.IPv4.DNS)
+	assert.Equal(t, int64(-1), cs.IPv4.RouteMetric)
+	assert.Equal(t, IPMethodDisabled, cs.IPv6.Method)
+	assert.Equal(t, MACRandom, cs.MACRandomization)
+	assert.Equal(t, ProxyNone, cs.Proxy.Method)
+
+	update := validConnectionSettings()
+	update.MACRandomization = MACStable
+	require.NoError(t, nmApplyConnectionSettings(settings, update))
+
+	ipv4 := settings["ipv4"]
+	assert.Equal(t, "manual", ipv4["method"])
+	assert.NotContains(t, ipv4, "addresses")
+	assert.Equal(t, "192.168.1.1", ipv4["gateway"])
+	assert.Equal(t, []uint32{0x01010101}, ipv4["dns"])
+	assert.Equal(t, int64(100), ipv4["route-metric"])
+	assert.Equal(t, "stable", settings["802-11-wireless"]["assigned-mac-address"])
+	assert.NotContains(t, settings["802-11-wireless"], "cloned-mac-address")
+	assert.Equal(t, uint32(1500), settings["802-11-wireless"]["mtu"])
+	assert.Equal(t, int32(1), settings["proxy"]["method"])
+	assert.Equal(t, "http://wpad/wpad.dat", settings["proxy"]["pac-url"])
+
+	// address-data comes back from NetworkManager as decoded maps
+	ipv4["address-data"] = []map[string]interface{}{{"address": "192.168.1.50", "prefix": uint32(24)}}
+	cs, err = nmReadConnectionSettings(settings)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.50/24"}, cs.IPv4.Addresses)
+	assert.Equal(t, int64(100), cs.IPv4.RouteMetric)
+	assert.Equal(t, ProxyAuto, cs.Proxy.Method)
+
+	_, err = nmReadConnectionSettings(gonetworkmanager.ConnectionSettings{"connection": {"type": "vpn"}})
+	assert.Error(t, err)
+}
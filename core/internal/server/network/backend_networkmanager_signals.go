@@ -219,6 +219,15 @@ func (b *NetworkManagerBackend) handleNetworkManagerChange(changes map[string]db
 				b.stateMutex.Unlock()
 				needsUpdate = true
 			}
+		case "Connectivity":
+			if value, ok := changes[key].Value().(uint32); ok {
+				b.stateMutex.Lock()
+				b.state.Connectivity = nmConnectivity(gonetworkmanager.NmConnectivity(value))
+				b.stateMutex.Unlock()
+				if b.onStateChange != nil {
+					b.onStateChange()
+				}
+			}
 		default:
 			continue
 		}
@@ -127,6 +127,38 @@ func TestNetworkManagerBackend_HandleNetworkManagerChange_WirelessEnabled(t *tes
 	})
 }
 
+func TestNetworkManagerBackend_HandleNetworkManagerChange_Connectivity(t *testing.T) {
+	mockNM := mock_gonetworkmanager.NewMockNetworkManager(t)
+
+	backend, err := NewNetworkManagerBackend(mockNM)
+	assert.NoError(t, err)
+
+	notified := 0
+	backend.onStateChange = func() { notified++ }
+
+	backend.handleNetworkManagerChange(map[string]dbus.Variant{
+		"Connectivity": dbus.MakeVariant(uint32(gonetworkmanager.NmConnectivityPortal)),
+	})
+
+	state, _ := backend.GetCurrentState()
+	assert.Equal(t, ConnectivityPortal, state.Connectivity)
+	assert.Equal(t, 1, notified)
+}
+
+func TestNetworkManagerBackend_CheckConnectivity(t *testing.T) {
+	mockNM := mock_gonetworkmanager.NewMockNetworkManager(t)
+
+	backend, err := NewNetworkManagerBackend(mockNM)
+	assert.NoError(t, err)
+
+	mockNM.EXPECT().CheckConnectivity().Return(nil)
+	mockNM.EXPECT().GetPropertyConnectivity().Return(gonetworkmanager.NmConnectivityFull, nil)
+
+	connectivity, err := backend.CheckConnectivity()
+	assert.NoError(t, err)
+	assert.Equal(t, ConnectivityFull, connectivity)
+}
+
 func TestNetworkManagerBackend_HandleNetworkManagerChange_ActiveConnections(t *testing.T) {
 	mockNM := mock_gonetworkmanager.NewMockNetworkManager(t)
 
@@ -168,7 +168,13 @@ func (b *NetworkManagerBackend) updateWiFiState() error {
 	var ip, ssid, bssid string
 	var signal uint8
 
+	hotspot := HotspotState{Supported: true}
 	if connected {
+		b.ensureWiFiDevice()
+		hotspot = b.readHotspotState(iface)
+	}
+
+	if connected && !hotspot.Active {
 		if err := b.ensureWiFiDevice(); err == nil && b.wifiDev != nil {
 			w := b.wifiDev.(gonetworkmanager.DeviceWireless)
 			activeAP, err := w.GetPropertyActiveAccessPoint()
@@ -247,7 +253,8 @@ func (b *NetworkManagerBackend) updateWiFiState() error {
 	}
 
 	b.state.WiFiDevice = iface
-	b.state.WiFiConnected = connected
+	b.state.WiFiConnected = connected && !hotspot.Active
+	b.state.Hotspot = hotspot
 	b.state.WiFiIP = ip
 	b.state.WiFiSSID = ssid
 	b.state.WiFiBSSID = bssid
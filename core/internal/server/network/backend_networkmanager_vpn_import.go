@@ -0,0 +1,226 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/Wifx/gonetworkmanager/v2"
+)
+
+func vpnDataDir(name string) string {
+	return filepath.Join(config.DMSDir("XDG_DATA_HOME", filepath.Join(".local", "share")), "vpn", vpnInterfaceName(name))
+}
+
+func nmAddressData(addrs []string, v6 bool) []map[string]interface{} {
+	var data []map[string]interface{}
+	for _, addr := range addrs {
+		ip, ipNet, err := net.ParseCIDR(addr)
+		var prefix int
+		if err != nil {
+			ip = net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+			prefix = 128
+			if ip.To4() != nil {
+				prefix = 32
+			}
+		} else {
+			prefix, _ = ipNet.Mask.Size()
+		}
+		if (ip.To4() == nil) != v6 {
+			continue
+		}
+		data = append(data, map[string]interface{}{
+			"address": ip.String(),
+			"prefix":  uint32(prefix),
+		})
+	}
+	return data
+}
+
+func nmWireGuardSettings(name string, cfg *WireGuardConfig) map[string]map[string]interface{} {
+	peers := make([]map[string]interface{}, 0, len(cfg.Peers))
+	for _, p := range cfg.Peers {
+		peer := map[string]interface{}{
+			"public-key":  p.PublicKey,
+			"allowed-ips": p.AllowedIPs,
+		}
+		if p.Endpoint != "" {
+			peer["endpoint"] = p.Endpoint
+		}
+		if p.PresharedKey != "" {
+			peer["preshared-key"] = p.PresharedKey
+			peer["preshared-key-flags"] = uint32(0)
+		}
+		if p.PersistentKeepalive != 0 {
+			peer["persistent-keepalive"] = p.PersistentKeepalive
+		}
+		peers = append(peers, peer)
+	}
+
+	wireguard := map[string]interface{}{
+		"private-key":       cfg.PrivateKey,
+		"private-key-flags": uint32(0),
+		"peers":             peers,
+	}
+	if cfg.ListenPort != 0 {
+		wireguard["listen-port"] = cfg.ListenPort
+	}
+	if cfg.MTU != 0 {
+		wireguard["mtu"] = cfg.MTU
+	}
+	if cfg.FwMark != 0 {
+		wireguard["fwmark"] = cfg.FwMark
+	}
+
+	var dns4 []uint32
+	var dns6 [][]byte
+	for _, server := range cfg.DNS {
+		ip := net.ParseIP(server)
+		if ip4 := ip.To4(); ip4 != nil {
+			dns4 = append(dns4, binary.LittleEndian.Uint32(ip4))
+		} else if ip != nil {
+			dns6 = append(dns6, []byte(ip.To16()))
+		}
+	}
+
+	ipv4 := map[string]interface{}{"method": "disabled"}
+	if addrs := nmAddressData(cfg.Addresses, false); len(addrs) > 0 {
+		ipv4["method"] = "manual"
+		ipv4["address-data"] = addrs
+		if len(dns4) > 0 {
+			ipv4["dns"] = dns4
+			if len(cfg.Domains) > 0 {
+				ipv4["dns-search"] = cfg.Domains
+			}
+			if cfg.FullTunnel() {
+				ipv4["dns-priority"] = int32(-50)
+			}
+		}
+	}
+
+	ipv6 := map[string]interface{}{"method": "ignore"}
+	if addrs := nmAddressData(cfg.Addresses, true); len(addrs) > 0 {
+		ipv6["method"] = "manual"
+		ipv6["address-data"] = addrs
+		if len(dns6) > 0 {
+			ipv6["dns"] = dns6
+			if len(cfg.Domains) > 0 {
+				ipv6["dns-search"] = cfg.Domains
+			}
+			if cfg.FullTunnel() {
+				ipv6["dns-priority"] = int32(-50)
+			}
+		}
+	}
+
+	return map[string]map[string]interface{}{
+		"connection": {
+			"id":             name,
+			"uuid":           newUUID(),
+			"type":           "wireguard",
+			"interface-name": vpnInterfaceName(name),
+			"autoconnect":    false,
+		},
+		"wireguard": wireguard,
+		"ipv4":      ipv4,
+		"ipv6":      ipv6,
+	}
+}
+
+// writeOpenVPNInline stores embedded certificates and keys next to each other
+// so the openvpn plugin, which only accepts paths, can read them.
+func writeOpenVPNInline(dir string, cfg *OpenVPNConfig) (map[string]string, error) {
+	data := make(map[string]string, len(cfg.Data)+len(cfg.Inline))
+	for k, v := range cfg.Data {
+		data[k] = v
+	}
+	if len(cfg.Inline) == 0 {
+		return data, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	for key, content := range cfg.Inline {
+		path := filepath.Join(dir, key+".pem")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, err
+		}
+		data[key] = path
+	}
+	return data, nil
+}
+
+func nmOpenVPNSettings(name string, data map[string]string) map[string]map[string]interface{} {
+	vpn := map[string]interface{}{
+		"service-type": nmOpenVPNService,
+		"data":         data,
+	}
+
+	return map[string]map[string]interface{}{
+		"connection": {
+			"id":          name,
+			"uuid":        newUUID(),
+			"type":        "vpn",
+			"autoconnect": false,
+		},
+		"vpn":  vpn,
+		"ipv4": {"method": "auto"},
+		"ipv6": {"method": "auto"},
+	}
+}
+
+func (b *NetworkManagerBackend) ImportVPN(imp *VPNImport) (*VPNProfile, error) {
+	profiles, err := b.ListVPNProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		if p.Name == imp.Name {
+			return nil, fmt.Errorf("VPN profile %q already exists", imp.Name)
+		}
+	}
+
+	var settings map[string]map[string]interface{}
+	switch {
+	case imp.WireGuard != nil:
+		settings = nmWireGuardSettings(imp.Name, imp.WireGuard)
+	case imp.OpenVPN != nil:
+		data, err := writeOpenVPNInline(vpnDataDir(imp.Name), imp.OpenVPN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store inline certificates: %w", err)
+		}
+		settings = nmOpenVPNSettings(imp.Name, data)
+	default:
+		return nil, fmt.Errorf("empty VPN import")
+	}
+
+	settingsMgr := b.settings.(gonetworkmanager.Settings)
+	if _, err := settingsMgr.AddConnection(settings); err != nil {
+		return nil, fmt.Errorf("failed to add connection: %w", err)
+	}
+
+	profile := &VPNProfile{
+		Name: imp.Name,
+		UUID: settings["connection"]["uuid"].(string),
+		Type: settings["connection"]["type"].(string),
+	}
+	if imp.OpenVPN != nil {
+		profile.ServiceType = nmOpenVPNService
+	}
+	log.Infof("[ImportVPN] Imported %s profile %q (%s)", profile.Type, profile.Name, profile.UUID)
+
+	b.ListVPNProfiles()
+	if b.onStateChange != nil {
+		b.onStateChange()
+	}
+
+	return profile, nil
+}
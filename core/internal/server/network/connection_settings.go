@@ -0,0 +1,151 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	IPMethodAuto      = "auto"
+	IPMethodManual    = "manual"
+	IPMethodLinkLocal = "link-local"
+	IPMethodDisabled  = "disabled"
+
+	MACDefault   = "default"
+	MACPermanent = "permanent"
+	MACPreserve  = "preserve"
+	MACRandom    = "random"
+	MACStable    = "stable"
+
+	ProxyNone = "none"
+	ProxyAuto = "auto"
+)
+
+var searchDomainPattern = regexp.MustCompile(`^~?([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.?$`)
+
+func validSearchDomain(domain string) bool {
+	return domain == "~." || (len(domain) <= 254 && searchDomainPattern.MatchString(domain))
+}
+
+func (s *IPSettings) validate(family string, v6 bool) error {
+	matchesFamily := func(ip net.IP) bool {
+		return ip != nil && (ip.To4() == nil) == v6
+	}
+
+	switch s.Method {
+	case IPMethodAuto, IPMethodManual, IPMethodLinkLocal, IPMethodDisabled:
+	default:
+		return fmt.Errorf("%s: invalid method %q", family, s.Method)
+	}
+
+	if s.Method == IPMethodManual && len(s.Addresses) == 0 {
+		return fmt.Errorf("%s: manual method requires at least one address", family)
+	}
+	if s.Method != IPMethodManual && len(s.Addresses) > 0 {
+		return fmt.Errorf("%s: addresses require the manual method", family)
+	}
+	for _, addr := range s.Addresses {
+		ip, _, err := net.ParseCIDR(addr)
+		if err != nil || !matchesFamily(ip) {
+			return fmt.Errorf("%s: invalid address %q, expected CIDR notation", family, addr)
+		}
+	}
+
+	if s.Gateway != "" {
+		if s.Method != IPMethodManual {
+			return fmt.Errorf("%s: gateway requires the manual method", family)
+		}
+		if !matchesFamily(net.ParseIP(s.Gateway)) {
+			return fmt.Errorf("%s: invalid gateway %q", family, s.Gateway)
+		}
+	}
+
+	if s.Method == IPMethodDisabled && (len(s.DNS) > 0 || len(s.DNSSearch) > 0) {
+		return fmt.Errorf("%s: DNS settings require the family to be enabled", family)
+	}
+	for _, dns := range s.DNS {
+		if !matchesFamily(net.ParseIP(dns)) {
+			return fmt.Errorf("%s: invalid DNS server %q", family, dns)
+		}
+	}
+	for _, domain := range s.DNSSearch {
+		if !validSearchDomain(domain) {
+			return fmt.Errorf("%s: invalid search domain %q", family, domain)
+		}
+	}
+
+	if s.RouteMetric < -1 || s.RouteMetric > math.MaxUint32 {
+		return fmt.Errorf("%s: route metric must be between -1 and %d", family, uint32(math.MaxUint32))
+	}
+
+	return nil
+}
+
+// Validate checks settings before a backend writes them. A route metric of
+// -1 and an MTU of 0 select the defaults.
+func (s *ConnectionSettings) Validate() error {
+	if err := s.IPv4.validate("ipv4", false); err != nil {
+		return err
+	}
+	if err := s.IPv6.validate("ipv6", true); err != nil {
+		return err
+	}
+
+	if s.MTU != 0 && (s.MTU < 68 || s.MTU > 65535) {
+		return fmt.Errorf("MTU must be between 68 and 65535")
+	}
+	if s.MTU != 0 && s.MTU < 1280 && s.IPv6.Method != IPMethodDisabled {
+		return fmt.Errorf("MTU must be at least 1280 when IPv6 is enabled")
+	}
+
+	switch s.MACRandomization {
+	case "", MACDefault, MACPermanent, MACPreserve, MACRandom, MACStable:
+	default:
+		return fmt.Errorf("invalid macRandomization %q", s.MACRandomization)
+	}
+
+	switch s.Proxy.Method {
+	case "", ProxyNone:
+		if s.Proxy.PACURL != "" {
+			return fmt.Errorf("proxy pacUrl requires the auto method")
+		}
+	case ProxyAuto:
+		if s.Proxy.PACURL != "" {
+			u, err := url.Parse(s.Proxy.PACURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "file") || (u.Scheme != "file" && u.Host == "") {
+				return fmt.Errorf("invalid proxy pacUrl %q", s.Proxy.PACURL)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid proxy method %q", s.Proxy.Method)
+	}
+
+	return nil
+}
+
+func normalizeDomains(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func (m *Manager) GetConnectionSettings(uuid string) (*ConnectionSettings, error) {
+	return m.backend.GetConnectionSettings(uuid)
+}
+
+func (m *Manager) UpdateConnectionSettings(uuid string, settings ConnectionSettings) error {
+	settings.IPv4.DNSSearch = normalizeDomains(settings.IPv4.DNSSearch)
+	settings.IPv6.DNSSearch = normalizeDomains(settings.IPv6.DNSSearch)
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	return m.backend.UpdateConnectionSettings(uuid, settings)
+}
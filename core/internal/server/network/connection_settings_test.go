@@ -0,0 +1,59 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validConnectionSettings() ConnectionSettings {
+	return ConnectionSettings{
+		IPv4: IPSettings{
+			Method:      IPMethodManual,
+			Addresses:   []string{"192.168.1.50/24"},
+			Gateway:     "192.168.1.1",
+			DNS:         []string{"1.1.1.1"},
+			DNSSearch:   []string{"home.arpa"},
+			RouteMetric: 100,
+		},
+		IPv6:  IPSettings{Method: IPMethodAuto, RouteMetric: -1},
+		MTU:   1500,
+		Proxy: ProxySettings{Method: ProxyAuto, PACURL: "http://wpad/wpad.dat"},
+	}
+}
+
+func TestConnectionSettings_Validate(t *testing.T) {
+	s := validConnectionSettings()
+	assert.NoError(t, s.Validate())
+
+	tests := map[string]func(*ConnectionSettings){
+		"bad method":          func(s *ConnectionSettings) { s.IPv4.Method = "static" },
+		"manual no address":   func(s *ConnectionSettings) { s.IPv4.Addresses = nil },
+		"address not cidr":    func(s *ConnectionSettings) { s.IPv4.Addresses = []string{"192.168.1.50"} },
+		"address wrong fam":   func(s *ConnectionSettings) { s.IPv4.Addresses = []string{"fd00::1/64"} },
+		"gateway wrong fam":   func(s *ConnectionSettings) { s.IPv4.Gateway = "fd00::1" },
+		"gateway with auto":   func(s *ConnectionSettings) { s.IPv6.Gateway = "fd00::1" },
+		"addresses with auto": func(s *ConnectionSettings) { s.IPv6.Addresses = []string{"fd00::2/64"} },
+		"bad dns":             func(s *ConnectionSettings) { s.IPv4.DNS = []string{"one.one"} },
+		"bad domain":          func(s *ConnectionSettings) { s.IPv4.DNSSearch = []string{"bad domain"} },
+		"bad metric":          func(s *ConnectionSettings) { s.IPv4.RouteMetric = -2 },
+		"mtu too small v6":    func(s *ConnectionSettings) { s.MTU = 1000 },
+		"mtu too large":       func(s *ConnectionSettings) { s.MTU = 70000 },
+		"bad mac":             func(s *ConnectionSettings) { s.MACRandomization = "sometimes" },
+		"bad proxy":           func(s *ConnectionSettings) { s.Proxy.Method = "manual" },
+		"bad pac url":         func(s *ConnectionSettings) { s.Proxy.PACURL = "ftp://x/wpad.dat" },
+		"pac without auto":    func(s *ConnectionSettings) { s.Proxy.Method = ProxyNone },
+		"dns when disabled":   func(s *ConnectionSettings) { s.IPv6 = IPSettings{Method: IPMethodDisabled, DNS: []string{"fd00::53"}} },
+	}
+	for name, mutate := range tests {
+		s := validConnectionSettings()
+		mutate(&s)
+		assert.Error(t, s.Validate(), name)
+	}
+
+	s = validConnectionSettings()
+	s.IPv6 = IPSettings{Method: IPMethodDisabled}
+	s.MTU = 1000
+	s.IPv4.DNSSearch = []string{"~corp.example", "~."}
+	assert.NoError(t, s.Validate())
+}
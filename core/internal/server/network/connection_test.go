@@ -1,12 +1,14 @@
 package network_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	mocks_network "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/network"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/network"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestConnectionRequest_Validation(t *testing.T) {
@@ -107,3 +109,81 @@ func TestManager_DisconnectEthernet_NoDevice(t *testing.T) {
 // mocking the NetworkManager D-Bus interfaces, which is beyond the scope
 // of these unit tests. The tests above cover the basic error cases and
 // validation logic. Integration tests would be needed for full coverage.
+
+func TestManager_StartHotspot(t *testing.T) {
+	backend := mocks_network.NewMockBackend(t)
+	backend.EXPECT().StartHotspot("Hotspot", "password123", "5GHz", uint32(36)).Return(nil)
+	backend.EXPECT().StopHotspot().Return(errors.New("hotspot not active"))
+
+	manager := network.NewTestManager(backend, &network.NetworkState{})
+
+	assert.NoError(t, manager.StartHotspot("Hotspot", "password123", "5GHz", 36))
+	assert.ErrorContains(t, manager.StopHotspot(), "hotspot not active")
+}
+
+func TestManager_ImportVPN(t *testing.T) {
+	backend := mocks_network.NewMockBackend(t)
+	backend.EXPECT().ImportVPN(mock.MatchedBy(func(imp *network.VPNImport) bool {
+		return imp.Name == "home" && imp.OpenVPN != nil && imp.WireGuard == nil
+	})).Return(&network.VPNProfile{Name: "home", UUID: "uuid-1", Type: "vpn"}, nil)
+
+	manager := network.NewTestManager(backend, &network.NetworkState{})
+
+	profile, err := manager.ImportVPN(network.VPNImportRequest{Name: "home", Content: "remote vpn.example.com 1194\nauth-user-pass\n"})
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid-1", profile.UUID)
+
+	_, err = manager.ImportVPN(network.VPNImportRequest{Name: "bad", Type: "wireguard", Content: "[Interface]\n"})
+	assert.Error(t, err)
+}
+
+func TestManager_UpdateConnectionSettings(t *testing.T) {
+	backend := mocks_network.NewMockBackend(t)
+	manager := network.NewTestManager(backend, &network.NetworkState{})
+
+	invalid := network.ConnectionSettings{
+		IPv4: network.IPSettings{Method: "manual"},
+		IPv6: network.IPSettings{Method: "auto"},
+	}
+	assert.Error(t, manager.UpdateConnectionSettings("uuid-1", invalid), "rejected before reaching the backend")
+
+	valid := network.ConnectionSettings{
+		IPv4: network.IPSettings{Method: "auto", RouteMetric: -1, DNSSearch: []string{" lan ", ""}},
+		IPv6: network.IPSettings{Method: "auto", RouteMetric: -1},
+	}
+	backend.EXPECT().UpdateConnectionSettings("uuid-1", mock.MatchedBy(func(s network.ConnectionSettings) bool {
+		return len(s.IPv4.DNSSearch) == 1 && s.IPv4.DNSSearch[0] == "lan"
+	})).Return(nil)
+	assert.NoError(t, manager.UpdateConnectionSettings("uuid-1", valid))
+}
+
+func TestManager_CheckConnectivity(t *testing.T) {
+	backend := mocks_network.NewMockBackend(t)
+	manager := network.NewTestManager(backend, &network.NetworkState{NetworkStatus: network.StatusWiFi})
+
+	probed := 0
+	manager.SetConnectivityProbe(func(ctx context.Context) network.ConnectivityResult {
+		probed++
+		return network.ConnectivityResult{Connectivity: network.ConnectivityPortal, PortalURL: "http://portal.example/login"}
+	})
+
+	backend.EXPECT().CheckConnectivity().Return(network.ConnectivityUnknown, errors.New("not supported")).Once()
+	result := manager.CheckConnectivity()
+	assert.Equal(t, network.ConnectivityPortal, result.Connectivity)
+	assert.Equal(t, "http://portal.example/login", manager.GetState().PortalURL)
+	assert.Equal(t, 1, probed)
+
+	backend.EXPECT().CheckConnectivity().Return(network.ConnectivityPortal, nil).Once()
+	result = manager.CheckConnectivity()
+	assert.Equal(t, "http://portal.example/login", result.PortalURL, "portal URL comes from the probe")
+	assert.Equal(t, 2, probed)
+
+	backend.EXPECT().CheckConnectivity().Return(network.ConnectivityFull, nil).Once()
+	result = manager.CheckConnectivity()
+	assert.Equal(t, network.ConnectivityResult{Connectivity: network.ConnectivityFull}, result)
+	assert.Equal(t, 2, probed, "backend result is trusted without probing")
+	assert.Empty(t, manager.GetState().PortalURL)
+
+	disconnected := network.NewTestManager(backend, &network.NetworkState{NetworkStatus: network.StatusDisconnected})
+	assert.Equal(t, network.ConnectivityNone, disconnected.CheckConnectivity().Connectivity)
+}
@@ -0,0 +1,204 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const (
+	DefaultConnectivityURL  = "http://nmcheck.gnome.org/check_network_status.txt"
+	DefaultConnectivityBody = "NetworkManager is online"
+
+	connectivityInterval  = 5 * time.Minute
+	portalRecheckInterval = 30 * time.Second
+	connectivityTimeout   = 10 * time.Second
+)
+
+type ConnectivityConfig struct {
+	URL          string `json:"url"`
+	ExpectedBody string `json:"expectedBody"`
+}
+
+type ConnectivityResult struct {
+	Connectivity Connectivity `json:"connectivity"`
+	PortalURL    string       `json:"portalUrl,omitempty"`
+}
+
+// ConnectivityProbe performs one connectivity check. The manager uses an
+// HTTP probe by default; tests substitute their own.
+type ConnectivityProbe func(ctx context.Context) ConnectivityResult
+
+func DefaultConnectivityConfig() ConnectivityConfig {
+	return ConnectivityConfig{URL: DefaultConnectivityURL, ExpectedBody: DefaultConnectivityBody}
+}
+
+func (c ConnectivityConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid connectivity URL %q", c.URL)
+	}
+	return nil
+}
+
+// NewHTTPProbe checks connectivity the way NetworkManager does: fetch a known
+// URL without following redirects. A redirect or an unexpected body means a
+// captive portal intercepted the request; a 204 or the expected body means
+// full access. An empty ExpectedBody accepts any 2xx response.
+func NewHTTPProbe(cfg ConnectivityConfig, client *http.Client) ConnectivityProbe {
+	if client == nil {
+		client = &http.Client{Timeout: connectivityTimeout}
+	}
+	probeClient := *client
+	probeClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return func(ctx context.Context) ConnectivityResult {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+		if err != nil {
+			return ConnectivityResult{Connectivity: ConnectivityUnknown}
+		}
+		req.Header.Set("Cache-Control", "no-cache")
+
+		resp, err := probeClient.Do(req)
+		if err != nil {
+			log.Debugf("[Connectivity] probe failed: %v", err)
+			return ConnectivityResult{Connectivity: ConnectivityLimited}
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode >= 300 && resp.StatusCode < 400:
+			portal := cfg.URL
+			if loc, err := resp.Location(); err == nil {
+				portal = loc.String()
+			}
+			return ConnectivityResult{Connectivity: ConnectivityPortal, PortalURL: portal}
+
+		case resp.StatusCode == http.StatusNoContent:
+			return ConnectivityResult{Connectivity: ConnectivityFull}
+
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+			if cfg.ExpectedBody == "" || strings.TrimSpace(string(body)) == strings.TrimSpace(cfg.ExpectedBody) {
+				return ConnectivityResult{Connectivity: ConnectivityFull}
+			}
+			return ConnectivityResult{Connectivity: ConnectivityPortal, PortalURL: cfg.URL}
+		}
+
+		return ConnectivityResult{Connectivity: ConnectivityLimited}
+	}
+}
+
+func (m *Manager) connectivityProbe() ConnectivityProbe {
+	m.connMutex.Lock()
+	defer m.connMutex.Unlock()
+	if m.connProbe == nil {
+		m.connProbe = NewHTTPProbe(DefaultConnectivityConfig(), nil)
+	}
+	return m.connProbe
+}
+
+// SetConnectivityProbe replaces the probe used when the backend cannot report
+// connectivity itself, or to find the login URL of a portal it detected.
+func (m *Manager) SetConnectivityProbe(probe ConnectivityProbe) {
+	m.connMutex.Lock()
+	m.connProbe = probe
+	m.connMutex.Unlock()
+}
+
+func (m *Manager) ConfigureConnectivity(cfg ConnectivityConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	m.SetConnectivityProbe(NewHTTPProbe(cfg, nil))
+	m.requestConnectivityCheck()
+	return nil
+}
+
+func (m *Manager) requestConnectivityCheck() {
+	select {
+	case m.connTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// CheckConnectivity runs a check now and stores the result in the state.
+func (m *Manager) CheckConnectivity() ConnectivityResult {
+	m.stateMutex.RLock()
+	status := m.state.NetworkStatus
+	m.stateMutex.RUnlock()
+
+	var result ConnectivityResult
+	if status == StatusDisconnected {
+		result.Connectivity = ConnectivityNone
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), connectivityTimeout)
+		defer cancel()
+
+		backendResult, err := m.backend.CheckConnectivity()
+		switch {
+		case err != nil || backendResult == ConnectivityUnknown || backendResult == "":
+			result = m.connectivityProbe()(ctx)
+		case backendResult == ConnectivityPortal:
+			result = ConnectivityResult{Connectivity: ConnectivityPortal}
+			if probed := m.connectivityProbe()(ctx); probed.Connectivity == ConnectivityPortal {
+				result.PortalURL = probed.PortalURL
+			}
+		default:
+			result.Connectivity = backendResult
+		}
+	}
+
+	m.stateMutex.Lock()
+	changed := m.state.Connectivity != result.Connectivity || m.state.PortalURL != result.PortalURL
+	m.state.Connectivity = result.Connectivity
+	m.state.PortalURL = result.PortalURL
+	m.stateMutex.Unlock()
+
+	if changed {
+		log.Infof("[Connectivity] %s %s", result.Connectivity, result.PortalURL)
+		m.notifySubscribers()
+	}
+	return result
+}
+
+// watchConnectivity re-checks when the connection changes, periodically, and
+// more often while a portal is blocking access so a completed login is
+// noticed quickly.
+func (m *Manager) watchConnectivity() {
+	defer m.notifierWg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-m.connTrigger:
+		case <-timer.C:
+		}
+
+		result := m.CheckConnectivity()
+
+		interval := connectivityInterval
+		if result.Connectivity == ConnectivityPortal || result.Connectivity == ConnectivityLimited {
+			interval = portalRecheckInterval
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(interval)
+	}
+}
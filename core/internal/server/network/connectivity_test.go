@@ -0,0 +1,54 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPProbe(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/online", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(DefaultConnectivityBody + "\n"))
+	})
+	mux.HandleFunc("/generate_204", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login?orig=1", http.StatusFound)
+	})
+	mux.HandleFunc("/intercept", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>Welcome to Hotel WiFi</html>"))
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	probe := func(path, body string) ConnectivityResult {
+		return NewHTTPProbe(ConnectivityConfig{URL: srv.URL + path, ExpectedBody: body}, srv.Client())(context.Background())
+	}
+
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityFull}, probe("/online", DefaultConnectivityBody))
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityFull}, probe("/generate_204", ""))
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityPortal, PortalURL: srv.URL + "/login?orig=1"}, probe("/redirect", DefaultConnectivityBody))
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityPortal, PortalURL: srv.URL + "/intercept"}, probe("/intercept", DefaultConnectivityBody))
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityFull}, probe("/intercept", ""))
+	assert.Equal(t, ConnectivityResult{Connectivity: ConnectivityLimited}, probe("/error", ""))
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	result := NewHTTPProbe(ConnectivityConfig{URL: unreachable.URL}, nil)(context.Background())
+	assert.Equal(t, ConnectivityLimited, result.Connectivity)
+}
+
+func TestConnectivityConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultConnectivityConfig().Validate())
+	assert.NoError(t, ConnectivityConfig{URL: "https://example.com/generate_204"}.Validate())
+	assert.Error(t, ConnectivityConfig{URL: "ftp://example.com"}.Validate())
+	assert.Error(t, ConnectivityConfig{URL: "not a url"}.Validate())
+}
@@ -4,13 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -70,8 +71,34 @@ func HandleRequest(conn net.Conn, req Request, manager *Manager) {
 		handleDisconnectAllVPN(conn, req, manager)
 	case "network.vpn.clearCredentials":
 		handleClearVPNCredentials(conn, req, manager)
+	case "network.vpn.import":
+		handleImportVPN(conn, req, manager)
+	case "network.connection.getSettings":
+		handleGetConnectionSettings(conn, req, manager)
+	case "network.connection.updateSettings":
+		handleUpdateConnectionSettings(conn, req, manager)
+	case "network.connectivity.check":
+		models.Respond(conn, req.ID, manager.CheckConnectivity())
+	case "network.connectivity.configure":
+		handleConfigureConnectivity(conn, req, manager)
 	case "network.wifi.setAutoconnect":
 		handleSetWiFiAutoconnect(conn, req, manager)
+	case "network.hotspot.start":
+		handleStartHotspot(conn, req, manager)
+	case "network.hotspot.stop":
+		handleStopHotspot(conn, req, manager)
+	case "network.hotspot.status":
+		models.Respond(conn, req.ID, manager.GetHotspotState())
+	case "network.traffic.getState":
+		models.Respond(conn, req.ID, manager.GetTrafficState())
+	case "network.traffic.setInterval":
+		handleSetTrafficInterval(conn, req, manager)
+	case "network.traffic.getUsage":
+		models.Respond(conn, req.ID, manager.GetUsage())
+	case "network.traffic.setCap":
+		handleSetDataCap(conn, req, manager)
+	case "network.traffic.resetUsage":
+		handleResetUsage(conn, req, manager)
 	default:
 		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
 	}
@@ -81,14 +108,14 @@ func handleCredentialsSubmit(conn net.Conn, req Request, manager *Manager) {
 	token, ok := req.Params["token"].(string)
 	if !ok {
 		log.Warnf("handleCredentialsSubmit: missing or invalid token parameter")
-		models.RespondError(conn, req.ID, "missing or invalid 'token' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'token' parameter"))
 		return
 	}
 
 	secretsRaw, ok := req.Params["secrets"].(map[string]interface{})
 	if !ok {
 		log.Warnf("handleCredentialsSubmit: missing or invalid secrets parameter")
-		models.RespondError(conn, req.ID, "missing or invalid 'secrets' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'secrets' parameter"))
 		return
 	}
 
@@ -117,7 +144,7 @@ func handleCredentialsSubmit(conn net.Conn, req Request, manager *Manager) {
 func handleCredentialsCancel(conn net.Conn, req Request, manager *Manager) {
 	token, ok := req.Params["token"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'token' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'token' parameter"))
 		return
 	}
 
@@ -150,7 +177,7 @@ func handleGetWiFiNetworks(conn net.Conn, req Request, manager *Manager) {
 func handleConnectWiFi(conn net.Conn, req Request, manager *Manager) {
 	ssid, ok := req.Params["ssid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'ssid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'ssid' parameter"))
 		return
 	}
 
@@ -210,7 +237,7 @@ func handleDisconnectWiFi(conn net.Conn, req Request, manager *Manager) {
 func handleForgetWiFi(conn net.Conn, req Request, manager *Manager) {
 	ssid, ok := req.Params["ssid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'ssid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'ssid' parameter"))
 		return
 	}
 
@@ -251,7 +278,7 @@ func handleDisableWiFi(conn net.Conn, req Request, manager *Manager) {
 func handleConnectEthernetSpecificConfig(conn net.Conn, req Request, manager *Manager) {
 	uuid, ok := req.Params["uuid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'uuid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'uuid' parameter"))
 		return
 	}
 	if err := manager.activateConnection(uuid); err != nil {
@@ -280,7 +307,7 @@ func handleDisconnectEthernet(conn net.Conn, req Request, manager *Manager) {
 func handleSetPreference(conn net.Conn, req Request, manager *Manager) {
 	preference, ok := req.Params["preference"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'preference' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'preference' parameter"))
 		return
 	}
 
@@ -295,7 +322,7 @@ func handleSetPreference(conn net.Conn, req Request, manager *Manager) {
 func handleGetNetworkInfo(conn net.Conn, req Request, manager *Manager) {
 	ssid, ok := req.Params["ssid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'ssid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'ssid' parameter"))
 		return
 	}
 
@@ -311,7 +338,7 @@ func handleGetNetworkInfo(conn net.Conn, req Request, manager *Manager) {
 func handleGetWiredNetworkInfo(conn net.Conn, req Request, manager *Manager) {
 	uuid, ok := req.Params["uuid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'uuid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'uuid' parameter"))
 		return
 	}
 
@@ -465,16 +492,110 @@ func handleClearVPNCredentials(conn net.Conn, req Request, manager *Manager) {
 	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "VPN credentials cleared"})
 }
 
+func handleImportVPN(conn net.Conn, req Request, manager *Manager) {
+	importReq := VPNImportRequest{}
+	importReq.Name, _ = req.Params["name"].(string)
+	importReq.Type, _ = req.Params["type"].(string)
+	importReq.Path, _ = req.Params["path"].(string)
+	importReq.Content, _ = req.Params["content"].(string)
+
+	if importReq.Path == "" && importReq.Content == "" {
+		models.RespondError(conn, req.ID, "missing 'path' or 'content' parameter")
+		return
+	}
+
+	profile, err := manager.ImportVPN(importReq)
+	if err != nil {
+		log.Warnf("handleImportVPN: failed: %v", err)
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to import VPN: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, profile)
+}
+
+func handleGetConnectionSettings(conn net.Conn, req Request, manager *Manager) {
+	uuid, ok := req.Params["uuid"].(string)
+	if !ok || uuid == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'uuid' parameter"))
+		return
+	}
+
+	settings, err := manager.GetConnectionSettings(uuid)
+	if err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to get connection settings: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, settings)
+}
+
+// handleUpdateConnectionSettings merges the given fields onto the current
+// settings, so callers only need to send what changed.
+func handleUpdateConnectionSettings(conn net.Conn, req Request, manager *Manager) {
+	uuid, ok := req.Params["uuid"].(string)
+	if !ok || uuid == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'uuid' parameter"))
+		return
+	}
+	patch, ok := req.Params["settings"].(map[string]interface{})
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'settings' parameter"))
+		return
+	}
+
+	settings, err := manager.GetConnectionSettings(uuid)
+	if err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to get connection settings: %v", err))
+		return
+	}
+
+	data, err := json.Marshal(patch)
+	if err == nil {
+		err = json.Unmarshal(data, settings)
+	}
+	if err != nil {
+		models.RespondErr(conn, req.ID, models.NewParamError("invalid 'settings' parameter: %v", err))
+		return
+	}
+
+	if err := manager.UpdateConnectionSettings(uuid, *settings); err != nil {
+		log.Warnf("handleUpdateConnectionSettings: failed: %v", err)
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to update connection settings: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "connection settings updated"})
+}
+
+func handleConfigureConnectivity(conn net.Conn, req Request, manager *Manager) {
+	cfg := DefaultConnectivityConfig()
+	if url, ok := req.Params["url"].(string); ok && url != "" {
+		cfg.URL = url
+		cfg.ExpectedBody = ""
+	}
+	if body, ok := req.Params["expectedBody"].(string); ok {
+		cfg.ExpectedBody = body
+	}
+
+	if err := manager.ConfigureConnectivity(cfg); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to configure connectivity check: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "connectivity check configured"})
+}
+
 func handleSetWiFiAutoconnect(conn net.Conn, req Request, manager *Manager) {
 	ssid, ok := req.Params["ssid"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'ssid' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'ssid' parameter"))
 		return
 	}
 
 	autoconnect, ok := req.Params["autoconnect"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'autoconnect' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'autoconnect' parameter"))
 		return
 	}
 
@@ -485,3 +606,95 @@ func handleSetWiFiAutoconnect(conn net.Conn, req Request, manager *Manager) {
 
 	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "autoconnect updated"})
 }
+
+func handleStartHotspot(conn net.Conn, req Request, manager *Manager) {
+	ssid, ok := req.Params["ssid"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'ssid' parameter"))
+		return
+	}
+
+	password, _ := req.Params["password"].(string)
+	band, _ := req.Params["band"].(string)
+
+	var channel uint32
+	if ch, ok := req.Params["channel"].(float64); ok {
+		if ch < 0 {
+			models.RespondErr(conn, req.ID, models.NewParamError("invalid 'channel' parameter"))
+			return
+		}
+		channel = uint32(ch)
+	}
+
+	if err := manager.StartHotspot(ssid, password, band, channel); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to start hotspot: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "hotspot starting"})
+}
+
+func handleStopHotspot(conn net.Conn, req Request, manager *Manager) {
+	if err := manager.StopHotspot(); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to stop hotspot: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "hotspot stopped"})
+}
+
+func handleSetTrafficInterval(conn net.Conn, req Request, manager *Manager) {
+	ms, ok := req.Params["intervalMs"].(float64)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'intervalMs' parameter"))
+		return
+	}
+
+	if err := manager.SetTrafficInterval(time.Duration(ms) * time.Millisecond); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to set traffic interval: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "traffic interval set"})
+}
+
+func handleSetDataCap(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok || id == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	var cap DataCap
+	if v, ok := req.Params["dailyBytes"].(float64); ok && v > 0 {
+		cap.DailyBytes = uint64(v)
+	}
+	if v, ok := req.Params["monthlyBytes"].(float64); ok && v > 0 {
+		cap.MonthlyBytes = uint64(v)
+	}
+	if v, ok := req.Params["warnPercent"].(float64); ok {
+		cap.WarnPercent = int(v)
+	}
+
+	if err := manager.SetDataCap(id, &cap); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to set data cap: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "data cap updated"})
+}
+
+func handleResetUsage(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok || id == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	if err := manager.ResetUsage(id); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("failed to reset usage: %v", err))
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "usage reset"})
+}
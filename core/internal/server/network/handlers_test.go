@@ -46,7 +46,7 @@ func TestRespondError_Network(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Equal(t, "test error", resp.Error)
 	assert.Nil(t, resp.Result)
 }
@@ -60,7 +60,7 @@ func TestRespond_Network(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.True(t, resp.Result.Success)
@@ -85,7 +85,7 @@ func TestHandleGetState(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.Equal(t, StatusWiFi, resp.Result.NetworkStatus)
@@ -111,7 +111,7 @@ func TestHandleGetWiFiNetworks(t *testing.T) {
 	err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.Len(t, *resp.Result, 2)
@@ -137,7 +137,7 @@ func TestHandleConnectWiFi(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'ssid' parameter")
 	})
 }
@@ -161,7 +161,7 @@ func TestHandleSetPreference(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'preference' parameter")
 	})
 }
@@ -185,7 +185,7 @@ func TestHandleGetNetworkInfo(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "missing or invalid 'ssid' parameter")
 	})
 }
@@ -210,7 +210,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Contains(t, resp.Error, "unknown method")
 	})
 
@@ -227,7 +227,7 @@ func TestHandleRequest(t *testing.T) {
 		err := json.NewDecoder(conn.writeBuf).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, resp.ID)
+		assert.EqualValues(t, 123, resp.ID)
 		assert.Empty(t, resp.Error)
 	})
 }
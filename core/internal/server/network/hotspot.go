@@ -0,0 +1,166 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	HotspotBand24 = "2.4GHz"
+	HotspotBand5  = "5GHz"
+
+	arpTablePath          = "/proc/net/arp"
+	hotspotClientInterval = 5 * time.Second
+)
+
+type hotspotConfig struct {
+	SSID     string
+	Password string
+	Band     string
+	Channel  uint32
+}
+
+func normalizeHotspotBand(band string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(band)) {
+	case "", "auto":
+		return "", nil
+	case "2.4ghz", "2.4", "bg":
+		return HotspotBand24, nil
+	case "5ghz", "5", "a":
+		return HotspotBand5, nil
+	default:
+		return "", fmt.Errorf("invalid band: %s", band)
+	}
+}
+
+func bandForChannel(channel uint32) string {
+	switch {
+	case channel >= 1 && channel <= 14:
+		return HotspotBand24
+	case channel >= 32 && channel <= 177:
+		return HotspotBand5
+	default:
+		return ""
+	}
+}
+
+// newHotspotConfig validates hotspot parameters. An empty password creates an
+// open network; a channel without a band implies the band.
+func newHotspotConfig(ssid, password, band string, channel uint32) (hotspotConfig, error) {
+	if len(ssid) == 0 || len(ssid) > 32 {
+		return hotspotConfig{}, fmt.Errorf("ssid must be 1-32 bytes")
+	}
+	if password != "" && (len(password) < 8 || len(password) > 63) {
+		return hotspotConfig{}, fmt.Errorf("password must be 8-63 characters")
+	}
+
+	normalized, err := normalizeHotspotBand(band)
+	if err != nil {
+		return hotspotConfig{}, err
+	}
+
+	if channel != 0 {
+		channelBand := bandForChannel(channel)
+		switch {
+		case channelBand == "":
+			return hotspotConfig{}, fmt.Errorf("invalid channel: %d", channel)
+		case normalized == "":
+			normalized = channelBand
+		case normalized != channelBand:
+			return hotspotConfig{}, fmt.Errorf("channel %d is not in the %s band", channel, normalized)
+		}
+	}
+
+	return hotspotConfig{SSID: ssid, Password: password, Band: normalized, Channel: channel}, nil
+}
+
+func frequencyBand(freq uint32) string {
+	switch {
+	case freq >= 2412 && freq <= 2484:
+		return HotspotBand24
+	case freq >= 5170 && freq <= 5825:
+		return HotspotBand5
+	default:
+		return ""
+	}
+}
+
+// countHotspotClients counts resolved neighbours on the hotspot interface.
+// Neither NetworkManager nor iwd expose associated stations over D-Bus, so
+// the ARP table is the common ground between the two.
+func countHotspotClients(arpPath, iface string) int {
+	if iface == "" {
+		return 0
+	}
+
+	f, err := os.Open(arpPath)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[5] != iface {
+			continue
+		}
+		if fields[2] == "0x0" || fields[3] == "00:00:00:00:00:00" {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+func (m *Manager) StartHotspot(ssid, password, band string, channel uint32) error {
+	return m.backend.StartHotspot(ssid, password, band, channel)
+}
+
+func (m *Manager) StopHotspot() error {
+	return m.backend.StopHotspot()
+}
+
+func (m *Manager) GetHotspotState() HotspotState {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+	return m.state.Hotspot
+}
+
+func (m *Manager) updateHotspotClients() bool {
+	m.stateMutex.Lock()
+	defer m.stateMutex.Unlock()
+
+	clients := 0
+	if m.state.Hotspot.Active {
+		clients = countHotspotClients(arpTablePath, m.state.Hotspot.Device)
+	}
+	if clients == m.state.Hotspot.Clients {
+		return false
+	}
+	m.state.Hotspot.Clients = clients
+	return true
+}
+
+func (m *Manager) watchHotspotClients() {
+	defer m.notifierWg.Done()
+
+	ticker := time.NewTicker(hotspotClientInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			if m.updateHotspotClients() {
+				m.notifySubscribers()
+			}
+		}
+	}
+}
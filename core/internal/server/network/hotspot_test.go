@@ -0,0 +1,89 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHotspotConfig(t *testing.T) {
+	cfg, err := newHotspotConfig("Hotspot", "password123", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, hotspotConfig{SSID: "Hotspot", Password: "password123"}, cfg)
+
+	cfg, err = newHotspotConfig("Hotspot", "", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, HotspotBand5, cfg.Band)
+
+	cfg, err = newHotspotConfig("Hotspot", "", "", 6)
+	require.NoError(t, err)
+	assert.Equal(t, HotspotBand24, cfg.Band)
+
+	invalid := []struct {
+		ssid, password, band string
+		channel              uint32
+	}{
+		{"", "", "", 0},
+		{"this-ssid-is-far-too-long-for-802.11", "", "", 0},
+		{"Hotspot", "short", "", 0},
+		{"Hotspot", "", "60GHz", 0},
+		{"Hotspot", "", "", 200},
+		{"Hotspot", "", HotspotBand5, 6},
+	}
+	for _, tc := range invalid {
+		_, err := newHotspotConfig(tc.ssid, tc.password, tc.band, tc.channel)
+		assert.Error(t, err, "%+v", tc)
+	}
+}
+
+func TestFrequencyBand(t *testing.T) {
+	assert.Equal(t, HotspotBand24, frequencyBand(2437))
+	assert.Equal(t, HotspotBand5, frequencyBand(5180))
+	assert.Empty(t, frequencyBand(5975))
+}
+
+func TestNMHotspotSettings(t *testing.T) {
+	settings := nmHotspotSettings(hotspotConfig{SSID: "Hotspot", Password: "password123", Band: HotspotBand5, Channel: 36})
+
+	wireless := settings["802-11-wireless"]
+	assert.Equal(t, []byte("Hotspot"), wireless["ssid"])
+	assert.Equal(t, "ap", wireless["mode"])
+	assert.Equal(t, "a", wireless["band"])
+	assert.Equal(t, uint32(36), wireless["channel"])
+	assert.Equal(t, "802-11-wireless-security", wireless["security"])
+	assert.Equal(t, "shared", settings["ipv4"]["method"])
+	assert.Equal(t, "password123", settings["802-11-wireless-security"]["psk"])
+
+	open := nmHotspotSettings(hotspotConfig{SSID: "Open"})
+	assert.NotContains(t, open, "802-11-wireless-security")
+	assert.NotContains(t, open["802-11-wireless"], "band")
+}
+
+func TestCountHotspotClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arp")
+	table := `IP address       HW type     Flags       HW address            Mask     Device
+10.42.0.23       0x1         0x2         aa:bb:cc:dd:ee:01     *        wlan0
+10.42.0.57       0x1         0x0         00:00:00:00:00:00     *        wlan0
+10.42.0.80       0x1         0x2         aa:bb:cc:dd:ee:02     *        wlan0
+192.168.1.1      0x1         0x2         aa:bb:cc:dd:ee:03     *        eth0
+`
+	require.NoError(t, os.WriteFile(path, []byte(table), 0644))
+
+	assert.Equal(t, 2, countHotspotClients(path, "wlan0"))
+	assert.Equal(t, 0, countHotspotClients(path, ""))
+	assert.Equal(t, 0, countHotspotClients(filepath.Join(t.TempDir(), "missing"), "wlan0"))
+}
+
+func TestNetworkdHotspotUnsupported(t *testing.T) {
+	backend := &SystemdNetworkdBackend{}
+
+	assert.ErrorContains(t, backend.StartHotspot("Hotspot", "password123", "", 0), "not supported")
+	assert.ErrorContains(t, backend.StopHotspot(), "not supported")
+
+	state, err := backend.GetHotspotState()
+	require.NoError(t, err)
+	assert.False(t, state.Supported)
+}
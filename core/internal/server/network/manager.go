@@ -64,6 +64,7 @@ func NewManager() (*Manager, error) {
 		state: &NetworkState{
 			NetworkStatus: StatusDisconnected,
 			Preference:    PreferenceAuto,
+			Connectivity:  ConnectivityUnknown,
 			WiFiNetworks:  []WiFiNetwork{},
 		},
 		stateMutex:            sync.RWMutex{},
@@ -73,8 +74,15 @@ func NewManager() (*Manager, error) {
 		dirty:                 make(chan struct{}, 1),
 		credentialSubscribers: make(map[string]chan CredentialPrompt),
 		credSubMutex:          sync.RWMutex{},
+		connTrigger:           make(chan struct{}, 1),
 	}
 
+	usage, err := LoadUsageStore(DefaultUsagePath())
+	if err != nil {
+		log.Warnf("Failed to load network usage, starting fresh: %v", err)
+	}
+	m.traffic = newTrafficMonitor(sysClassNet, usage)
+
 	broker := NewSubscriptionBroker(m.broadcastCredentialPrompt)
 	if err := backend.SetPromptBroker(broker); err != nil {
 		return nil, fmt.Errorf("failed to set prompt broker: %w", err)
@@ -88,8 +96,11 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to sync initial state: %w", err)
 	}
 
-	m.notifierWg.Add(1)
+	m.notifierWg.Add(4)
 	go m.notifier()
+	go m.watchHotspotClients()
+	go m.watchConnectivity()
+	go m.watchTraffic()
 
 	if err := backend.StartMonitoring(m.onBackendStateChange); err != nil {
 		m.Close()
@@ -106,6 +117,11 @@ func (m *Manager) syncStateFromBackend() error {
 	}
 
 	m.stateMutex.Lock()
+	connectionChanged := m.state.NetworkStatus != backendState.NetworkStatus ||
+		m.state.EthernetIP != backendState.EthernetIP ||
+		m.state.WiFiIP != backendState.WiFiIP ||
+		m.state.WiFiBSSID != backendState.WiFiBSSID ||
+		(backendState.Connectivity != "" && m.state.Connectivity != backendState.Connectivity)
 	m.state.Backend = backendState.Backend
 	m.state.NetworkStatus = backendState.NetworkStatus
 	m.state.EthernetIP = backendState.EthernetIP
@@ -126,8 +142,18 @@ func (m *Manager) syncStateFromBackend() error {
 	m.state.IsConnecting = backendState.IsConnecting
 	m.state.ConnectingSSID = backendState.ConnectingSSID
 	m.state.LastError = backendState.LastError
+	m.state.Hotspot = backendState.Hotspot
+	if backendState.NetworkStatus == StatusDisconnected {
+		m.state.Connectivity = ConnectivityNone
+		m.state.PortalURL = ""
+	}
 	m.stateMutex.Unlock()
 
+	m.updateHotspotClients()
+	if connectionChanged {
+		m.requestConnectivityCheck()
+	}
+
 	return nil
 }
 
@@ -204,6 +230,12 @@ func stateChangedMeaningfully(old, new *NetworkState) bool {
 	if old.LastError != new.LastError {
 		return true
 	}
+	if old.Hotspot != new.Hotspot {
+		return true
+	}
+	if old.Connectivity != new.Connectivity || old.PortalURL != new.PortalURL {
+		return true
+	}
 	if len(old.WiFiNetworks) != len(new.WiFiNetworks) {
 		return true
 	}
@@ -402,6 +434,15 @@ func (m *Manager) Close() {
 	}
 	m.subscribers = make(map[string]chan NetworkState)
 	m.subMutex.Unlock()
+
+	if m.traffic != nil {
+		m.traffic.subMutex.Lock()
+		for _, ch := range m.traffic.subscribers {
+			close(ch)
+		}
+		m.traffic.subscribers = make(map[string]chan TrafficState)
+		m.traffic.subMutex.Unlock()
+	}
 }
 
 func (m *Manager) ScanWiFi() error {
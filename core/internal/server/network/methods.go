@@ -0,0 +1,253 @@
+package network
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "network.getState",
+		Description: "Get current network state",
+	},
+	{
+		Name:        "network.wifi.scan",
+		Description: "Scan for WiFi networks",
+	},
+	{
+		Name:        "network.wifi.networks",
+		Description: "Get WiFi network list",
+	},
+	{
+		Name:        "network.wifi.connect",
+		Description: "Connect to WiFi",
+		Params: []models.ParamSpec{
+			{Name: "ssid", Type: models.ParamString, Required: true},
+			{Name: "password", Type: models.ParamString},
+			{Name: "username", Type: models.ParamString},
+			{Name: "interactive", Type: models.ParamBool},
+			{Name: "anonymousIdentity", Type: models.ParamString},
+			{Name: "domainSuffixMatch", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "network.wifi.disconnect",
+		Description: "Disconnect WiFi",
+	},
+	{
+		Name:        "network.wifi.forget",
+		Description: "Forget network",
+		Params: []models.ParamSpec{
+			{Name: "ssid", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.wifi.toggle",
+		Description: "Toggle WiFi radio",
+	},
+	{
+		Name:        "network.wifi.enable",
+		Description: "Enable WiFi",
+	},
+	{
+		Name:        "network.wifi.disable",
+		Description: "Disable WiFi",
+	},
+	{
+		Name:        "network.wifi.setAutoconnect",
+		Description: "Set network autoconnect",
+		Params: []models.ParamSpec{
+			{Name: "ssid", Type: models.ParamString, Required: true},
+			{Name: "autoconnect", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "network.ethernet.connect",
+		Description: "Connect Ethernet",
+	},
+	{
+		Name:        "network.ethernet.connect.config",
+		Description: "Connect Ethernet to a specific configuration",
+		Params: []models.ParamSpec{
+			{Name: "uuid", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.ethernet.disconnect",
+		Description: "Disconnect Ethernet",
+	},
+	{
+		Name:        "network.ethernet.info",
+		Description: "Get wired connection info",
+		Params: []models.ParamSpec{
+			{Name: "uuid", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.vpn.profiles",
+		Description: "List VPN profiles",
+	},
+	{
+		Name:        "network.vpn.active",
+		Description: "List active VPN connections",
+	},
+	{
+		Name:        "network.vpn.connect",
+		Description: "Connect VPN",
+		Params: []models.ParamSpec{
+			{Name: "uuidOrName", Type: models.ParamString},
+			{Name: "name", Type: models.ParamString},
+			{Name: "uuid", Type: models.ParamString},
+			{Name: "singleActive", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "network.vpn.disconnect",
+		Description: "Disconnect VPN",
+		Params: []models.ParamSpec{
+			{Name: "uuidOrName", Type: models.ParamString},
+			{Name: "name", Type: models.ParamString},
+			{Name: "uuid", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "network.vpn.disconnectAll",
+		Description: "Disconnect all VPNs",
+	},
+	{
+		Name:        "network.vpn.clearCredentials",
+		Description: "Clear saved VPN credentials",
+		Params: []models.ParamSpec{
+			{Name: "uuidOrName", Type: models.ParamString},
+			{Name: "name", Type: models.ParamString},
+			{Name: "uuid", Type: models.ParamString},
+		},
+	},
+	{
+		Name:        "network.vpn.import",
+		Description: "Create a VPN profile from a WireGuard .conf or OpenVPN .ovpn file",
+		Params: []models.ParamSpec{
+			{Name: "path", Type: models.ParamString, Description: "Path to the file to import"},
+			{Name: "content", Type: models.ParamString, Description: "File contents, used instead of path"},
+			{Name: "name", Type: models.ParamString, Description: "Profile name, defaults to the file name"},
+			{Name: "type", Type: models.ParamString, Enum: []string{VPNTypeWireGuard, VPNTypeOpenVPN}, Description: "Detected from the file when omitted"},
+		},
+		Notes: []string{"The networkd backend only supports WireGuard and installs units via pkexec", "Not supported by the iwd backend"},
+	},
+	{
+		Name:        "network.connection.getSettings",
+		Description: "Get IP, DNS, MTU, MAC and proxy settings of a wired or WiFi connection",
+		Params: []models.ParamSpec{
+			{Name: "uuid", Type: models.ParamString, Required: true, Description: "Connection UUID, WiFi SSID, or wired:<iface> on networkd"},
+		},
+	},
+	{
+		Name:        "network.connection.updateSettings",
+		Description: "Update connection settings; omitted fields keep their current values",
+		Params: []models.ParamSpec{
+			{Name: "uuid", Type: models.ParamString, Required: true},
+			{Name: "settings", Type: models.ParamObject, Required: true, Description: "Partial ConnectionSettings object"},
+		},
+		Notes: []string{"Settings are validated before anything is written", "networkd does not support proxy or MAC randomization"},
+	},
+	{
+		Name:        "network.connectivity.check",
+		Description: "Re-check internet connectivity and detect captive portals",
+		Notes:       []string{"Returns {connectivity, portalUrl}; connectivity is unknown, none, portal, limited or full"},
+	},
+	{
+		Name:        "network.connectivity.configure",
+		Description: "Set the HTTP endpoint used for connectivity checks",
+		Params: []models.ParamSpec{
+			{Name: "url", Type: models.ParamString, Description: "http(s) URL; omit to restore the default"},
+			{Name: "expectedBody", Type: models.ParamString, Description: "Response body of a successful check; empty accepts any 2xx"},
+		},
+		Notes: []string{"With NetworkManager its own checker is used first; the endpoint is probed to find the portal login URL or when NM checking is disabled"},
+	},
+	{
+		Name:        "network.hotspot.start",
+		Description: "Start a WiFi hotspot sharing the current connection",
+		Params: []models.ParamSpec{
+			{Name: "ssid", Type: models.ParamString, Required: true},
+			{Name: "password", Type: models.ParamString, Description: "8-63 characters, omit for an open network"},
+			{Name: "band", Type: models.ParamString, Enum: []string{"auto", HotspotBand24, HotspotBand5}},
+			{Name: "channel", Type: models.ParamNumber},
+		},
+		Notes: []string{"iwd requires a password and does not support band or channel selection", "Not supported by the networkd backend"},
+	},
+	{
+		Name:        "network.hotspot.stop",
+		Description: "Stop the WiFi hotspot",
+	},
+	{
+		Name:        "network.hotspot.status",
+		Description: "Get hotspot state and connected client count",
+	},
+	{
+		Name:        "network.traffic.getState",
+		Description: "Get the latest rx/tx rates and usage of active connections",
+		Notes:       []string{"Rates are bytes per second; live updates are published on the network.traffic subscription"},
+	},
+	{
+		Name:        "network.traffic.setInterval",
+		Description: "Set how often traffic is sampled while subscribed",
+		Params: []models.ParamSpec{
+			{Name: "intervalMs", Type: models.ParamNumber, Required: true, Description: "250-60000, default 2000"},
+		},
+		Notes: []string{"Without subscribers sampling slows to every 15 seconds to keep usage totals current"},
+	},
+	{
+		Name:        "network.traffic.getUsage",
+		Description: "Get today's and this month's usage of every connection",
+	},
+	{
+		Name:        "network.traffic.setCap",
+		Description: "Set a data cap on a metered connection",
+		Params: []models.ParamSpec{
+			{Name: "id", Type: models.ParamString, Required: true, Description: "Connection UUID, or SSID for WiFi"},
+			{Name: "dailyBytes", Type: models.ParamNumber, Description: "0 or omitted disables the daily cap"},
+			{Name: "monthlyBytes", Type: models.ParamNumber, Description: "0 or omitted disables the monthly cap"},
+			{Name: "warnPercent", Type: models.ParamNumber, Description: "Percentage of a cap that raises a warning, default 80"},
+		},
+		Notes: []string{"Omitting both caps removes the cap", "Alerts are delivered in the alerts field of network.traffic events"},
+	},
+	{
+		Name:        "network.traffic.resetUsage",
+		Description: "Clear recorded usage of a connection",
+		Params: []models.ParamSpec{
+			{Name: "id", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.preference.set",
+		Description: "Set preference",
+		Params: []models.ParamSpec{
+			{Name: "preference", Type: models.ParamString, Required: true, Enum: []string{"auto", "wifi", "ethernet"}},
+		},
+	},
+	{
+		Name:        "network.info",
+		Description: "Get network info",
+		Params: []models.ParamSpec{
+			{Name: "ssid", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.credentials.submit",
+		Description: "Submit credentials for prompt",
+		Params: []models.ParamSpec{
+			{Name: "token", Type: models.ParamString, Required: true},
+			{Name: "secrets", Type: models.ParamObject, Required: true},
+			{Name: "save", Type: models.ParamBool},
+		},
+	},
+	{
+		Name:        "network.credentials.cancel",
+		Description: "Cancel credential prompt",
+		Params: []models.ParamSpec{
+			{Name: "token", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "network.subscribe",
+		Description: "Subscribe to network state changes",
+		Streaming:   true,
+	},
+}
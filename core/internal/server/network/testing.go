@@ -11,5 +11,7 @@ func NewTestManager(backend Backend, state *NetworkState) *Manager {
 		subscribers: make(map[string]chan NetworkState),
 		stopChan:    make(chan struct{}),
 		dirty:       make(chan struct{}, 1),
+		connTrigger: make(chan struct{}, 1),
+		traffic:     newTrafficMonitor(sysClassNet, NewUsageStore("")),
 	}
 }
@@ -0,0 +1,387 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+)
+
+const (
+	DefaultTrafficInterval = 2 * time.Second
+	MinTrafficInterval     = 250 * time.Millisecond
+	MaxTrafficInterval     = time.Minute
+
+	sysClassNet         = "/sys/class/net"
+	idleTrafficInterval = 15 * time.Second
+	usageSaveInterval   = time.Minute
+)
+
+type InterfaceTraffic struct {
+	Interface string `json:"interface"`
+	RxBytes   uint64 `json:"rxBytes"`
+	TxBytes   uint64 `json:"txBytes"`
+	RxRate    uint64 `json:"rxRate"`
+	TxRate    uint64 `json:"txRate"`
+}
+
+// TrafficState is published on the network.traffic subscription. Rates are
+// bytes per second; the totals cover physical interfaces only, so tunnel
+// traffic is not counted twice. Alerts lists caps crossed since the last
+// sample.
+type TrafficState struct {
+	IntervalMs  int64              `json:"intervalMs"`
+	RxRate      uint64             `json:"rxRate"`
+	TxRate      uint64             `json:"txRate"`
+	Interfaces  []InterfaceTraffic `json:"interfaces"`
+	Connections []ConnectionUsage  `json:"connections"`
+	Alerts      []UsageAlert       `json:"alerts,omitempty"`
+}
+
+type trafficCounters struct {
+	rx, tx uint64
+}
+
+type trafficConnection struct {
+	id, name, connType, iface string
+}
+
+type trafficMonitor struct {
+	sysDir      string
+	usage       *UsageStore
+	mutex       sync.Mutex
+	interval    time.Duration
+	last        map[string]trafficCounters
+	lastSample  time.Time
+	lastSave    time.Time
+	state       TrafficState
+	subscribers map[string]chan TrafficState
+	subMutex    sync.RWMutex
+	reschedule  chan struct{}
+}
+
+func newTrafficMonitor(sysDir string, usage *UsageStore) *trafficMonitor {
+	return &trafficMonitor{
+		sysDir:      sysDir,
+		usage:       usage,
+		interval:    DefaultTrafficInterval,
+		subscribers: make(map[string]chan TrafficState),
+		reschedule:  make(chan struct{}, 1),
+		state: TrafficState{
+			IntervalMs:  DefaultTrafficInterval.Milliseconds(),
+			Interfaces:  []InterfaceTraffic{},
+			Connections: []ConnectionUsage{},
+		},
+	}
+}
+
+func readCounter(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readInterfaceCounters reads the byte counters of every interface except
+// loopback from sysfs.
+func readInterfaceCounters(sysDir string) (map[string]trafficCounters, error) {
+	entries, err := os.ReadDir(sysDir)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]trafficCounters, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "lo" {
+			continue
+		}
+		stats := filepath.Join(sysDir, name, "statistics")
+		rx, err := readCounter(filepath.Join(stats, "rx_bytes"))
+		if err != nil {
+			continue
+		}
+		tx, err := readCounter(filepath.Join(stats, "tx_bytes"))
+		if err != nil {
+			continue
+		}
+		counters[name] = trafficCounters{rx: rx, tx: tx}
+	}
+	return counters, nil
+}
+
+// counterDelta treats a counter going backwards as a reset, e.g. when the
+// interface was recreated, and counts nothing for that sample.
+func counterDelta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+func activeTrafficConnections(state *NetworkState) []trafficConnection {
+	var conns []trafficConnection
+
+	if state.EthernetConnected && state.EthernetDevice != "" {
+		id := state.EthernetConnectionUuid
+		name := state.EthernetDevice
+		for _, wired := range state.WiredConnections {
+			if wired.UUID == id && wired.ID != "" {
+				name = wired.ID
+			}
+		}
+		if id == "" {
+			id = state.EthernetDevice
+		}
+		conns = append(conns, trafficConnection{id: id, name: name, connType: "ethernet", iface: state.EthernetDevice})
+	}
+
+	if state.WiFiConnected && state.WiFiDevice != "" && state.WiFiSSID != "" {
+		conns = append(conns, trafficConnection{id: state.WiFiSSID, name: state.WiFiSSID, connType: "wifi", iface: state.WiFiDevice})
+	}
+
+	for _, vpn := range state.VPNActive {
+		if vpn.Device != "" && vpn.UUID != "" {
+			conns = append(conns, trafficConnection{id: vpn.UUID, name: vpn.Name, connType: "vpn", iface: vpn.Device})
+		}
+	}
+
+	return conns
+}
+
+func (m *Manager) activeTrafficConnections() []trafficConnection {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+	return activeTrafficConnections(m.state)
+}
+
+// sampleTraffic reads the counters, updates rates and usage totals, and
+// returns the new state. The first sample only establishes a baseline.
+func (m *Manager) sampleTraffic(now time.Time) (TrafficState, error) {
+	t := m.traffic
+	counters, err := readInterfaceCounters(t.sysDir)
+	if err != nil {
+		return TrafficState{}, err
+	}
+	conns := m.activeTrafficConnections()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	elapsed := now.Sub(t.lastSample).Seconds()
+	baseline := t.lastSample.IsZero() || elapsed <= 0
+
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	state := TrafficState{
+		IntervalMs:  t.interval.Milliseconds(),
+		Interfaces:  make([]InterfaceTraffic, 0, len(names)),
+		Connections: make([]ConnectionUsage, 0, len(conns)),
+	}
+	deltas := make(map[string]trafficCounters, len(names))
+
+	for _, name := range names {
+		cur := counters[name]
+		it := InterfaceTraffic{Interface: name, RxBytes: cur.rx, TxBytes: cur.tx}
+
+		if prev, ok := t.last[name]; ok && !baseline {
+			d := trafficCounters{rx: counterDelta(prev.rx, cur.rx), tx: counterDelta(prev.tx, cur.tx)}
+			deltas[name] = d
+			it.RxRate = uint64(float64(d.rx) / elapsed)
+			it.TxRate = uint64(float64(d.tx) / elapsed)
+		}
+
+		if !isVirtualInterface(name) {
+			state.RxRate += it.RxRate
+			state.TxRate += it.TxRate
+		}
+		state.Interfaces = append(state.Interfaces, it)
+	}
+
+	for _, conn := range conns {
+		if d := deltas[conn.iface]; d.rx > 0 || d.tx > 0 {
+			state.Alerts = append(state.Alerts, t.usage.Add(conn.id, conn.name, conn.connType, d.rx, d.tx)...)
+		}
+		usage, _ := t.usage.Usage(conn.id)
+		usage.Active = true
+		if usage.Type == "" {
+			usage.Name = conn.name
+			usage.Type = conn.connType
+		}
+		state.Connections = append(state.Connections, usage)
+	}
+
+	t.last = counters
+	t.lastSample = now
+
+	t.state = state
+	t.state.Alerts = nil
+
+	for _, alert := range state.Alerts {
+		log.Warnf("[Traffic] %s usage of %s %s: %d of %d bytes", alert.Period, alert.Name, alert.Level, alert.Used, alert.Limit)
+	}
+
+	return state, nil
+}
+
+func (m *Manager) saveUsage(force bool) {
+	t := m.traffic
+	t.mutex.Lock()
+	due := force || time.Since(t.lastSave) >= usageSaveInterval
+	if due {
+		t.lastSave = time.Now()
+	}
+	t.mutex.Unlock()
+
+	if !due {
+		return
+	}
+	if err := t.usage.Save(); err != nil {
+		log.Warnf("[Traffic] failed to save usage: %v", err)
+	}
+}
+
+// pollInterval slows sampling down while nobody is subscribed; usage still
+// has to be accounted for, but rates are not being displayed.
+func (t *trafficMonitor) pollInterval() time.Duration {
+	t.mutex.Lock()
+	interval := t.interval
+	t.mutex.Unlock()
+
+	t.subMutex.RLock()
+	idle := len(t.subscribers) == 0
+	t.subMutex.RUnlock()
+
+	if idle && interval < idleTrafficInterval {
+		return idleTrafficInterval
+	}
+	return interval
+}
+
+func (m *Manager) watchTraffic() {
+	defer m.notifierWg.Done()
+	defer m.saveUsage(true)
+
+	if _, err := m.sampleTraffic(time.Now()); err != nil {
+		log.Warnf("[Traffic] failed to read interface statistics: %v", err)
+	}
+
+	timer := time.NewTimer(m.traffic.pollInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-m.traffic.reschedule:
+		case <-timer.C:
+			if state, err := m.sampleTraffic(time.Now()); err == nil {
+				m.broadcastTraffic(state)
+			}
+			m.saveUsage(false)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(m.traffic.pollInterval())
+	}
+}
+
+func (m *Manager) requestTrafficReschedule() {
+	select {
+	case m.traffic.reschedule <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) SubscribeTraffic(id string) chan TrafficState {
+	ch := make(chan TrafficState, 16)
+	m.traffic.subMutex.Lock()
+	m.traffic.subscribers[id] = ch
+	m.traffic.subMutex.Unlock()
+	m.requestTrafficReschedule()
+	return ch
+}
+
+func (m *Manager) UnsubscribeTraffic(id string) {
+	m.traffic.subMutex.Lock()
+	if ch, ok := m.traffic.subscribers[id]; ok {
+		close(ch)
+		delete(m.traffic.subscribers, id)
+	}
+	m.traffic.subMutex.Unlock()
+}
+
+func (m *Manager) broadcastTraffic(state TrafficState) {
+	m.traffic.subMutex.RLock()
+	defer m.traffic.subMutex.RUnlock()
+
+	for _, ch := range m.traffic.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+func (m *Manager) GetTrafficState() TrafficState {
+	m.traffic.mutex.Lock()
+	defer m.traffic.mutex.Unlock()
+	return m.traffic.state
+}
+
+func (m *Manager) SetTrafficInterval(interval time.Duration) error {
+	if interval < MinTrafficInterval || interval > MaxTrafficInterval {
+		return fmt.Errorf("interval must be between %d and %d ms", MinTrafficInterval.Milliseconds(), MaxTrafficInterval.Milliseconds())
+	}
+
+	m.traffic.mutex.Lock()
+	m.traffic.interval = interval
+	m.traffic.state.IntervalMs = interval.Milliseconds()
+	m.traffic.mutex.Unlock()
+
+	m.requestTrafficReschedule()
+	return nil
+}
+
+// GetUsage returns the totals of every connection with recorded usage.
+func (m *Manager) GetUsage() []ConnectionUsage {
+	active := make(map[string]bool)
+	for _, conn := range m.activeTrafficConnections() {
+		active[conn.id] = true
+	}
+
+	usage := m.traffic.usage.All()
+	for i := range usage {
+		usage[i].Active = active[usage[i].ID]
+	}
+	return usage
+}
+
+// SetDataCap marks a connection, by UUID or SSID, as metered with the given
+// limits. A nil cap removes it.
+func (m *Manager) SetDataCap(id string, cap *DataCap) error {
+	if id == "" {
+		return fmt.Errorf("connection id is required")
+	}
+	return m.traffic.usage.SetCap(id, cap)
+}
+
+func (m *Manager) ResetUsage(id string) error {
+	return m.traffic.usage.Reset(id)
+}
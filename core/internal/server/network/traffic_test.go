@@ -0,0 +1,149 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCounters(t *testing.T, sysDir, iface string, rx, tx uint64) {
+	t.Helper()
+	stats := filepath.Join(sysDir, iface, "statistics")
+	require.NoError(t, os.MkdirAll(stats, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(stats, "rx_bytes"), []byte(strconv.FormatUint(rx, 10)+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stats, "tx_bytes"), []byte(strconv.FormatUint(tx, 10)+"\n"), 0644))
+}
+
+func TestManager_SampleTraffic(t *testing.T) {
+	sysDir := t.TempDir()
+	writeCounters(t, sysDir, "lo", 0, 0)
+	writeCounters(t, sysDir, "wlan0", 1000, 500)
+	writeCounters(t, sysDir, "wg0", 100, 100)
+
+	manager := NewTestManager(nil, &NetworkState{
+		WiFiConnected: true,
+		WiFiDevice:    "wlan0",
+		WiFiSSID:      "Home",
+	})
+	manager.traffic = newTrafficMonitor(sysDir, NewUsageStore(""))
+
+	start := time.Now()
+	state, err := manager.sampleTraffic(start)
+	require.NoError(t, err)
+	assert.Len(t, state.Interfaces, 2)
+	assert.Zero(t, state.RxRate)
+
+	writeCounters(t, sysDir, "wlan0", 5000, 2500)
+	writeCounters(t, sysDir, "wg0", 2100, 1100)
+
+	state, err = manager.sampleTraffic(start.Add(2 * time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2000), state.RxRate)
+	assert.Equal(t, uint64(1000), state.TxRate)
+	assert.Equal(t, InterfaceTraffic{Interface: "wg0", RxBytes: 2100, TxBytes: 1100, RxRate: 1000, TxRate: 500}, state.Interfaces[0])
+
+	require.Len(t, state.Connections, 1)
+	assert.Equal(t, "Home", state.Connections[0].ID)
+	assert.True(t, state.Connections[0].Active)
+	assert.Equal(t, UsageTotals{Rx: 4000, Tx: 2000}, state.Connections[0].Today)
+
+	// A counter reset must not count as traffic.
+	writeCounters(t, sysDir, "wlan0", 10, 10)
+	state, err = manager.sampleTraffic(start.Add(4 * time.Second))
+	require.NoError(t, err)
+	assert.Zero(t, state.RxRate)
+	assert.Equal(t, UsageTotals{Rx: 4000, Tx: 2000}, state.Connections[0].Today)
+
+	assert.Equal(t, state, manager.GetTrafficState())
+}
+
+func TestManager_TrafficSubscription(t *testing.T) {
+	manager := NewTestManager(nil, nil)
+
+	ch := manager.SubscribeTraffic("client")
+	manager.broadcastTraffic(TrafficState{RxRate: 42})
+	assert.Equal(t, uint64(42), (<-ch).RxRate)
+
+	manager.UnsubscribeTraffic("client")
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestManager_SetTrafficInterval(t *testing.T) {
+	manager := NewTestManager(nil, nil)
+
+	assert.Error(t, manager.SetTrafficInterval(10*time.Millisecond))
+	assert.Error(t, manager.SetTrafficInterval(2*time.Minute))
+	assert.Equal(t, idleTrafficInterval, manager.traffic.pollInterval())
+
+	require.NoError(t, manager.SetTrafficInterval(500*time.Millisecond))
+	assert.Equal(t, int64(500), manager.GetTrafficState().IntervalMs)
+
+	manager.SubscribeTraffic("client")
+	assert.Equal(t, 500*time.Millisecond, manager.traffic.pollInterval())
+}
+
+func TestUsageStore_CapAlerts(t *testing.T) {
+	store := NewUsageStore("")
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.SetCap("Phone", &DataCap{DailyBytes: 1000, MonthlyBytes: 10000}))
+	assert.Error(t, store.SetCap("Phone", &DataCap{DailyBytes: 1000, WarnPercent: 100}))
+
+	assert.Empty(t, store.Add("Phone", "Phone", "wifi", 500, 0))
+
+	alerts := store.Add("Phone", "Phone", "wifi", 300, 0)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, UsageAlert{ID: "Phone", Name: "Phone", Period: UsagePeriodDaily, Level: UsageAlertWarning, Used: 800, Limit: 1000}, alerts[0])
+
+	assert.Empty(t, store.Add("Phone", "Phone", "wifi", 100, 0))
+
+	alerts = store.Add("Phone", "Phone", "wifi", 100, 100)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, UsageAlertExceeded, alerts[0].Level)
+	assert.Empty(t, store.Add("Phone", "Phone", "wifi", 100, 0))
+
+	now = now.AddDate(0, 0, 1)
+	assert.Empty(t, store.Add("Phone", "Phone", "wifi", 100, 0))
+
+	usage, ok := store.Usage("Phone")
+	require.True(t, ok)
+	assert.Equal(t, UsageTotals{Rx: 100}, usage.Today)
+	assert.Equal(t, UsageTotals{Rx: 1200, Tx: 100}, usage.Month)
+
+	require.NoError(t, store.SetCap("Phone", nil))
+	usage, _ = store.Usage("Phone")
+	assert.Nil(t, usage.Cap)
+}
+
+func TestUsageStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "network-usage.json")
+
+	store, err := LoadUsageStore(path)
+	require.NoError(t, err)
+	store.Add("uuid-1", "Wired", "ethernet", 10, 20)
+	require.NoError(t, store.SetCap("uuid-1", &DataCap{MonthlyBytes: 1 << 30}))
+	require.NoError(t, store.Save())
+
+	loaded, err := LoadUsageStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, store.All(), loaded.All())
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	loaded, err = LoadUsageStore(path)
+	assert.Error(t, err)
+	assert.Empty(t, loaded.All())
+}
+
+func TestDataCap_Validate(t *testing.T) {
+	assert.NoError(t, DataCap{MonthlyBytes: 1}.Validate())
+	assert.NoError(t, DataCap{MonthlyBytes: 1, WarnPercent: 99}.Validate())
+	assert.EqualError(t, DataCap{WarnPercent: 100}.Validate(), "warnPercent must be between 1 and 99, or 0 for the default")
+	assert.Error(t, DataCap{WarnPercent: -1}.Validate())
+}
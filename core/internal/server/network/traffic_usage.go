@@ -0,0 +1,362 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+)
+
+const (
+	UsagePeriodDaily   = "daily"
+	UsagePeriodMonthly = "monthly"
+
+	UsageAlertWarning  = "warning"
+	UsageAlertExceeded = "exceeded"
+
+	DefaultCapWarnPercent = 80
+
+	usageDayFormat   = "2006-01-02"
+	usageMonthFormat = "2006-01"
+	usageKeepDays    = 62
+	usageKeepMonths  = 24
+)
+
+type UsageTotals struct {
+	Rx uint64 `json:"rx"`
+	Tx uint64 `json:"tx"`
+}
+
+func (u UsageTotals) Total() uint64 {
+	return u.Rx + u.Tx
+}
+
+// DataCap limits usage of a metered connection. A zero limit disables that
+// period; WarnPercent sets when the warning alert fires.
+type DataCap struct {
+	DailyBytes   uint64 `json:"dailyBytes,omitempty"`
+	MonthlyBytes uint64 `json:"monthlyBytes,omitempty"`
+	WarnPercent  int    `json:"warnPercent,omitempty"`
+}
+
+// Validate accepts a WarnPercent of 0, which selects DefaultCapWarnPercent.
+func (c DataCap) Validate() error {
+	if c.WarnPercent < 0 || c.WarnPercent > 99 {
+		return fmt.Errorf("warnPercent must be between 1 and 99, or 0 for the default")
+	}
+	return nil
+}
+
+type ConnectionUsage struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Type   string      `json:"type"`
+	Active bool        `json:"active"`
+	Today  UsageTotals `json:"today"`
+	Month  UsageTotals `json:"month"`
+	Cap    *DataCap    `json:"cap,omitempty"`
+}
+
+type UsageAlert struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Period string `json:"period"`
+	Level  string `json:"level"`
+	Used   uint64 `json:"used"`
+	Limit  uint64 `json:"limit"`
+}
+
+type usageRecord struct {
+	Name    string                 `json:"name"`
+	Type    string                 `json:"type"`
+	Daily   map[string]UsageTotals `json:"daily"`
+	Monthly map[string]UsageTotals `json:"monthly"`
+	Cap     *DataCap               `json:"cap,omitempty"`
+	Alerted map[string]string      `json:"alerted,omitempty"`
+}
+
+// UsageStore keeps daily and monthly byte totals per connection, keyed by
+// connection UUID or, for WiFi, SSID. An empty path keeps it in memory only.
+type UsageStore struct {
+	path    string
+	mutex   sync.Mutex
+	records map[string]*usageRecord
+	dirty   bool
+	now     func() time.Time
+}
+
+func DefaultUsagePath() string {
+	return filepath.Join(config.DMSDir("XDG_STATE_HOME", filepath.Join(".local", "state")), "network-usage.json")
+}
+
+func NewUsageStore(path string) *UsageStore {
+	return &UsageStore{
+		path:    path,
+		records: make(map[string]*usageRecord),
+		now:     time.Now,
+	}
+}
+
+// LoadUsageStore reads the totals at path. A missing file yields an empty
+// store; an unreadable one yields an empty store and the error.
+func LoadUsageStore(path string) (*UsageStore, error) {
+	s := NewUsageStore(path)
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s.records); err != nil {
+		s.records = make(map[string]*usageRecord)
+		return s, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for id, rec := range s.records {
+		if rec == nil {
+			delete(s.records, id)
+			continue
+		}
+		rec.init()
+	}
+	return s, nil
+}
+
+func (r *usageRecord) init() {
+	if r.Daily == nil {
+		r.Daily = make(map[string]UsageTotals)
+	}
+	if r.Monthly == nil {
+		r.Monthly = make(map[string]UsageTotals)
+	}
+	if r.Alerted == nil {
+		r.Alerted = make(map[string]string)
+	}
+}
+
+func (s *UsageStore) record(id string) *usageRecord {
+	rec, ok := s.records[id]
+	if !ok {
+		rec = &usageRecord{Name: id}
+		rec.init()
+		s.records[id] = rec
+	}
+	return rec
+}
+
+// Add records traffic for a connection and returns any cap alerts it raised.
+// Each alert fires once per period and level.
+func (s *UsageStore) Add(id, name, connType string, rx, tx uint64) []UsageAlert {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	day := now.Format(usageDayFormat)
+	month := now.Format(usageMonthFormat)
+
+	rec := s.record(id)
+	if name != "" {
+		rec.Name = name
+	}
+	if connType != "" {
+		rec.Type = connType
+	}
+
+	d := rec.Daily[day]
+	d.Rx += rx
+	d.Tx += tx
+	rec.Daily[day] = d
+
+	mo := rec.Monthly[month]
+	mo.Rx += rx
+	mo.Tx += tx
+	rec.Monthly[month] = mo
+
+	s.pruneLocked(now)
+	s.dirty = true
+
+	if rec.Cap == nil {
+		return nil
+	}
+
+	var alerts []UsageAlert
+	if alert := rec.checkCap(id, UsagePeriodDaily, day, d.Total(), rec.Cap.DailyBytes); alert != nil {
+		alerts = append(alerts, *alert)
+	}
+	if alert := rec.checkCap(id, UsagePeriodMonthly, month, mo.Total(), rec.Cap.MonthlyBytes); alert != nil {
+		alerts = append(alerts, *alert)
+	}
+	return alerts
+}
+
+func (r *usageRecord) checkCap(id, period, key string, used, limit uint64) *UsageAlert {
+	if limit == 0 {
+		return nil
+	}
+
+	warnPercent := r.Cap.WarnPercent
+	if warnPercent == 0 {
+		warnPercent = DefaultCapWarnPercent
+	}
+
+	var level string
+	switch {
+	case used >= limit:
+		level = UsageAlertExceeded
+	case used*100 >= limit*uint64(warnPercent):
+		level = UsageAlertWarning
+	default:
+		return nil
+	}
+
+	alertKey := period + ":" + key
+	if prev := r.Alerted[alertKey]; prev == level || prev == UsageAlertExceeded {
+		return nil
+	}
+	r.Alerted[alertKey] = level
+
+	return &UsageAlert{ID: id, Name: r.Name, Period: period, Level: level, Used: used, Limit: limit}
+}
+
+func (s *UsageStore) pruneLocked(now time.Time) {
+	oldestDay := now.AddDate(0, 0, -usageKeepDays).Format(usageDayFormat)
+	oldestMonth := now.AddDate(0, -usageKeepMonths, 0).Format(usageMonthFormat)
+
+	for _, rec := range s.records {
+		for day := range rec.Daily {
+			if day < oldestDay {
+				delete(rec.Daily, day)
+			}
+		}
+		for month := range rec.Monthly {
+			if month < oldestMonth {
+				delete(rec.Monthly, month)
+			}
+		}
+		for key := range rec.Alerted {
+			period, periodKey, _ := strings.Cut(key, ":")
+			oldest := oldestMonth
+			if period == UsagePeriodDaily {
+				oldest = oldestDay
+			}
+			if periodKey < oldest {
+				delete(rec.Alerted, key)
+			}
+		}
+	}
+}
+
+func (s *UsageStore) usageLocked(id string, rec *usageRecord) ConnectionUsage {
+	now := s.now()
+	u := ConnectionUsage{
+		ID:    id,
+		Name:  rec.Name,
+		Type:  rec.Type,
+		Today: rec.Daily[now.Format(usageDayFormat)],
+		Month: rec.Monthly[now.Format(usageMonthFormat)],
+	}
+	if rec.Cap != nil {
+		c := *rec.Cap
+		u.Cap = &c
+	}
+	return u
+}
+
+func (s *UsageStore) Usage(id string) (ConnectionUsage, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return ConnectionUsage{ID: id, Name: id}, false
+	}
+	return s.usageLocked(id, rec), true
+}
+
+func (s *UsageStore) All() []ConnectionUsage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	usage := make([]ConnectionUsage, 0, len(s.records))
+	for id, rec := range s.records {
+		usage = append(usage, s.usageLocked(id, rec))
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].ID < usage[j].ID })
+	return usage
+}
+
+// SetCap sets or, when cap is nil or has no limits, clears the cap. Alerts
+// for the current periods are re-evaluated against the new limits.
+func (s *UsageStore) SetCap(id string, cap *DataCap) error {
+	if cap != nil {
+		if err := cap.Validate(); err != nil {
+			return err
+		}
+		if cap.DailyBytes == 0 && cap.MonthlyBytes == 0 {
+			cap = nil
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec := s.record(id)
+	if cap != nil {
+		c := *cap
+		rec.Cap = &c
+	} else {
+		rec.Cap = nil
+	}
+	rec.Alerted = make(map[string]string)
+	s.dirty = true
+	return nil
+}
+
+func (s *UsageStore) Reset(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return fmt.Errorf("no usage recorded for %s", id)
+	}
+	rec.Daily = make(map[string]UsageTotals)
+	rec.Monthly = make(map[string]UsageTotals)
+	rec.Alerted = make(map[string]string)
+	s.dirty = true
+	return nil
+}
+
+func (s *UsageStore) Save() error {
+	s.mutex.Lock()
+	if s.path == "" || !s.dirty {
+		s.mutex.Unlock()
+		return nil
+	}
+	data, err := json.MarshalIndent(s.records, "", "  ")
+	s.dirty = false
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := config.WriteFileAtomic(s.path, append(data, '\n')); err != nil {
+		s.mutex.Lock()
+		s.dirty = true
+		s.mutex.Unlock()
+		return err
+	}
+	return nil
+}
@@ -15,6 +15,16 @@ const (
 	StatusVPN          NetworkStatus = "vpn"
 )
 
+type Connectivity string
+
+const (
+	ConnectivityUnknown Connectivity = "unknown"
+	ConnectivityNone    Connectivity = "none"
+	ConnectivityPortal  Connectivity = "portal"
+	ConnectivityLimited Connectivity = "limited"
+	ConnectivityFull    Connectivity = "full"
+)
+
 type ConnectionPreference string
 
 const (
@@ -59,6 +69,16 @@ type VPNState struct {
 	Active   []VPNActive  `json:"activeConnections"`
 }
 
+type HotspotState struct {
+	Supported bool   `json:"supported"`
+	Active    bool   `json:"active"`
+	SSID      string `json:"ssid,omitempty"`
+	Band      string `json:"band,omitempty"`
+	Channel   uint32 `json:"channel,omitempty"`
+	Device    string `json:"device,omitempty"`
+	Clients   int    `json:"clients"`
+}
+
 type NetworkState struct {
 	Backend                string               `json:"backend"`
 	NetworkStatus          NetworkStatus        `json:"networkStatus"`
@@ -80,6 +100,9 @@ type NetworkState struct {
 	VPNActive              []VPNActive          `json:"vpnActive"`
 	IsConnecting           bool                 `json:"isConnecting"`
 	ConnectingSSID         string               `json:"connectingSSID"`
+	Hotspot                HotspotState         `json:"hotspot"`
+	Connectivity           Connectivity         `json:"connectivity"`
+	PortalURL              string               `json:"portalUrl,omitempty"`
 	LastError              string               `json:"lastError"`
 }
 
@@ -116,6 +139,10 @@ type Manager struct {
 	lastNotifiedState     *NetworkState
 	credentialSubscribers map[string]chan CredentialPrompt
 	credSubMutex          sync.RWMutex
+	connProbe             ConnectivityProbe
+	connMutex             sync.Mutex
+	connTrigger           chan struct{}
+	traffic               *trafficMonitor
 }
 
 type EventType string
@@ -188,3 +215,31 @@ type WiredIPConfig struct {
 	Gateway string   `json:"gateway"`
 	DNS     string   `json:"dns"`
 }
+
+type IPSettings struct {
+	Method        string   `json:"method"`
+	Addresses     []string `json:"addresses"`
+	Gateway       string   `json:"gateway"`
+	DNS           []string `json:"dns"`
+	DNSSearch     []string `json:"dnsSearch"`
+	IgnoreAutoDNS bool     `json:"ignoreAutoDns"`
+	RouteMetric   int64    `json:"routeMetric"`
+}
+
+type ProxySettings struct {
+	Method      string `json:"method"`
+	PACURL      string `json:"pacUrl,omitempty"`
+	BrowserOnly bool   `json:"browserOnly"`
+}
+
+type ConnectionSettings struct {
+	UUID             string        `json:"uuid"`
+	ID               string        `json:"id"`
+	Type             string        `json:"type"`
+	Interface        string        `json:"interface,omitempty"`
+	IPv4             IPSettings    `json:"ipv4"`
+	IPv6             IPSettings    `json:"ipv6"`
+	MTU              uint32        `json:"mtu"`
+	MACRandomization string        `json:"macRandomization"`
+	Proxy            ProxySettings `json:"proxy"`
+}
@@ -0,0 +1,528 @@
+package network
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	VPNTypeWireGuard = "wireguard"
+	VPNTypeOpenVPN   = "openvpn"
+
+	nmOpenVPNService = "org.freedesktop.NetworkManager.openvpn"
+)
+
+type VPNImportRequest struct {
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// VPNImport is a parsed profile ready to be handed to a backend. Exactly one
+// of WireGuard and OpenVPN is set.
+type VPNImport struct {
+	Name      string
+	WireGuard *WireGuardConfig
+	OpenVPN   *OpenVPNConfig
+}
+
+type WireGuardPeer struct {
+	PublicKey           string
+	PresharedKey        string
+	Endpoint            string
+	AllowedIPs          []string
+	PersistentKeepalive uint32
+}
+
+type WireGuardConfig struct {
+	PrivateKey string
+	Addresses  []string
+	DNS        []string
+	Domains    []string
+	ListenPort uint32
+	MTU        uint32
+	FwMark     uint32
+	Peers      []WireGuardPeer
+}
+
+// FullTunnel reports whether any peer routes all traffic.
+func (c *WireGuardConfig) FullTunnel() bool {
+	for _, peer := range c.Peers {
+		for _, ip := range peer.AllowedIPs {
+			if ip == "0.0.0.0/0" || ip == "::/0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type OpenVPNConfig struct {
+	// Data holds NetworkManager openvpn plugin keys.
+	Data map[string]string
+	// Inline holds embedded <ca>, <cert>, ... blocks keyed like Data; they
+	// are written to files when the profile is created.
+	Inline       map[string]string
+	AuthUserPass bool
+}
+
+var interfaceNameInvalid = regexp.MustCompile(`[^A-Za-z0-9_=+.-]`)
+
+// vpnInterfaceName derives a kernel interface name (at most 15 bytes) from a
+// profile name.
+func vpnInterfaceName(name string) string {
+	iface := interfaceNameInvalid.ReplaceAllString(name, "-")
+	iface = strings.Trim(iface, "-.")
+	if len(iface) > 15 {
+		iface = iface[:15]
+	}
+	if iface == "" {
+		iface = "wg0"
+	}
+	return iface
+}
+
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func detectVPNType(path, content string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".conf":
+		if strings.Contains(content, "[Interface]") {
+			return VPNTypeWireGuard
+		}
+	case ".ovpn":
+		return VPNTypeOpenVPN
+	}
+
+	if strings.Contains(content, "[Interface]") && strings.Contains(content, "[Peer]") {
+		return VPNTypeWireGuard
+	}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (fields[0] == "remote" || fields[0] == "client") {
+			return VPNTypeOpenVPN
+		}
+	}
+	return ""
+}
+
+// ParseVPNImport reads and parses a WireGuard or OpenVPN profile. Relative
+// file references in .ovpn files resolve against the file's directory.
+func ParseVPNImport(req VPNImportRequest) (*VPNImport, error) {
+	content := req.Content
+	if content == "" {
+		if req.Path == "" {
+			return nil, fmt.Errorf("either path or content is required")
+		}
+		data, err := os.ReadFile(req.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", req.Path, err)
+		}
+		content = string(data)
+	}
+
+	name := req.Name
+	if name == "" && req.Path != "" {
+		name = strings.TrimSuffix(filepath.Base(req.Path), filepath.Ext(req.Path))
+	}
+	if name == "" {
+		return nil, fmt.Errorf("name is required when importing from content")
+	}
+
+	vpnType := strings.ToLower(req.Type)
+	if vpnType == "" {
+		vpnType = detectVPNType(req.Path, content)
+	}
+
+	imp := &VPNImport{Name: name}
+	switch vpnType {
+	case VPNTypeWireGuard:
+		cfg, err := parseWireGuardConfig(content)
+		if err != nil {
+			return nil, err
+		}
+		imp.WireGuard = cfg
+	case VPNTypeOpenVPN:
+		baseDir := ""
+		if req.Path != "" {
+			baseDir = filepath.Dir(req.Path)
+		}
+		cfg, err := parseOpenVPNConfig(content, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		imp.OpenVPN = cfg
+	case "":
+		return nil, fmt.Errorf("unable to detect VPN type, set 'type' to wireguard or openvpn")
+	default:
+		return nil, fmt.Errorf("unsupported VPN type: %s", req.Type)
+	}
+
+	return imp, nil
+}
+
+func validWireGuardKey(key string) bool {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(raw) == 32
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseUint32(key, value string) (uint32, error) {
+	if strings.EqualFold(value, "off") {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return uint32(n), nil
+}
+
+// parseWireGuardConfig parses a wg-quick style configuration. wg-quick only
+// directives (PreUp, PostUp, Table, SaveConfig, ...) are ignored.
+func parseWireGuardConfig(content string) (*WireGuardConfig, error) {
+	cfg := &WireGuardConfig{}
+	var peer *WireGuardPeer
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			switch section {
+			case "interface":
+			case "peer":
+				cfg.Peers = append(cfg.Peers, WireGuardPeer{})
+				peer = &cfg.Peers[len(cfg.Peers)-1]
+			default:
+				return nil, fmt.Errorf("line %d: unknown section [%s]", lineNo, section)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch section {
+		case "interface":
+			switch key {
+			case "privatekey":
+				cfg.PrivateKey = value
+			case "address":
+				cfg.Addresses = append(cfg.Addresses, splitList(value)...)
+			case "dns":
+				for _, entry := range splitList(value) {
+					if net.ParseIP(entry) != nil {
+						cfg.DNS = append(cfg.DNS, entry)
+					} else {
+						cfg.Domains = append(cfg.Domains, entry)
+					}
+				}
+			case "listenport":
+				cfg.ListenPort, err = parseUint32("ListenPort", value)
+			case "mtu":
+				cfg.MTU, err = parseUint32("MTU", value)
+			case "fwmark":
+				cfg.FwMark, err = parseUint32("FwMark", value)
+			}
+		case "peer":
+			switch key {
+			case "publickey":
+				peer.PublicKey = value
+			case "presharedkey":
+				peer.PresharedKey = value
+			case "endpoint":
+				peer.Endpoint = value
+			case "allowedips":
+				peer.AllowedIPs = append(peer.AllowedIPs, splitList(value)...)
+			case "persistentkeepalive":
+				peer.PersistentKeepalive, err = parseUint32("PersistentKeepalive", value)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: key outside of a section", lineNo)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+
+	if !validWireGuardKey(cfg.PrivateKey) {
+		return nil, fmt.Errorf("missing or invalid PrivateKey")
+	}
+	for _, addr := range cfg.Addresses {
+		if _, _, err := net.ParseCIDR(addr); err != nil && net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid Address: %s", addr)
+		}
+	}
+	if len(cfg.Peers) == 0 {
+		return nil, fmt.Errorf("no [Peer] section")
+	}
+	for i, p := range cfg.Peers {
+		if !validWireGuardKey(p.PublicKey) {
+			return nil, fmt.Errorf("peer %d: missing or invalid PublicKey", i+1)
+		}
+		if p.PresharedKey != "" && !validWireGuardKey(p.PresharedKey) {
+			return nil, fmt.Errorf("peer %d: invalid PresharedKey", i+1)
+		}
+		for _, ip := range p.AllowedIPs {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return nil, fmt.Errorf("peer %d: invalid AllowedIPs entry: %s", i+1, ip)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+var openVPNInlineKeys = map[string]string{
+	"ca":        "ca",
+	"cert":      "cert",
+	"key":       "key",
+	"tls-auth":  "ta",
+	"tls-crypt": "tls-crypt",
+	"secret":    "static-key",
+}
+
+var openVPNDataKeys = map[string]string{
+	"cipher":          "cipher",
+	"data-ciphers":    "data-ciphers",
+	"auth":            "auth",
+	"remote-cert-tls": "remote-cert-tls",
+	"tun-mtu":         "tunnel-mtu",
+	"mssfix":          "mssfix",
+	"reneg-sec":       "reneg-seconds",
+	"ping":            "ping",
+	"ping-exit":       "ping-exit",
+	"ping-restart":    "ping-restart",
+	"tls-version-min": "tls-version-min",
+	"tls-version-max": "tls-version-max",
+	"fragment":        "fragment",
+}
+
+// parseOpenVPNConfig converts a client .ovpn file into NetworkManager openvpn
+// plugin data. Directives NetworkManager has no equivalent for are ignored.
+func parseOpenVPNConfig(content, baseDir string) (*OpenVPNConfig, error) {
+	cfg := &OpenVPNConfig{
+		Data:   make(map[string]string),
+		Inline: make(map[string]string),
+	}
+
+	resolve := func(path string) string {
+		if path == "[inline]" || filepath.IsAbs(path) || baseDir == "" {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	var remotes []string
+	var keyDirection string
+	var inlineTag string
+	var inline strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inlineTag != "" {
+			if line == "</"+inlineTag+">" {
+				cfg.Inline[openVPNInlineKeys[inlineTag]] = inline.String()
+				inlineTag = ""
+				inline.Reset()
+				continue
+			}
+			inline.WriteString(line)
+			inline.WriteByte('\n')
+			continue
+		}
+
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") && !strings.HasPrefix(line, "</") {
+			tag := line[1 : len(line)-1]
+			if _, ok := openVPNInlineKeys[tag]; !ok {
+				return nil, fmt.Errorf("unsupported inline block <%s>", tag)
+			}
+			inlineTag = tag
+			continue
+		}
+
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		directive := strings.TrimPrefix(fields[0], "--")
+		args := fields[1:]
+		arg := func(i int) string {
+			if i < len(args) {
+				return strings.Trim(args[i], `"'`)
+			}
+			return ""
+		}
+
+		switch directive {
+		case "remote":
+			if arg(0) == "" {
+				return nil, fmt.Errorf("remote requires a host")
+			}
+			remote := arg(0)
+			if arg(1) != "" {
+				remote += ":" + arg(1)
+				if arg(2) != "" {
+					remote += ":" + arg(2)
+				}
+			}
+			remotes = append(remotes, remote)
+		case "port", "rport":
+			cfg.Data["port"] = arg(0)
+		case "proto":
+			if strings.HasPrefix(arg(0), "tcp") {
+				cfg.Data["proto-tcp"] = "yes"
+			}
+		case "dev":
+			switch {
+			case strings.HasPrefix(arg(0), "tap"):
+				cfg.Data["dev-type"] = "tap"
+			case strings.HasPrefix(arg(0), "tun"):
+				cfg.Data["dev-type"] = "tun"
+			}
+			if arg(0) != "tun" && arg(0) != "tap" {
+				cfg.Data["dev"] = arg(0)
+			}
+		case "dev-type":
+			cfg.Data["dev-type"] = arg(0)
+		case "ca", "cert", "key", "tls-auth", "tls-crypt", "secret":
+			nmKey := openVPNInlineKeys[directive]
+			if arg(0) != "" && arg(0) != "[inline]" {
+				cfg.Data[nmKey] = resolve(arg(0))
+			}
+			if arg(1) != "" {
+				switch directive {
+				case "tls-auth":
+					cfg.Data["ta-dir"] = arg(1)
+				case "secret":
+					cfg.Data["static-key-direction"] = arg(1)
+				}
+			}
+		case "key-direction":
+			keyDirection = arg(0)
+		case "auth-user-pass":
+			cfg.AuthUserPass = true
+		case "comp-lzo":
+			if arg(0) == "" {
+				cfg.Data["comp-lzo"] = "adaptive"
+			} else {
+				cfg.Data["comp-lzo"] = arg(0)
+			}
+		case "compress":
+			if arg(0) == "" {
+				cfg.Data["compress"] = "yes"
+			} else {
+				cfg.Data["compress"] = arg(0)
+			}
+		case "float":
+			cfg.Data["float"] = "yes"
+		case "verify-x509-name":
+			kind := arg(1)
+			if kind == "" {
+				kind = "subject"
+			}
+			cfg.Data["verify-x509-name"] = kind + ":" + arg(0)
+		case "pkcs12":
+			return nil, fmt.Errorf("pkcs12 certificates are not supported, convert them to PEM first")
+		default:
+			if nmKey, ok := openVPNDataKeys[directive]; ok && arg(0) != "" {
+				cfg.Data[nmKey] = arg(0)
+			}
+		}
+	}
+
+	if inlineTag != "" {
+		return nil, fmt.Errorf("unterminated inline block <%s>", inlineTag)
+	}
+	if len(remotes) == 0 {
+		return nil, fmt.Errorf("no remote directive")
+	}
+	cfg.Data["remote"] = strings.Join(remotes, ", ")
+	if cfg.Data["dev-type"] == "" {
+		cfg.Data["dev-type"] = "tun"
+	}
+
+	has := func(key string) bool {
+		_, inData := cfg.Data[key]
+		_, inInline := cfg.Inline[key]
+		return inData || inInline
+	}
+
+	if keyDirection != "" {
+		switch {
+		case has("ta") && cfg.Data["ta-dir"] == "":
+			cfg.Data["ta-dir"] = keyDirection
+		case has("static-key") && cfg.Data["static-key-direction"] == "":
+			cfg.Data["static-key-direction"] = keyDirection
+		}
+	}
+
+	switch {
+	case has("static-key"):
+		cfg.Data["connection-type"] = "static-key"
+	case has("cert") && has("key") && cfg.AuthUserPass:
+		cfg.Data["connection-type"] = "password-tls"
+	case has("cert") && has("key"):
+		cfg.Data["connection-type"] = "tls"
+	case cfg.AuthUserPass:
+		cfg.Data["connection-type"] = "password"
+	default:
+		return nil, fmt.Errorf("no supported authentication method (certificates, auth-user-pass or static key)")
+	}
+	if cfg.AuthUserPass {
+		cfg.Data["password-flags"] = "1"
+	}
+
+	return cfg, nil
+}
+
+func (m *Manager) ImportVPN(req VPNImportRequest) (*VPNProfile, error) {
+	imp, err := ParseVPNImport(req)
+	if err != nil {
+		return nil, err
+	}
+	return m.backend.ImportVPN(imp)
+}
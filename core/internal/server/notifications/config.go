@@ -0,0 +1,156 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+)
+
+const (
+	defaultMaxHistory = 300
+	maxHistoryLimit   = 5000
+)
+
+func DefaultSettingsPath() string {
+	return filepath.Join(config.DMSDir("XDG_CONFIG_HOME", ".config"), "notifications.json")
+}
+
+func DefaultHistoryPath() string {
+	return filepath.Join(config.DMSDir("XDG_STATE_HOME", filepath.Join(".local", "state")), "notification-history.json")
+}
+
+func DefaultImageDir() string {
+	return filepath.Join(config.DMSDir("XDG_CACHE_HOME", ".cache"), "notification-images")
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Schedule:   Schedule{Start: "22:00", End: "07:00"},
+		Rules:      []Rule{},
+		MaxHistory: defaultMaxHistory,
+	}
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (s Schedule) Validate() error {
+	if _, err := parseClock(s.Start); err != nil {
+		return err
+	}
+	if _, err := parseClock(s.End); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Schedule) Active(now time.Time) bool {
+	if !s.Enabled {
+		return false
+	}
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(s.End)
+	if err != nil || start == end {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func (r Rule) Validate() error {
+	if strings.TrimSpace(r.App) == "" {
+		return fmt.Errorf("rule app is required")
+	}
+	switch r.Urgency {
+	case "", UrgencyLow, UrgencyNormal, UrgencyCritical:
+	default:
+		return fmt.Errorf("invalid urgency: %s", r.Urgency)
+	}
+	if r.Timeout != nil && *r.Timeout < 0 {
+		return fmt.Errorf("invalid timeout: %d", *r.Timeout)
+	}
+	return nil
+}
+
+func (r Rule) Matches(n *Notification) bool {
+	return strings.EqualFold(r.App, n.AppName) || (n.DesktopEntry != "" && strings.EqualFold(r.App, n.DesktopEntry))
+}
+
+func (s Settings) Validate() error {
+	if err := s.Schedule.Validate(); err != nil {
+		return err
+	}
+	for _, rule := range s.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.MaxHistory < 0 || s.MaxHistory > maxHistoryLimit {
+		return fmt.Errorf("maxHistory must be between 0 and %d", maxHistoryLimit)
+	}
+	return nil
+}
+
+// LoadSettings reads saved settings. A missing file yields DefaultSettings,
+// and fields absent from the file keep their default values.
+func LoadSettings(path string) (Settings, error) {
+	settings := DefaultSettings()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("parse %s: %w", path, err)
+	}
+	if settings.Rules == nil {
+		settings.Rules = []Rule{}
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings(), fmt.Errorf("invalid settings %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+type historyFile struct {
+	NextID  uint32         `json:"nextId"`
+	History []Notification `json:"history"`
+}
+
+func loadHistory(path string) (historyFile, error) {
+	var file historyFile
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return file, err
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return historyFile{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return file, nil
+}
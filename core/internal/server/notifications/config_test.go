@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleActive(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse("15:04", clock)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	overnight := Schedule{Enabled: true, Start: "22:00", End: "07:00"}
+	assert.True(t, overnight.Active(at("23:30")))
+	assert.True(t, overnight.Active(at("06:59")))
+	assert.False(t, overnight.Active(at("07:00")))
+	assert.False(t, overnight.Active(at("12:00")))
+
+	daytime := Schedule{Enabled: true, Start: "09:00", End: "17:00"}
+	assert.True(t, daytime.Active(at("09:00")))
+	assert.False(t, daytime.Active(at("17:00")))
+
+	disabled := overnight
+	disabled.Enabled = false
+	assert.False(t, disabled.Active(at("23:30")))
+}
+
+func TestScheduleValidate(t *testing.T) {
+	assert.NoError(t, Schedule{Start: "00:00", End: "23:59"}.Validate())
+	assert.Error(t, Schedule{Start: "24:00", End: "07:00"}.Validate())
+	assert.Error(t, Schedule{Start: "22:00", End: "7am"}.Validate())
+}
+
+func TestRuleMatches(t *testing.T) {
+	rule := Rule{App: "firefox"}
+	assert.True(t, rule.Matches(&Notification{AppName: "Firefox"}))
+	assert.True(t, rule.Matches(&Notification{AppName: "Web", DesktopEntry: "firefox"}))
+	assert.False(t, rule.Matches(&Notification{AppName: "Thunderbird"}))
+
+	negative := -1
+	assert.Error(t, Rule{}.Validate())
+	assert.Error(t, Rule{App: "x", Urgency: "loud"}.Validate())
+	assert.Error(t, Rule{App: "x", Timeout: &negative}.Validate())
+}
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+
+	settings, err := LoadSettings(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSettings(), settings)
+
+	path := filepath.Join(dir, "notifications.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"doNotDisturb": true}`), 0644))
+	settings, err = LoadSettings(path)
+	require.NoError(t, err)
+	assert.True(t, settings.DoNotDisturb)
+	assert.Equal(t, defaultMaxHistory, settings.MaxHistory)
+	assert.Equal(t, "22:00", settings.Schedule.Start)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"schedule": {"start": "nope", "end": "07:00"}}`), 0644))
+	settings, err = LoadSettings(path)
+	assert.Error(t, err)
+	assert.Equal(t, DefaultSettings(), settings)
+}
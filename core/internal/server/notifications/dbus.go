@@ -0,0 +1,269 @@
+package notifications
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusName     = "org.freedesktop.Notifications"
+	dbusPath     = "/org/freedesktop/Notifications"
+	dbusIface    = "org.freedesktop.Notifications"
+	specVersion  = "1.2"
+	serverName   = "DankMaterialShell"
+	serverVendor = "AvengeMedia"
+)
+
+var capabilities = []string{"actions", "action-icons", "body", "body-hyperlinks", "body-markup", "icon-static", "persistence"}
+
+const introspectXML = `
+<node>
+	<interface name="org.freedesktop.Notifications">
+		<method name="GetCapabilities">
+			<arg direction="out" type="as" name="capabilities"/>
+		</method>
+		<method name="Notify">
+			<arg direction="in" type="s" name="app_name"/>
+			<arg direction="in" type="u" name="replaces_id"/>
+			<arg direction="in" type="s" name="app_icon"/>
+			<arg direction="in" type="s" name="summary"/>
+			<arg direction="in" type="s" name="body"/>
+			<arg direction="in" type="as" name="actions"/>
+			<arg direction="in" type="a{sv}" name="hints"/>
+			<arg direction="in" type="i" name="expire_timeout"/>
+			<arg direction="out" type="u" name="id"/>
+		</method>
+		<method name="CloseNotification">
+			<arg direction="in" type="u" name="id"/>
+		</method>
+		<method name="GetServerInformation">
+			<arg direction="out" type="s" name="name"/>
+			<arg direction="out" type="s" name="vendor"/>
+			<arg direction="out" type="s" name="version"/>
+			<arg direction="out" type="s" name="spec_version"/>
+		</method>
+		<signal name="NotificationClosed">
+			<arg type="u" name="id"/>
+			<arg type="u" name="reason"/>
+		</signal>
+		<signal name="ActionInvoked">
+			<arg type="u" name="id"/>
+			<arg type="s" name="action_key"/>
+		</signal>
+	</interface>
+	<interface name="org.freedesktop.DBus.Introspectable">
+		<method name="Introspect">
+			<arg direction="out" type="s" name="data"/>
+		</method>
+	</interface>
+</node>`
+
+// server is exported as org.freedesktop.Notifications.
+type server struct {
+	m *Manager
+}
+
+func (s *server) GetCapabilities() ([]string, *dbus.Error) {
+	return capabilities, nil
+}
+
+func (s *server) Notify(appName string, replacesID uint32, appIcon, summary, body string, actions []string, hints map[string]dbus.Variant, expireTimeout int32) (uint32, *dbus.Error) {
+	n := Notification{
+		AppName:       appName,
+		AppIcon:       appIcon,
+		Summary:       summary,
+		Body:          body,
+		Actions:       parseActions(actions),
+		ExpireTimeout: expireTimeout,
+	}
+	imageData := parseHints(hints, &n)
+	return s.m.Notify(n, replacesID, imageData), nil
+}
+
+func (s *server) CloseNotification(id uint32) *dbus.Error {
+	s.m.close(id, CloseByCall)
+	return nil
+}
+
+func (s *server) GetServerInformation() (string, string, string, string, *dbus.Error) {
+	return serverName, serverVendor, "1.0", specVersion, nil
+}
+
+func (s *server) Introspect() (string, *dbus.Error) {
+	return introspectXML, nil
+}
+
+func parseActions(raw []string) []Action {
+	actions := make([]Action, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		actions = append(actions, Action{ID: raw[i], Label: raw[i+1]})
+	}
+	return actions
+}
+
+type rawImage struct {
+	Width         int32
+	Height        int32
+	RowStride     int32
+	HasAlpha      bool
+	BitsPerSample int32
+	Channels      int32
+	Data          []byte
+}
+
+// parseHints copies the hints we understand onto n and returns raw image
+// data, if any, for the manager to store.
+func parseHints(hints map[string]dbus.Variant, n *Notification) *rawImage {
+	n.Urgency = UrgencyNormal
+	if v, ok := hints["urgency"]; ok {
+		switch u := v.Value().(type) {
+		case byte:
+			n.Urgency = urgencyName(int(u))
+		case int32:
+			n.Urgency = urgencyName(int(u))
+		case uint32:
+			n.Urgency = urgencyName(int(u))
+		}
+	}
+
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			if v, ok := hints[key]; ok {
+				if s, ok := v.Value().(string); ok {
+					return s
+				}
+			}
+		}
+		return ""
+	}
+	flag := func(key string) bool {
+		if v, ok := hints[key]; ok {
+			b, _ := v.Value().(bool)
+			return b
+		}
+		return false
+	}
+
+	n.Category = str("category")
+	n.DesktopEntry = str("desktop-entry")
+	n.SoundFile = str("sound-file")
+	n.SuppressSound = flag("suppress-sound")
+	n.ActionIcons = flag("action-icons")
+	n.Resident = flag("resident")
+	n.Transient = flag("transient")
+	n.Image = imagePath(str("image-path", "image_path"))
+
+	for _, key := range []string{"image-data", "image_data", "icon_data"} {
+		if v, ok := hints[key]; ok {
+			var img rawImage
+			if err := v.Store(&img); err == nil {
+				return &img
+			}
+		}
+	}
+	return nil
+}
+
+func urgencyName(level int) string {
+	switch level {
+	case 0:
+		return UrgencyLow
+	case 2:
+		return UrgencyCritical
+	default:
+		return UrgencyNormal
+	}
+}
+
+// imagePath turns file:// URIs into paths; icon names pass through.
+func imagePath(value string) string {
+	if strings.HasPrefix(value, "file://") {
+		if u, err := url.Parse(value); err == nil {
+			return u.Path
+		}
+	}
+	return value
+}
+
+func decodeRawImage(img *rawImage) (*image.NRGBA, error) {
+	w, h, stride := int(img.Width), int(img.Height), int(img.RowStride)
+	channels := int(img.Channels)
+	if w <= 0 || h <= 0 || img.BitsPerSample != 8 || (channels != 3 && channels != 4) {
+		return nil, fmt.Errorf("unsupported image format")
+	}
+	if stride < w*channels || len(img.Data) < stride*(h-1)+w*channels {
+		return nil, fmt.Errorf("image data too short")
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src := img.Data[y*stride+x*channels:]
+			dst := out.Pix[(y*w+x)*4:]
+			dst[0], dst[1], dst[2], dst[3] = src[0], src[1], src[2], 0xff
+			if channels == 4 && img.HasAlpha {
+				dst[3] = src[3]
+			}
+		}
+	}
+	return out, nil
+}
+
+func writeImage(dir string, id uint32, img *rawImage) (string, error) {
+	decoded, err := decodeRawImage(img)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.png", id))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(f, decoded); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func (m *Manager) exportServer() error {
+	s := &server{m: m}
+	if err := m.conn.Export(s, dbusPath, dbusIface); err != nil {
+		return fmt.Errorf("notification server export failed: %w", err)
+	}
+	if err := m.conn.Export(s, dbusPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return fmt.Errorf("introspection export failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) emitClosed(id uint32, reason CloseReason) {
+	if m.conn == nil {
+		return
+	}
+	if err := m.conn.Emit(dbusPath, dbusIface+".NotificationClosed", id, uint32(reason)); err != nil {
+		log.Debugf("[Notifications] failed to emit NotificationClosed: %v", err)
+	}
+}
+
+func (m *Manager) emitActionInvoked(id uint32, action string) {
+	if m.conn == nil {
+		return
+	}
+	if err := m.conn.Emit(dbusPath, dbusIface+".ActionInvoked", id, action); err != nil {
+		log.Debugf("[Notifications] failed to emit ActionInvoked: %v", err)
+	}
+}
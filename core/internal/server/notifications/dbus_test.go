@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActions(t *testing.T) {
+	actions := parseActions([]string{"default", "", "reply", "Reply", "dangling"})
+	assert.Equal(t, []Action{{ID: "default"}, {ID: "reply", Label: "Reply"}}, actions)
+}
+
+func TestParseHints(t *testing.T) {
+	var n Notification
+	img := parseHints(map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(byte(2)),
+		"category":      dbus.MakeVariant("email.arrived"),
+		"desktop-entry": dbus.MakeVariant("thunderbird"),
+		"resident":      dbus.MakeVariant(true),
+		"image-path":    dbus.MakeVariant("file:///tmp/avatar%20one.png"),
+	}, &n)
+
+	assert.Nil(t, img)
+	assert.Equal(t, UrgencyCritical, n.Urgency)
+	assert.Equal(t, "email.arrived", n.Category)
+	assert.Equal(t, "thunderbird", n.DesktopEntry)
+	assert.True(t, n.Resident)
+	assert.False(t, n.Transient)
+	assert.Equal(t, "/tmp/avatar one.png", n.Image)
+}
+
+func TestParseHintsImageData(t *testing.T) {
+	var n Notification
+	raw := []interface{}{int32(2), int32(1), int32(6), false, int32(8), int32(3), []byte{255, 0, 0, 0, 255, 0}}
+	img := parseHints(map[string]dbus.Variant{"image-data": dbus.MakeVariant(raw)}, &n)
+	require.NotNil(t, img)
+	assert.Equal(t, UrgencyNormal, n.Urgency)
+
+	decoded, err := decodeRawImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{255, 0, 0, 255, 0, 255, 0, 255}, decoded.Pix)
+
+	img.Data = img.Data[:3]
+	_, err = decodeRawImage(img)
+	assert.Error(t, err)
+}
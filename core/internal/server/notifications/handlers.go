@@ -0,0 +1,234 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type Request struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req Request, manager *Manager) {
+	if manager == nil {
+		models.RespondError(conn, req.ID, "notifications manager not initialized")
+		return
+	}
+
+	switch req.Method {
+	case "notifications.getState":
+		models.Respond(conn, req.ID, manager.GetState())
+	case "notifications.dismiss":
+		handleDismiss(conn, req, manager)
+	case "notifications.remove":
+		handleRemove(conn, req, manager)
+	case "notifications.clearHistory":
+		manager.ClearHistory()
+		models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "history cleared"})
+	case "notifications.invokeAction":
+		handleInvokeAction(conn, req, manager)
+	case "notifications.setDaemon":
+		handleSetDaemon(conn, req, manager)
+	case "notifications.setDoNotDisturb":
+		handleSetDoNotDisturb(conn, req, manager)
+	case "notifications.setSchedule":
+		handleSetSchedule(conn, req, manager)
+	case "notifications.setRule":
+		handleSetRule(conn, req, manager)
+	case "notifications.removeRule":
+		handleRemoveRule(conn, req, manager)
+	case "notifications.subscribe":
+		handleSubscribe(conn, req, manager)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func idParam(req Request) (uint32, bool) {
+	id, ok := req.Params["id"].(float64)
+	if !ok || id <= 0 {
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// decodeParam round-trips a raw parameter through JSON into a typed value.
+func decodeParam(raw interface{}, target interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func handleDismiss(conn net.Conn, req Request, manager *Manager) {
+	id, ok := idParam(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	if err := manager.Dismiss(id); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "notification dismissed"})
+}
+
+func handleRemove(conn net.Conn, req Request, manager *Manager) {
+	var ids []uint32
+	if raw, ok := req.Params["ids"]; ok {
+		if err := decodeParam(raw, &ids); err != nil {
+			models.RespondErr(conn, req.ID, models.NewParamError("invalid 'ids' parameter: %v", err))
+			return
+		}
+	} else if id, ok := idParam(req); ok {
+		ids = []uint32{id}
+	}
+	if len(ids) == 0 {
+		models.RespondError(conn, req.ID, "missing 'id' or 'ids' parameter")
+		return
+	}
+
+	if err := manager.Remove(ids); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "notifications removed"})
+}
+
+func handleInvokeAction(conn net.Conn, req Request, manager *Manager) {
+	id, ok := idParam(req)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	action, ok := req.Params["action"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'action' parameter"))
+		return
+	}
+
+	if err := manager.InvokeAction(id, action); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "action invoked"})
+}
+
+func handleSetDaemon(conn net.Conn, req Request, manager *Manager) {
+	enabled, ok := req.Params["enabled"].(bool)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'enabled' parameter"))
+		return
+	}
+
+	if err := manager.SetDaemon(enabled); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "notification daemon updated"})
+}
+
+func handleSetDoNotDisturb(conn net.Conn, req Request, manager *Manager) {
+	enabled, ok := req.Params["enabled"].(bool)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'enabled' parameter"))
+		return
+	}
+
+	if err := manager.SetDoNotDisturb(enabled); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "do not disturb updated"})
+}
+
+func handleSetSchedule(conn net.Conn, req Request, manager *Manager) {
+	schedule := manager.GetState().Settings.Schedule
+	if enabled, ok := req.Params["enabled"].(bool); ok {
+		schedule.Enabled = enabled
+	}
+	if start, ok := req.Params["start"].(string); ok {
+		schedule.Start = start
+	}
+	if end, ok := req.Params["end"].(string); ok {
+		schedule.End = end
+	}
+
+	if err := manager.SetSchedule(schedule); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "schedule updated"})
+}
+
+func handleSetRule(conn net.Conn, req Request, manager *Manager) {
+	var rule Rule
+	if err := decodeParam(req.Params, &rule); err != nil {
+		models.RespondError(conn, req.ID, fmt.Sprintf("invalid rule: %v", err))
+		return
+	}
+
+	if err := manager.SetRule(rule); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "rule set"})
+}
+
+func handleRemoveRule(conn net.Conn, req Request, manager *Manager) {
+	app, ok := req.Params["app"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'app' parameter"))
+		return
+	}
+
+	if err := manager.RemoveRule(app); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "rule removed"})
+}
+
+func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
+	clientID := fmt.Sprintf("client-%p", conn)
+	eventChan := manager.Subscribe(clientID)
+	defer manager.Unsubscribe(clientID)
+
+	state := manager.GetState()
+	initial := Event{Type: EventState, State: &state}
+	if err := json.NewEncoder(conn).Encode(models.Response[Event]{
+		ID:     req.ID,
+		Result: &initial,
+	}); err != nil {
+		return
+	}
+
+	for event := range eventChan {
+		if err := json.NewEncoder(conn).Encode(models.Response[Event]{
+			Result: &event,
+		}); err != nil {
+			return
+		}
+	}
+}
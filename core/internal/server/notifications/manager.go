@@ -0,0 +1,629 @@
+package notifications
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	defaultTimeoutMs = 5000
+	saveDelay        = time.Second
+	scheduleInterval = 30 * time.Second
+)
+
+func NewManager() (*Manager, error) {
+	m := newManager(DefaultSettingsPath(), DefaultHistoryPath(), DefaultImageDir())
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+	m.conn = conn
+
+	if err := m.exportServer(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+
+	m.wg.Add(2)
+	go m.watchName(signals)
+	go m.scheduleLoop()
+
+	if m.settings.Daemon {
+		if err := m.claimName(); err != nil {
+			log.Warnf("[Notifications] %v", err)
+		}
+	}
+
+	return m, nil
+}
+
+// claimName takes org.freedesktop.Notifications only if it is free. It never
+// replaces or queues behind another daemon, such as the shell's own server.
+func (m *Manager) claimName() error {
+	reply, err := m.conn.RequestName(dbusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", dbusName, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner && reply != dbus.RequestNameReplyAlreadyOwner {
+		return fmt.Errorf("%s is owned by another daemon", dbusName)
+	}
+	m.setOwned(true)
+	return nil
+}
+
+func (m *Manager) releaseName() error {
+	if _, err := m.conn.ReleaseName(dbusName); err != nil {
+		return fmt.Errorf("failed to release %s: %w", dbusName, err)
+	}
+	m.setOwned(false)
+	return nil
+}
+
+// SetDaemon turns serving org.freedesktop.Notifications on or off. The
+// setting is only saved once the name was claimed or released.
+func (m *Manager) SetDaemon(enabled bool) error {
+	if m.conn == nil {
+		return fmt.Errorf("not connected to the session bus")
+	}
+
+	var err error
+	if enabled {
+		err = m.claimName()
+	} else {
+		err = m.releaseName()
+	}
+	if err != nil {
+		return err
+	}
+
+	return m.updateSettings(func(s *Settings) error {
+		s.Daemon = enabled
+		return nil
+	})
+}
+
+func newManager(settingsPath, historyPath, imageDir string) *Manager {
+	settings, err := LoadSettings(settingsPath)
+	if err != nil {
+		log.Warnf("[Notifications] %v", err)
+	}
+
+	file, err := loadHistory(historyPath)
+	if err != nil {
+		log.Warnf("[Notifications] %v", err)
+	}
+
+	m := &Manager{
+		settings:     settings,
+		settingsPath: settingsPath,
+		historyPath:  historyPath,
+		imageDir:     imageDir,
+		nextID:       file.NextID,
+		active:       make(map[uint32]*Notification),
+		history:      file.History,
+		timers:       make(map[uint32]*time.Timer),
+		subscribers:  make(map[string]chan Event),
+		stopChan:     make(chan struct{}),
+	}
+	if m.history == nil {
+		m.history = []Notification{}
+	}
+	for _, n := range m.history {
+		if n.ID >= m.nextID {
+			m.nextID = n.ID + 1
+		}
+	}
+	m.dndActive = m.computeDND(time.Now())
+	m.pruneImages()
+
+	return m
+}
+
+func (m *Manager) watchName(signals chan *dbus.Signal) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case sig, ok := <-signals:
+			if !ok {
+				return
+			}
+			if len(sig.Body) == 0 || sig.Body[0] != dbusName {
+				continue
+			}
+			switch sig.Name {
+			case "org.freedesktop.DBus.NameAcquired":
+				m.setOwned(true)
+			case "org.freedesktop.DBus.NameLost":
+				m.setOwned(false)
+			}
+		}
+	}
+}
+
+func (m *Manager) setOwned(owned bool) {
+	m.mutex.Lock()
+	changed := m.owned != owned
+	m.owned = owned
+	m.mutex.Unlock()
+
+	if changed {
+		log.Infof("[Notifications] %s owned: %v", dbusName, owned)
+		m.broadcastState()
+	}
+}
+
+func (m *Manager) scheduleLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.updateDND()
+		}
+	}
+}
+
+func (m *Manager) computeDND(now time.Time) bool {
+	return m.settings.DoNotDisturb || m.settings.Schedule.Active(now)
+}
+
+func (m *Manager) updateDND() {
+	m.mutex.Lock()
+	active := m.computeDND(time.Now())
+	changed := active != m.dndActive
+	m.dndActive = active
+	m.mutex.Unlock()
+
+	if changed {
+		m.broadcastState()
+	}
+}
+
+func effectiveTimeout(n *Notification, override *int) int {
+	switch {
+	case override != nil:
+		return *override
+	case n.ExpireTimeout > 0:
+		return int(n.ExpireTimeout)
+	case n.ExpireTimeout == 0 || n.Urgency == UrgencyCritical || n.Resident:
+		return 0
+	default:
+		return defaultTimeoutMs
+	}
+}
+
+// Notify stores a notification and returns its id. replacesID reuses an
+// existing id; an unknown one is treated like a new notification, as the
+// spec asks.
+func (m *Manager) Notify(n Notification, replacesID uint32, img *rawImage) uint32 {
+	m.mutex.Lock()
+
+	replaced := replacesID != 0 && m.existsLocked(replacesID)
+	if replaced {
+		n.ID = replacesID
+	} else {
+		n.ID = m.allocateIDLocked()
+	}
+	if n.Actions == nil {
+		n.Actions = []Action{}
+	}
+	if n.Urgency == "" {
+		n.Urgency = UrgencyNormal
+	}
+	n.Time = time.Now().UnixMilli()
+
+	mute := false
+	var timeoutOverride *int
+	for _, rule := range m.settings.Rules {
+		if !rule.Matches(&n) {
+			continue
+		}
+		mute = rule.Mute
+		if rule.Urgency != "" {
+			n.Urgency = rule.Urgency
+		}
+		timeoutOverride = rule.Timeout
+		break
+	}
+	n.Timeout = effectiveTimeout(&n, timeoutOverride)
+	n.Popup = !mute && (!m.dndActive || n.Urgency == UrgencyCritical)
+
+	var oldImage string
+	if old := m.findLocked(n.ID); replaced && old != nil {
+		oldImage = old.Image
+	}
+	if img != nil {
+		if path, err := writeImage(m.imageDir, n.ID, img); err == nil {
+			n.Image = path
+		} else {
+			log.Debugf("[Notifications] dropping image of %d: %v", n.ID, err)
+		}
+	}
+	if oldImage != "" && oldImage != n.Image {
+		m.removeImage(oldImage)
+	}
+
+	if timer, ok := m.timers[n.ID]; ok {
+		timer.Stop()
+		delete(m.timers, n.ID)
+	}
+
+	current := n
+	m.active[n.ID] = &current
+	if n.Timeout > 0 {
+		id := n.ID
+		m.timers[id] = time.AfterFunc(time.Duration(n.Timeout)*time.Millisecond, func() {
+			m.closeIf(id, CloseExpired, &current)
+		})
+	}
+
+	m.history = slices.DeleteFunc(m.history, func(h Notification) bool { return h.ID == n.ID })
+	var trimmed []Notification
+	if !n.Transient {
+		m.history = append([]Notification{n}, m.history...)
+		if len(m.history) > m.settings.MaxHistory {
+			trimmed = m.history[m.settings.MaxHistory:]
+			m.history = slices.Clone(m.history[:m.settings.MaxHistory])
+		}
+	}
+	for _, t := range trimmed {
+		if _, open := m.active[t.ID]; !open {
+			m.removeImage(t.Image)
+		}
+	}
+	m.mutex.Unlock()
+
+	m.broadcast(Event{Type: EventNotified, Notification: &n, Replaced: replaced})
+	m.scheduleSave()
+	return n.ID
+}
+
+func (m *Manager) allocateIDLocked() uint32 {
+	if m.nextID == 0 {
+		m.nextID = 1
+	}
+	id := m.nextID
+	m.nextID++
+	return id
+}
+
+func (m *Manager) existsLocked(id uint32) bool {
+	return m.findLocked(id) != nil
+}
+
+func (m *Manager) findLocked(id uint32) *Notification {
+	if n, ok := m.active[id]; ok {
+		return n
+	}
+	for i := range m.history {
+		if m.history[i].ID == id {
+			return &m.history[i]
+		}
+	}
+	return nil
+}
+
+func (m *Manager) close(id uint32, reason CloseReason) bool {
+	return m.closeIf(id, reason, nil)
+}
+
+// closeIf closes an open notification. A non-nil expected guards against
+// closing a notification that replaced the one a timer was started for.
+func (m *Manager) closeIf(id uint32, reason CloseReason, expected *Notification) bool {
+	m.mutex.Lock()
+	n, ok := m.active[id]
+	if !ok || (expected != nil && n != expected) {
+		m.mutex.Unlock()
+		return false
+	}
+	delete(m.active, id)
+	if timer, ok := m.timers[id]; ok {
+		timer.Stop()
+		delete(m.timers, id)
+	}
+	if n.Transient {
+		m.removeImage(n.Image)
+	}
+	m.mutex.Unlock()
+
+	m.emitClosed(id, reason)
+	m.broadcast(Event{Type: EventClosed, ID: id, Reason: reason.String()})
+	return true
+}
+
+func (m *Manager) Dismiss(id uint32) error {
+	if m.close(id, CloseDismissed) {
+		return nil
+	}
+
+	m.mutex.RLock()
+	exists := m.existsLocked(id)
+	m.mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("notification not found: %d", id)
+	}
+	return nil
+}
+
+func (m *Manager) InvokeAction(id uint32, action string) error {
+	m.mutex.RLock()
+	var n Notification
+	found := m.findLocked(id)
+	if found != nil {
+		n = *found
+	}
+	m.mutex.RUnlock()
+
+	if found == nil {
+		return fmt.Errorf("notification not found: %d", id)
+	}
+	if !slices.ContainsFunc(n.Actions, func(a Action) bool { return a.ID == action }) {
+		return fmt.Errorf("notification %d has no action %q", id, action)
+	}
+
+	m.emitActionInvoked(id, action)
+	if !n.Resident {
+		m.close(id, CloseDismissed)
+	}
+	return nil
+}
+
+// Remove deletes notifications from history, dismissing any still open.
+func (m *Manager) Remove(ids []uint32) error {
+	m.mutex.Lock()
+	var removed []Notification
+	m.history = slices.DeleteFunc(m.history, func(n Notification) bool {
+		if slices.Contains(ids, n.ID) {
+			removed = append(removed, n)
+			return true
+		}
+		return false
+	})
+	var open []uint32
+	for _, id := range ids {
+		if _, ok := m.active[id]; ok {
+			open = append(open, id)
+		}
+	}
+	m.mutex.Unlock()
+
+	if len(removed) == 0 && len(open) == 0 {
+		return fmt.Errorf("notifications not found: %v", ids)
+	}
+
+	for _, id := range open {
+		m.close(id, CloseDismissed)
+	}
+	removedIDs := make([]uint32, 0, len(removed))
+	for _, n := range removed {
+		m.removeImage(n.Image)
+		removedIDs = append(removedIDs, n.ID)
+	}
+
+	m.broadcast(Event{Type: EventRemoved, IDs: removedIDs})
+	m.scheduleSave()
+	return nil
+}
+
+func (m *Manager) ClearHistory() {
+	m.mutex.Lock()
+	history := m.history
+	m.history = []Notification{}
+	open := make([]uint32, 0, len(m.active))
+	for id := range m.active {
+		open = append(open, id)
+	}
+	m.mutex.Unlock()
+
+	for _, id := range open {
+		m.close(id, CloseDismissed)
+	}
+	for _, n := range history {
+		m.removeImage(n.Image)
+	}
+
+	m.broadcastState()
+	m.scheduleSave()
+}
+
+func (m *Manager) SetDoNotDisturb(enabled bool) error {
+	return m.updateSettings(func(s *Settings) error {
+		s.DoNotDisturb = enabled
+		return nil
+	})
+}
+
+func (m *Manager) SetSchedule(schedule Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	return m.updateSettings(func(s *Settings) error {
+		s.Schedule = schedule
+		return nil
+	})
+}
+
+func (m *Manager) SetRule(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return m.updateSettings(func(s *Settings) error {
+		s.Rules = slices.DeleteFunc(s.Rules, func(r Rule) bool { return strings.EqualFold(r.App, rule.App) })
+		s.Rules = append(s.Rules, rule)
+		return nil
+	})
+}
+
+func (m *Manager) RemoveRule(app string) error {
+	return m.updateSettings(func(s *Settings) error {
+		before := len(s.Rules)
+		s.Rules = slices.DeleteFunc(s.Rules, func(r Rule) bool { return strings.EqualFold(r.App, app) })
+		if len(s.Rules) == before {
+			return fmt.Errorf("no rule for app: %s", app)
+		}
+		return nil
+	})
+}
+
+func (m *Manager) updateSettings(update func(*Settings) error) error {
+	m.mutex.Lock()
+	settings := m.settings
+	settings.Rules = slices.Clone(m.settings.Rules)
+	if err := update(&settings); err != nil {
+		m.mutex.Unlock()
+		return err
+	}
+	m.settings = settings
+	m.dndActive = m.computeDND(time.Now())
+	m.mutex.Unlock()
+
+	if err := config.WriteJSON(m.settingsPath, settings); err != nil {
+		log.Warnf("[Notifications] failed to save settings: %v", err)
+	}
+
+	m.broadcastState()
+	return nil
+}
+
+func (m *Manager) GetState() State {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	state := State{
+		Available:    true,
+		Owned:        m.owned,
+		DoNotDisturb: m.dndActive,
+		Settings:     m.settings,
+		Active:       make([]Notification, 0, len(m.active)),
+		History:      slices.Clone(m.history),
+	}
+	state.Settings.Rules = slices.Clone(m.settings.Rules)
+	for _, n := range m.active {
+		state.Active = append(state.Active, *n)
+	}
+	slices.SortFunc(state.Active, func(a, b Notification) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return state
+}
+
+func (m *Manager) removeImage(path string) {
+	if path == "" || filepath.Dir(path) != m.imageDir {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Debugf("[Notifications] failed to remove image %s: %v", path, err)
+	}
+}
+
+// pruneImages removes cached images no longer referenced by history, left
+// behind by transient notifications open when the daemon stopped.
+func (m *Manager) pruneImages() {
+	entries, err := os.ReadDir(m.imageDir)
+	if err != nil {
+		return
+	}
+
+	referenced := make(map[string]bool, len(m.history))
+	for _, n := range m.history {
+		referenced[n.Image] = true
+	}
+	for _, entry := range entries {
+		path := filepath.Join(m.imageDir, entry.Name())
+		if !referenced[path] {
+			m.removeImage(path)
+		}
+	}
+}
+
+func (m *Manager) scheduleSave() {
+	m.saveMutex.Lock()
+	defer m.saveMutex.Unlock()
+
+	if m.saveTimer == nil {
+		m.saveTimer = time.AfterFunc(saveDelay, m.saveHistory)
+	}
+}
+
+func (m *Manager) saveHistory() {
+	m.saveMutex.Lock()
+	m.saveTimer = nil
+	m.saveMutex.Unlock()
+
+	m.mutex.RLock()
+	file := historyFile{NextID: m.nextID, History: slices.Clone(m.history)}
+	m.mutex.RUnlock()
+
+	if err := config.WriteJSON(m.historyPath, file); err != nil {
+		log.Warnf("[Notifications] failed to save history: %v", err)
+	}
+}
+
+func (m *Manager) broadcastState() {
+	state := m.GetState()
+	m.broadcast(Event{Type: EventState, State: &state})
+}
+
+func (m *Manager) broadcast(event Event) {
+	m.subMutex.RLock()
+	defer m.subMutex.RUnlock()
+
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stopChan)
+		m.wg.Wait()
+
+		m.mutex.Lock()
+		for id, timer := range m.timers {
+			timer.Stop()
+			delete(m.timers, id)
+		}
+		m.mutex.Unlock()
+
+		m.saveMutex.Lock()
+		pending := m.saveTimer != nil && m.saveTimer.Stop()
+		m.saveMutex.Unlock()
+		if pending {
+			m.saveHistory()
+		}
+
+		if m.conn != nil {
+			m.conn.Close()
+		}
+
+		m.subMutex.Lock()
+		for id, ch := range m.subscribers {
+			close(ch)
+			delete(m.subscribers, id)
+		}
+		m.subMutex.Unlock()
+	})
+}
@@ -0,0 +1,211 @@
+package notifications
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testManager(t *testing.T, dir string) *Manager {
+	t.Helper()
+	m := newManager(filepath.Join(dir, "notifications.json"), filepath.Join(dir, "history.json"), filepath.Join(dir, "images"))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func TestNotifyAndReplace(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	first := m.Notify(Notification{AppName: "mail", Summary: "one", ExpireTimeout: -1}, 0, nil)
+	second := m.Notify(Notification{AppName: "mail", Summary: "two", ExpireTimeout: -1}, 0, nil)
+	assert.Equal(t, uint32(1), first)
+	assert.Equal(t, uint32(2), second)
+
+	replaced := m.Notify(Notification{AppName: "mail", Summary: "one again", ExpireTimeout: -1}, first, nil)
+	assert.Equal(t, first, replaced)
+
+	unknown := m.Notify(Notification{AppName: "mail", Summary: "three", ExpireTimeout: -1}, 99, nil)
+	assert.Equal(t, uint32(3), unknown)
+
+	state := m.GetState()
+	require.Len(t, state.Active, 3)
+	require.Len(t, state.History, 3)
+	assert.Equal(t, "three", state.History[0].Summary)
+	assert.Equal(t, "one again", state.History[1].Summary)
+	assert.Equal(t, defaultTimeoutMs, state.Active[0].Timeout)
+	assert.True(t, state.Active[0].Popup)
+}
+
+func TestNotifyTimeouts(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	persistent := m.Notify(Notification{ExpireTimeout: 0}, 0, nil)
+	critical := m.Notify(Notification{ExpireTimeout: -1, Urgency: UrgencyCritical}, 0, nil)
+	explicit := m.Notify(Notification{ExpireTimeout: 1500}, 0, nil)
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	assert.Equal(t, 0, m.active[persistent].Timeout)
+	assert.Equal(t, 0, m.active[critical].Timeout)
+	assert.Equal(t, 1500, m.active[explicit].Timeout)
+	assert.NotContains(t, m.timers, persistent)
+	assert.Contains(t, m.timers, explicit)
+}
+
+func TestNotifyExpires(t *testing.T) {
+	m := testManager(t, t.TempDir())
+	events := m.Subscribe("test")
+
+	id := m.Notify(Notification{AppName: "app", ExpireTimeout: 10}, 0, nil)
+
+	notified := <-events
+	assert.Equal(t, EventNotified, notified.Type)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, EventClosed, event.Type)
+		assert.Equal(t, id, event.ID)
+		assert.Equal(t, "expired", event.Reason)
+	case <-time.After(time.Second):
+		t.Fatal("notification did not expire")
+	}
+
+	state := m.GetState()
+	assert.Empty(t, state.Active)
+	assert.Len(t, state.History, 1)
+}
+
+func TestRulesAndDoNotDisturb(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	never := 0
+	require.NoError(t, m.SetRule(Rule{App: "chat", Mute: true, Timeout: &never}))
+	require.NoError(t, m.SetRule(Rule{App: "backup", Urgency: UrgencyLow}))
+
+	muted := m.Notify(Notification{AppName: "Chat", ExpireTimeout: -1}, 0, nil)
+	low := m.Notify(Notification{AppName: "backup", Urgency: UrgencyCritical, ExpireTimeout: -1}, 0, nil)
+
+	m.mutex.RLock()
+	assert.False(t, m.active[muted].Popup)
+	assert.Equal(t, 0, m.active[muted].Timeout)
+	assert.Equal(t, UrgencyLow, m.active[low].Urgency)
+	assert.True(t, m.active[low].Popup)
+	m.mutex.RUnlock()
+
+	require.NoError(t, m.SetDoNotDisturb(true))
+	assert.True(t, m.GetState().DoNotDisturb)
+
+	quiet := m.Notify(Notification{AppName: "other", ExpireTimeout: -1}, 0, nil)
+	urgent := m.Notify(Notification{AppName: "other", Urgency: UrgencyCritical, ExpireTimeout: -1}, 0, nil)
+
+	m.mutex.RLock()
+	assert.False(t, m.active[quiet].Popup)
+	assert.True(t, m.active[urgent].Popup)
+	m.mutex.RUnlock()
+
+	require.NoError(t, m.RemoveRule("CHAT"))
+	assert.Error(t, m.RemoveRule("chat"))
+	assert.Error(t, m.SetSchedule(Schedule{Enabled: true, Start: "late", End: "07:00"}))
+
+	settings, err := LoadSettings(m.settingsPath)
+	require.NoError(t, err)
+	assert.True(t, settings.DoNotDisturb)
+	require.Len(t, settings.Rules, 1)
+	assert.Equal(t, "backup", settings.Rules[0].App)
+}
+
+func TestInvokeAction(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	actions := []Action{{ID: "default", Label: ""}, {ID: "reply", Label: "Reply"}}
+	id := m.Notify(Notification{Actions: actions, ExpireTimeout: 0}, 0, nil)
+	resident := m.Notify(Notification{Actions: actions, Resident: true, ExpireTimeout: 0}, 0, nil)
+
+	assert.Error(t, m.InvokeAction(id, "delete"))
+	assert.Error(t, m.InvokeAction(42, "reply"))
+
+	require.NoError(t, m.InvokeAction(id, "reply"))
+	require.NoError(t, m.InvokeAction(resident, "reply"))
+
+	state := m.GetState()
+	require.Len(t, state.Active, 1)
+	assert.Equal(t, resident, state.Active[0].ID)
+}
+
+func TestTransientSkipsHistory(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	id := m.Notify(Notification{Transient: true, ExpireTimeout: 0}, 0, nil)
+	state := m.GetState()
+	assert.Len(t, state.Active, 1)
+	assert.Empty(t, state.History)
+
+	require.NoError(t, m.Dismiss(id))
+	assert.Error(t, m.Dismiss(id))
+}
+
+func TestHistoryTrimAndRemove(t *testing.T) {
+	dir := t.TempDir()
+	m := testManager(t, dir)
+	m.settings.MaxHistory = 2
+
+	img := &rawImage{Width: 1, Height: 1, RowStride: 4, HasAlpha: true, BitsPerSample: 8, Channels: 4, Data: []byte{1, 2, 3, 4}}
+	first := m.Notify(Notification{ExpireTimeout: 0}, 0, img)
+	require.NoError(t, m.Dismiss(first))
+	imagePath := filepath.Join(dir, "images", "1.png")
+	assert.FileExists(t, imagePath)
+
+	m.Notify(Notification{ExpireTimeout: 0}, 0, nil)
+	third := m.Notify(Notification{ExpireTimeout: 0}, 0, nil)
+
+	state := m.GetState()
+	require.Len(t, state.History, 2)
+	assert.Equal(t, third, state.History[0].ID)
+	assert.NoFileExists(t, imagePath)
+
+	require.NoError(t, m.Remove([]uint32{third}))
+	state = m.GetState()
+	assert.Len(t, state.History, 1)
+	assert.Len(t, state.Active, 1)
+	assert.Error(t, m.Remove([]uint32{third}))
+
+	m.ClearHistory()
+	state = m.GetState()
+	assert.Empty(t, state.History)
+	assert.Empty(t, state.Active)
+}
+
+func TestHistoryPersists(t *testing.T) {
+	dir := t.TempDir()
+	m := newManager(filepath.Join(dir, "notifications.json"), filepath.Join(dir, "history.json"), filepath.Join(dir, "images"))
+
+	m.Notify(Notification{AppName: "app", Summary: "kept", ExpireTimeout: 0}, 0, nil)
+	m.Notify(Notification{AppName: "app", Summary: "gone", Transient: true, ExpireTimeout: 0}, 0, nil)
+	m.Close()
+
+	_, err := os.Stat(filepath.Join(dir, "history.json"))
+	require.NoError(t, err)
+
+	reloaded := testManager(t, dir)
+	state := reloaded.GetState()
+	require.Len(t, state.History, 1)
+	assert.Equal(t, "kept", state.History[0].Summary)
+	assert.Empty(t, state.Active)
+
+	assert.Equal(t, uint32(3), reloaded.Notify(Notification{ExpireTimeout: 0}, 0, nil))
+}
+
+func TestDaemonIsOptIn(t *testing.T) {
+	m := testManager(t, t.TempDir())
+
+	state := m.GetState()
+	assert.False(t, state.Settings.Daemon)
+	assert.False(t, state.Owned)
+
+	assert.Error(t, m.SetDaemon(true))
+	assert.False(t, m.GetState().Settings.Daemon, "setting must not be saved without the name")
+}
@@ -0,0 +1,92 @@
+package notifications
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var idParamSpec = models.ParamSpec{Name: "id", Type: models.ParamNumber, Required: true, Description: "notification id"}
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "notifications.getState",
+		Description: "Get open notifications, history and do-not-disturb settings",
+		Notes: []string{
+			"owned is false until settings.daemon is enabled, or while another daemon holds org.freedesktop.Notifications",
+			"popup is false for muted apps and for non-critical notifications during do not disturb",
+			"timeout is in milliseconds, 0 means the notification stays until dismissed",
+		},
+	},
+	{
+		Name:        "notifications.dismiss",
+		Description: "Close an open notification, keeping it in history",
+		Params:      []models.ParamSpec{idParamSpec},
+	},
+	{
+		Name:        "notifications.remove",
+		Description: "Remove notifications from history, closing any still open",
+		Params: []models.ParamSpec{
+			{Name: "id", Type: models.ParamNumber},
+			{Name: "ids", Type: models.ParamArray, Description: "list of notification ids"},
+		},
+	},
+	{
+		Name:        "notifications.clearHistory",
+		Description: "Close all notifications and clear history",
+	},
+	{
+		Name:        "notifications.invokeAction",
+		Description: "Invoke a notification action",
+		Params: []models.ParamSpec{
+			idParamSpec,
+			{Name: "action", Type: models.ParamString, Required: true, Description: `action id, "default" for the body click`},
+		},
+		Notes: []string{"Non-resident notifications are dismissed after the action"},
+	},
+	{
+		Name:        "notifications.setDaemon",
+		Description: "Serve org.freedesktop.Notifications from the server",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool, Required: true},
+		},
+		Notes: []string{
+			"Off by default; enabling fails while another daemon, such as the shell's notification server, owns the name",
+		},
+	},
+	{
+		Name:        "notifications.setDoNotDisturb",
+		Description: "Enable or disable do not disturb",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "notifications.setSchedule",
+		Description: "Update the do-not-disturb schedule",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool},
+			{Name: "start", Type: models.ParamString, Description: "HH:MM"},
+			{Name: "end", Type: models.ParamString, Description: "HH:MM, may be earlier than start to span midnight"},
+		},
+	},
+	{
+		Name:        "notifications.setRule",
+		Description: "Add or replace a per-app rule",
+		Params: []models.ParamSpec{
+			{Name: "app", Type: models.ParamString, Required: true, Description: "app name or desktop entry, case-insensitive"},
+			{Name: "mute", Type: models.ParamBool, Description: "keep in history without a popup"},
+			{Name: "urgency", Type: models.ParamString, Enum: []string{UrgencyLow, UrgencyNormal, UrgencyCritical}},
+			{Name: "timeout", Type: models.ParamNumber, Description: "popup timeout in milliseconds, 0 for never"},
+		},
+	},
+	{
+		Name:        "notifications.removeRule",
+		Description: "Remove a per-app rule",
+		Params: []models.ParamSpec{
+			{Name: "app", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "notifications.subscribe",
+		Description: "Subscribe to notified, closed, removed and state events",
+		Streaming:   true,
+		Notes:       []string{"The first event is a full state snapshot"},
+	},
+}
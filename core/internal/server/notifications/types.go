@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type CloseReason uint32
+
+const (
+	CloseExpired   CloseReason = 1
+	CloseDismissed CloseReason = 2
+	CloseByCall    CloseReason = 3
+	CloseUndefined CloseReason = 4
+)
+
+func (r CloseReason) String() string {
+	switch r {
+	case CloseExpired:
+		return "expired"
+	case CloseDismissed:
+		return "dismissed"
+	case CloseByCall:
+		return "closed"
+	default:
+		return "undefined"
+	}
+}
+
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+type Action struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type Notification struct {
+	ID            uint32   `json:"id"`
+	AppName       string   `json:"appName"`
+	AppIcon       string   `json:"appIcon"`
+	Summary       string   `json:"summary"`
+	Body          string   `json:"body"`
+	Actions       []Action `json:"actions"`
+	Urgency       string   `json:"urgency"`
+	Category      string   `json:"category,omitempty"`
+	DesktopEntry  string   `json:"desktopEntry,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	SoundFile     string   `json:"soundFile,omitempty"`
+	SuppressSound bool     `json:"suppressSound,omitempty"`
+	ActionIcons   bool     `json:"actionIcons,omitempty"`
+	Resident      bool     `json:"resident,omitempty"`
+	Transient     bool     `json:"transient,omitempty"`
+	ExpireTimeout int32    `json:"expireTimeout"`
+	Timeout       int      `json:"timeout"`
+	Popup         bool     `json:"popup"`
+	Time          int64    `json:"time"`
+}
+
+// Rule adjusts notifications from an app, matched case-insensitively
+// against the app name or desktop entry.
+type Rule struct {
+	App     string `json:"app"`
+	Mute    bool   `json:"mute,omitempty"`
+	Urgency string `json:"urgency,omitempty"`
+	Timeout *int   `json:"timeout,omitempty"`
+}
+
+// Schedule enables Do Not Disturb between Start and End ("HH:MM", local
+// time). An End before Start spans midnight.
+type Schedule struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// Settings.Daemon opts in to serving org.freedesktop.Notifications. It is
+// off by default so the shell's own notification server keeps the name.
+type Settings struct {
+	Daemon       bool     `json:"daemon"`
+	DoNotDisturb bool     `json:"doNotDisturb"`
+	Schedule     Schedule `json:"schedule"`
+	Rules        []Rule   `json:"rules"`
+	MaxHistory   int      `json:"maxHistory"`
+}
+
+type State struct {
+	Available    bool           `json:"available"`
+	Owned        bool           `json:"owned"`
+	DoNotDisturb bool           `json:"doNotDisturb"`
+	Settings     Settings       `json:"settings"`
+	Active       []Notification `json:"active"`
+	History      []Notification `json:"history"`
+}
+
+const (
+	EventState    = "state"
+	EventNotified = "notified"
+	EventClosed   = "closed"
+	EventRemoved  = "removed"
+)
+
+type Event struct {
+	Type         string        `json:"type"`
+	Notification *Notification `json:"notification,omitempty"`
+	Replaced     bool          `json:"replaced,omitempty"`
+	ID           uint32        `json:"id,omitempty"`
+	IDs          []uint32      `json:"ids,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	State        *State        `json:"state,omitempty"`
+}
+
+type Manager struct {
+	conn         *dbus.Conn
+	owned        bool
+	settings     Settings
+	settingsPath string
+	historyPath  string
+	imageDir     string
+	nextID       uint32
+	active       map[uint32]*Notification
+	history      []Notification
+	timers       map[uint32]*time.Timer
+	dndActive    bool
+	mutex        sync.RWMutex
+	saveTimer    *time.Timer
+	saveMutex    sync.Mutex
+	subscribers  map[string]chan Event
+	subMutex     sync.RWMutex
+	stopChan     chan struct{}
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+}
+
+func (m *Manager) Subscribe(id string) chan Event {
+	ch := make(chan Event, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
@@ -0,0 +1,45 @@
+package plugins
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "plugins.list",
+		Description: "List all plugins",
+	},
+	{
+		Name:        "plugins.listInstalled",
+		Description: "List installed plugins",
+	},
+	{
+		Name:        "plugins.install",
+		Description: "Install plugin",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "plugins.uninstall",
+		Description: "Uninstall plugin",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "plugins.update",
+		Description: "Update plugin",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "plugins.search",
+		Description: "Search plugins",
+		Params: []models.ParamSpec{
+			{Name: "query", Type: models.ParamString, Required: true},
+			{Name: "category", Type: models.ParamString},
+			{Name: "compositor", Type: models.ParamString},
+			{Name: "capability", Type: models.ParamString},
+		},
+	},
+}
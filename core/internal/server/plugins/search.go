@@ -12,7 +12,7 @@ import (
 func HandleSearch(conn net.Conn, req models.Request) {
 	query, ok := req.Params["query"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'query' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'query' parameter"))
 		return
 	}
 
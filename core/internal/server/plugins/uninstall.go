@@ -11,7 +11,7 @@ import (
 func HandleUninstall(conn net.Conn, req models.Request) {
 	name, ok := req.Params["name"].(string)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'name' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
 		return
 	}
 
@@ -0,0 +1,80 @@
+package power
+
+const (
+	dbusUPowerDest            = "org.freedesktop.UPower"
+	dbusUPowerPath            = "/org/freedesktop/UPower"
+	dbusUPowerInterface       = "org.freedesktop.UPower"
+	dbusUPowerDeviceInterface = "org.freedesktop.UPower.Device"
+	dbusPropsInterface        = "org.freedesktop.DBus.Properties"
+
+	dbusProfilesDest      = "org.freedesktop.UPower.PowerProfiles"
+	dbusProfilesPath      = "/org/freedesktop/UPower/PowerProfiles"
+	dbusProfilesInterface = "org.freedesktop.UPower.PowerProfiles"
+
+	dbusLegacyProfilesDest      = "net.hadess.PowerProfiles"
+	dbusLegacyProfilesPath      = "/net/hadess/PowerProfiles"
+	dbusLegacyProfilesInterface = "net.hadess.PowerProfiles"
+)
+
+const (
+	DefaultLowThreshold      = 20.0
+	DefaultCriticalThreshold = 10.0
+)
+
+var deviceTypes = []string{
+	"unknown",
+	"line-power",
+	"battery",
+	"ups",
+	"monitor",
+	"mouse",
+	"keyboard",
+	"pda",
+	"phone",
+	"media-player",
+	"tablet",
+	"computer",
+	"gaming-input",
+	"pen",
+	"touchpad",
+	"modem",
+	"network",
+	"headset",
+	"speakers",
+	"headphones",
+	"video",
+	"other-audio",
+	"remote-control",
+	"printer",
+	"scanner",
+	"camera",
+	"wearable",
+	"toy",
+	"bluetooth-generic",
+}
+
+var deviceStates = []string{
+	"unknown",
+	"charging",
+	"discharging",
+	"empty",
+	"fully-charged",
+	"pending-charge",
+	"pending-discharge",
+}
+
+var warningLevels = []string{
+	"unknown",
+	"none",
+	"discharging",
+	"low",
+	"critical",
+	"action",
+}
+
+func enumName(names []string, v uint32) string {
+	if int(v) < len(names) {
+		return names[v]
+	}
+	return names[0]
+}
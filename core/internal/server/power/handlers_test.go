@@ -0,0 +1,93 @@
+package power
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNetConn struct {
+	net.Conn
+	writeBuf *bytes.Buffer
+}
+
+func newMockNetConn() *mockNetConn {
+	return &mockNetConn{writeBuf: &bytes.Buffer{}}
+}
+
+func (m *mockNetConn) Write(b []byte) (n int, err error) {
+	return m.writeBuf.Write(b)
+}
+
+func (m *mockNetConn) Close() error {
+	return nil
+}
+
+func TestHandleGetState(t *testing.T) {
+	m := newTestManager(&State{
+		Available: true,
+		OnBattery: true,
+		Battery:   &Device{Path: "/display", Type: "battery", Percentage: 64, TimeToEmpty: 7200},
+	})
+
+	conn := newMockNetConn()
+	HandleRequest(conn, Request{ID: 1, Method: "power.getState"}, m)
+
+	var resp models.Response[State]
+	require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+	assert.EqualValues(t, 1, resp.ID)
+	require.NotNil(t, resp.Result)
+	assert.True(t, resp.Result.OnBattery)
+	require.NotNil(t, resp.Result.Battery)
+	assert.Equal(t, int64(7200), resp.Result.Battery.TimeToEmpty)
+}
+
+func TestHandleSetProfile_MissingParam(t *testing.T) {
+	m := newTestManager(&State{})
+
+	conn := newMockNetConn()
+	HandleRequest(conn, Request{ID: 2, Method: "power.setProfile", Params: map[string]interface{}{}}, m)
+
+	var resp models.Response[SuccessResult]
+	require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+	assert.Equal(t, "missing or invalid 'profile' parameter", resp.Error)
+}
+
+func TestHandleSetThresholds(t *testing.T) {
+	m := newTestManager(&State{})
+
+	t.Run("partial update", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 3, Method: "power.setThresholds", Params: map[string]interface{}{"low": float64(25)}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Empty(t, resp.Error)
+		assert.Equal(t, Thresholds{Low: 25, Critical: DefaultCriticalThreshold}, m.GetState().Thresholds)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		conn := newMockNetConn()
+		HandleRequest(conn, Request{ID: 4, Method: "power.setThresholds", Params: map[string]interface{}{"critical": float64(50)}}, m)
+
+		var resp models.Response[SuccessResult]
+		require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+		assert.Contains(t, resp.Error, "invalid thresholds")
+	})
+}
+
+func TestHandleUnknownMethod(t *testing.T) {
+	m := newTestManager(&State{})
+
+	conn := newMockNetConn()
+	HandleRequest(conn, Request{ID: 5, Method: "power.nope"}, m)
+
+	var resp models.Response[any]
+	require.NoError(t, json.NewDecoder(conn.writeBuf).Decode(&resp))
+	assert.Equal(t, "unknown method: power.nope", resp.Error)
+}
@@ -0,0 +1,615 @@
+package power
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+)
+
+func NewManager() (*Manager, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+
+	m := &Manager{
+		state: &State{
+			Thresholds: Thresholds{
+				Low:      DefaultLowThreshold,
+				Critical: DefaultCriticalThreshold,
+			},
+		},
+		subscribers:      make(map[string]chan State),
+		alertSubscribers: make(map[string]chan BatteryAlert),
+		stopChan:         make(chan struct{}),
+		conn:             conn,
+		dirty:            make(chan struct{}, 1),
+		signals:          make(chan *dbus.Signal, 256),
+		alertLevels:      make(map[string]AlertLevel),
+	}
+
+	if err := m.initialize(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m.notifierWg.Add(1)
+	go m.notifier()
+
+	if err := m.startSignalPump(); err != nil {
+		m.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *Manager) initialize() error {
+	m.upowerObj = m.conn.Object(dbusUPowerDest, dbus.ObjectPath(dbusUPowerPath))
+
+	if err := m.updateDaemonState(); err != nil {
+		return fmt.Errorf("UPower not available: %w", err)
+	}
+
+	if err := m.updateDevices(); err != nil {
+		return fmt.Errorf("failed to enumerate UPower devices: %w", err)
+	}
+
+	m.initializeProfiles()
+
+	// Seed alert levels so a battery that is already low at startup does not
+	// produce an alert before anyone has subscribed.
+	m.evaluateAlerts()
+
+	return nil
+}
+
+func (m *Manager) initializeProfiles() {
+	candidates := []struct {
+		dest  string
+		path  string
+		iface string
+	}{
+		{dbusProfilesDest, dbusProfilesPath, dbusProfilesInterface},
+		{dbusLegacyProfilesDest, dbusLegacyProfilesPath, dbusLegacyProfilesInterface},
+	}
+
+	for _, c := range candidates {
+		m.profilesObj = m.conn.Object(c.dest, dbus.ObjectPath(c.path))
+		m.profilesIface = c.iface
+		m.profilesPath = dbus.ObjectPath(c.path)
+		if err := m.updateProfiles(); err == nil {
+			return
+		}
+	}
+
+	log.Debugf("power-profiles-daemon not available")
+	m.profilesObj = nil
+	m.profilesIface = ""
+	m.profilesPath = ""
+}
+
+func (m *Manager) updateDaemonState() error {
+	var props map[string]dbus.Variant
+	if err := m.upowerObj.Call(dbusPropsInterface+".GetAll", 0, dbusUPowerInterface).Store(&props); err != nil {
+		return err
+	}
+
+	m.stateMutex.Lock()
+	defer m.stateMutex.Unlock()
+
+	m.state.Available = true
+	if v, ok := props["OnBattery"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			m.state.OnBattery = val
+		}
+	}
+	if v, ok := props["LidIsPresent"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			m.state.LidIsPresent = val
+		}
+	}
+	if v, ok := props["LidIsClosed"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			m.state.LidIsClosed = val
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) updateDevices() error {
+	var paths []dbus.ObjectPath
+	if err := m.upowerObj.Call(dbusUPowerInterface+".EnumerateDevices", 0).Store(&paths); err != nil {
+		return err
+	}
+
+	devices := make([]Device, 0, len(paths))
+	for _, path := range paths {
+		dev, err := m.getDevice(path)
+		if err != nil {
+			log.Debugf("Failed to read UPower device %s: %v", path, err)
+			continue
+		}
+		devices = append(devices, dev)
+	}
+	slices.SortFunc(devices, func(a, b Device) int {
+		switch {
+		case a.Path < b.Path:
+			return -1
+		case a.Path > b.Path:
+			return 1
+		}
+		return 0
+	})
+
+	var battery *Device
+	var displayPath dbus.ObjectPath
+	if err := m.upowerObj.Call(dbusUPowerInterface+".GetDisplayDevice", 0).Store(&displayPath); err == nil {
+		if dev, err := m.getDevice(displayPath); err == nil && dev.IsPresent && dev.Type == "battery" {
+			battery = &dev
+		}
+	}
+
+	m.stateMutex.Lock()
+	m.state.Devices = devices
+	m.state.Battery = battery
+	m.stateMutex.Unlock()
+
+	return nil
+}
+
+func (m *Manager) getDevice(path dbus.ObjectPath) (Device, error) {
+	var props map[string]dbus.Variant
+	obj := m.conn.Object(dbusUPowerDest, path)
+	if err := obj.Call(dbusPropsInterface+".GetAll", 0, dbusUPowerDeviceInterface).Store(&props); err != nil {
+		return Device{}, err
+	}
+	return deviceFromProps(string(path), props), nil
+}
+
+func deviceFromProps(path string, props map[string]dbus.Variant) Device {
+	dev := Device{
+		Path:         path,
+		Type:         deviceTypes[0],
+		State:        deviceStates[0],
+		WarningLevel: warningLevels[0],
+	}
+
+	if v, ok := props["NativePath"]; ok {
+		if val, ok := v.Value().(string); ok {
+			dev.NativePath = val
+		}
+	}
+	if v, ok := props["Vendor"]; ok {
+		if val, ok := v.Value().(string); ok {
+			dev.Vendor = val
+		}
+	}
+	if v, ok := props["Model"]; ok {
+		if val, ok := v.Value().(string); ok {
+			dev.Model = val
+		}
+	}
+	if v, ok := props["Serial"]; ok {
+		if val, ok := v.Value().(string); ok {
+			dev.Serial = val
+		}
+	}
+	if v, ok := props["Type"]; ok {
+		if val, ok := v.Value().(uint32); ok {
+			dev.Type = enumName(deviceTypes, val)
+		}
+	}
+	if v, ok := props["PowerSupply"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			dev.PowerSupply = val
+		}
+	}
+	if v, ok := props["Online"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			dev.Online = val
+		}
+	}
+	if v, ok := props["IsPresent"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			dev.IsPresent = val
+		}
+	}
+	if v, ok := props["IsRechargeable"]; ok {
+		if val, ok := v.Value().(bool); ok {
+			dev.IsRechargeable = val
+		}
+	}
+	if v, ok := props["State"]; ok {
+		if val, ok := v.Value().(uint32); ok {
+			dev.State = enumName(deviceStates, val)
+		}
+	}
+	if v, ok := props["Percentage"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.Percentage = val
+		}
+	}
+	if v, ok := props["TimeToEmpty"]; ok {
+		if val, ok := v.Value().(int64); ok {
+			dev.TimeToEmpty = val
+		}
+	}
+	if v, ok := props["TimeToFull"]; ok {
+		if val, ok := v.Value().(int64); ok {
+			dev.TimeToFull = val
+		}
+	}
+	if v, ok := props["EnergyRate"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.EnergyRate = val
+		}
+	}
+	if v, ok := props["Energy"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.Energy = val
+		}
+	}
+	if v, ok := props["EnergyFull"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.EnergyFull = val
+		}
+	}
+	if v, ok := props["EnergyFullDesign"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.EnergyFullDesign = val
+		}
+	}
+	if v, ok := props["Capacity"]; ok {
+		if val, ok := v.Value().(float64); ok {
+			dev.Capacity = val
+		}
+	}
+	if v, ok := props["ChargeCycles"]; ok {
+		if val, ok := v.Value().(int32); ok {
+			dev.ChargeCycles = val
+		}
+	}
+	if v, ok := props["WarningLevel"]; ok {
+		if val, ok := v.Value().(uint32); ok {
+			dev.WarningLevel = enumName(warningLevels, val)
+		}
+	}
+	if v, ok := props["IconName"]; ok {
+		if val, ok := v.Value().(string); ok {
+			dev.IconName = val
+		}
+	}
+
+	return dev
+}
+
+func (m *Manager) updateProfiles() error {
+	if m.profilesObj == nil {
+		return fmt.Errorf("power profiles not available")
+	}
+
+	var props map[string]dbus.Variant
+	if err := m.profilesObj.Call(dbusPropsInterface+".GetAll", 0, m.profilesIface).Store(&props); err != nil {
+		return err
+	}
+
+	profiles := profilesFromProps(props)
+
+	m.stateMutex.Lock()
+	m.state.PowerProfiles = profiles
+	m.stateMutex.Unlock()
+
+	return nil
+}
+
+func profilesFromProps(props map[string]dbus.Variant) ProfilesState {
+	state := ProfilesState{Available: true}
+
+	if v, ok := props["ActiveProfile"]; ok {
+		if val, ok := v.Value().(string); ok {
+			state.ActiveProfile = val
+		}
+	}
+	if v, ok := props["PerformanceDegraded"]; ok {
+		if val, ok := v.Value().(string); ok {
+			state.PerformanceDegraded = val
+		}
+	}
+	if v, ok := props["Profiles"]; ok {
+		if list, ok := v.Value().([]map[string]dbus.Variant); ok {
+			for _, entry := range list {
+				if p, ok := entry["Profile"]; ok {
+					if name, ok := p.Value().(string); ok {
+						state.Profiles = append(state.Profiles, name)
+					}
+				}
+			}
+		}
+	}
+
+	return state
+}
+
+func (m *Manager) refresh() {
+	if err := m.updateDaemonState(); err != nil {
+		log.Debugf("Failed to update UPower state: %v", err)
+	}
+	if err := m.updateDevices(); err != nil {
+		log.Debugf("Failed to update UPower devices: %v", err)
+	}
+	if m.profilesObj != nil {
+		if err := m.updateProfiles(); err != nil {
+			log.Debugf("Failed to update power profiles: %v", err)
+		}
+	}
+
+	for _, alert := range m.evaluateAlerts() {
+		m.broadcastAlert(alert)
+	}
+}
+
+// evaluateAlerts compares every tracked battery against the configured
+// thresholds and returns alerts for devices that crossed into a more severe
+// level. Levels reset once a device charges or recovers above a threshold.
+func (m *Manager) evaluateAlerts() []BatteryAlert {
+	m.stateMutex.RLock()
+	thresholds := m.state.Thresholds
+	var candidates []Device
+	if m.state.Battery != nil {
+		candidates = append(candidates, *m.state.Battery)
+	}
+	for _, dev := range m.state.Devices {
+		// System batteries are covered by the composite display device.
+		if dev.PowerSupply || !dev.IsPresent || dev.Type == "line-power" {
+			continue
+		}
+		candidates = append(candidates, dev)
+	}
+	m.stateMutex.RUnlock()
+
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+
+	var alerts []BatteryAlert
+	seen := make(map[string]bool, len(candidates))
+	for _, dev := range candidates {
+		seen[dev.Path] = true
+		level := alertLevelFor(dev, thresholds)
+		previous, ok := m.alertLevels[dev.Path]
+		m.alertLevels[dev.Path] = level
+		if ok && level.severity() > previous.severity() {
+			alerts = append(alerts, BatteryAlert{Level: level, Device: dev})
+		}
+	}
+	for path := range m.alertLevels {
+		if !seen[path] {
+			delete(m.alertLevels, path)
+		}
+	}
+
+	return alerts
+}
+
+func alertLevelFor(dev Device, thresholds Thresholds) AlertLevel {
+	if dev.IsCharging() {
+		return AlertNone
+	}
+	switch {
+	case dev.Percentage <= thresholds.Critical:
+		return AlertCritical
+	case dev.Percentage <= thresholds.Low:
+		return AlertLow
+	}
+	return AlertNone
+}
+
+func (m *Manager) broadcastAlert(alert BatteryAlert) {
+	m.subMutex.RLock()
+	defer m.subMutex.RUnlock()
+
+	for _, ch := range m.alertSubscribers {
+		select {
+		case ch <- alert:
+		default:
+		}
+	}
+}
+
+func (m *Manager) startSignalPump() error {
+	m.conn.Signal(m.signals)
+
+	if err := m.conn.AddMatchSignal(
+		dbus.WithMatchSender(dbusUPowerDest),
+		dbus.WithMatchInterface(dbusPropsInterface),
+		dbus.WithMatchMember("PropertiesChanged"),
+	); err != nil {
+		return err
+	}
+
+	if err := m.conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbus.ObjectPath(dbusUPowerPath)),
+		dbus.WithMatchInterface(dbusUPowerInterface),
+	); err != nil {
+		return err
+	}
+
+	if m.profilesPath != "" {
+		if err := m.conn.AddMatchSignal(
+			dbus.WithMatchObjectPath(m.profilesPath),
+			dbus.WithMatchInterface(dbusPropsInterface),
+			dbus.WithMatchMember("PropertiesChanged"),
+		); err != nil {
+			return err
+		}
+	}
+
+	m.sigWG.Add(1)
+	go func() {
+		defer m.sigWG.Done()
+		for {
+			select {
+			case <-m.stopChan:
+				return
+			case sig, ok := <-m.signals:
+				if !ok {
+					return
+				}
+				if sig == nil {
+					continue
+				}
+				m.handleSignal(sig)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (m *Manager) handleSignal(sig *dbus.Signal) {
+	switch sig.Name {
+	case dbusPropsInterface + ".PropertiesChanged":
+		if len(sig.Body) < 1 {
+			return
+		}
+		iface, ok := sig.Body[0].(string)
+		if !ok {
+			return
+		}
+		switch iface {
+		case dbusUPowerInterface, dbusUPowerDeviceInterface, m.profilesIface:
+			m.notifySubscribers()
+		}
+	case dbusUPowerInterface + ".DeviceAdded", dbusUPowerInterface + ".DeviceRemoved":
+		m.notifySubscribers()
+	}
+}
+
+func (m *Manager) notifier() {
+	defer m.notifierWg.Done()
+	const minGap = 200 * time.Millisecond
+	timer := time.NewTimer(minGap)
+	timer.Stop()
+	var pending bool
+
+	for {
+		select {
+		case <-m.stopChan:
+			timer.Stop()
+			return
+		case <-m.dirty:
+			if pending {
+				continue
+			}
+			pending = true
+			timer.Reset(minGap)
+		case <-timer.C:
+			if !pending {
+				continue
+			}
+			m.refresh()
+
+			m.subMutex.RLock()
+			if len(m.subscribers) == 0 {
+				m.subMutex.RUnlock()
+				pending = false
+				continue
+			}
+
+			currentState := m.GetState()
+
+			if m.lastNotifiedState != nil && !stateChanged(m.lastNotifiedState, &currentState) {
+				m.subMutex.RUnlock()
+				pending = false
+				continue
+			}
+
+			for _, ch := range m.subscribers {
+				select {
+				case ch <- currentState:
+				default:
+				}
+			}
+			m.subMutex.RUnlock()
+
+			stateCopy := currentState
+			m.lastNotifiedState = &stateCopy
+			pending = false
+		}
+	}
+}
+
+func (m *Manager) notifySubscribers() {
+	select {
+	case m.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) SetProfile(profile string) error {
+	m.stateMutex.RLock()
+	profiles := m.state.PowerProfiles
+	m.stateMutex.RUnlock()
+
+	if m.profilesObj == nil || !profiles.Available {
+		return fmt.Errorf("power profiles not available")
+	}
+	if !slices.Contains(profiles.Profiles, profile) {
+		return fmt.Errorf("unknown power profile: %s", profile)
+	}
+
+	call := m.profilesObj.Call(dbusPropsInterface+".Set", 0, m.profilesIface, "ActiveProfile", dbus.MakeVariant(profile))
+	if call.Err != nil {
+		return fmt.Errorf("failed to set power profile: %w", call.Err)
+	}
+
+	m.stateMutex.Lock()
+	m.state.PowerProfiles.ActiveProfile = profile
+	m.stateMutex.Unlock()
+	m.notifySubscribers()
+
+	return nil
+}
+
+func (m *Manager) SetThresholds(low, critical float64) error {
+	if critical < 0 || low > 100 || critical > low {
+		return fmt.Errorf("invalid thresholds: require 0 <= critical <= low <= 100")
+	}
+
+	m.stateMutex.Lock()
+	m.state.Thresholds = Thresholds{Low: low, Critical: critical}
+	m.stateMutex.Unlock()
+	m.notifySubscribers()
+
+	return nil
+}
+
+func (m *Manager) Close() {
+	close(m.stopChan)
+	m.notifierWg.Wait()
+	m.sigWG.Wait()
+
+	if m.signals != nil {
+		m.conn.RemoveSignal(m.signals)
+		close(m.signals)
+	}
+
+	m.subMutex.Lock()
+	for _, ch := range m.subscribers {
+		close(ch)
+	}
+	m.subscribers = make(map[string]chan State)
+	for _, ch := range m.alertSubscribers {
+		close(ch)
+	}
+	m.alertSubscribers = make(map[string]chan BatteryAlert)
+	m.subMutex.Unlock()
+
+	if m.conn != nil {
+		m.conn.Close()
+	}
+}
@@ -0,0 +1,162 @@
+package power
+
+import (
+	"errors"
+	"testing"
+
+	mockdbus "github.com/AvengeMedia/DankMaterialShell/core/internal/mocks/github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(state *State) *Manager {
+	if state.Thresholds == (Thresholds{}) {
+		state.Thresholds = Thresholds{Low: DefaultLowThreshold, Critical: DefaultCriticalThreshold}
+	}
+	return &Manager{
+		state:            state,
+		subscribers:      make(map[string]chan State),
+		alertSubscribers: make(map[string]chan BatteryAlert),
+		dirty:            make(chan struct{}, 1),
+		alertLevels:      make(map[string]AlertLevel),
+	}
+}
+
+func TestManager_GetStateIsCopy(t *testing.T) {
+	m := newTestManager(&State{
+		Available: true,
+		Battery:   &Device{Path: "/bat", Percentage: 80},
+		Devices:   []Device{{Path: "/bat", Percentage: 80}},
+	})
+
+	state := m.GetState()
+	state.Battery.Percentage = 10
+	state.Devices[0].Percentage = 10
+
+	fresh := m.GetState()
+	assert.Equal(t, 80.0, fresh.Battery.Percentage)
+	assert.Equal(t, 80.0, fresh.Devices[0].Percentage)
+}
+
+func TestManager_EvaluateAlerts(t *testing.T) {
+	battery := Device{Path: "/display", Type: "battery", IsPresent: true, PowerSupply: true, State: "discharging", Percentage: 50}
+	m := newTestManager(&State{Battery: &battery})
+
+	assert.Empty(t, m.evaluateAlerts(), "first pass only seeds levels")
+
+	m.state.Battery.Percentage = 19
+	alerts := m.evaluateAlerts()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertLow, alerts[0].Level)
+	assert.Equal(t, 19.0, alerts[0].Device.Percentage)
+
+	m.state.Battery.Percentage = 18
+	assert.Empty(t, m.evaluateAlerts(), "no repeat while level is unchanged")
+
+	m.state.Battery.Percentage = 9
+	alerts = m.evaluateAlerts()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertCritical, alerts[0].Level)
+
+	m.state.Battery.State = "charging"
+	assert.Empty(t, m.evaluateAlerts())
+
+	m.state.Battery.State = "discharging"
+	alerts = m.evaluateAlerts()
+	require.Len(t, alerts, 1, "unplugging while critical alerts again")
+	assert.Equal(t, AlertCritical, alerts[0].Level)
+}
+
+func TestManager_EvaluateAlerts_Peripherals(t *testing.T) {
+	m := newTestManager(&State{
+		Devices: []Device{
+			{Path: "/bat0", Type: "battery", IsPresent: true, PowerSupply: true, Percentage: 50},
+			{Path: "/ac", Type: "line-power", PowerSupply: true, Online: true},
+			{Path: "/mouse", Type: "mouse", IsPresent: true, Percentage: 30},
+		},
+	})
+	m.evaluateAlerts()
+
+	m.state.Devices[0].Percentage = 5
+	m.state.Devices[2].Percentage = 15
+	alerts := m.evaluateAlerts()
+	require.Len(t, alerts, 1, "system batteries are tracked through the display device")
+	assert.Equal(t, "/mouse", alerts[0].Device.Path)
+	assert.Equal(t, AlertLow, alerts[0].Level)
+
+	m.state.Devices = m.state.Devices[:2]
+	m.evaluateAlerts()
+	_, tracked := m.alertLevels["/mouse"]
+	assert.False(t, tracked, "removed devices are forgotten")
+}
+
+func TestManager_AlertSubscribers(t *testing.T) {
+	m := newTestManager(&State{})
+
+	ch := m.SubscribeAlerts("test")
+	m.broadcastAlert(BatteryAlert{Level: AlertLow, Device: Device{Path: "/bat"}})
+
+	alert := <-ch
+	assert.Equal(t, AlertLow, alert.Level)
+
+	m.UnsubscribeAlerts("test")
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestManager_SetThresholds(t *testing.T) {
+	m := newTestManager(&State{})
+
+	require.NoError(t, m.SetThresholds(30, 5))
+	assert.Equal(t, Thresholds{Low: 30, Critical: 5}, m.GetState().Thresholds)
+
+	assert.Error(t, m.SetThresholds(5, 30))
+	assert.Error(t, m.SetThresholds(101, 5))
+	assert.Error(t, m.SetThresholds(20, -1))
+}
+
+func TestManager_SetProfile(t *testing.T) {
+	profiles := ProfilesState{
+		Available:     true,
+		ActiveProfile: "balanced",
+		Profiles:      []string{"power-saver", "balanced", "performance"},
+	}
+
+	t.Run("unavailable", func(t *testing.T) {
+		m := newTestManager(&State{})
+		assert.ErrorContains(t, m.SetProfile("balanced"), "not available")
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		m := newTestManager(&State{PowerProfiles: profiles})
+		m.profilesObj = mockdbus.NewMockBusObject(t)
+		assert.ErrorContains(t, m.SetProfile("turbo"), "unknown power profile")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		obj := mockdbus.NewMockBusObject(t)
+		obj.EXPECT().Call(dbusPropsInterface+".Set", dbus.Flags(0), dbusProfilesInterface, "ActiveProfile", dbus.MakeVariant("performance")).
+			Return(&dbus.Call{})
+
+		m := newTestManager(&State{PowerProfiles: profiles})
+		m.profilesObj = obj
+		m.profilesIface = dbusProfilesInterface
+
+		require.NoError(t, m.SetProfile("performance"))
+		assert.Equal(t, "performance", m.GetState().PowerProfiles.ActiveProfile)
+	})
+
+	t.Run("dbus error", func(t *testing.T) {
+		obj := mockdbus.NewMockBusObject(t)
+		obj.EXPECT().Call(dbusPropsInterface+".Set", dbus.Flags(0), dbusProfilesInterface, "ActiveProfile", dbus.MakeVariant("power-saver")).
+			Return(&dbus.Call{Err: errors.New("denied")})
+
+		m := newTestManager(&State{PowerProfiles: profiles})
+		m.profilesObj = obj
+		m.profilesIface = dbusProfilesInterface
+
+		assert.ErrorContains(t, m.SetProfile("power-saver"), "denied")
+		assert.Equal(t, "balanced", m.GetState().PowerProfiles.ActiveProfile)
+	})
+}
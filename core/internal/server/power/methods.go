@@ -0,0 +1,34 @@
+package power
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "power.getState",
+		Description: "Get UPower devices, battery and power profile state",
+	},
+	{
+		Name:        "power.setProfile",
+		Description: "Set active power profile",
+		Params: []models.ParamSpec{
+			{Name: "profile", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "power.setThresholds",
+		Description: "Set low/critical battery alert thresholds",
+		Params: []models.ParamSpec{
+			{Name: "low", Type: models.ParamNumber},
+			{Name: "critical", Type: models.ParamNumber},
+		},
+	},
+	{
+		Name:        "power.subscribe",
+		Description: "Subscribe to power state changes",
+		Streaming:   true,
+		Notes: []string{
+			"power      : Full power state (devices, battery, profiles, thresholds)",
+			"power.alert: Battery crossed the low or critical threshold while discharging",
+		},
+	},
+}
@@ -0,0 +1,201 @@
+package power
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type Device struct {
+	Path             string  `json:"path"`
+	NativePath       string  `json:"nativePath"`
+	Vendor           string  `json:"vendor"`
+	Model            string  `json:"model"`
+	Serial           string  `json:"serial"`
+	Type             string  `json:"type"`
+	PowerSupply      bool    `json:"powerSupply"`
+	Online           bool    `json:"online"`
+	IsPresent        bool    `json:"isPresent"`
+	IsRechargeable   bool    `json:"isRechargeable"`
+	State            string  `json:"state"`
+	Percentage       float64 `json:"percentage"`
+	TimeToEmpty      int64   `json:"timeToEmpty"`
+	TimeToFull       int64   `json:"timeToFull"`
+	EnergyRate       float64 `json:"energyRate"`
+	Energy           float64 `json:"energy"`
+	EnergyFull       float64 `json:"energyFull"`
+	EnergyFullDesign float64 `json:"energyFullDesign"`
+	Capacity         float64 `json:"capacity"`
+	ChargeCycles     int32   `json:"chargeCycles"`
+	WarningLevel     string  `json:"warningLevel"`
+	IconName         string  `json:"iconName"`
+}
+
+func (d Device) IsCharging() bool {
+	switch d.State {
+	case "charging", "fully-charged", "pending-charge":
+		return true
+	}
+	return false
+}
+
+type ProfilesState struct {
+	Available           bool     `json:"available"`
+	ActiveProfile       string   `json:"activeProfile"`
+	Profiles            []string `json:"profiles"`
+	PerformanceDegraded string   `json:"performanceDegraded"`
+}
+
+type Thresholds struct {
+	Low      float64 `json:"low"`
+	Critical float64 `json:"critical"`
+}
+
+type State struct {
+	Available     bool          `json:"available"`
+	OnBattery     bool          `json:"onBattery"`
+	LidIsPresent  bool          `json:"lidIsPresent"`
+	LidIsClosed   bool          `json:"lidIsClosed"`
+	Battery       *Device       `json:"battery"`
+	Devices       []Device      `json:"devices"`
+	PowerProfiles ProfilesState `json:"powerProfiles"`
+	Thresholds    Thresholds    `json:"thresholds"`
+}
+
+type AlertLevel string
+
+const (
+	AlertNone     AlertLevel = "none"
+	AlertLow      AlertLevel = "low"
+	AlertCritical AlertLevel = "critical"
+)
+
+func (l AlertLevel) severity() int {
+	switch l {
+	case AlertLow:
+		return 1
+	case AlertCritical:
+		return 2
+	}
+	return 0
+}
+
+type BatteryAlert struct {
+	Level  AlertLevel `json:"level"`
+	Device Device     `json:"device"`
+}
+
+type Manager struct {
+	state             *State
+	stateMutex        sync.RWMutex
+	subscribers       map[string]chan State
+	alertSubscribers  map[string]chan BatteryAlert
+	subMutex          sync.RWMutex
+	stopChan          chan struct{}
+	conn              *dbus.Conn
+	upowerObj         dbus.BusObject
+	profilesObj       dbus.BusObject
+	profilesIface     string
+	profilesPath      dbus.ObjectPath
+	dirty             chan struct{}
+	notifierWg        sync.WaitGroup
+	lastNotifiedState *State
+	signals           chan *dbus.Signal
+	sigWG             sync.WaitGroup
+	alertMutex        sync.Mutex
+	alertLevels       map[string]AlertLevel
+}
+
+func (m *Manager) GetState() State {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+	return m.snapshotLocked()
+}
+
+func (m *Manager) snapshotLocked() State {
+	s := *m.state
+	s.Devices = append([]Device(nil), m.state.Devices...)
+	s.PowerProfiles.Profiles = append([]string(nil), m.state.PowerProfiles.Profiles...)
+	if m.state.Battery != nil {
+		battery := *m.state.Battery
+		s.Battery = &battery
+	}
+	return s
+}
+
+func (m *Manager) Subscribe(id string) chan State {
+	ch := make(chan State, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
+
+func (m *Manager) SubscribeAlerts(id string) chan BatteryAlert {
+	ch := make(chan BatteryAlert, 16)
+	m.subMutex.Lock()
+	m.alertSubscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) UnsubscribeAlerts(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.alertSubscribers[id]; ok {
+		close(ch)
+		delete(m.alertSubscribers, id)
+	}
+	m.subMutex.Unlock()
+}
+
+func stateChanged(old, new *State) bool {
+	if old == nil || new == nil {
+		return true
+	}
+	if old.Available != new.Available || old.OnBattery != new.OnBattery {
+		return true
+	}
+	if old.LidIsPresent != new.LidIsPresent || old.LidIsClosed != new.LidIsClosed {
+		return true
+	}
+	if old.Thresholds != new.Thresholds {
+		return true
+	}
+	if (old.Battery == nil) != (new.Battery == nil) {
+		return true
+	}
+	if old.Battery != nil && *old.Battery != *new.Battery {
+		return true
+	}
+	if len(old.Devices) != len(new.Devices) {
+		return true
+	}
+	for i := range old.Devices {
+		if old.Devices[i] != new.Devices[i] {
+			return true
+		}
+	}
+	if old.PowerProfiles.Available != new.PowerProfiles.Available ||
+		old.PowerProfiles.ActiveProfile != new.PowerProfiles.ActiveProfile ||
+		old.PowerProfiles.PerformanceDegraded != new.PowerProfiles.PerformanceDegraded {
+		return true
+	}
+	if len(old.PowerProfiles.Profiles) != len(new.PowerProfiles.Profiles) {
+		return true
+	}
+	for i := range old.PowerProfiles.Profiles {
+		if old.PowerProfiles.Profiles[i] != new.PowerProfiles.Profiles[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceFromProps(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"NativePath":   dbus.MakeVariant("BAT0"),
+		"Vendor":       dbus.MakeVariant("SMP"),
+		"Model":        dbus.MakeVariant("5B10W13930"),
+		"Type":         dbus.MakeVariant(uint32(2)),
+		"PowerSupply":  dbus.MakeVariant(true),
+		"IsPresent":    dbus.MakeVariant(true),
+		"State":        dbus.MakeVariant(uint32(2)),
+		"Percentage":   dbus.MakeVariant(42.5),
+		"TimeToEmpty":  dbus.MakeVariant(int64(5400)),
+		"EnergyRate":   dbus.MakeVariant(7.25),
+		"Capacity":     dbus.MakeVariant(91.0),
+		"WarningLevel": dbus.MakeVariant(uint32(1)),
+		"IconName":     dbus.MakeVariant("battery-good-symbolic"),
+	}
+
+	dev := deviceFromProps("/org/freedesktop/UPower/devices/battery_BAT0", props)
+	assert.Equal(t, "/org/freedesktop/UPower/devices/battery_BAT0", dev.Path)
+	assert.Equal(t, "BAT0", dev.NativePath)
+	assert.Equal(t, "SMP", dev.Vendor)
+	assert.Equal(t, "battery", dev.Type)
+	assert.True(t, dev.PowerSupply)
+	assert.True(t, dev.IsPresent)
+	assert.Equal(t, "discharging", dev.State)
+	assert.Equal(t, 42.5, dev.Percentage)
+	assert.Equal(t, int64(5400), dev.TimeToEmpty)
+	assert.Equal(t, 7.25, dev.EnergyRate)
+	assert.Equal(t, 91.0, dev.Capacity)
+	assert.Equal(t, "none", dev.WarningLevel)
+	assert.Equal(t, "battery-good-symbolic", dev.IconName)
+	assert.False(t, dev.IsCharging())
+}
+
+func TestDeviceFromProps_UnknownEnums(t *testing.T) {
+	dev := deviceFromProps("/dev", map[string]dbus.Variant{
+		"Type":  dbus.MakeVariant(uint32(999)),
+		"State": dbus.MakeVariant("not a number"),
+	})
+	assert.Equal(t, "unknown", dev.Type)
+	assert.Equal(t, "unknown", dev.State)
+	assert.Equal(t, "unknown", dev.WarningLevel)
+}
+
+func TestProfilesFromProps(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"ActiveProfile": dbus.MakeVariant("balanced"),
+		"Profiles": dbus.MakeVariant([]map[string]dbus.Variant{
+			{"Profile": dbus.MakeVariant("power-saver"), "Driver": dbus.MakeVariant("platform_profile")},
+			{"Profile": dbus.MakeVariant("balanced"), "Driver": dbus.MakeVariant("platform_profile")},
+			{"Profile": dbus.MakeVariant("performance"), "Driver": dbus.MakeVariant("platform_profile")},
+		}),
+		"PerformanceDegraded": dbus.MakeVariant(""),
+	}
+
+	state := profilesFromProps(props)
+	assert.True(t, state.Available)
+	assert.Equal(t, "balanced", state.ActiveProfile)
+	assert.Equal(t, []string{"power-saver", "balanced", "performance"}, state.Profiles)
+}
+
+func TestDevice_IsCharging(t *testing.T) {
+	for state, want := range map[string]bool{
+		"charging":          true,
+		"fully-charged":     true,
+		"pending-charge":    true,
+		"discharging":       false,
+		"pending-discharge": false,
+		"unknown":           false,
+	} {
+		assert.Equal(t, want, Device{State: state}.IsCharging(), state)
+	}
+}
+
+func TestStateChanged(t *testing.T) {
+	base := &State{
+		Available: true,
+		Battery:   &Device{Path: "/bat", Percentage: 50},
+		Devices:   []Device{{Path: "/bat", Percentage: 50}},
+		PowerProfiles: ProfilesState{
+			Available:     true,
+			ActiveProfile: "balanced",
+			Profiles:      []string{"balanced", "performance"},
+		},
+	}
+
+	same := &State{
+		Available: true,
+		Battery:   &Device{Path: "/bat", Percentage: 50},
+		Devices:   []Device{{Path: "/bat", Percentage: 50}},
+		PowerProfiles: ProfilesState{
+			Available:     true,
+			ActiveProfile: "balanced",
+			Profiles:      []string{"balanced", "performance"},
+		},
+	}
+	assert.False(t, stateChanged(base, same))
+
+	drained := *same
+	drained.Battery = &Device{Path: "/bat", Percentage: 49}
+	assert.True(t, stateChanged(base, &drained))
+
+	profile := *same
+	profile.PowerProfiles.ActiveProfile = "performance"
+	assert.True(t, stateChanged(base, &profile))
+
+	assert.True(t, stateChanged(nil, base))
+}
@@ -5,6 +5,8 @@ import (
 	"net"
 	"strings"
 
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/audio"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/bluez"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/brightness"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/cups"
@@ -12,174 +14,270 @@ import (
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/evdev"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/extworkspace"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/freedesktop"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/idle"
+	serverKeybinds "github.com/AvengeMedia/DankMaterialShell/core/internal/server/keybinds"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/keyboard"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/loginctl"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/network"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/notifications"
 	serverPlugins "github.com/AvengeMedia/DankMaterialShell/core/internal/server/plugins"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/power"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/tray"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/wayland"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/wlroutput"
 )
 
-func RouteRequest(conn net.Conn, req models.Request) {
-	if strings.HasPrefix(req.Method, "network.") {
-		if networkManager == nil {
-			models.RespondError(conn, req.ID, "network manager not initialized")
-			return
-		}
-		netReq := network.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		network.HandleRequest(conn, netReq, networkManager)
-		return
-	}
+// service groups the methods of one package with the handler that serves
+// them. unavailable reports why calls cannot be served right now, or "" when
+// the backing manager is ready.
+type service struct {
+	title       string
+	methods     []models.MethodSpec
+	unavailable func() string
+	handle      func(conn net.Conn, req models.Request)
+}
 
-	if strings.HasPrefix(req.Method, "plugins.") {
-		serverPlugins.HandleRequest(conn, req)
-		return
-	}
+type methodEntry struct {
+	spec    models.MethodSpec
+	service *service
+}
 
-	if strings.HasPrefix(req.Method, "loginctl.") {
-		if loginctlManager == nil {
-			models.RespondError(conn, req.ID, "loginctl manager not initialized")
-			return
-		}
-		loginReq := loginctl.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		loginctl.HandleRequest(conn, loginReq, loginctlManager)
-		return
-	}
+type MethodInfo struct {
+	models.MethodSpec
+	Service   string `json:"service"`
+	Available bool   `json:"available"`
+}
 
-	if strings.HasPrefix(req.Method, "freedesktop.") {
-		if freedesktopManager == nil {
-			models.RespondError(conn, req.ID, "freedesktop manager not initialized")
-			return
-		}
-		freedeskReq := freedesktop.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		freedesktop.HandleRequest(conn, freedeskReq, freedesktopManager)
-		return
-	}
+var services []*service
+var methodIndex = make(map[string]methodEntry)
 
-	if strings.HasPrefix(req.Method, "wayland.") {
-		if waylandManager == nil {
-			models.RespondError(conn, req.ID, "wayland manager not initialized")
-			return
-		}
-		waylandReq := wayland.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		wayland.HandleRequest(conn, waylandReq, waylandManager)
-		return
-	}
+var serverMethods = []models.MethodSpec{
+	{
+		Name:        "ping",
+		Description: "Test connection",
+	},
+	{
+		Name:        "getServerInfo",
+		Description: "Get server info (API version and capabilities)",
+	},
+	{
+		Name:        "subscribe",
+		Description: "Subscribe to multiple services",
+		Params: []models.ParamSpec{
+			{Name: "services", Type: models.ParamArray, Description: "service names, defaults to all"},
+		},
+		Streaming: true,
+	},
+	{
+		Name:        "listMethods",
+		Description: "List all methods with their parameter schemas",
+	},
+	{
+		Name:        "describe",
+		Description: "Describe a single method",
+		Params: []models.ParamSpec{
+			{Name: "method", Type: models.ParamString, Required: true},
+		},
+	},
+}
 
-	if strings.HasPrefix(req.Method, "bluetooth.") {
-		if bluezManager == nil {
-			models.RespondError(conn, req.ID, "bluetooth manager not initialized")
-			return
-		}
-		bluezReq := bluez.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		bluez.HandleRequest(conn, bluezReq, bluezManager)
-		return
-	}
+func init() {
+	registerService(&service{
+		title:   "Server",
+		methods: serverMethods,
+		handle:  handleServerRequest,
+	})
+	registerService(&service{
+		title:   "Plugins",
+		methods: serverPlugins.Methods,
+		handle:  serverPlugins.HandleRequest,
+	})
+	registerService(&service{
+		title:   "Keybinds",
+		methods: serverKeybinds.Methods,
+		handle:  serverKeybinds.HandleRequest,
+	})
+	registerService(&service{
+		title:       "Network",
+		methods:     network.Methods,
+		unavailable: requireManager(func() bool { return networkManager != nil }, "network manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			network.HandleRequest(conn, network.Request{ID: req.ID, Method: req.Method, Params: req.Params}, networkManager)
+		},
+	})
+	registerService(&service{
+		title:       "Loginctl",
+		methods:     loginctl.Methods,
+		unavailable: requireManager(func() bool { return loginctlManager != nil }, "loginctl manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			loginctl.HandleRequest(conn, loginctl.Request{ID: req.ID, Method: req.Method, Params: req.Params}, loginctlManager)
+		},
+	})
+	registerService(&service{
+		title:       "Freedesktop",
+		methods:     freedesktop.Methods,
+		unavailable: requireManager(func() bool { return freedesktopManager != nil }, "freedesktop manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			freedesktop.HandleRequest(conn, freedesktop.Request{ID: req.ID, Method: req.Method, Params: req.Params}, freedesktopManager)
+		},
+	})
+	registerService(&service{
+		title:       "Wayland",
+		methods:     wayland.Methods,
+		unavailable: requireManager(func() bool { return waylandManager != nil }, "wayland manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			wayland.HandleRequest(conn, wayland.Request{ID: req.ID, Method: req.Method, Params: req.Params}, waylandManager)
+		},
+	})
+	registerService(&service{
+		title:       "Bluetooth",
+		methods:     bluez.Methods,
+		unavailable: requireManager(func() bool { return bluezManager != nil }, "bluetooth manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			bluez.HandleRequest(conn, bluez.Request{ID: req.ID, Method: req.Method, Params: req.Params}, bluezManager)
+		},
+	})
+	registerService(&service{
+		title:       "CUPS",
+		methods:     cups.Methods,
+		unavailable: requireManager(func() bool { return cupsManager != nil }, "CUPS manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			cups.HandleRequest(conn, cups.Request{ID: req.ID, Method: req.Method, Params: req.Params}, cupsManager)
+		},
+	})
+	registerService(&service{
+		title:       "DWL",
+		methods:     dwl.Methods,
+		unavailable: requireManager(func() bool { return dwlManager != nil }, "dwl manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			dwl.HandleRequest(conn, dwl.Request{ID: req.ID, Method: req.Method, Params: req.Params}, dwlManager)
+		},
+	})
+	registerService(&service{
+		title:       "ExtWorkspace",
+		methods:     extworkspace.Methods,
+		unavailable: requireManager(func() bool { return extWorkspaceManager != nil }, "extworkspace manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			extworkspace.HandleRequest(conn, extworkspace.Request{ID: req.ID, Method: req.Method, Params: req.Params}, extWorkspaceManager)
+		},
+	})
+	registerService(&service{
+		title:       "Idle",
+		methods:     idle.Methods,
+		unavailable: requireManager(func() bool { return idleManager != nil }, "idle manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			idle.HandleRequest(conn, idle.Request{ID: req.ID, Method: req.Method, Params: req.Params}, idleManager)
+		},
+	})
+	registerService(&service{
+		title:       "Brightness",
+		methods:     brightness.Methods,
+		unavailable: requireManager(func() bool { return brightnessManager != nil }, "brightness manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			brightness.HandleRequest(conn, brightness.Request{ID: req.ID, Method: req.Method, Params: req.Params}, brightnessManager)
+		},
+	})
+	registerService(&service{
+		title:       "WlrOutput",
+		methods:     wlroutput.Methods,
+		unavailable: requireManager(func() bool { return wlrOutputManager != nil }, "wlroutput manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			wlroutput.HandleRequest(conn, wlroutput.Request{ID: req.ID, Method: req.Method, Params: req.Params}, wlrOutputManager)
+		},
+	})
+	registerService(&service{
+		title:       "Evdev",
+		methods:     evdev.Methods,
+		unavailable: requireManager(func() bool { return evdevManager != nil }, "evdev manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			evdev.HandleRequest(conn, evdev.Request{ID: req.ID, Method: req.Method, Params: req.Params}, evdevManager)
+		},
+	})
+	registerService(&service{
+		title:       "Keyboard",
+		methods:     keyboard.Methods,
+		unavailable: requireManager(func() bool { return keyboardManager != nil }, "keyboard manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			keyboard.HandleRequest(conn, keyboard.Request{ID: req.ID, Method: req.Method, Params: req.Params}, keyboardManager)
+		},
+	})
+	registerService(&service{
+		title:       "Tray",
+		methods:     tray.Methods,
+		unavailable: requireManager(func() bool { return trayManager != nil }, "tray manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			tray.HandleRequest(conn, tray.Request{ID: req.ID, Method: req.Method, Params: req.Params}, trayManager)
+		},
+	})
+	registerService(&service{
+		title:       "Notifications",
+		methods:     notifications.Methods,
+		unavailable: requireManager(func() bool { return notificationsManager != nil }, "notifications manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			notifications.HandleRequest(conn, notifications.Request{ID: req.ID, Method: req.Method, Params: req.Params}, notificationsManager)
+		},
+	})
+	registerService(&service{
+		title:       "Audio",
+		methods:     audio.Methods,
+		unavailable: requireManager(func() bool { return audioManager != nil }, "audio manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			audio.HandleRequest(conn, audio.Request{ID: req.ID, Method: req.Method, Params: req.Params}, audioManager)
+		},
+	})
+	registerService(&service{
+		title:       "Power",
+		methods:     power.Methods,
+		unavailable: requireManager(func() bool { return powerManager != nil }, "power manager not initialized"),
+		handle: func(conn net.Conn, req models.Request) {
+			power.HandleRequest(conn, power.Request{ID: req.ID, Method: req.Method, Params: req.Params}, powerManager)
+		},
+	})
+}
 
-	if strings.HasPrefix(req.Method, "cups.") {
-		if cupsManager == nil {
-			models.RespondError(conn, req.ID, "CUPS manager not initialized")
-			return
-		}
-		cupsReq := cups.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
+func requireManager(ready func() bool, msg string) func() string {
+	return func() string {
+		if ready() {
+			return ""
 		}
-		cups.HandleRequest(conn, cupsReq, cupsManager)
-		return
+		return msg
 	}
+}
 
-	if strings.HasPrefix(req.Method, "dwl.") {
-		if dwlManager == nil {
-			models.RespondError(conn, req.ID, "dwl manager not initialized")
-			return
-		}
-		dwlReq := dwl.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
+func registerService(svc *service) {
+	for _, spec := range svc.methods {
+		if _, exists := methodIndex[spec.Name]; exists {
+			panic(fmt.Sprintf("method %s registered twice", spec.Name))
 		}
-		dwl.HandleRequest(conn, dwlReq, dwlManager)
-		return
+		methodIndex[spec.Name] = methodEntry{spec: spec, service: svc}
 	}
+	services = append(services, svc)
+}
 
-	if strings.HasPrefix(req.Method, "brightness.") {
-		if brightnessManager == nil {
-			models.RespondError(conn, req.ID, "brightness manager not initialized")
-			return
-		}
-		brightnessReq := brightness.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		brightness.HandleRequest(conn, brightnessReq, brightnessManager)
+func RouteRequest(conn net.Conn, req models.Request) {
+	entry, ok := methodIndex[req.Method]
+	if !ok {
+		models.RespondErrorCode(conn, req.ID, models.ErrCodeMethodNotFound, fmt.Sprintf("unknown method: %s", req.Method))
 		return
 	}
 
-	if strings.HasPrefix(req.Method, "extworkspace.") {
-		if extWorkspaceManager == nil {
-			models.RespondError(conn, req.ID, "extworkspace manager not initialized")
+	if entry.service.unavailable != nil {
+		if msg := entry.service.unavailable(); msg != "" {
+			models.RespondErrorCode(conn, req.ID, models.ErrCodeServer, msg)
 			return
 		}
-		extWorkspaceReq := extworkspace.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		extworkspace.HandleRequest(conn, extWorkspaceReq, extWorkspaceManager)
-		return
 	}
 
-	if strings.HasPrefix(req.Method, "wlroutput.") {
-		if wlrOutputManager == nil {
-			models.RespondError(conn, req.ID, "wlroutput manager not initialized")
-			return
-		}
-		wlrOutputReq := wlroutput.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		wlroutput.HandleRequest(conn, wlrOutputReq, wlrOutputManager)
+	if err := entry.spec.Validate(req.Params); err != nil {
+		models.RespondErrorCode(conn, req.ID, models.ErrCodeInvalidParams, err.Error())
 		return
 	}
 
-	if strings.HasPrefix(req.Method, "evdev.") {
-		if evdevManager == nil {
-			models.RespondError(conn, req.ID, "evdev manager not initialized")
-			return
-		}
-		evdevReq := evdev.Request{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-		}
-		evdev.HandleRequest(conn, evdevReq, evdevManager)
-		return
-	}
+	entry.service.handle(conn, req)
+}
 
+func handleServerRequest(conn net.Conn, req models.Request) {
 	switch req.Method {
 	case "ping":
 		models.Respond(conn, req.ID, "pong")
@@ -188,7 +286,67 @@ func RouteRequest(conn net.Conn, req models.Request) {
 		models.Respond(conn, req.ID, info)
 	case "subscribe":
 		handleSubscribe(conn, req)
+	case "listMethods":
+		models.Respond(conn, req.ID, ListMethods())
+	case "describe":
+		name, _ := req.Params["method"].(string)
+		info, ok := describeMethod(name)
+		if !ok {
+			models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", name))
+			return
+		}
+		models.Respond(conn, req.ID, info)
 	default:
 		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
 	}
 }
+
+func methodInfo(spec models.MethodSpec, svc *service) MethodInfo {
+	if spec.Params == nil {
+		spec.Params = []models.ParamSpec{}
+	}
+	return MethodInfo{
+		MethodSpec: spec,
+		Service:    svc.title,
+		Available:  svc.unavailable == nil || svc.unavailable() == "",
+	}
+}
+
+// ListMethods returns the registered methods in registration order.
+func ListMethods() []MethodInfo {
+	methods := make([]MethodInfo, 0, len(methodIndex))
+	for _, svc := range services {
+		for _, spec := range svc.methods {
+			methods = append(methods, methodInfo(spec, svc))
+		}
+	}
+	return methods
+}
+
+func describeMethod(name string) (MethodInfo, bool) {
+	entry, ok := methodIndex[name]
+	if !ok {
+		return MethodInfo{}, false
+	}
+	return methodInfo(entry.spec, entry.service), true
+}
+
+func printMethodDocs() {
+	log.Info("Available methods:")
+	for _, svc := range services {
+		log.Infof("%s:", svc.title)
+		for _, spec := range svc.methods {
+			desc := spec.Description
+			if summary := spec.ParamSummary(); summary != "" {
+				desc += " (params: " + summary + ")"
+			}
+			if spec.Streaming {
+				desc += " (streaming)"
+			}
+			log.Infof(" %-37s - %s", spec.Name, desc)
+			for _, note := range spec.Notes {
+				log.Info("     " + strings.TrimSpace(note))
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodRegistryCoversHandlers(t *testing.T) {
+	for _, name := range []string{
+		"ping",
+		"listMethods",
+		"network.ethernet.info",
+		"wayland.gamma.setUseIPLocation",
+		"cups.subscribe",
+		"power.setProfile",
+		"keybinds.check",
+	} {
+		_, ok := methodIndex[name]
+		assert.True(t, ok, "method %s not registered", name)
+	}
+}
+
+func TestRouteRequestUnknownMethod(t *testing.T) {
+	conn := &mockConn{}
+	RouteRequest(conn, models.Request{ID: 1, Method: "nope.nothing"})
+
+	var resp models.Response[any]
+	require.NoError(t, json.Unmarshal(conn.written, &resp))
+	assert.Equal(t, "unknown method: nope.nothing", resp.Error)
+}
+
+func TestRouteRequestManagerNotInitialized(t *testing.T) {
+	conn := &mockConn{}
+	RouteRequest(conn, models.Request{ID: 1, Method: "power.getState"})
+
+	var resp models.Response[any]
+	require.NoError(t, json.Unmarshal(conn.written, &resp))
+	assert.Equal(t, "power manager not initialized", resp.Error)
+}
+
+func TestRouteRequestValidatesParams(t *testing.T) {
+	conn := &mockConn{}
+	RouteRequest(conn, models.Request{
+		ID:     1,
+		Method: "describe",
+		Params: map[string]interface{}{"method": 42},
+	})
+
+	var resp models.Response[any]
+	require.NoError(t, json.Unmarshal(conn.written, &resp))
+	assert.Equal(t, "missing or invalid 'method' parameter", resp.Error)
+}
+
+func TestDescribeMethod(t *testing.T) {
+	conn := &mockConn{}
+	RouteRequest(conn, models.Request{
+		ID:     1,
+		Method: "describe",
+		Params: map[string]interface{}{"method": "wayland.gamma.setUseIPLocation"},
+	})
+
+	var resp models.Response[MethodInfo]
+	require.NoError(t, json.Unmarshal(conn.written, &resp))
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, "Wayland", resp.Result.Service)
+	assert.False(t, resp.Result.Available)
+	require.Len(t, resp.Result.Params, 1)
+	assert.Equal(t, "use", resp.Result.Params[0].Name)
+	assert.Equal(t, models.ParamBool, resp.Result.Params[0].Type)
+}
+
+func TestListMethods(t *testing.T) {
+	methods := ListMethods()
+	assert.Len(t, methods, len(methodIndex))
+	assert.Equal(t, "ping", methods[0].Name)
+	assert.True(t, methods[0].Available)
+}
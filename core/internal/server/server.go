@@ -2,11 +2,13 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 	"time"
 
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/audio"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/bluez"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/brightness"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/cups"
@@ -21,18 +24,26 @@ import (
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/evdev"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/extworkspace"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/freedesktop"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/idle"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/keyboard"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/loginctl"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/network"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/notifications"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/power"
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/tray"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/wayland"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/wlcontext"
 	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/wlroutput"
 )
 
-const APIVersion = 18
+const APIVersion = 40
+
+const maxRequestSize = 1024 * 1024
 
 type Capabilities struct {
 	Capabilities []string `json:"capabilities"`
+	Protocols    []string `json:"protocols,omitempty"`
 }
 
 type ServerInfo struct {
@@ -53,9 +64,15 @@ var bluezManager *bluez.Manager
 var cupsManager *cups.Manager
 var dwlManager *dwl.Manager
 var extWorkspaceManager *extworkspace.Manager
+var idleManager *idle.Manager
 var brightnessManager *brightness.Manager
 var wlrOutputManager *wlroutput.Manager
 var evdevManager *evdev.Manager
+var keyboardManager *keyboard.Manager
+var trayManager *tray.Manager
+var notificationsManager *notifications.Manager
+var audioManager *audio.Manager
+var powerManager *power.Manager
 var wlContext *wlcontext.SharedContext
 
 var capabilitySubscribers = make(map[string]chan ServerInfo)
@@ -64,6 +81,10 @@ var capabilityMutex sync.RWMutex
 var cupsSubscribers = make(map[string]bool)
 var cupsSubscribersMutex sync.Mutex
 
+// sessionHandoffMutex orders the loginctl and idle initializers, which run on
+// different goroutines, so whichever finishes last attaches the session.
+var sessionHandoffMutex sync.Mutex
+
 func getSocketDir() string {
 	if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
 		return runtime
@@ -83,6 +104,51 @@ func GetSocketPath() string {
 	return filepath.Join(getSocketDir(), fmt.Sprintf("danklinux-%d.sock", os.Getpid()))
 }
 
+// FindSocket returns the socket of a running server, preferring DMS_SOCKET
+// and otherwise the most recently started live server.
+func FindSocket() (string, error) {
+	if path := os.Getenv("DMS_SOCKET"); path != "" {
+		return path, nil
+	}
+
+	dir := getSocketDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var found string
+	var newest time.Time
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "danklinux-") || !strings.HasSuffix(entry.Name(), ".sock") {
+			continue
+		}
+
+		pidStr := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "danklinux-"), ".sock")
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			continue
+		}
+		if process, err := os.FindProcess(pid); err != nil || process.Signal(syscall.Signal(0)) != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if found == "" || info.ModTime().After(newest) {
+			found = filepath.Join(dir, entry.Name())
+			newest = info.ModTime()
+		}
+	}
+
+	if found == "" {
+		return "", fmt.Errorf("no running dms server found in %s (is the shell running?)", dir)
+	}
+	return found, nil
+}
+
 func cleanupStaleSockets() {
 	dir := getSocketDir()
 	entries, err := os.ReadDir(dir)
@@ -139,7 +205,12 @@ func InitializeLoginctlManager() error {
 		return err
 	}
 
+	sessionHandoffMutex.Lock()
 	loginctlManager = manager
+	if idleManager != nil {
+		idleManager.SetSession(manager)
+	}
+	sessionHandoffMutex.Unlock()
 
 	log.Info("Loginctl manager initialized")
 	return nil
@@ -170,12 +241,18 @@ func InitializeWaylandManager() error {
 		wlContext = ctx
 	}
 
-	config := wayland.DefaultConfig()
+	configPath := wayland.DefaultConfigPath()
+	config, err := wayland.LoadConfig(configPath)
+	if err != nil {
+		log.Warnf("Failed to load gamma config, using defaults: %v", err)
+	}
+
 	manager, err := wayland.NewManager(wlContext.Display(), config)
 	if err != nil {
 		log.Errorf("Failed to initialize wayland manager: %v", err)
 		return err
 	}
+	manager.SetConfigPath(configPath)
 
 	waylandManager = manager
 
@@ -240,6 +317,18 @@ func InitializeBrightnessManager() error {
 		return err
 	}
 
+	autoPath := brightness.DefaultAutoBrightnessPath()
+	autoConfig, err := brightness.LoadAutoBrightnessConfig(autoPath)
+	if err != nil {
+		log.Warnf("Failed to load auto brightness config, using defaults: %v", err)
+	}
+	if err := manager.SetAutoBrightness(autoConfig); err != nil {
+		log.Warnf("Failed to restore auto brightness, keeping it disabled: %v", err)
+		autoConfig.Enabled = false
+		manager.SetAutoBrightness(autoConfig)
+	}
+	manager.SetAutoBrightnessConfigPath(autoPath)
+
 	brightnessManager = manager
 
 	log.Info("Brightness manager initialized")
@@ -270,6 +359,34 @@ func InitializeExtWorkspaceManager() error {
 	return nil
 }
 
+func InitializeIdleManager() error {
+	log.Info("Attempting to initialize idle notifications...")
+
+	if wlContext == nil {
+		ctx, err := wlcontext.New()
+		if err != nil {
+			log.Errorf("Failed to create shared Wayland context: %v", err)
+			return err
+		}
+		wlContext = ctx
+	}
+
+	manager, err := idle.NewManager(wlContext.Display())
+	if err != nil {
+		log.Debugf("Failed to initialize idle manager: %v", err)
+		return err
+	}
+	sessionHandoffMutex.Lock()
+	if loginctlManager != nil {
+		manager.SetSession(loginctlManager)
+	}
+	idleManager = manager
+	sessionHandoffMutex.Unlock()
+
+	log.Info("Idle notifications initialized successfully")
+	return nil
+}
+
 func InitializeWlrOutputManager() error {
 	log.Info("Attempting to initialize WlrOutput management...")
 
@@ -307,22 +424,108 @@ func InitializeEvdevManager() error {
 	return nil
 }
 
+func InitializeKeyboardManager() error {
+	manager, err := keyboard.InitializeManager()
+	if err != nil {
+		log.Warnf("Failed to initialize keyboard manager: %v", err)
+		return err
+	}
+
+	keyboardManager = manager
+
+	log.Info("Keyboard layout manager initialized")
+	return nil
+}
+
+func InitializeTrayManager() error {
+	manager, err := tray.NewManager()
+	if err != nil {
+		log.Warnf("Failed to initialize tray manager: %v", err)
+		return err
+	}
+
+	trayManager = manager
+
+	log.Info("Tray manager initialized")
+	return nil
+}
+
+func InitializeNotificationsManager() error {
+	manager, err := notifications.NewManager()
+	if err != nil {
+		log.Warnf("Failed to initialize notifications manager: %v", err)
+		return err
+	}
+
+	notificationsManager = manager
+
+	log.Info("Notifications manager initialized")
+	return nil
+}
+
+func InitializeAudioManager() error {
+	manager, err := audio.NewManager()
+	if err != nil {
+		log.Warnf("Failed to initialize audio manager: %v", err)
+		return err
+	}
+
+	audioManager = manager
+
+	log.Info("Audio manager initialized")
+	return nil
+}
+
+func InitializePowerManager() error {
+	manager, err := power.NewManager()
+	if err != nil {
+		log.Warnf("Failed to initialize power manager: %v", err)
+		return err
+	}
+
+	powerManager = manager
+
+	log.Info("Power manager initialized")
+	return nil
+}
+
 func handleConnection(conn net.Conn) {
 	defer conn.Close()
 
 	caps := getCapabilities()
+	caps.Protocols = []string{"dms", "jsonrpc-2.0"}
 	capsData, _ := json.Marshal(caps)
 	conn.Write(capsData)
 	conn.Write([]byte("\n"))
 
 	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+
+	// The first message picks the framing for the rest of the connection.
+	var rpc *rpcConn
+	negotiated := false
 	for scanner.Scan() {
-		line := scanner.Bytes()
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if !negotiated {
+			negotiated = true
+			if isJSONRPCMessage(line) {
+				rpc = &rpcConn{conn: conn}
+			}
+		}
+
+		if rpc != nil {
+			rpc.handleMessage(line)
+			continue
+		}
 
 		var req models.Request
 		if err := json.Unmarshal(line, &req); err != nil {
 			log.Warnf("handleConnection: Failed to unmarshal JSON: %v, line: %s", err, string(line))
-			models.RespondError(conn, 0, "invalid json")
+			models.RespondErrorCode(conn, nil, models.ErrCodeParse, "invalid json")
 			continue
 		}
 
@@ -331,7 +534,7 @@ func handleConnection(conn net.Conn) {
 }
 
 func getCapabilities() Capabilities {
-	caps := []string{"plugins"}
+	caps := []string{"plugins", "keybinds"}
 
 	if networkManager != nil {
 		caps = append(caps, "network")
@@ -365,6 +568,10 @@ func getCapabilities() Capabilities {
 		caps = append(caps, "extworkspace")
 	}
 
+	if idleManager != nil {
+		caps = append(caps, "idle")
+	}
+
 	if brightnessManager != nil {
 		caps = append(caps, "brightness")
 	}
@@ -377,11 +584,31 @@ func getCapabilities() Capabilities {
 		caps = append(caps, "evdev")
 	}
 
+	if keyboardManager != nil {
+		caps = append(caps, "keyboard")
+	}
+
+	if trayManager != nil {
+		caps = append(caps, "tray")
+	}
+
+	if notificationsManager != nil {
+		caps = append(caps, "notifications")
+	}
+
+	if audioManager != nil {
+		caps = append(caps, "audio")
+	}
+
+	if powerManager != nil {
+		caps = append(caps, "power")
+	}
+
 	return Capabilities{Capabilities: caps}
 }
 
 func getServerInfo() ServerInfo {
-	caps := []string{"plugins"}
+	caps := []string{"plugins", "keybinds"}
 
 	if networkManager != nil {
 		caps = append(caps, "network")
@@ -415,6 +642,10 @@ func getServerInfo() ServerInfo {
 		caps = append(caps, "extworkspace")
 	}
 
+	if idleManager != nil {
+		caps = append(caps, "idle")
+	}
+
 	if brightnessManager != nil {
 		caps = append(caps, "brightness")
 	}
@@ -427,6 +658,26 @@ func getServerInfo() ServerInfo {
 		caps = append(caps, "evdev")
 	}
 
+	if keyboardManager != nil {
+		caps = append(caps, "keyboard")
+	}
+
+	if trayManager != nil {
+		caps = append(caps, "tray")
+	}
+
+	if notificationsManager != nil {
+		caps = append(caps, "notifications")
+	}
+
+	if audioManager != nil {
+		caps = append(caps, "audio")
+	}
+
+	if powerManager != nil {
+		caps = append(caps, "power")
+	}
+
 	return ServerInfo{
 		APIVersion:   APIVersion,
 		Capabilities: caps,
@@ -574,6 +825,40 @@ func handleSubscribe(conn net.Conn, req models.Request) {
 		}()
 	}
 
+	// Traffic samples arrive every few seconds, so they are only sent to
+	// clients that ask for them by name.
+	if slices.Contains(services, "network.traffic") && networkManager != nil {
+		wg.Add(1)
+		trafficChan := networkManager.SubscribeTraffic(clientID + "-traffic")
+		go func() {
+			defer wg.Done()
+			defer networkManager.UnsubscribeTraffic(clientID + "-traffic")
+
+			initialState := networkManager.GetTrafficState()
+			select {
+			case eventChan <- ServiceEvent{Service: "network.traffic", Data: initialState}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case state, ok := <-trafficChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "network.traffic", Data: state}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
 	if shouldSubscribe("loginctl") && loginctlManager != nil {
 		wg.Add(1)
 		loginChan := loginctlManager.Subscribe(clientID + "-loginctl")
@@ -854,6 +1139,38 @@ func handleSubscribe(conn net.Conn, req models.Request) {
 		}()
 	}
 
+	if shouldSubscribe("idle") && idleManager != nil {
+		wg.Add(1)
+		idleChan := idleManager.Subscribe(clientID + "-idle")
+		go func() {
+			defer wg.Done()
+			defer idleManager.Unsubscribe(clientID + "-idle")
+
+			initialEvent := idle.Event{Type: idle.EventState, State: idleManager.GetState()}
+			select {
+			case eventChan <- ServiceEvent{Service: "idle", Data: initialEvent}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case event, ok := <-idleChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "idle", Data: event}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
 	if shouldSubscribe("brightness") && brightnessManager != nil {
 		wg.Add(2)
 		brightnessStateChan := brightnessManager.Subscribe(clientID + "-brightness-state")
@@ -973,6 +1290,191 @@ func handleSubscribe(conn net.Conn, req models.Request) {
 		}()
 	}
 
+	if shouldSubscribe("keyboard") && keyboardManager != nil {
+		wg.Add(1)
+		keyboardChan := keyboardManager.Subscribe(clientID + "-keyboard")
+		go func() {
+			defer wg.Done()
+			defer keyboardManager.Unsubscribe(clientID + "-keyboard")
+
+			initialState := keyboardManager.GetState()
+			select {
+			case eventChan <- ServiceEvent{Service: "keyboard", Data: initialState}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case state, ok := <-keyboardChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "keyboard", Data: state}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	if shouldSubscribe("tray") && trayManager != nil {
+		wg.Add(1)
+		trayChan := trayManager.Subscribe(clientID + "-tray")
+		go func() {
+			defer wg.Done()
+			defer trayManager.Unsubscribe(clientID + "-tray")
+
+			initialState := trayManager.GetState()
+			select {
+			case eventChan <- ServiceEvent{Service: "tray", Data: initialState}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case state, ok := <-trayChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "tray", Data: state}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	if shouldSubscribe("notifications") && notificationsManager != nil {
+		wg.Add(1)
+		notificationsChan := notificationsManager.Subscribe(clientID + "-notifications")
+		go func() {
+			defer wg.Done()
+			defer notificationsManager.Unsubscribe(clientID + "-notifications")
+
+			initialState := notificationsManager.GetState()
+			select {
+			case eventChan <- ServiceEvent{Service: "notifications", Data: notifications.Event{Type: notifications.EventState, State: &initialState}}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case event, ok := <-notificationsChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "notifications", Data: event}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	if shouldSubscribe("audio") && audioManager != nil {
+		wg.Add(1)
+		audioChan := audioManager.Subscribe(clientID + "-audio")
+		go func() {
+			defer wg.Done()
+			defer audioManager.Unsubscribe(clientID + "-audio")
+
+			initialState := audioManager.GetState()
+			select {
+			case eventChan <- ServiceEvent{Service: "audio", Data: initialState}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case state, ok := <-audioChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "audio", Data: state}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	if shouldSubscribe("power") && powerManager != nil {
+		wg.Add(1)
+		powerChan := powerManager.Subscribe(clientID + "-power")
+		go func() {
+			defer wg.Done()
+			defer powerManager.Unsubscribe(clientID + "-power")
+
+			initialState := powerManager.GetState()
+			select {
+			case eventChan <- ServiceEvent{Service: "power", Data: initialState}:
+			case <-stopChan:
+				return
+			}
+
+			for {
+				select {
+				case state, ok := <-powerChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "power", Data: state}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	if shouldSubscribe("power.alert") && powerManager != nil {
+		wg.Add(1)
+		alertChan := powerManager.SubscribeAlerts(clientID + "-power-alert")
+		go func() {
+			defer wg.Done()
+			defer powerManager.UnsubscribeAlerts(clientID + "-power-alert")
+
+			for {
+				select {
+				case alert, ok := <-alertChan:
+					if !ok {
+						return
+					}
+					select {
+					case eventChan <- ServiceEvent{Service: "power.alert", Data: alert}:
+					case <-stopChan:
+						return
+					}
+				case <-stopChan:
+					return
+				}
+			}
+		}()
+	}
+
 	go func() {
 		wg.Wait()
 		close(eventChan)
@@ -1023,6 +1525,9 @@ func cleanupManagers() {
 	if extWorkspaceManager != nil {
 		extWorkspaceManager.Close()
 	}
+	if idleManager != nil {
+		idleManager.Close()
+	}
 	if brightnessManager != nil {
 		brightnessManager.Close()
 	}
@@ -1032,6 +1537,21 @@ func cleanupManagers() {
 	if evdevManager != nil {
 		evdevManager.Close()
 	}
+	if keyboardManager != nil {
+		keyboardManager.Close()
+	}
+	if trayManager != nil {
+		trayManager.Close()
+	}
+	if notificationsManager != nil {
+		notificationsManager.Close()
+	}
+	if audioManager != nil {
+		audioManager.Close()
+	}
+	if powerManager != nil {
+		powerManager.Close()
+	}
 	if wlContext != nil {
 		wlContext.Close()
 	}
@@ -1055,134 +1575,10 @@ func Start(printDocs bool) error {
 	log.Info("Protocol: JSON over Unix socket")
 	log.Info("Request format: {\"id\": <any>, \"method\": \"...\", \"params\": {...}}")
 	log.Info("Response format: {\"id\": <any>, \"result\": {...}} or {\"id\": <any>, \"error\": \"...\"}")
+	log.Info("JSON-RPC 2.0: send \"jsonrpc\": \"2.0\" (or a batch) as the first message to switch the connection")
 	log.Info("")
 	if printDocs {
-		log.Info("Available methods:")
-		log.Info("  ping          - Test connection")
-		log.Info("  getServerInfo - Get server info (API version and capabilities)")
-		log.Info("  subscribe     - Subscribe to multiple services (params: services [default: all])")
-		log.Info("Plugins:")
-		log.Info(" plugins.list                - List all plugins")
-		log.Info(" plugins.listInstalled       - List installed plugins")
-		log.Info(" plugins.install             - Install plugin (params: name)")
-		log.Info(" plugins.uninstall           - Uninstall plugin (params: name)")
-		log.Info(" plugins.update              - Update plugin (params: name)")
-		log.Info(" plugins.search              - Search plugins (params: query, category?, compositor?, capability?)")
-		log.Info("Network:")
-		log.Info(" network.getState            - Get current network state")
-		log.Info(" network.wifi.scan           - Scan for WiFi networks")
-		log.Info(" network.wifi.networks       - Get WiFi network list")
-		log.Info(" network.wifi.connect        - Connect to WiFi (params: ssid, password?, username?)")
-		log.Info(" network.wifi.disconnect     - Disconnect WiFi")
-		log.Info(" network.wifi.forget         - Forget network (params: ssid)")
-		log.Info(" network.wifi.toggle         - Toggle WiFi radio")
-		log.Info(" network.wifi.enable         - Enable WiFi")
-		log.Info(" network.wifi.disable        - Disable WiFi")
-		log.Info(" network.wifi.setAutoconnect - Set network autoconnect (params: ssid, autoconnect)")
-		log.Info(" network.ethernet.connect    - Connect Ethernet")
-		log.Info(" network.ethernet.connect.config - Connect Ethernet to a specific configuration")
-		log.Info(" network.ethernet.disconnect - Disconnect Ethernet")
-		log.Info(" network.vpn.profiles        - List VPN profiles")
-		log.Info(" network.vpn.active          - List active VPN connections")
-		log.Info(" network.vpn.connect         - Connect VPN (params: uuidOrName|name|uuid, singleActive?)")
-		log.Info(" network.vpn.disconnect      - Disconnect VPN (params: uuidOrName|name|uuid)")
-		log.Info(" network.vpn.disconnectAll   - Disconnect all VPNs")
-		log.Info(" network.vpn.clearCredentials - Clear saved VPN credentials (params: uuidOrName|name|uuid)")
-		log.Info(" network.preference.set      - Set preference (params: preference [auto|wifi|ethernet])")
-		log.Info(" network.info                - Get network info (params: ssid)")
-		log.Info(" network.credentials.submit  - Submit credentials for prompt (params: token, secrets, save?)")
-		log.Info(" network.credentials.cancel  - Cancel credential prompt (params: token)")
-		log.Info(" network.subscribe           - Subscribe to network state changes (streaming)")
-		log.Info("Loginctl:")
-		log.Info(" loginctl.getState           - Get current session state")
-		log.Info(" loginctl.lock               - Lock session")
-		log.Info(" loginctl.unlock             - Unlock session")
-		log.Info(" loginctl.activate           - Activate session")
-		log.Info(" loginctl.setIdleHint        - Set idle hint (params: idle)")
-		log.Info(" loginctl.setLockBeforeSuspend - Set lock before suspend (params: enabled)")
-		log.Info(" loginctl.setSleepInhibitorEnabled - Enable/disable sleep inhibitor (params: enabled)")
-		log.Info(" loginctl.lockerReady        - Signal locker UI is ready (releases sleep inhibitor)")
-		log.Info(" loginctl.terminate          - Terminate session")
-		log.Info(" loginctl.subscribe          - Subscribe to session state changes (streaming)")
-		log.Info("Freedesktop:")
-		log.Info(" freedesktop.getState                  - Get accounts & settings state")
-		log.Info(" freedesktop.accounts.setIconFile      - Set profile icon (params: path)")
-		log.Info(" freedesktop.accounts.setRealName      - Set real name (params: name)")
-		log.Info(" freedesktop.accounts.setEmail         - Set email (params: email)")
-		log.Info(" freedesktop.accounts.setLanguage      - Set language (params: language)")
-		log.Info(" freedesktop.accounts.setLocation      - Set location (params: location)")
-		log.Info(" freedesktop.accounts.getUserIconFile  - Get user icon (params: username)")
-		log.Info(" freedesktop.settings.getColorScheme   - Get color scheme")
-		log.Info(" freedesktop.settings.setIconTheme     - Set icon theme (params: iconTheme)")
-		log.Info("Wayland:")
-		log.Info(" wayland.gamma.getState                - Get current gamma control state")
-		log.Info(" wayland.gamma.setTemperature          - Set temperature range (params: low, high)")
-		log.Info(" wayland.gamma.setLocation             - Set location (params: latitude, longitude)")
-		log.Info(" wayland.gamma.setManualTimes          - Set manual times (params: sunrise, sunset)")
-		log.Info(" wayland.gamma.setGamma                - Set gamma value (params: gamma)")
-		log.Info(" wayland.gamma.setEnabled              - Enable/disable gamma control (params: enabled)")
-		log.Info(" wayland.gamma.subscribe               - Subscribe to gamma state changes (streaming)")
-		log.Info("Bluetooth:")
-		log.Info(" bluetooth.getState                    - Get current bluetooth state")
-		log.Info(" bluetooth.startDiscovery              - Start device discovery")
-		log.Info(" bluetooth.stopDiscovery               - Stop device discovery")
-		log.Info(" bluetooth.setPowered                  - Set adapter power state (params: powered)")
-		log.Info(" bluetooth.pair                        - Pair with device (params: device)")
-		log.Info(" bluetooth.connect                     - Connect to device (params: device)")
-		log.Info(" bluetooth.disconnect                  - Disconnect from device (params: device)")
-		log.Info(" bluetooth.remove                      - Remove/unpair device (params: device)")
-		log.Info(" bluetooth.trust                       - Trust device (params: device)")
-		log.Info(" bluetooth.untrust                     - Untrust device (params: device)")
-		log.Info(" bluetooth.pairing.submit              - Submit pairing response (params: token, secrets?, accept?)")
-		log.Info(" bluetooth.pairing.cancel              - Cancel pairing prompt (params: token)")
-		log.Info(" bluetooth.subscribe                   - Subscribe to bluetooth state changes (streaming)")
-		log.Info("CUPS:")
-		log.Info(" cups.getPrinters                      - Get printers list")
-		log.Info(" cups.getJobs                          - Get non-completed jobs list (params: printerName)")
-		log.Info(" cups.pausePrinter                     - Pause printer (params: printerName)")
-		log.Info(" cups.resumePrinter                    - Resume printer (params: printerName)")
-		log.Info(" cups.cancelJob                        - Cancel job (params: printerName, jobID)")
-		log.Info(" cups.purgeJobs                        - Cancel all jobs (params: printerName)")
-		log.Info("DWL:")
-		log.Info(" dwl.getState                          - Get current dwl state (tags, windows, layouts)")
-		log.Info(" dwl.setTags                           - Set active tags (params: output, tagmask, toggleTagset)")
-		log.Info(" dwl.setClientTags                     - Set focused client tags (params: output, andTags, xorTags)")
-		log.Info(" dwl.setLayout                         - Set layout (params: output, index)")
-		log.Info(" dwl.subscribe                         - Subscribe to dwl state changes (streaming)")
-		log.Info("ExtWorkspace:")
-		log.Info(" extworkspace.getState                 - Get current workspace state (groups, workspaces)")
-		log.Info(" extworkspace.activateWorkspace        - Activate workspace (params: groupID, workspaceID)")
-		log.Info(" extworkspace.deactivateWorkspace      - Deactivate workspace (params: groupID, workspaceID)")
-		log.Info(" extworkspace.removeWorkspace          - Remove workspace (params: groupID, workspaceID)")
-		log.Info(" extworkspace.createWorkspace          - Create workspace (params: groupID, name)")
-		log.Info(" extworkspace.subscribe                - Subscribe to workspace state changes (streaming)")
-		log.Info("Brightness:")
-		log.Info(" brightness.getState                   - Get current brightness state for all devices")
-		log.Info(" brightness.setBrightness              - Set device brightness (params: device, percent)")
-		log.Info(" brightness.increment                  - Increment device brightness (params: device, step?)")
-		log.Info(" brightness.decrement                  - Decrement device brightness (params: device, step?)")
-		log.Info(" brightness.rescan                     - Rescan for brightness devices (e.g., after plugging in monitor)")
-		log.Info(" brightness.subscribe                  - Subscribe to brightness state changes (streaming)")
-		log.Info("   Subscription events:")
-		log.Info("     - brightness       : Full device list (on rescan, DDC discovery, device changes)")
-		log.Info("     - brightness.update: Single device update (on brightness change for efficiency)")
-		log.Info("WlrOutput:")
-		log.Info(" wlroutput.getState                    - Get current output configuration state")
-		log.Info(" wlroutput.applyConfiguration          - Apply output configuration (params: heads)")
-		log.Info(" wlroutput.testConfiguration           - Test output configuration without applying (params: heads)")
-		log.Info(" wlroutput.subscribe                   - Subscribe to output state changes (streaming)")
-		log.Info("   Head configuration params:")
-		log.Info("     - name         : Output name (required)")
-		log.Info("     - enabled      : Enable/disable output (required)")
-		log.Info("     - modeId       : Mode ID from available modes (optional)")
-		log.Info("     - customMode   : Custom mode {width, height, refresh} (optional)")
-		log.Info("     - position     : Position {x, y} (optional)")
-		log.Info("     - transform    : Transform value (optional)")
-		log.Info("     - scale        : Scale value (optional)")
-		log.Info("     - adaptiveSync : Adaptive sync state (optional)")
-		log.Info("Evdev:")
-		log.Info(" evdev.getState                        - Get current evdev state (caps lock)")
-		log.Info(" evdev.subscribe                       - Subscribe to evdev state changes (streaming)")
+		printMethodDocs()
 		log.Info("")
 	}
 	log.Info("Initializing managers...")
@@ -1248,6 +1644,10 @@ func Start(printDocs bool) error {
 		log.Debugf("ExtWorkspace manager unavailable: %v", err)
 	}
 
+	if err := InitializeIdleManager(); err != nil {
+		log.Debugf("Idle manager unavailable: %v", err)
+	}
+
 	if err := InitializeWlrOutputManager(); err != nil {
 		log.Debugf("WlrOutput manager unavailable: %v", err)
 	}
@@ -1276,6 +1676,60 @@ func Start(printDocs bool) error {
 		}
 	}()
 
+	go func() {
+		if err := InitializeKeyboardManager(); err != nil {
+			log.Debugf("Keyboard manager unavailable: %v", err)
+		} else {
+			notifyCapabilityChange()
+		}
+	}()
+
+	go func() {
+		if err := InitializeTrayManager(); err != nil {
+			log.Warnf("Tray manager unavailable: %v", err)
+		} else {
+			notifyCapabilityChange()
+		}
+	}()
+
+	go func() {
+		if err := InitializeNotificationsManager(); err != nil {
+			log.Warnf("Notifications manager unavailable: %v", err)
+		} else {
+			notifyCapabilityChange()
+		}
+	}()
+
+	go func() {
+		if err := InitializePowerManager(); err != nil {
+			log.Warnf("Power manager unavailable: %v", err)
+		} else {
+			notifyCapabilityChange()
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		if err := InitializeAudioManager(); err != nil {
+			log.Debugf("Audio manager unavailable: %v", err)
+		} else {
+			notifyCapabilityChange()
+			return
+		}
+
+		for range ticker.C {
+			if audioManager != nil {
+				return
+			}
+			if err := InitializeAudioManager(); err == nil {
+				notifyCapabilityChange()
+				return
+			}
+		}
+	}()
+
 	if wlContext != nil {
 		wlContext.Start()
 		log.Info("Wayland event dispatcher started")
@@ -88,7 +88,7 @@ func TestRespondError(t *testing.T) {
 	err := json.Unmarshal(conn.written, &resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Equal(t, "test error", resp.Error)
 	assert.Nil(t, resp.Result)
 }
@@ -102,7 +102,7 @@ func TestRespond(t *testing.T) {
 	err := json.Unmarshal(conn.written, &resp)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, resp.ID)
+	assert.EqualValues(t, 123, resp.ID)
 	assert.Empty(t, resp.Error)
 	require.NotNil(t, resp.Result)
 	assert.Equal(t, "bar", (*resp.Result)["foo"])
@@ -114,7 +114,7 @@ func TestRequest_JSON(t *testing.T) {
 	err := json.Unmarshal([]byte(jsonStr), &req)
 	require.NoError(t, err)
 
-	assert.Equal(t, 123, req.ID)
+	assert.Equal(t, json.Number("123"), req.ID)
 	assert.Equal(t, "test.method", req.Method)
 	assert.Equal(t, "value", req.Params["key"])
 }
@@ -134,7 +134,7 @@ func TestResponse_JSON(t *testing.T) {
 		err = json.Unmarshal(data, &decoded)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, decoded.ID)
+		assert.EqualValues(t, 123, decoded.ID)
 		assert.Equal(t, "success", *decoded.Result)
 		assert.Empty(t, decoded.Error)
 	})
@@ -152,7 +152,7 @@ func TestResponse_JSON(t *testing.T) {
 		err = json.Unmarshal(data, &decoded)
 		require.NoError(t, err)
 
-		assert.Equal(t, 123, decoded.ID)
+		assert.EqualValues(t, 123, decoded.ID)
 		assert.Equal(t, "test error", decoded.Error)
 		assert.Nil(t, decoded.Result)
 	})
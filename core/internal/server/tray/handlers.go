@@ -0,0 +1,163 @@
+package tray
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+)
+
+type Request struct {
+	ID     interface{}            `json:"id,omitempty"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type SuccessResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func HandleRequest(conn net.Conn, req Request, manager *Manager) {
+	if manager == nil {
+		models.RespondError(conn, req.ID, "tray manager not initialized")
+		return
+	}
+
+	switch req.Method {
+	case "tray.getState":
+		models.Respond(conn, req.ID, manager.GetState())
+	case "tray.getMenu":
+		handleGetMenu(conn, req, manager)
+	case "tray.menuEvent":
+		handleMenuEvent(conn, req, manager)
+	case "tray.activate", "tray.secondaryActivate", "tray.contextMenu":
+		handleActivate(conn, req, manager)
+	case "tray.scroll":
+		handleScroll(conn, req, manager)
+	case "tray.subscribe":
+		handleSubscribe(conn, req, manager)
+	default:
+		models.RespondError(conn, req.ID, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func intParam(params map[string]interface{}, name string) int32 {
+	value, _ := params[name].(float64)
+	return int32(value)
+}
+
+func handleGetMenu(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	menu, err := manager.GetMenu(id, intParam(req.Params, "parentId"))
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, menu)
+}
+
+func handleMenuEvent(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	if _, ok := req.Params["menuItemId"].(float64); !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'menuItemId' parameter"))
+		return
+	}
+
+	event, _ := req.Params["event"].(string)
+	if event == "" {
+		event = "clicked"
+	}
+
+	if err := manager.MenuEvent(id, intParam(req.Params, "menuItemId"), event); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "menu event sent"})
+}
+
+func handleActivate(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	x, y := intParam(req.Params, "x"), intParam(req.Params, "y")
+
+	var err error
+	switch req.Method {
+	case "tray.activate":
+		err = manager.Activate(id, x, y)
+	case "tray.secondaryActivate":
+		err = manager.SecondaryActivate(id, x, y)
+	case "tray.contextMenu":
+		err = manager.ContextMenu(id, x, y)
+	}
+	if err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "activated"})
+}
+
+func handleScroll(conn net.Conn, req Request, manager *Manager) {
+	id, ok := req.Params["id"].(string)
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'id' parameter"))
+		return
+	}
+
+	if _, ok := req.Params["delta"].(float64); !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'delta' parameter"))
+		return
+	}
+
+	orientation, _ := req.Params["orientation"].(string)
+	if orientation == "" {
+		orientation = "vertical"
+	}
+
+	if err := manager.Scroll(id, intParam(req.Params, "delta"), orientation); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "scrolled"})
+}
+
+func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
+	clientID := fmt.Sprintf("client-%p", conn)
+	stateChan := manager.Subscribe(clientID)
+	defer manager.Unsubscribe(clientID)
+
+	initialState := manager.GetState()
+	if err := json.NewEncoder(conn).Encode(models.Response[State]{
+		ID:     req.ID,
+		Result: &initialState,
+	}); err != nil {
+		return
+	}
+
+	for state := range stateChan {
+		if err := json.NewEncoder(conn).Encode(models.Response[State]{
+			Result: &state,
+		}); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package tray
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+)
+
+// refreshCoalesce batches the bursts of New* signals some items emit when
+// they change several properties at once.
+const refreshCoalesce = 50 * time.Millisecond
+
+type rawPixmap struct {
+	Width  int32
+	Height int32
+	Data   []byte
+}
+
+type rawToolTip struct {
+	IconName    string
+	IconPixmaps []rawPixmap
+	Title       string
+	Description string
+}
+
+// convertPixmaps turns SNI ARGB32 (network byte order) pixmaps into PNGs,
+// skipping malformed ones.
+func convertPixmaps(raw []rawPixmap) []Pixmap {
+	pixmaps := []Pixmap{}
+	for _, p := range raw {
+		w, h := int(p.Width), int(p.Height)
+		if w <= 0 || h <= 0 || len(p.Data) < w*h*4 {
+			continue
+		}
+
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for i := 0; i < w*h; i++ {
+			a, r, g, b := p.Data[i*4], p.Data[i*4+1], p.Data[i*4+2], p.Data[i*4+3]
+			img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = r, g, b, a
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			continue
+		}
+		pixmaps = append(pixmaps, Pixmap{Width: w, Height: h, PNG: base64.StdEncoding.EncodeToString(buf.Bytes())})
+	}
+	return pixmaps
+}
+
+func parseItemProperties(props map[string]dbus.Variant, item *Item) {
+	str := func(key string) string {
+		if v, ok := props[key]; ok {
+			if s, ok := v.Value().(string); ok {
+				return s
+			}
+		}
+		return ""
+	}
+	pixmaps := func(key string) []Pixmap {
+		var raw []rawPixmap
+		if v, ok := props[key]; ok {
+			if err := v.Store(&raw); err != nil {
+				log.Debugf("[Tray] invalid %s on %s: %v", key, item.ID, err)
+			}
+		}
+		return convertPixmaps(raw)
+	}
+
+	item.ItemID = str("Id")
+	item.Category = str("Category")
+	item.Title = str("Title")
+	item.Status = str("Status")
+	item.IconName = str("IconName")
+	item.IconThemePath = str("IconThemePath")
+	item.IconPixmaps = pixmaps("IconPixmap")
+	item.OverlayIconName = str("OverlayIconName")
+	item.OverlayIconPixmaps = pixmaps("OverlayIconPixmap")
+	item.AttentionIconName = str("AttentionIconName")
+	item.AttentionIconPixmaps = pixmaps("AttentionIconPixmap")
+	item.AttentionMovieName = str("AttentionMovieName")
+
+	switch id := props["WindowId"].Value().(type) {
+	case int32:
+		item.WindowID = int(id)
+	case uint32:
+		item.WindowID = int(id)
+	}
+
+	if v, ok := props["ItemIsMenu"]; ok {
+		item.ItemIsMenu, _ = v.Value().(bool)
+	}
+
+	item.Menu = ""
+	if v, ok := props["Menu"]; ok {
+		if path, ok := v.Value().(dbus.ObjectPath); ok && path != "/" {
+			item.Menu = string(path)
+		}
+	}
+
+	item.ToolTip = nil
+	if v, ok := props["ToolTip"]; ok {
+		var raw rawToolTip
+		if err := v.Store(&raw); err == nil && (raw.Title != "" || raw.Description != "" || raw.IconName != "" || len(raw.IconPixmaps) > 0) {
+			item.ToolTip = &ToolTip{
+				IconName:    raw.IconName,
+				IconPixmaps: convertPixmaps(raw.IconPixmaps),
+				Title:       raw.Title,
+				Description: raw.Description,
+			}
+		}
+	}
+}
+
+func (m *Manager) fetchItemProperties(busName, path string) (map[string]dbus.Variant, error) {
+	var props map[string]dbus.Variant
+	obj := m.conn.Object(busName, dbus.ObjectPath(path))
+	if err := obj.Call("org.freedesktop.DBus.Properties.GetAll", 0, itemIface).Store(&props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func (m *Manager) addItem(busName, path string) {
+	owner := busName
+	if !strings.HasPrefix(busName, ":") {
+		if err := m.conn.BusObject().Call(dbusName+".GetNameOwner", 0, busName).Store(&owner); err != nil {
+			log.Debugf("[Tray] item %s%s has no owner: %v", busName, path, err)
+			return
+		}
+	}
+
+	props, err := m.fetchItemProperties(busName, path)
+	if err != nil {
+		log.Debugf("[Tray] failed to read item %s%s: %v", busName, path, err)
+		return
+	}
+
+	item := Item{ID: busName + path, Service: busName, Path: path}
+	parseItemProperties(props, &item)
+	m.storeItem(item, owner)
+}
+
+func (m *Manager) storeItem(item Item, owner string) {
+	m.itemsMutex.Lock()
+	existing, exists := m.items[item.ID]
+	if exists {
+		item.MenuRevision = existing.MenuRevision
+		existing.Item = item
+		existing.owner = owner
+	} else {
+		m.nextSeq++
+		m.items[item.ID] = &trackedItem{Item: item, owner: owner, seq: m.nextSeq}
+	}
+	ids := m.itemIDsLocked()
+	m.itemsMutex.Unlock()
+
+	if !exists {
+		log.Infof("[Tray] item registered: %s (%s)", item.ID, item.ItemID)
+		m.updateRegisteredItems(ids)
+		m.emitWatcherSignal("StatusNotifierItemRegistered", item.ID)
+	}
+	m.notifySubscribers()
+}
+
+func (m *Manager) refreshItem(id string) {
+	m.itemsMutex.Lock()
+	tracked, ok := m.items[id]
+	if !ok {
+		m.itemsMutex.Unlock()
+		return
+	}
+	tracked.pending = false
+	item := tracked.Item
+	owner := tracked.owner
+	m.itemsMutex.Unlock()
+
+	props, err := m.fetchItemProperties(item.Service, item.Path)
+	if err != nil {
+		log.Debugf("[Tray] failed to refresh item %s: %v", id, err)
+		return
+	}
+	parseItemProperties(props, &item)
+
+	m.itemsMutex.RLock()
+	_, ok = m.items[id]
+	m.itemsMutex.RUnlock()
+	if ok {
+		m.storeItem(item, owner)
+	}
+}
+
+func (m *Manager) scheduleRefresh(id string) {
+	m.itemsMutex.Lock()
+	tracked, ok := m.items[id]
+	if !ok || tracked.pending {
+		m.itemsMutex.Unlock()
+		return
+	}
+	tracked.pending = true
+	m.itemsMutex.Unlock()
+
+	time.AfterFunc(refreshCoalesce, func() { m.refreshItem(id) })
+}
+
+func (m *Manager) removeItems(match func(*trackedItem) bool) {
+	m.itemsMutex.Lock()
+	var removed []string
+	for id, tracked := range m.items {
+		if match(tracked) {
+			removed = append(removed, id)
+			delete(m.items, id)
+		}
+	}
+	ids := m.itemIDsLocked()
+	m.itemsMutex.Unlock()
+
+	if len(removed) == 0 {
+		return
+	}
+
+	m.updateRegisteredItems(ids)
+	for _, id := range removed {
+		log.Infof("[Tray] item unregistered: %s", id)
+		m.emitWatcherSignal("StatusNotifierItemUnregistered", id)
+	}
+	m.notifySubscribers()
+}
+
+func (m *Manager) findItem(owner string, path dbus.ObjectPath) string {
+	m.itemsMutex.RLock()
+	defer m.itemsMutex.RUnlock()
+	for id, tracked := range m.items {
+		if tracked.owner == owner && tracked.Path == string(path) {
+			return id
+		}
+	}
+	return ""
+}
+
+func (m *Manager) bumpMenuRevision(owner string, path dbus.ObjectPath) {
+	m.itemsMutex.Lock()
+	changed := false
+	for _, tracked := range m.items {
+		if tracked.owner == owner && tracked.Menu == string(path) {
+			tracked.MenuRevision++
+			changed = true
+		}
+	}
+	m.itemsMutex.Unlock()
+
+	if changed {
+		m.notifySubscribers()
+	}
+}
+
+func (m *Manager) sortedItemsLocked() []*trackedItem {
+	items := make([]*trackedItem, 0, len(m.items))
+	for _, tracked := range m.items {
+		items = append(items, tracked)
+	}
+	slices.SortFunc(items, func(a, b *trackedItem) int {
+		return int(a.seq) - int(b.seq)
+	})
+	return items
+}
+
+func (m *Manager) itemIDsLocked() []string {
+	ids := make([]string, 0, len(m.items))
+	for _, tracked := range m.sortedItemsLocked() {
+		ids = append(ids, tracked.ID)
+	}
+	return ids
+}
+
+func (m *Manager) lookupItem(id string) (Item, error) {
+	m.itemsMutex.RLock()
+	defer m.itemsMutex.RUnlock()
+	tracked, ok := m.items[id]
+	if !ok {
+		return Item{}, fmt.Errorf("tray item not found: %s", id)
+	}
+	return tracked.Item, nil
+}
@@ -0,0 +1,119 @@
+package tray
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseItemService(t *testing.T) {
+	tests := []struct {
+		sender, service string
+		busName, path   string
+	}{
+		{":1.42", "org.kde.StatusNotifierItem-1234-1", "org.kde.StatusNotifierItem-1234-1", defaultItemPath},
+		{":1.42", "/org/ayatana/NotificationItem/nm_applet", ":1.42", "/org/ayatana/NotificationItem/nm_applet"},
+		{":1.42", "", ":1.42", defaultItemPath},
+		{"", ":1.7/StatusNotifierItem", ":1.7", "/StatusNotifierItem"},
+	}
+
+	for _, tt := range tests {
+		busName, path := parseItemService(tt.sender, tt.service)
+		assert.Equal(t, tt.busName, busName, tt.service)
+		assert.Equal(t, tt.path, path, tt.service)
+	}
+}
+
+func TestConvertPixmaps(t *testing.T) {
+	pixmaps := convertPixmaps([]rawPixmap{
+		{Width: 1, Height: 2, Data: []byte{0xff, 0x10, 0x20, 0x30, 0x80, 0x40, 0x50, 0x60}},
+		{Width: 4, Height: 4, Data: []byte{0x00}},
+	})
+	require.Len(t, pixmaps, 1)
+	assert.Equal(t, 1, pixmaps[0].Width)
+	assert.Equal(t, 2, pixmaps[0].Height)
+
+	data, err := base64.StdEncoding.DecodeString(pixmaps[0].PNG)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	r, g, b, a := img.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0x10, 0x20, 0x30, 0xff}, []uint32{r >> 8, g >> 8, b >> 8, a >> 8})
+	_, _, _, a = img.At(0, 1).RGBA()
+	assert.Equal(t, uint32(0x80), a>>8)
+}
+
+func TestParseItemProperties(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"Id":         dbus.MakeVariant("nm-applet"),
+		"Category":   dbus.MakeVariant("SystemServices"),
+		"Status":     dbus.MakeVariant("Active"),
+		"IconName":   dbus.MakeVariant("nm-signal-75"),
+		"WindowId":   dbus.MakeVariant(uint32(7)),
+		"ItemIsMenu": dbus.MakeVariant(true),
+		"Menu":       dbus.MakeVariant(dbus.ObjectPath("/MenuBar")),
+		"IconPixmap": dbus.MakeVariant([]rawPixmap{{Width: 1, Height: 1, Data: []byte{0xff, 0, 0, 0}}}),
+		"ToolTip":    dbus.MakeVariant(rawToolTip{Title: "Wi-Fi", Description: "Connected", IconPixmaps: []rawPixmap{}}),
+	}
+
+	item := Item{ID: ":1.5/StatusNotifierItem"}
+	parseItemProperties(props, &item)
+
+	assert.Equal(t, "nm-applet", item.ItemID)
+	assert.Equal(t, "SystemServices", item.Category)
+	assert.Equal(t, "Active", item.Status)
+	assert.Equal(t, "nm-signal-75", item.IconName)
+	assert.Equal(t, 7, item.WindowID)
+	assert.True(t, item.ItemIsMenu)
+	assert.Equal(t, "/MenuBar", item.Menu)
+	assert.Len(t, item.IconPixmaps, 1)
+	require.NotNil(t, item.ToolTip)
+	assert.Equal(t, "Connected", item.ToolTip.Description)
+
+	parseItemProperties(map[string]dbus.Variant{"Menu": dbus.MakeVariant(dbus.ObjectPath("/"))}, &item)
+	assert.Empty(t, item.Menu)
+	assert.Nil(t, item.ToolTip)
+	assert.Empty(t, item.IconPixmaps)
+}
+
+func TestManager_ItemTracking(t *testing.T) {
+	m := newManager(nil)
+	ch := m.Subscribe("test")
+
+	m.storeItem(Item{ID: ":1.9/StatusNotifierItem", Service: ":1.9", Path: "/StatusNotifierItem", Menu: "/Menu"}, ":1.9")
+	m.storeItem(Item{ID: "org.kde.StatusNotifierItem-5-1/StatusNotifierItem", Service: "org.kde.StatusNotifierItem-5-1", Path: "/StatusNotifierItem"}, ":1.5")
+	<-ch
+	state := <-ch
+	require.Len(t, state.Items, 2)
+	assert.Equal(t, ":1.9/StatusNotifierItem", state.Items[0].ID, "registration order is kept")
+
+	m.bumpMenuRevision(":1.9", "/Menu")
+	state = <-ch
+	assert.Equal(t, uint32(1), state.Items[0].MenuRevision)
+
+	m.storeItem(Item{ID: ":1.9/StatusNotifierItem", Service: ":1.9", Path: "/StatusNotifierItem", Menu: "/Menu", Title: "updated"}, ":1.9")
+	state = <-ch
+	assert.Equal(t, "updated", state.Items[0].Title)
+	assert.Equal(t, uint32(1), state.Items[0].MenuRevision, "menu revision survives property refreshes")
+
+	assert.Equal(t, "org.kde.StatusNotifierItem-5-1/StatusNotifierItem", m.findItem(":1.5", "/StatusNotifierItem"))
+
+	m.handleSignal(&dbus.Signal{
+		Sender: dbusName,
+		Name:   dbusName + ".NameOwnerChanged",
+		Body:   []interface{}{"org.kde.StatusNotifierItem-5-1", ":1.5", ""},
+	})
+	state = <-ch
+	require.Len(t, state.Items, 1)
+	assert.Equal(t, ":1.9/StatusNotifierItem", state.Items[0].ID)
+
+	_, err := m.lookupItem("missing")
+	assert.Error(t, err)
+	assert.Error(t, m.Scroll(":1.9/StatusNotifierItem", 1, "diagonal"))
+}
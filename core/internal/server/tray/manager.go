@@ -0,0 +1,226 @@
+package tray
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+)
+
+func NewManager() (*Manager, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	m := newManager(conn)
+	if err := m.start(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func newManager(conn *dbus.Conn) *Manager {
+	return &Manager{
+		conn:        conn,
+		items:       make(map[string]*trackedItem),
+		subscribers: make(map[string]chan State),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+func (m *Manager) start() error {
+	if err := m.exportWatcher(); err != nil {
+		return err
+	}
+
+	matches := [][]dbus.MatchOption{
+		{dbus.WithMatchSender(dbusName), dbus.WithMatchInterface(dbusName), dbus.WithMatchMember("NameOwnerChanged")},
+		{dbus.WithMatchInterface(itemIface)},
+		{dbus.WithMatchInterface(menuIface), dbus.WithMatchMember("LayoutUpdated")},
+		{dbus.WithMatchInterface(menuIface), dbus.WithMatchMember("ItemsPropertiesUpdated")},
+	}
+	for _, match := range matches {
+		if err := m.conn.AddMatchSignal(match...); err != nil {
+			return fmt.Errorf("failed to add signal match: %w", err)
+		}
+	}
+
+	m.signals = make(chan *dbus.Signal, 256)
+	m.conn.Signal(m.signals)
+
+	owns, err := m.claimWatcher()
+	if err != nil {
+		return err
+	}
+	if owns {
+		m.ownsWatcher.Store(true)
+		m.emitWatcherSignal("StatusNotifierHostRegistered")
+		log.Info("[Tray] owning " + watcherName)
+	} else {
+		if err := m.registerAsHost(); err != nil {
+			return err
+		}
+		log.Infof("[Tray] %s is owned elsewhere, registered as host %s", watcherName, m.hostName)
+	}
+
+	m.wg.Add(1)
+	go m.handleSignals()
+	return nil
+}
+
+func (m *Manager) handleSignals() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case sig, ok := <-m.signals:
+			if !ok {
+				return
+			}
+			m.handleSignal(sig)
+		}
+	}
+}
+
+func (m *Manager) handleSignal(sig *dbus.Signal) {
+	switch {
+	case sig.Name == dbusName+".NameOwnerChanged":
+		if len(sig.Body) != 3 {
+			return
+		}
+		name, _ := sig.Body[0].(string)
+		oldOwner, _ := sig.Body[1].(string)
+		newOwner, _ := sig.Body[2].(string)
+		if newOwner != "" {
+			return
+		}
+		if oldOwner != "" {
+			m.removeItems(func(t *trackedItem) bool { return t.owner == oldOwner })
+		}
+		if name == watcherName && !m.ownsWatcher.Load() {
+			m.takeOverWatcher()
+		}
+
+	case strings.HasPrefix(sig.Name, itemIface+"."):
+		if id := m.findItem(sig.Sender, sig.Path); id != "" {
+			m.scheduleRefresh(id)
+		}
+
+	case strings.HasPrefix(sig.Name, menuIface+"."):
+		m.bumpMenuRevision(sig.Sender, sig.Path)
+
+	case sig.Name == watcherIface+".StatusNotifierItemRegistered" && !m.ownsWatcher.Load():
+		if len(sig.Body) == 1 {
+			service, _ := sig.Body[0].(string)
+			busName, path := parseItemService("", service)
+			go m.addItem(busName, path)
+		}
+
+	case sig.Name == watcherIface+".StatusNotifierItemUnregistered" && !m.ownsWatcher.Load():
+		if len(sig.Body) == 1 {
+			service, _ := sig.Body[0].(string)
+			busName, path := parseItemService("", service)
+			m.removeItems(func(t *trackedItem) bool { return t.ID == busName+path })
+		}
+	}
+}
+
+// takeOverWatcher claims the watcher name after the previous owner, usually
+// an earlier shell instance, went away. Items re-register on their own once
+// the name reappears.
+func (m *Manager) takeOverWatcher() {
+	owns, err := m.claimWatcher()
+	if err != nil || !owns {
+		return
+	}
+	m.ownsWatcher.Store(true)
+
+	m.itemsMutex.RLock()
+	ids := m.itemIDsLocked()
+	m.itemsMutex.RUnlock()
+
+	m.updateRegisteredItems(ids)
+	m.emitWatcherSignal("StatusNotifierHostRegistered")
+	log.Info("[Tray] took over " + watcherName)
+}
+
+func (m *Manager) GetState() State {
+	m.itemsMutex.RLock()
+	defer m.itemsMutex.RUnlock()
+
+	state := State{Available: true, Watcher: m.ownsWatcher.Load(), Items: []Item{}}
+	for _, tracked := range m.sortedItemsLocked() {
+		state.Items = append(state.Items, tracked.Item)
+	}
+	return state
+}
+
+func (m *Manager) callItem(id, method string, args ...interface{}) error {
+	item, err := m.lookupItem(id)
+	if err != nil {
+		return err
+	}
+
+	obj := m.conn.Object(item.Service, dbus.ObjectPath(item.Path))
+	if err := obj.Call(itemIface+"."+method, 0, args...).Err; err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	return nil
+}
+
+func (m *Manager) Activate(id string, x, y int32) error {
+	return m.callItem(id, "Activate", x, y)
+}
+
+func (m *Manager) SecondaryActivate(id string, x, y int32) error {
+	return m.callItem(id, "SecondaryActivate", x, y)
+}
+
+func (m *Manager) ContextMenu(id string, x, y int32) error {
+	return m.callItem(id, "ContextMenu", x, y)
+}
+
+func (m *Manager) Scroll(id string, delta int32, orientation string) error {
+	if orientation != "vertical" && orientation != "horizontal" {
+		return fmt.Errorf("invalid orientation: %s", orientation)
+	}
+	return m.callItem(id, "Scroll", delta, orientation)
+}
+
+func (m *Manager) notifySubscribers() {
+	state := m.GetState()
+
+	m.subMutex.RLock()
+	defer m.subMutex.RUnlock()
+
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stopChan)
+		m.wg.Wait()
+
+		if m.conn != nil {
+			m.conn.RemoveSignal(m.signals)
+			m.conn.Close()
+		}
+
+		m.subMutex.Lock()
+		for id, ch := range m.subscribers {
+			close(ch)
+			delete(m.subscribers, id)
+		}
+		m.subMutex.Unlock()
+	})
+}
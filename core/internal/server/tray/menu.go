@@ -0,0 +1,127 @@
+package tray
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type dbusMenuLayout struct {
+	ID       int32
+	Props    map[string]dbus.Variant
+	Children []dbus.Variant
+}
+
+// stripMnemonic removes dbusmenu access key markers: "_File" becomes "File"
+// and "__" an underscore.
+func stripMnemonic(label string) string {
+	var b strings.Builder
+	for i := 0; i < len(label); i++ {
+		if label[i] == '_' {
+			if i+1 < len(label) && label[i+1] == '_' {
+				b.WriteByte('_')
+				i++
+			}
+			continue
+		}
+		b.WriteByte(label[i])
+	}
+	return b.String()
+}
+
+func convertMenuLayout(layout dbusMenuLayout) MenuItem {
+	item := MenuItem{
+		ID:          layout.ID,
+		Type:        "standard",
+		Enabled:     true,
+		Visible:     true,
+		ToggleState: -1,
+		Children:    []MenuItem{},
+	}
+
+	for key, value := range layout.Props {
+		switch key {
+		case "type":
+			item.Type, _ = value.Value().(string)
+		case "label":
+			label, _ := value.Value().(string)
+			item.Label = stripMnemonic(label)
+		case "enabled":
+			item.Enabled, _ = value.Value().(bool)
+		case "visible":
+			item.Visible, _ = value.Value().(bool)
+		case "icon-name":
+			item.IconName, _ = value.Value().(string)
+		case "icon-data":
+			if data, ok := value.Value().([]byte); ok && len(data) > 0 {
+				item.IconData = base64.StdEncoding.EncodeToString(data)
+			}
+		case "toggle-type":
+			item.ToggleType, _ = value.Value().(string)
+		case "toggle-state":
+			item.ToggleState, _ = value.Value().(int32)
+		case "children-display":
+			item.ChildrenDisplay, _ = value.Value().(string)
+		case "shortcut":
+			item.Shortcut, _ = value.Value().([][]string)
+		}
+	}
+
+	for _, child := range layout.Children {
+		var childLayout dbusMenuLayout
+		if err := child.Store(&childLayout); err != nil {
+			continue
+		}
+		item.Children = append(item.Children, convertMenuLayout(childLayout))
+	}
+	return item
+}
+
+func (m *Manager) menuObject(id string) (dbus.BusObject, error) {
+	item, err := m.lookupItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Menu == "" {
+		return nil, fmt.Errorf("tray item has no menu: %s", id)
+	}
+	return m.conn.Object(item.Service, dbus.ObjectPath(item.Menu)), nil
+}
+
+// GetMenu returns the dbusmenu layout below parentID, 0 being the root.
+func (m *Manager) GetMenu(id string, parentID int32) (*Menu, error) {
+	obj, err := m.menuObject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Call(menuIface+".AboutToShow", 0, parentID)
+
+	var revision uint32
+	var layout dbusMenuLayout
+	if err := obj.Call(menuIface+".GetLayout", 0, parentID, int32(-1), []string{}).Store(&revision, &layout); err != nil {
+		return nil, fmt.Errorf("failed to get menu layout: %w", err)
+	}
+
+	return &Menu{Revision: revision, Root: convertMenuLayout(layout)}, nil
+}
+
+func (m *Manager) MenuEvent(id string, menuItemID int32, eventID string) error {
+	obj, err := m.menuObject(id)
+	if err != nil {
+		return err
+	}
+
+	if eventID == "opened" {
+		obj.Call(menuIface+".AboutToShow", 0, menuItemID)
+	}
+
+	call := obj.Call(menuIface+".Event", 0, menuItemID, eventID, dbus.MakeVariant(""), uint32(time.Now().Unix()))
+	if call.Err != nil {
+		return fmt.Errorf("menu event failed: %w", call.Err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package tray
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripMnemonic(t *testing.T) {
+	assert.Equal(t, "File", stripMnemonic("_File"))
+	assert.Equal(t, "snake_case", stripMnemonic("snake__case"))
+	assert.Equal(t, "Quit", stripMnemonic("Quit"))
+}
+
+func TestConvertMenuLayout(t *testing.T) {
+	child := func(id int32, props map[string]dbus.Variant) dbus.Variant {
+		return dbus.MakeVariant(dbusMenuLayout{ID: id, Props: props, Children: []dbus.Variant{}})
+	}
+
+	layout := dbusMenuLayout{
+		ID:    0,
+		Props: map[string]dbus.Variant{"children-display": dbus.MakeVariant("submenu")},
+		Children: []dbus.Variant{
+			child(1, map[string]dbus.Variant{
+				"label":        dbus.MakeVariant("_Enable Wi-Fi"),
+				"toggle-type":  dbus.MakeVariant("checkmark"),
+				"toggle-state": dbus.MakeVariant(int32(1)),
+			}),
+			child(2, map[string]dbus.Variant{"type": dbus.MakeVariant("separator")}),
+			child(3, map[string]dbus.Variant{
+				"label":     dbus.MakeVariant("Quit"),
+				"enabled":   dbus.MakeVariant(false),
+				"icon-data": dbus.MakeVariant([]byte{0x89, 'P', 'N', 'G'}),
+			}),
+		},
+	}
+
+	root := convertMenuLayout(layout)
+	assert.Equal(t, "submenu", root.ChildrenDisplay)
+	require.Len(t, root.Children, 3)
+
+	assert.Equal(t, MenuItem{
+		ID: 1, Type: "standard", Label: "Enable Wi-Fi", Enabled: true, Visible: true,
+		ToggleType: "checkmark", ToggleState: 1, Children: []MenuItem{},
+	}, root.Children[0])
+	assert.Equal(t, "separator", root.Children[1].Type)
+	assert.False(t, root.Children[2].Enabled)
+	assert.Equal(t, "iVBORw==", root.Children[2].IconData)
+	assert.Equal(t, int32(-1), root.Children[2].ToggleState)
+}
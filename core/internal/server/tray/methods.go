@@ -0,0 +1,66 @@
+package tray
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var itemParam = models.ParamSpec{Name: "id", Type: models.ParamString, Required: true, Description: "item id from getState"}
+
+var positionParams = []models.ParamSpec{
+	itemParam,
+	{Name: "x", Type: models.ParamNumber, Description: "screen x coordinate"},
+	{Name: "y", Type: models.ParamNumber, Description: "screen y coordinate"},
+}
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "tray.getState",
+		Description: "Get registered StatusNotifierItems with icons, pixmaps and tooltips",
+		Notes:       []string{"Pixmaps are base64 encoded PNGs", "watcher is false when another process owns org.kde.StatusNotifierWatcher"},
+	},
+	{
+		Name:        "tray.getMenu",
+		Description: "Get an item's dbusmenu layout",
+		Params: []models.ParamSpec{
+			itemParam,
+			{Name: "parentId", Type: models.ParamNumber, Description: "submenu id, defaults to the root (0)"},
+		},
+		Notes: []string{"Refetch when the item's menuRevision changes"},
+	},
+	{
+		Name:        "tray.menuEvent",
+		Description: "Send a dbusmenu event to a menu entry",
+		Params: []models.ParamSpec{
+			itemParam,
+			{Name: "menuItemId", Type: models.ParamNumber, Required: true},
+			{Name: "event", Type: models.ParamString, Enum: []string{"clicked", "hovered", "opened", "closed"}, Description: "defaults to clicked"},
+		},
+	},
+	{
+		Name:        "tray.activate",
+		Description: "Primary activation (left click)",
+		Params:      positionParams,
+	},
+	{
+		Name:        "tray.secondaryActivate",
+		Description: "Secondary activation (middle click)",
+		Params:      positionParams,
+	},
+	{
+		Name:        "tray.contextMenu",
+		Description: "Ask the item to show its own context menu",
+		Params:      positionParams,
+	},
+	{
+		Name:        "tray.scroll",
+		Description: "Forward a scroll to the item",
+		Params: []models.ParamSpec{
+			itemParam,
+			{Name: "delta", Type: models.ParamNumber, Required: true},
+			{Name: "orientation", Type: models.ParamString, Enum: []string{"vertical", "horizontal"}, Description: "defaults to vertical"},
+		},
+	},
+	{
+		Name:        "tray.subscribe",
+		Description: "Subscribe to tray item changes",
+		Streaming:   true,
+	},
+}
@@ -0,0 +1,114 @@
+package tray
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// Pixmap is an SNI ARGB32 pixmap converted to a base64 encoded PNG.
+type Pixmap struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	PNG    string `json:"png"`
+}
+
+type ToolTip struct {
+	IconName    string   `json:"iconName"`
+	IconPixmaps []Pixmap `json:"iconPixmaps"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+}
+
+type Item struct {
+	ID                   string   `json:"id"`
+	Service              string   `json:"service"`
+	Path                 string   `json:"path"`
+	ItemID               string   `json:"itemId"`
+	Category             string   `json:"category"`
+	Title                string   `json:"title"`
+	Status               string   `json:"status"`
+	WindowID             int      `json:"windowId"`
+	IconName             string   `json:"iconName"`
+	IconThemePath        string   `json:"iconThemePath"`
+	IconPixmaps          []Pixmap `json:"iconPixmaps"`
+	OverlayIconName      string   `json:"overlayIconName"`
+	OverlayIconPixmaps   []Pixmap `json:"overlayIconPixmaps"`
+	AttentionIconName    string   `json:"attentionIconName"`
+	AttentionIconPixmaps []Pixmap `json:"attentionIconPixmaps"`
+	AttentionMovieName   string   `json:"attentionMovieName"`
+	ToolTip              *ToolTip `json:"toolTip,omitempty"`
+	ItemIsMenu           bool     `json:"itemIsMenu"`
+	Menu                 string   `json:"menu"`
+	MenuRevision         uint32   `json:"menuRevision"`
+}
+
+type MenuItem struct {
+	ID              int32      `json:"id"`
+	Type            string     `json:"type"`
+	Label           string     `json:"label"`
+	Enabled         bool       `json:"enabled"`
+	Visible         bool       `json:"visible"`
+	IconName        string     `json:"iconName,omitempty"`
+	IconData        string     `json:"iconData,omitempty"`
+	ToggleType      string     `json:"toggleType,omitempty"`
+	ToggleState     int32      `json:"toggleState"`
+	ChildrenDisplay string     `json:"childrenDisplay,omitempty"`
+	Shortcut        [][]string `json:"shortcut,omitempty"`
+	Children        []MenuItem `json:"children"`
+}
+
+type Menu struct {
+	Revision uint32   `json:"revision"`
+	Root     MenuItem `json:"root"`
+}
+
+// State reports whether this daemon owns the watcher name. When another
+// watcher already owns it, items are tracked as a host of that watcher.
+type State struct {
+	Available bool   `json:"available"`
+	Watcher   bool   `json:"watcher"`
+	Items     []Item `json:"items"`
+}
+
+type trackedItem struct {
+	Item
+	owner   string
+	seq     uint64
+	pending bool
+}
+
+type Manager struct {
+	conn        *dbus.Conn
+	props       *prop.Properties
+	ownsWatcher atomic.Bool
+	hostName    string
+	items       map[string]*trackedItem
+	nextSeq     uint64
+	itemsMutex  sync.RWMutex
+	subscribers map[string]chan State
+	subMutex    sync.RWMutex
+	signals     chan *dbus.Signal
+	stopChan    chan struct{}
+	wg          sync.WaitGroup
+	closeOnce   sync.Once
+}
+
+func (m *Manager) Subscribe(id string) chan State {
+	ch := make(chan State, 64)
+	m.subMutex.Lock()
+	m.subscribers[id] = ch
+	m.subMutex.Unlock()
+	return ch
+}
+
+func (m *Manager) Unsubscribe(id string) {
+	m.subMutex.Lock()
+	if ch, ok := m.subscribers[id]; ok {
+		close(ch)
+		delete(m.subscribers, id)
+	}
+	m.subMutex.Unlock()
+}
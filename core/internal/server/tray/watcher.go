@@ -0,0 +1,185 @@
+package tray
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/log"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const (
+	watcherName     = "org.kde.StatusNotifierWatcher"
+	watcherPath     = "/StatusNotifierWatcher"
+	watcherIface    = "org.kde.StatusNotifierWatcher"
+	itemIface       = "org.kde.StatusNotifierItem"
+	menuIface       = "com.canonical.dbusmenu"
+	defaultItemPath = "/StatusNotifierItem"
+	dbusName        = "org.freedesktop.DBus"
+)
+
+const watcherIntrospectXML = `
+<node>
+	<interface name="org.kde.StatusNotifierWatcher">
+		<method name="RegisterStatusNotifierItem">
+			<arg direction="in" type="s" name="service"/>
+		</method>
+		<method name="RegisterStatusNotifierHost">
+			<arg direction="in" type="s" name="service"/>
+		</method>
+		<property name="RegisteredStatusNotifierItems" type="as" access="read"/>
+		<property name="IsStatusNotifierHostRegistered" type="b" access="read"/>
+		<property name="ProtocolVersion" type="i" access="read"/>
+		<signal name="StatusNotifierItemRegistered">
+			<arg type="s" name="service"/>
+		</signal>
+		<signal name="StatusNotifierItemUnregistered">
+			<arg type="s" name="service"/>
+		</signal>
+		<signal name="StatusNotifierHostRegistered"/>
+		<signal name="StatusNotifierHostUnregistered"/>
+	</interface>
+	<interface name="org.freedesktop.DBus.Properties">
+		<method name="Get">
+			<arg direction="in" type="s" name="interface"/>
+			<arg direction="in" type="s" name="property"/>
+			<arg direction="out" type="v" name="value"/>
+		</method>
+		<method name="GetAll">
+			<arg direction="in" type="s" name="interface"/>
+			<arg direction="out" type="a{sv}" name="properties"/>
+		</method>
+		<signal name="PropertiesChanged">
+			<arg type="s" name="interface"/>
+			<arg type="a{sv}" name="changed"/>
+			<arg type="as" name="invalidated"/>
+		</signal>
+	</interface>
+	<interface name="org.freedesktop.DBus.Introspectable">
+		<method name="Introspect">
+			<arg direction="out" type="s" name="data"/>
+		</method>
+	</interface>
+</node>`
+
+// watcher is exported as org.kde.StatusNotifierWatcher. The daemon is its own
+// host, so IsStatusNotifierHostRegistered stays true while it runs.
+type watcher struct {
+	m *Manager
+}
+
+func (w *watcher) RegisterStatusNotifierItem(sender dbus.Sender, service string) *dbus.Error {
+	busName, path := parseItemService(string(sender), service)
+	go w.m.addItem(busName, path)
+	return nil
+}
+
+func (w *watcher) RegisterStatusNotifierHost(sender dbus.Sender, service string) *dbus.Error {
+	log.Debugf("[Tray] host registered: %s (%s)", service, sender)
+	return nil
+}
+
+func (w *watcher) Introspect() (string, *dbus.Error) {
+	return watcherIntrospectXML, nil
+}
+
+// parseItemService resolves the argument of RegisterStatusNotifierItem,
+// which is either a bus name or, for Ayatana style items, an object path on
+// the sender's connection.
+func parseItemService(sender, service string) (string, string) {
+	switch {
+	case service == "":
+		return sender, defaultItemPath
+	case strings.HasPrefix(service, "/"):
+		return sender, service
+	}
+
+	if idx := strings.Index(service, "/"); idx > 0 {
+		return service[:idx], service[idx:]
+	}
+	return service, defaultItemPath
+}
+
+func (m *Manager) exportWatcher() error {
+	w := &watcher{m: m}
+	if err := m.conn.Export(w, watcherPath, watcherIface); err != nil {
+		return fmt.Errorf("watcher export failed: %w", err)
+	}
+
+	props, err := prop.Export(m.conn, watcherPath, prop.Map{
+		watcherIface: {
+			"RegisteredStatusNotifierItems":  {Value: []string{}, Emit: prop.EmitTrue},
+			"IsStatusNotifierHostRegistered": {Value: true, Emit: prop.EmitTrue},
+			"ProtocolVersion":                {Value: int32(0), Emit: prop.EmitConst},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("watcher properties export failed: %w", err)
+	}
+	m.props = props
+
+	if err := m.conn.Export(w, watcherPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return fmt.Errorf("introspection export failed: %w", err)
+	}
+	return nil
+}
+
+// claimWatcher takes over the watcher name, replacing a watcher that allows
+// it. Returns false when another watcher keeps the name.
+func (m *Manager) claimWatcher() (bool, error) {
+	reply, err := m.conn.RequestName(watcherName, dbus.NameFlagReplaceExisting|dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return false, fmt.Errorf("failed to request %s: %w", watcherName, err)
+	}
+	return reply == dbus.RequestNameReplyPrimaryOwner || reply == dbus.RequestNameReplyAlreadyOwner, nil
+}
+
+// registerAsHost registers with a watcher owned by another process and
+// adopts its items.
+func (m *Manager) registerAsHost() error {
+	m.hostName = fmt.Sprintf("org.kde.StatusNotifierHost-%d", os.Getpid())
+	if _, err := m.conn.RequestName(m.hostName, dbus.NameFlagDoNotQueue); err != nil {
+		return fmt.Errorf("failed to request %s: %w", m.hostName, err)
+	}
+
+	if err := m.conn.AddMatchSignal(
+		dbus.WithMatchInterface(watcherIface),
+		dbus.WithMatchObjectPath(watcherPath),
+	); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", watcherName, err)
+	}
+
+	obj := m.conn.Object(watcherName, watcherPath)
+	if err := obj.Call(watcherIface+".RegisterStatusNotifierHost", 0, m.hostName).Err; err != nil {
+		return fmt.Errorf("failed to register host: %w", err)
+	}
+
+	variant, err := obj.GetProperty(watcherIface + ".RegisteredStatusNotifierItems")
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+	services, _ := variant.Value().([]string)
+	for _, service := range services {
+		busName, path := parseItemService("", service)
+		go m.addItem(busName, path)
+	}
+	return nil
+}
+
+func (m *Manager) emitWatcherSignal(member string, args ...interface{}) {
+	if !m.ownsWatcher.Load() {
+		return
+	}
+	if err := m.conn.Emit(watcherPath, watcherIface+"."+member, args...); err != nil {
+		log.Debugf("[Tray] failed to emit %s: %v", member, err)
+	}
+}
+
+func (m *Manager) updateRegisteredItems(ids []string) {
+	if !m.ownsWatcher.Load() || m.props == nil {
+		return
+	}
+	m.props.SetMust(watcherIface, "RegisteredStatusNotifierItems", ids)
+}
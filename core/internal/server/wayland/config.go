@@ -0,0 +1,40 @@
+package wayland
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AvengeMedia/DankMaterialShell/core/internal/config"
+)
+
+func DefaultConfigPath() string {
+	return filepath.Join(config.DMSDir("XDG_CONFIG_HOME", ".config"), "gamma.json")
+}
+
+// LoadConfig reads a saved config. A missing file yields DefaultConfig, and
+// fields absent from the file keep their default values.
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return DefaultConfig(), fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return DefaultConfig(), fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func SaveConfig(path string, cfg Config) error {
+	return config.WriteJSON(path, cfg)
+}
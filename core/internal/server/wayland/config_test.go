@@ -0,0 +1,83 @@
+package wayland
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "gamma.json"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.LowTemp != 4000 || config.HighTemp != 6500 || config.Gamma != 1.0 {
+		t.Errorf("expected defaults, got %+v", config)
+	}
+}
+
+func TestSaveAndLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "DankMaterialShell", "gamma.json")
+
+	config := DefaultConfig()
+	config.LowTemp = 3500
+	config.Latitude = floatPtr(52.52)
+	config.Longitude = floatPtr(13.40)
+	config.Keyframes = []Keyframe{{Time: "07:00", Temp: 6500}, {Time: "21:00", Temp: 3500}}
+	config.Outputs["DP-1"] = OutputConfig{LowTemp: 5500, HighTemp: 5500, Gamma: 1.0, Enabled: true}
+	config.Profiles = map[string]Profile{
+		"movie": {LowTemp: 3000, HighTemp: 3000, Gamma: 1.0},
+	}
+	config.ActiveProfile = "movie"
+
+	if err := SaveConfig(path, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if loaded.LowTemp != 3500 || *loaded.Latitude != 52.52 || loaded.ActiveProfile != "movie" {
+		t.Errorf("loaded config mismatch: %+v", loaded)
+	}
+	if len(loaded.Keyframes) != 2 || loaded.Keyframes[1].Temp != 3500 {
+		t.Errorf("keyframes = %+v", loaded.Keyframes)
+	}
+	if loaded.Outputs["DP-1"].LowTemp != 5500 {
+		t.Errorf("outputs = %+v", loaded.Outputs)
+	}
+	if loaded.Profiles["movie"].LowTemp != 3000 {
+		t.Errorf("profiles = %+v", loaded.Profiles)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gamma.json")
+	if err := os.WriteFile(path, []byte(`{"lowTemp": 200}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err == nil {
+		t.Error("expected error for invalid config")
+	}
+	if config.LowTemp != 4000 {
+		t.Errorf("expected defaults on error, got %+v", config)
+	}
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	config := DefaultConfig()
+	config.LowTemp = 3000
+	config.Keyframes = []Keyframe{{Time: "20:00", Temp: 3000}}
+	profile := config.profile()
+
+	config.LowTemp = 4500
+	config.Keyframes = nil
+	config.applyProfile(profile)
+
+	if config.LowTemp != 3000 || len(config.Keyframes) != 1 {
+		t.Errorf("applyProfile() = %+v", config)
+	}
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -41,6 +41,18 @@ func HandleRequest(conn net.Conn, req Request, manager *Manager) {
 		handleSetGamma(conn, req, manager)
 	case "wayland.gamma.setEnabled":
 		handleSetEnabled(conn, req, manager)
+	case "wayland.gamma.setKeyframes":
+		handleSetKeyframes(conn, req, manager)
+	case "wayland.gamma.saveProfile":
+		handleSaveProfile(conn, req, manager)
+	case "wayland.gamma.applyProfile":
+		handleApplyProfile(conn, req, manager)
+	case "wayland.gamma.deleteProfile":
+		handleDeleteProfile(conn, req, manager)
+	case "wayland.gamma.setOutputConfig":
+		handleSetOutputConfig(conn, req, manager)
+	case "wayland.gamma.clearOutputConfig":
+		handleClearOutputConfig(conn, req, manager)
 	case "wayland.gamma.subscribe":
 		handleSubscribe(conn, req, manager)
 	default:
@@ -83,13 +95,13 @@ func handleSetTemperature(conn net.Conn, req Request, manager *Manager) {
 func handleSetLocation(conn net.Conn, req Request, manager *Manager) {
 	lat, ok := req.Params["latitude"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'latitude' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'latitude' parameter"))
 		return
 	}
 
 	lon, ok := req.Params["longitude"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'longitude' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'longitude' parameter"))
 		return
 	}
 
@@ -148,7 +160,7 @@ func handleSetManualTimes(conn net.Conn, req Request, manager *Manager) {
 func handleSetUseIPLocation(conn net.Conn, req Request, manager *Manager) {
 	use, ok := req.Params["use"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'use' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'use' parameter"))
 		return
 	}
 
@@ -159,7 +171,7 @@ func handleSetUseIPLocation(conn net.Conn, req Request, manager *Manager) {
 func handleSetGamma(conn net.Conn, req Request, manager *Manager) {
 	gamma, ok := req.Params["gamma"].(float64)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'gamma' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'gamma' parameter"))
 		return
 	}
 
@@ -174,7 +186,7 @@ func handleSetGamma(conn net.Conn, req Request, manager *Manager) {
 func handleSetEnabled(conn net.Conn, req Request, manager *Manager) {
 	enabled, ok := req.Params["enabled"].(bool)
 	if !ok {
-		models.RespondError(conn, req.ID, "missing or invalid 'enabled' parameter")
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'enabled' parameter"))
 		return
 	}
 
@@ -182,6 +194,130 @@ func handleSetEnabled(conn net.Conn, req Request, manager *Manager) {
 	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "enabled state set"})
 }
 
+func handleSetKeyframes(conn net.Conn, req Request, manager *Manager) {
+	raw, ok := req.Params["keyframes"].([]interface{})
+	if !ok {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'keyframes' parameter"))
+		return
+	}
+
+	frames := make([]Keyframe, 0, len(raw))
+	for _, item := range raw {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			models.RespondErr(conn, req.ID, models.NewParamError("invalid 'keyframes' parameter: expected objects with time and temp"))
+			return
+		}
+		t, okTime := entry["time"].(string)
+		temp, okTemp := entry["temp"].(float64)
+		if !okTime || !okTemp {
+			models.RespondErr(conn, req.ID, models.NewParamError("invalid 'keyframes' parameter: expected objects with time and temp"))
+			return
+		}
+		frames = append(frames, Keyframe{Time: t, Temp: int(temp)})
+	}
+
+	if err := manager.SetKeyframes(frames); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	if len(frames) == 0 {
+		models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "keyframes cleared"})
+		return
+	}
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "keyframes set"})
+}
+
+func handleSaveProfile(conn net.Conn, req Request, manager *Manager) {
+	name, ok := req.Params["name"].(string)
+	if !ok || name == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
+		return
+	}
+
+	if err := manager.SaveProfile(name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "profile saved"})
+}
+
+func handleApplyProfile(conn net.Conn, req Request, manager *Manager) {
+	name, ok := req.Params["name"].(string)
+	if !ok || name == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
+		return
+	}
+
+	if err := manager.ApplyProfile(name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "profile applied"})
+}
+
+func handleDeleteProfile(conn net.Conn, req Request, manager *Manager) {
+	name, ok := req.Params["name"].(string)
+	if !ok || name == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'name' parameter"))
+		return
+	}
+
+	if err := manager.DeleteProfile(name); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "profile deleted"})
+}
+
+func handleSetOutputConfig(conn net.Conn, req Request, manager *Manager) {
+	output, ok := req.Params["output"].(string)
+	if !ok || output == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'output' parameter"))
+		return
+	}
+
+	config := manager.GetOutputConfig(output)
+	if temp, ok := req.Params["temp"].(float64); ok {
+		config.LowTemp = int(temp)
+		config.HighTemp = int(temp)
+	}
+	if low, ok := req.Params["low"].(float64); ok {
+		config.LowTemp = int(low)
+	}
+	if high, ok := req.Params["high"].(float64); ok {
+		config.HighTemp = int(high)
+	}
+	if gamma, ok := req.Params["gamma"].(float64); ok {
+		config.Gamma = gamma
+	}
+	if enabled, ok := req.Params["enabled"].(bool); ok {
+		config.Enabled = enabled
+	}
+
+	if err := manager.SetOutputConfig(output, config); err != nil {
+		models.RespondError(conn, req.ID, err.Error())
+		return
+	}
+
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "output config set"})
+}
+
+func handleClearOutputConfig(conn net.Conn, req Request, manager *Manager) {
+	output, ok := req.Params["output"].(string)
+	if !ok || output == "" {
+		models.RespondErr(conn, req.ID, models.NewParamError("missing or invalid 'output' parameter"))
+		return
+	}
+
+	manager.ClearOutputConfig(output)
+	models.Respond(conn, req.ID, SuccessResult{Success: true, Message: "output config cleared"})
+}
+
 func handleSubscribe(conn net.Conn, req Request, manager *Manager) {
 	clientID := fmt.Sprintf("client-%p", conn)
 	stateChan := manager.Subscribe(clientID)
@@ -0,0 +1,67 @@
+package wayland
+
+import (
+	"slices"
+	"time"
+)
+
+type keyframeTime struct {
+	offset time.Duration
+	temp   int
+}
+
+func sortedKeyframes(frames []Keyframe) []keyframeTime {
+	sorted := make([]keyframeTime, 0, len(frames))
+	for _, frame := range frames {
+		t, err := time.Parse("15:04", frame.Time)
+		if err != nil {
+			continue
+		}
+		offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		sorted = append(sorted, keyframeTime{offset: offset, temp: frame.Temp})
+	}
+	slices.SortFunc(sorted, func(a, b keyframeTime) int {
+		return int(a.offset - b.offset)
+	})
+	return sorted
+}
+
+// keyframeTemperature returns the temperature of the last keyframe at or
+// before now, wrapping to the previous day's final keyframe before the first.
+func keyframeTemperature(frames []Keyframe, now time.Time) (int, bool) {
+	sorted := sortedKeyframes(frames)
+	if len(sorted) == 0 {
+		return 0, false
+	}
+
+	elapsed := now.Sub(startOfDay(now))
+	temp := sorted[len(sorted)-1].temp
+	for _, frame := range sorted {
+		if frame.offset > elapsed {
+			break
+		}
+		temp = frame.temp
+	}
+	return temp, true
+}
+
+func nextKeyframe(frames []Keyframe, now time.Time) (time.Time, bool) {
+	sorted := sortedKeyframes(frames)
+	if len(sorted) == 0 {
+		return time.Time{}, false
+	}
+
+	day := startOfDay(now)
+	for _, frame := range sorted {
+		if at := day.Add(frame.offset); at.After(now) {
+			return at, true
+		}
+	}
+	next := startOfDay(day.Add(36 * time.Hour))
+	return next.Add(sorted[0].offset), true
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,75 @@
+package wayland
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyframeTemperature(t *testing.T) {
+	frames := []Keyframe{
+		{Time: "19:00", Temp: 5000},
+		{Time: "07:00", Temp: 6500},
+		{Time: "22:30", Temp: 3500},
+	}
+
+	tests := []struct {
+		name     string
+		clock    string
+		wantTemp int
+		wantNext string
+	}{
+		{"before_first_wraps", "03:00", 3500, "07:00"},
+		{"at_keyframe", "07:00", 6500, "19:00"},
+		{"afternoon", "15:45", 6500, "19:00"},
+		{"evening", "20:00", 5000, "22:30"},
+		{"late_night", "23:00", 3500, "07:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock, _ := time.Parse("15:04", tt.clock)
+			now := time.Date(2024, 6, 1, clock.Hour(), clock.Minute(), 0, 0, time.Local)
+
+			temp, ok := keyframeTemperature(frames, now)
+			if !ok || temp != tt.wantTemp {
+				t.Errorf("keyframeTemperature() = %d, %v, want %d", temp, ok, tt.wantTemp)
+			}
+
+			next, ok := nextKeyframe(frames, now)
+			if !ok || next.Format("15:04") != tt.wantNext || !next.After(now) {
+				t.Errorf("nextKeyframe() = %v, %v, want %s after %v", next, ok, tt.wantNext, now)
+			}
+		})
+	}
+}
+
+func TestKeyframesEmpty(t *testing.T) {
+	if _, ok := keyframeTemperature(nil, time.Now()); ok {
+		t.Error("expected no temperature without keyframes")
+	}
+	if _, ok := nextKeyframe(nil, time.Now()); ok {
+		t.Error("expected no transition without keyframes")
+	}
+}
+
+func TestValidateKeyframes(t *testing.T) {
+	tests := []struct {
+		name    string
+		frames  []Keyframe
+		wantErr bool
+	}{
+		{"valid", []Keyframe{{Time: "07:00", Temp: 6500}, {Time: "21:00", Temp: 4000}}, false},
+		{"bad_time", []Keyframe{{Time: "7pm", Temp: 4000}}, true},
+		{"bad_temp", []Keyframe{{Time: "07:00", Temp: 200}}, true},
+		{"duplicate_time", []Keyframe{{Time: "07:00", Temp: 6500}, {Time: "07:00", Temp: 4000}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKeyframes(tt.frames)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKeyframes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
@@ -26,6 +29,7 @@ func NewManager(display *wlclient.Display, config Config) (*Manager, error) {
 		config:         config,
 		display:        display,
 		outputs:        make(map[uint32]*outputState),
+		outputNames:    make(map[uint32]string),
 		cmdq:           make(chan cmd, 128),
 		stopChan:       make(chan struct{}),
 		updateTrigger:  make(chan struct{}, 1),
@@ -158,7 +162,6 @@ func (m *Manager) setupRegistry() error {
 
 	outputs := make([]*wlclient.Output, 0)
 	outputRegNames := make(map[uint32]uint32)
-	outputNames := make(map[uint32]string)
 	var gammaMgr *wlr_gamma_control.ZwlrGammaControlManagerV1
 
 	registry.SetGlobalHandler(func(e wlclient.RegistryGlobalEvent) {
@@ -189,7 +192,7 @@ func (m *Manager) setupRegistry() error {
 
 				output.SetNameHandler(func(ev wlclient.OutputNameEvent) {
 					log.Infof("Output %d name: %s", outputID, ev.Name)
-					outputNames[outputID] = ev.Name
+					m.setOutputName(outputID, ev.Name)
 					isVirtual := len(ev.Name) >= 9 && ev.Name[:9] == "HEADLESS-"
 					if isVirtual {
 						log.Infof("Output %d identified as virtual", outputID)
@@ -239,6 +242,13 @@ func (m *Manager) setupRegistry() error {
 			m.outputsMutex.Lock()
 			defer m.outputsMutex.Unlock()
 
+			for id, regName := range m.outputRegNames {
+				if regName == e.Name {
+					delete(m.outputRegNames, id)
+					delete(m.outputNames, id)
+				}
+			}
+
 			for id, out := range m.outputs {
 				if out.registryName == e.Name {
 					log.Infof("Output %d (registry name %d) removed, destroying gamma control", id, e.Name)
@@ -280,7 +290,7 @@ func (m *Manager) setupRegistry() error {
 	physicalOutputs := make([]*wlclient.Output, 0)
 	for _, output := range outputs {
 		outputID := output.ID()
-		name := outputNames[outputID]
+		name := m.outputName(outputID)
 		if name != "" && (len(name) >= 9 && name[:9] == "HEADLESS-") {
 			log.Infof("Skipping virtual output %d (name=%s) for gamma control", outputID, name)
 			continue
@@ -310,6 +320,7 @@ func (m *Manager) setupOutputControls(outputs []*wlclient.Output, manager *wlr_g
 
 		outState := &outputState{
 			id:           output.ID(),
+			name:         m.outputName(output.ID()),
 			registryName: m.outputRegNames[output.ID()],
 			output:       output,
 			gammaControl: control,
@@ -372,12 +383,12 @@ func (m *Manager) setupOutputControls(outputs []*wlclient.Output, manager *wlr_g
 func (m *Manager) addOutputControl(output *wlclient.Output) error {
 	outputID := output.ID()
 
-	var outputName string
+	outputName := m.outputName(outputID)
 	output.SetNameHandler(func(ev wlclient.OutputNameEvent) {
 		outputName = ev.Name
+		m.setOutputName(outputID, ev.Name)
 		m.outputsMutex.Lock()
 		if outState, exists := m.outputs[outputID]; exists {
-			outState.name = ev.Name
 			if len(ev.Name) >= 9 && ev.Name[:9] == "HEADLESS-" {
 				log.Infof("Detected virtual output %d (name=%s), marking for gamma control skip", outputID, ev.Name)
 				outState.isVirtual = true
@@ -454,6 +465,21 @@ func (m *Manager) addOutputControl(output *wlclient.Output) error {
 	return nil
 }
 
+func (m *Manager) outputName(id uint32) string {
+	m.outputsMutex.RLock()
+	defer m.outputsMutex.RUnlock()
+	return m.outputNames[id]
+}
+
+func (m *Manager) setOutputName(id uint32, name string) {
+	m.outputsMutex.Lock()
+	m.outputNames[id] = name
+	if out, exists := m.outputs[id]; exists {
+		out.name = name
+	}
+	m.outputsMutex.Unlock()
+}
+
 func (m *Manager) updateLoop() {
 	defer m.wg.Done()
 
@@ -743,7 +769,7 @@ func (m *Manager) applyGammaImmediate(temp int) {
 
 func (m *Manager) applyNowOnActor(temp int) {
 	m.configMutex.RLock()
-	gamma := m.config.Gamma
+	config := m.config
 	m.configMutex.RUnlock()
 
 	if !m.controlsInitialized {
@@ -774,7 +800,13 @@ func (m *Manager) applyNowOnActor(temp int) {
 			continue
 		}
 
-		ramp := GenerateGammaRamp(out.rampSize, temp, gamma)
+		var ramp GammaRamp
+		outTemp, gamma, enabled := config.OutputSettings(out.name, temp)
+		if enabled {
+			ramp = GenerateGammaRamp(out.rampSize, outTemp, gamma)
+		} else {
+			ramp = GenerateIdentityRamp(out.rampSize)
+		}
 
 		// Pack once into []byte
 		buf := bytes.NewBuffer(make([]byte, 0, int(out.rampSize)*6))
@@ -897,6 +929,27 @@ func (m *Manager) updateState() {
 	nextTransition := m.calculateNextTransition(now)
 	isDay := now.After(sunrise) && now.Before(sunset)
 
+	m.outputsMutex.RLock()
+	outputs := make([]OutputStatus, 0, len(m.outputs))
+	for _, out := range m.outputs {
+		if out.name == "" || out.isVirtual {
+			continue
+		}
+		outTemp, gamma, enabled := configCopy.OutputSettings(out.name, temp)
+		_, custom := configCopy.Outputs[out.name]
+		outputs = append(outputs, OutputStatus{
+			Name:        out.name,
+			Enabled:     enabled,
+			CurrentTemp: outTemp,
+			Gamma:       gamma,
+			Custom:      custom,
+		})
+	}
+	m.outputsMutex.RUnlock()
+	slices.SortFunc(outputs, func(a, b OutputStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
 	newState := State{
 		Config:         configCopy,
 		CurrentTemp:    temp,
@@ -904,6 +957,7 @@ func (m *Manager) updateState() {
 		SunriseTime:    sunrise,
 		SunsetTime:     sunset,
 		IsDay:          isDay,
+		Outputs:        outputs,
 	}
 
 	m.stateMutex.Lock()
@@ -1010,6 +1064,30 @@ func (m *Manager) handleDBusSignal(sig *dbus.Signal) {
 	}
 }
 
+// SetConfigPath makes every later config change get saved to path.
+func (m *Manager) SetConfigPath(path string) {
+	m.configMutex.Lock()
+	m.configPath = path
+	m.configMutex.Unlock()
+}
+
+func (m *Manager) saveConfig() {
+	m.configMutex.RLock()
+	path := m.configPath
+	config := m.config
+	m.configMutex.RUnlock()
+
+	if path == "" {
+		return
+	}
+
+	m.saveMutex.Lock()
+	defer m.saveMutex.Unlock()
+	if err := SaveConfig(path, config); err != nil {
+		log.Warnf("Failed to save gamma config: %v", err)
+	}
+}
+
 func (m *Manager) triggerUpdate() {
 	select {
 	case m.updateTrigger <- struct{}{}:
@@ -1026,6 +1104,7 @@ func (m *Manager) SetConfig(config Config) error {
 	m.config = config
 	m.configMutex.Unlock()
 
+	m.saveConfig()
 	m.triggerUpdate()
 	return nil
 }
@@ -1034,12 +1113,14 @@ func (m *Manager) SetTemperature(low, high int) error {
 	m.configMutex.Lock()
 	m.config.LowTemp = low
 	m.config.HighTemp = high
+	m.config.ActiveProfile = ""
 	err := m.config.Validate()
 	m.configMutex.Unlock()
 
 	if err != nil {
 		return err
 	}
+	m.saveConfig()
 	m.triggerUpdate()
 	return nil
 }
@@ -1055,6 +1136,7 @@ func (m *Manager) SetLocation(lat, lon float64) error {
 	if err != nil {
 		return err
 	}
+	m.saveConfig()
 	m.triggerUpdate()
 	return nil
 }
@@ -1075,6 +1157,7 @@ func (m *Manager) SetUseIPLocation(use bool) {
 		m.locationMutex.Unlock()
 	}
 
+	m.saveConfig()
 	m.triggerUpdate()
 }
 
@@ -1109,6 +1192,10 @@ func (m *Manager) calculateTemperature(now time.Time) int {
 		return config.HighTemp
 	}
 
+	if temp, ok := keyframeTemperature(config.Keyframes, now); ok {
+		return temp
+	}
+
 	var sunrise, sunset time.Time
 
 	if config.ManualSunrise != nil && config.ManualSunset != nil {
@@ -1158,6 +1245,10 @@ func (m *Manager) calculateNextTransition(now time.Time) time.Time {
 		return now.Add(24 * time.Hour)
 	}
 
+	if next, ok := nextKeyframe(config.Keyframes, now); ok {
+		return next
+	}
+
 	var sunrise, sunset time.Time
 
 	if config.ManualSunrise != nil && config.ManualSunset != nil {
@@ -1230,12 +1321,14 @@ func (m *Manager) SetManualTimes(sunrise, sunset time.Time) error {
 	m.configMutex.Lock()
 	m.config.ManualSunrise = &sunrise
 	m.config.ManualSunset = &sunset
+	m.config.ActiveProfile = ""
 	err := m.config.Validate()
 	m.configMutex.Unlock()
 
 	if err != nil {
 		return err
 	}
+	m.saveConfig()
 	m.triggerUpdate()
 	return nil
 }
@@ -1244,28 +1337,162 @@ func (m *Manager) ClearManualTimes() {
 	m.configMutex.Lock()
 	m.config.ManualSunrise = nil
 	m.config.ManualSunset = nil
+	m.config.ActiveProfile = ""
+	m.configMutex.Unlock()
+	m.saveConfig()
+	m.triggerUpdate()
+}
+
+func (m *Manager) SetKeyframes(frames []Keyframe) error {
+	if err := validateKeyframes(frames); err != nil {
+		return err
+	}
+
+	m.configMutex.Lock()
+	m.config.Keyframes = slices.Clone(frames)
+	m.config.ActiveProfile = ""
+	m.configMutex.Unlock()
+
+	m.saveConfig()
+	m.triggerUpdate()
+	return nil
+}
+
+func (m *Manager) SaveProfile(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name required")
+	}
+
+	m.configMutex.Lock()
+	profiles := maps.Clone(m.config.Profiles)
+	if profiles == nil {
+		profiles = make(map[string]Profile)
+	}
+	profiles[name] = m.config.profile()
+	m.config.Profiles = profiles
+	m.config.ActiveProfile = name
+	m.configMutex.Unlock()
+
+	m.saveConfig()
+	m.updateState()
+	return nil
+}
+
+func (m *Manager) ApplyProfile(name string) error {
+	m.configMutex.Lock()
+	profile, ok := m.config.Profiles[name]
+	if !ok {
+		m.configMutex.Unlock()
+		return fmt.Errorf("profile not found: %s", name)
+	}
+	m.config.applyProfile(profile)
+	m.config.ActiveProfile = name
 	m.configMutex.Unlock()
+
+	m.saveConfig()
 	m.triggerUpdate()
+	return nil
+}
+
+func (m *Manager) DeleteProfile(name string) error {
+	m.configMutex.Lock()
+	if _, ok := m.config.Profiles[name]; !ok {
+		m.configMutex.Unlock()
+		return fmt.Errorf("profile not found: %s", name)
+	}
+	profiles := maps.Clone(m.config.Profiles)
+	delete(profiles, name)
+	m.config.Profiles = profiles
+	if m.config.ActiveProfile == name {
+		m.config.ActiveProfile = ""
+	}
+	m.configMutex.Unlock()
+
+	m.saveConfig()
+	m.updateState()
+	return nil
 }
 
 func (m *Manager) SetGamma(gamma float64) error {
 	m.configMutex.Lock()
 	m.config.Gamma = gamma
+	m.config.ActiveProfile = ""
 	err := m.config.Validate()
 	m.configMutex.Unlock()
 
 	if err != nil {
 		return err
 	}
+	m.saveConfig()
 	m.triggerUpdate()
 	return nil
 }
 
+// GetOutputConfig returns the override for an output, or one seeded from the
+// global settings when the output has none.
+func (m *Manager) GetOutputConfig(name string) OutputConfig {
+	m.configMutex.RLock()
+	defer m.configMutex.RUnlock()
+
+	if out, ok := m.config.Outputs[name]; ok {
+		return out
+	}
+	return OutputConfig{
+		LowTemp:  m.config.LowTemp,
+		HighTemp: m.config.HighTemp,
+		Gamma:    m.config.Gamma,
+		Enabled:  true,
+	}
+}
+
+func (m *Manager) SetOutputConfig(name string, config OutputConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	m.configMutex.Lock()
+	outputs := maps.Clone(m.config.Outputs)
+	if outputs == nil {
+		outputs = make(map[string]OutputConfig)
+	}
+	outputs[name] = config
+	m.config.Outputs = outputs
+	m.configMutex.Unlock()
+
+	m.saveConfig()
+	m.reapplyOutputs()
+	return nil
+}
+
+func (m *Manager) ClearOutputConfig(name string) {
+	m.configMutex.Lock()
+	outputs := maps.Clone(m.config.Outputs)
+	delete(outputs, name)
+	m.config.Outputs = outputs
+	m.configMutex.Unlock()
+
+	m.saveConfig()
+	m.reapplyOutputs()
+}
+
+// reapplyOutputs pushes new per-output settings without waiting for the
+// schedule, since the global temperature has not changed.
+func (m *Manager) reapplyOutputs() {
+	m.transitionMutex.RLock()
+	currentTemp := m.currentTemp
+	m.transitionMutex.RUnlock()
+
+	m.post(func() { m.applyNowOnActor(currentTemp) })
+	m.updateState()
+}
+
 func (m *Manager) SetEnabled(enabled bool) {
 	m.configMutex.Lock()
 	m.config.Enabled = enabled
 	m.configMutex.Unlock()
 
+	m.saveConfig()
+
 	if enabled {
 		if !m.controlsInitialized {
 			m.post(func() {
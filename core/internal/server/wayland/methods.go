@@ -0,0 +1,110 @@
+package wayland
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "wayland.gamma.getState",
+		Description: "Get current gamma control state",
+	},
+	{
+		Name:        "wayland.gamma.setTemperature",
+		Description: "Set temperature range (either temp, or low and high)",
+		Params: []models.ParamSpec{
+			{Name: "temp", Type: models.ParamNumber},
+			{Name: "low", Type: models.ParamNumber},
+			{Name: "high", Type: models.ParamNumber},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setLocation",
+		Description: "Set location",
+		Params: []models.ParamSpec{
+			{Name: "latitude", Type: models.ParamNumber, Required: true},
+			{Name: "longitude", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setManualTimes",
+		Description: "Set manual times as HH:MM (omit either to clear)",
+		Params: []models.ParamSpec{
+			{Name: "sunrise", Type: models.ParamAny},
+			{Name: "sunset", Type: models.ParamAny},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setUseIPLocation",
+		Description: "Use IP-based geolocation for sun times",
+		Params: []models.ParamSpec{
+			{Name: "use", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setGamma",
+		Description: "Set gamma value",
+		Params: []models.ParamSpec{
+			{Name: "gamma", Type: models.ParamNumber, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setEnabled",
+		Description: "Enable/disable gamma control",
+		Params: []models.ParamSpec{
+			{Name: "enabled", Type: models.ParamBool, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setKeyframes",
+		Description: "Set time-of-day temperature keyframes (empty array clears)",
+		Params: []models.ParamSpec{
+			{Name: "keyframes", Type: models.ParamArray, Required: true, Description: `[{"time": "HH:MM", "temp": 4000}, ...]`},
+		},
+		Notes: []string{"Keyframes replace the sunrise/sunset schedule while set."},
+	},
+	{
+		Name:        "wayland.gamma.saveProfile",
+		Description: "Save the current schedule as a named profile",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.applyProfile",
+		Description: "Switch to a saved schedule profile",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.deleteProfile",
+		Description: "Delete a saved schedule profile",
+		Params: []models.ParamSpec{
+			{Name: "name", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.setOutputConfig",
+		Description: "Override temperature range, gamma or enable flag for one output",
+		Params: []models.ParamSpec{
+			{Name: "output", Type: models.ParamString, Required: true, Description: "output name, e.g. DP-1"},
+			{Name: "temp", Type: models.ParamNumber, Description: "fixed temperature"},
+			{Name: "low", Type: models.ParamNumber},
+			{Name: "high", Type: models.ParamNumber},
+			{Name: "gamma", Type: models.ParamNumber},
+			{Name: "enabled", Type: models.ParamBool},
+		},
+		Notes: []string{"Omitted fields keep the output's current override, or the global value."},
+	},
+	{
+		Name:        "wayland.gamma.clearOutputConfig",
+		Description: "Remove an output override so it follows the global settings",
+		Params: []models.ParamSpec{
+			{Name: "output", Type: models.ParamString, Required: true},
+		},
+	},
+	{
+		Name:        "wayland.gamma.subscribe",
+		Description: "Subscribe to gamma state changes",
+		Streaming:   true,
+	},
+}
@@ -1,7 +1,10 @@
 package wayland
 
 import (
+	"maps"
 	"math"
+	"reflect"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,26 +14,66 @@ import (
 )
 
 type Config struct {
-	Outputs        []string
-	LowTemp        int
-	HighTemp       int
-	Latitude       *float64
-	Longitude      *float64
-	UseIPLocation  bool
-	ManualSunrise  *time.Time
-	ManualSunset   *time.Time
-	ManualDuration *time.Duration
-	Gamma          float64
-	Enabled        bool
+	Outputs        map[string]OutputConfig `json:"outputs,omitempty"`
+	LowTemp        int                     `json:"lowTemp"`
+	HighTemp       int                     `json:"highTemp"`
+	Latitude       *float64                `json:"latitude,omitempty"`
+	Longitude      *float64                `json:"longitude,omitempty"`
+	UseIPLocation  bool                    `json:"useIPLocation"`
+	ManualSunrise  *time.Time              `json:"manualSunrise,omitempty"`
+	ManualSunset   *time.Time              `json:"manualSunset,omitempty"`
+	ManualDuration *time.Duration          `json:"manualDuration,omitempty"`
+	Keyframes      []Keyframe              `json:"keyframes,omitempty"`
+	Gamma          float64                 `json:"gamma"`
+	Enabled        bool                    `json:"enabled"`
+	Profiles       map[string]Profile      `json:"profiles,omitempty"`
+	ActiveProfile  string                  `json:"activeProfile,omitempty"`
+}
+
+// OutputConfig overrides the global night light settings for one output,
+// keyed by output name in Config.Outputs. Setting LowTemp and HighTemp to
+// the same value pins the output to a fixed temperature.
+type OutputConfig struct {
+	LowTemp  int     `json:"lowTemp"`
+	HighTemp int     `json:"highTemp"`
+	Gamma    float64 `json:"gamma"`
+	Enabled  bool    `json:"enabled"`
+}
+
+// Keyframe switches to Temp at the given "HH:MM" time of day. When a config
+// has keyframes they replace the sunrise/sunset schedule.
+type Keyframe struct {
+	Time string `json:"time"`
+	Temp int    `json:"temp"`
+}
+
+// Profile is a named copy of the schedule settings that can be switched to
+// as a whole. Location and per-output overrides are not part of it.
+type Profile struct {
+	LowTemp       int        `json:"lowTemp"`
+	HighTemp      int        `json:"highTemp"`
+	Gamma         float64    `json:"gamma"`
+	ManualSunrise *time.Time `json:"manualSunrise,omitempty"`
+	ManualSunset  *time.Time `json:"manualSunset,omitempty"`
+	Keyframes     []Keyframe `json:"keyframes,omitempty"`
+}
+
+type OutputStatus struct {
+	Name        string  `json:"name"`
+	Enabled     bool    `json:"enabled"`
+	CurrentTemp int     `json:"currentTemp"`
+	Gamma       float64 `json:"gamma"`
+	Custom      bool    `json:"custom"`
 }
 
 type State struct {
-	Config         Config    `json:"config"`
-	CurrentTemp    int       `json:"currentTemp"`
-	NextTransition time.Time `json:"nextTransition"`
-	SunriseTime    time.Time `json:"sunriseTime"`
-	SunsetTime     time.Time `json:"sunsetTime"`
-	IsDay          bool      `json:"isDay"`
+	Config         Config         `json:"config"`
+	CurrentTemp    int            `json:"currentTemp"`
+	NextTransition time.Time      `json:"nextTransition"`
+	SunriseTime    time.Time      `json:"sunriseTime"`
+	SunsetTime     time.Time      `json:"sunsetTime"`
+	IsDay          bool           `json:"isDay"`
+	Outputs        []OutputStatus `json:"outputs"`
 }
 
 type cmd struct {
@@ -40,6 +83,8 @@ type cmd struct {
 type Manager struct {
 	config      Config
 	configMutex sync.RWMutex
+	configPath  string
+	saveMutex   sync.Mutex
 	state       *State
 	stateMutex  sync.RWMutex
 
@@ -48,6 +93,7 @@ type Manager struct {
 	gammaControl        interface{}
 	availableOutputs    []*wlclient.Output
 	outputRegNames      map[uint32]uint32
+	outputNames         map[uint32]string
 	outputs             map[uint32]*outputState
 	outputsMutex        sync.RWMutex
 	controlsInitialized bool
@@ -91,6 +137,8 @@ type outputState struct {
 	lastFailTime time.Time
 }
 
+const identityTemp = 6500
+
 type SunTimes struct {
 	Sunrise time.Time
 	Sunset  time.Time
@@ -98,7 +146,7 @@ type SunTimes struct {
 
 func DefaultConfig() Config {
 	return Config{
-		Outputs:  []string{},
+		Outputs:  map[string]OutputConfig{},
 		LowTemp:  4000,
 		HighTemp: 6500,
 		Gamma:    1.0,
@@ -131,9 +179,110 @@ func (c *Config) Validate() error {
 	if (c.ManualSunrise != nil) != (c.ManualSunset != nil) {
 		return errdefs.ErrInvalidManualTimes
 	}
+	if err := validateKeyframes(c.Keyframes); err != nil {
+		return err
+	}
+	for _, out := range c.Outputs {
+		if err := out.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, profile := range c.Profiles {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Profile) Validate() error {
+	config := Config{
+		LowTemp:       p.LowTemp,
+		HighTemp:      p.HighTemp,
+		Gamma:         p.Gamma,
+		ManualSunrise: p.ManualSunrise,
+		ManualSunset:  p.ManualSunset,
+		Keyframes:     p.Keyframes,
+	}
+	return config.Validate()
+}
+
+func validateKeyframes(frames []Keyframe) error {
+	seen := make(map[string]bool, len(frames))
+	for _, frame := range frames {
+		t, err := time.Parse("15:04", frame.Time)
+		if err != nil {
+			return errdefs.ErrInvalidKeyframes
+		}
+		if frame.Temp < 1000 || frame.Temp > 10000 {
+			return errdefs.ErrInvalidTemperature
+		}
+		key := t.Format("15:04")
+		if seen[key] {
+			return errdefs.ErrInvalidKeyframes
+		}
+		seen[key] = true
+	}
 	return nil
 }
 
+// profile snapshots the schedule settings of c.
+func (c *Config) profile() Profile {
+	return Profile{
+		LowTemp:       c.LowTemp,
+		HighTemp:      c.HighTemp,
+		Gamma:         c.Gamma,
+		ManualSunrise: c.ManualSunrise,
+		ManualSunset:  c.ManualSunset,
+		Keyframes:     slices.Clone(c.Keyframes),
+	}
+}
+
+func (c *Config) applyProfile(p Profile) {
+	c.LowTemp = p.LowTemp
+	c.HighTemp = p.HighTemp
+	c.Gamma = p.Gamma
+	c.ManualSunrise = p.ManualSunrise
+	c.ManualSunset = p.ManualSunset
+	c.Keyframes = slices.Clone(p.Keyframes)
+}
+
+func (o *OutputConfig) Validate() error {
+	if o.LowTemp < 1000 || o.LowTemp > 10000 {
+		return errdefs.ErrInvalidTemperature
+	}
+	if o.HighTemp < 1000 || o.HighTemp > 10000 {
+		return errdefs.ErrInvalidTemperature
+	}
+	if o.LowTemp > o.HighTemp {
+		return errdefs.ErrInvalidTemperature
+	}
+	if o.Gamma <= 0 || o.Gamma > 10 {
+		return errdefs.ErrInvalidGamma
+	}
+	return nil
+}
+
+// OutputSettings returns the temperature, gamma and enabled flag for an
+// output while the global schedule sits at temp. Outputs with their own
+// range follow the same day/night progress scaled into that range.
+func (c *Config) OutputSettings(name string, temp int) (int, float64, bool) {
+	out, ok := c.Outputs[name]
+	if !ok || !c.Enabled {
+		return temp, c.Gamma, true
+	}
+	if !out.Enabled {
+		return identityTemp, 1.0, false
+	}
+
+	progress := 0.0
+	if c.HighTemp > c.LowTemp {
+		progress = float64(temp-c.LowTemp) / float64(c.HighTemp-c.LowTemp)
+		progress = math.Max(0, math.Min(1, progress))
+	}
+	return out.LowTemp + int(math.Round(progress*float64(out.HighTemp-out.LowTemp))), out.Gamma, true
+}
+
 func (m *Manager) GetState() State {
 	m.stateMutex.RLock()
 	defer m.stateMutex.RUnlock()
@@ -190,5 +339,20 @@ func stateChanged(old, new *State) bool {
 	if old.Config.Enabled != new.Config.Enabled {
 		return true
 	}
+	if old.Config.ActiveProfile != new.Config.ActiveProfile {
+		return true
+	}
+	if !slices.Equal(old.Config.Keyframes, new.Config.Keyframes) {
+		return true
+	}
+	if !reflect.DeepEqual(old.Config.Profiles, new.Config.Profiles) {
+		return true
+	}
+	if !maps.Equal(old.Config.Outputs, new.Config.Outputs) {
+		return true
+	}
+	if !slices.Equal(old.Outputs, new.Outputs) {
+		return true
+	}
 	return false
 }
@@ -309,6 +309,20 @@ func TestStateChanged(t *testing.T) {
 			},
 			wantChanged: true,
 		},
+		{
+			name: "output_changed",
+			old:  baseState,
+			new: &State{
+				CurrentTemp:    baseState.CurrentTemp,
+				NextTransition: baseState.NextTransition,
+				SunriseTime:    baseState.SunriseTime,
+				SunsetTime:     baseState.SunsetTime,
+				IsDay:          baseState.IsDay,
+				Config:         baseState.Config,
+				Outputs:        []OutputStatus{{Name: "DP-1", Enabled: true, CurrentTemp: 5500, Gamma: 1.0, Custom: true}},
+			},
+			wantChanged: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -321,6 +335,65 @@ func TestStateChanged(t *testing.T) {
 	}
 }
 
+func TestOutputSettings(t *testing.T) {
+	config := Config{
+		LowTemp:  4000,
+		HighTemp: 6500,
+		Gamma:    1.0,
+		Enabled:  true,
+		Outputs: map[string]OutputConfig{
+			"DP-1":   {LowTemp: 5500, HighTemp: 5500, Gamma: 1.0, Enabled: true},
+			"DP-2":   {LowTemp: 3000, HighTemp: 5000, Gamma: 0.9, Enabled: true},
+			"HDMI-1": {LowTemp: 4000, HighTemp: 6500, Gamma: 1.0, Enabled: false},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		output      string
+		temp        int
+		wantTemp    int
+		wantGamma   float64
+		wantEnabled bool
+	}{
+		{"global_output", "eDP-1", 4000, 4000, 1.0, true},
+		{"fixed_night", "DP-1", 4000, 5500, 1.0, true},
+		{"fixed_day", "DP-1", 6500, 5500, 1.0, true},
+		{"range_night", "DP-2", 4000, 3000, 0.9, true},
+		{"range_day", "DP-2", 6500, 5000, 0.9, true},
+		{"range_midway", "DP-2", 5250, 4000, 0.9, true},
+		{"disabled_output", "HDMI-1", 4000, 6500, 1.0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp, gamma, enabled := config.OutputSettings(tt.output, tt.temp)
+			if temp != tt.wantTemp || gamma != tt.wantGamma || enabled != tt.wantEnabled {
+				t.Errorf("OutputSettings(%q, %d) = (%d, %v, %v), want (%d, %v, %v)",
+					tt.output, tt.temp, temp, gamma, enabled, tt.wantTemp, tt.wantGamma, tt.wantEnabled)
+			}
+		})
+	}
+
+	config.Enabled = false
+	if temp, _, _ := config.OutputSettings("DP-1", 6000); temp != 6000 {
+		t.Errorf("disabled config should follow global temp, got %d", temp)
+	}
+}
+
+func TestConfigValidateOutputs(t *testing.T) {
+	config := DefaultConfig()
+	config.Outputs["DP-1"] = OutputConfig{LowTemp: 6000, HighTemp: 5000, Gamma: 1.0, Enabled: true}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for reversed output range")
+	}
+
+	config.Outputs["DP-1"] = OutputConfig{LowTemp: 5500, HighTemp: 5500, Gamma: 1.0, Enabled: true}
+	if err := config.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func floatPtr(f float64) *float64 {
 	return &f
 }
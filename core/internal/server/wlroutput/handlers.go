@@ -12,7 +12,7 @@ import (
 )
 
 type Request struct {
-	ID     int                    `json:"id,omitempty"`
+	ID     interface{}            `json:"id,omitempty"`
 	Method string                 `json:"method"`
 	Params map[string]interface{} `json:"params,omitempty"`
 }
@@ -76,7 +76,7 @@ func handleApplyConfiguration(conn net.Conn, req Request, manager *Manager, test
 
 	headsJSON, err := json.Marshal(headsParam)
 	if err != nil {
-		models.RespondError(conn, req.ID, "invalid 'heads' parameter format")
+		models.RespondErr(conn, req.ID, models.NewParamError("invalid 'heads' parameter format"))
 		return
 	}
 
@@ -0,0 +1,51 @@
+package wlroutput
+
+import "github.com/AvengeMedia/DankMaterialShell/core/internal/server/models"
+
+var Methods = []models.MethodSpec{
+	{
+		Name:        "wlroutput.getState",
+		Description: "Get current output configuration state",
+	},
+	{
+		Name:        "wlroutput.applyConfiguration",
+		Description: "Apply output configuration",
+		Params: []models.ParamSpec{
+			{Name: "heads", Type: models.ParamArray, Required: true},
+		},
+		Notes: []string{
+			"Head configuration params:",
+			"name         : Output name (required)",
+			"enabled      : Enable/disable output (required)",
+			"modeId       : Mode ID from available modes (optional)",
+			"customMode   : Custom mode {width, height, refresh} (optional)",
+			"position     : Position {x, y} (optional)",
+			"transform    : Transform value (optional)",
+			"scale        : Scale value (optional)",
+			"adaptiveSync : Adaptive sync state (optional)",
+		},
+	},
+	{
+		Name:        "wlroutput.testConfiguration",
+		Description: "Test output configuration without applying",
+		Params: []models.ParamSpec{
+			{Name: "heads", Type: models.ParamArray, Required: true},
+		},
+		Notes: []string{
+			"Head configuration params:",
+			"name         : Output name (required)",
+			"enabled      : Enable/disable output (required)",
+			"modeId       : Mode ID from available modes (optional)",
+			"customMode   : Custom mode {width, height, refresh} (optional)",
+			"position     : Position {x, y} (optional)",
+			"transform    : Transform value (optional)",
+			"scale        : Scale value (optional)",
+			"adaptiveSync : Adaptive sync state (optional)",
+		},
+	},
+	{
+		Name:        "wlroutput.subscribe",
+		Description: "Subscribe to output state changes",
+		Streaming:   true,
+	},
+}